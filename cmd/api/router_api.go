@@ -9,21 +9,46 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
 	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	serviceAcademic "github.com/ArkaniLoveCoding/Shcool-manajement/service/academics"
+	serviceAnnouncement "github.com/ArkaniLoveCoding/Shcool-manajement/service/announcements"
+	serviceAttendance "github.com/ArkaniLoveCoding/Shcool-manajement/service/attendance"
+	serviceCalendar "github.com/ArkaniLoveCoding/Shcool-manajement/service/calendar"
+	serviceClass "github.com/ArkaniLoveCoding/Shcool-manajement/service/classes"
+	serviceFile "github.com/ArkaniLoveCoding/Shcool-manajement/service/files"
+	serviceGrade "github.com/ArkaniLoveCoding/Shcool-manajement/service/grades"
+	serviceGuardian "github.com/ArkaniLoveCoding/Shcool-manajement/service/guardians"
+	serviceExam "github.com/ArkaniLoveCoding/Shcool-manajement/service/exams"
+	serviceHomework "github.com/ArkaniLoveCoding/Shcool-manajement/service/homework"
+	serviceQuiz "github.com/ArkaniLoveCoding/Shcool-manajement/service/quizzes"
+	serviceMajor "github.com/ArkaniLoveCoding/Shcool-manajement/service/majors"
+	serviceNotification "github.com/ArkaniLoveCoding/Shcool-manajement/service/notifications"
+	servicePromotion "github.com/ArkaniLoveCoding/Shcool-manajement/service/promotions"
+	serviceReport "github.com/ArkaniLoveCoding/Shcool-manajement/service/reports"
 	serviceStudent "github.com/ArkaniLoveCoding/Shcool-manajement/service/students"
+	serviceSubject "github.com/ArkaniLoveCoding/Shcool-manajement/service/subjects"
+	serviceTimetable "github.com/ArkaniLoveCoding/Shcool-manajement/service/timetable"
 	serviceUser "github.com/ArkaniLoveCoding/Shcool-manajement/service/users"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/storage"
 )
 
 type ApiServer struct {
 	Addr   string
 	db     *sqlx.DB
+	cfg    config.ConfigParams
+	files  storage.Storage
+	signer *storage.Signer
 	server *http.Server
 }
 
-func ApiServerAddr(addr string, db *sqlx.DB) *ApiServer {
+func ApiServerAddr(addr string, db *sqlx.DB, cfg config.ConfigParams, files storage.Storage, signer *storage.Signer) *ApiServer {
 	return &ApiServer{
-		Addr: addr,
-		db:   db,
+		Addr:   addr,
+		db:     db,
+		cfg:    cfg,
+		files:  files,
+		signer: signer,
 	}
 }
 
@@ -53,7 +78,7 @@ func (s *ApiServer) Run() error {
 
 	// The router for the services
 	userStore := serviceUser.NewStore(s.db)
-	userService := serviceUser.NewHandlerUser(userStore)
+	userService := serviceUser.NewHandlerUser(userStore, s.files, s.cfg.StorageURLTTL)
 
 	// Router for the register user
 	subRouter.Handle(
@@ -63,6 +88,14 @@ func (s *ApiServer) Run() error {
 		),
 	).Methods("POST")
 
+	// Router for the admin to create the accounts of the guru, the admin and the orangtua
+	subRouter.Handle(
+		"/users",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(userService.CreateAccount_Bp),
+		),
+	).Methods("POST")
+
 	// Router for the login user
 	subRouter.Handle(
 		"/login",
@@ -85,17 +118,29 @@ func (s *ApiServer) Run() error {
 			http.HandlerFunc(userService.Update_Bp),
 	).Methods("PATCH")
 
-	// Router to see the file path for frontend to catch it
+	// Router to see the profile image, it redirect into a signed url
 	subRouter.Handle(
-		"/users/profile/{filename}",
+		"/users/{id}/profile_image",
 		http.HandlerFunc(
 			userService.Image_Bp,
 		),
 	).Methods("GET")
 
+	// Router to download the files from the local storage with a signed url
+	fileService := serviceFile.NewHandlerFile(s.files, s.signer)
+	subRouter.Handle(
+		"/files/{key:.+}",
+		http.HandlerFunc(
+			fileService.Download_Bp,
+		),
+	).Methods("GET")
+
 	//router for the student routes
 	studentStore := serviceStudent.NewStudentStore(s.db)
-	studentService := serviceStudent.NewHandlerStudent(studentStore)
+	classStore := serviceClass.NewClassStore(s.db)
+	majorStore := serviceMajor.NewMajorStore(s.db)
+	academicStore := serviceAcademic.NewAcademicStore(s.db)
+	studentService := serviceStudent.NewHandlerStudent(studentStore, classStore, majorStore, academicStore)
 
 	//router for register as a student
 	subRouter.Handle(
@@ -115,6 +160,1129 @@ func (s *ApiServer) Run() error {
 		),
 	).Methods("GET")
 
+	//router for the classes
+	classService := serviceClass.NewHandlerClass(classStore)
+
+	//router for the mapping report of the old free-text classes (before /classes/{id})
+	subRouter.Handle(
+		"/classes/mapping-report",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.MappingReport_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/classes/mapping-report/resolve",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.ResolveMapping_Bp),
+		),
+	).Methods("POST")
+
+	//router for create and list the classes
+	subRouter.Handle(
+		"/classes",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.CreateClass_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/classes",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.GetAllClasses_Bp),
+		),
+	).Methods("GET")
+
+	//router for one class
+	subRouter.Handle(
+		"/classes/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.GetClass_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/classes/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.UpdateClass_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/classes/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.DeleteClass_Bp),
+		),
+	).Methods("DELETE")
+
+	//router for the roster of the class
+	subRouter.Handle(
+		"/classes/{id}/students",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(classService.Roster_Bp),
+		),
+	).Methods("GET")
+
+	//router for the majors catalogue
+	majorService := serviceMajor.NewHandlerMajor(majorStore)
+	subRouter.Handle(
+		"/majors",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.CreateMajor_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/majors",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.GetAllMajors_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/majors/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.GetMajor_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/majors/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.UpdateMajor_Bp),
+		),
+	).Methods("PATCH")
+
+	//router for the seat capacity and the waitlist of the major
+	subRouter.Handle(
+		"/majors/{id}/capacity",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.SetCapacity_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/majors/{id}/waitlist",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.Waitlist_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/majors/{id}/waitlist/promote",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.PromoteWaitlist_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/majors/{id}/waitlist/{entry_id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(majorService.CancelWaitlist_Bp),
+		),
+	).Methods("DELETE")
+
+	//router for the academic years and the terms
+	academicService := serviceAcademic.NewHandlerAcademic(academicStore)
+	subRouter.Handle(
+		"/academic-years",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.CreateAcademicYear_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/academic-years",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.GetAllAcademicYears_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/academic-years/{id}/activate",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.ActivateAcademicYear_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/academic-years/{id}/terms",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.CreateTerm_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/academic-years/{id}/terms",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.GetTerms_Bp),
+		),
+	).Methods("GET")
+
+	//router for the open term, and to open and close a term
+	subRouter.Handle(
+		"/terms/active",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.GetActiveTerm_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/terms/{id}/open",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.OpenTerm_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/terms/{id}/close",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(academicService.CloseTerm_Bp),
+		),
+	).Methods("POST")
+
+	//router for the end of year promotion and graduation
+	promotionService := servicePromotion.NewHandlerPromotion(servicePromotion.NewPromotionStore(s.db))
+	subRouter.Handle(
+		"/promotions/preview",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.Preview_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/promotions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.Commit_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/promotions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.GetRuns_Bp),
+		),
+	).Methods("GET")
+
+	//router for the retained students (before /promotions/{id})
+	subRouter.Handle(
+		"/promotions/retentions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.CreateRetention_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/promotions/retentions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.GetRetentions_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/promotions/retentions/{student_id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.DeleteRetention_Bp),
+		),
+	).Methods("DELETE")
+
+	subRouter.Handle(
+		"/promotions/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.GetRun_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/promotions/{id}/rollback",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(promotionService.Rollback_Bp),
+		),
+	).Methods("POST")
+
+	//router for the daily attendance
+	timetableStore := serviceTimetable.NewTimetableStore(s.db)
+	subjectStore := serviceSubject.NewSubjectStore(s.db)
+	guardianStore := serviceGuardian.NewGuardianStore(s.db)
+	notificationStore := serviceNotification.NewNotificationStore(s.db)
+	notifier := serviceNotification.NewHub(notificationStore)
+	attendanceService := serviceAttendance.NewHandlerAttendance(serviceAttendance.NewAttendanceStore(s.db), timetableStore, subjectStore, guardianStore, notifier, s.cfg)
+	subRouter.Handle(
+		"/classes/{id}/attendance",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.SubmitAttendance_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/classes/{id}/attendance",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.ClassAttendance_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/students/{id}/attendance",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.StudentAttendance_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/attendance/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.CorrectAttendance_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/attendance/{id}/history",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.History_Bp),
+		),
+	).Methods("GET")
+
+	//router for the timetable of the class
+	timetableService := serviceTimetable.NewHandlerTimetable(timetableStore, academicStore, subjectStore)
+	subRouter.Handle(
+		"/classes/{id}/timetable",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.CreateSlot_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/classes/{id}/timetable",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.ClassTimetable_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/timetable/slots/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.DeleteSlot_Bp),
+		),
+	).Methods("DELETE")
+
+	subRouter.Handle(
+		"/classes/{id}/timetable/quota",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.ClassQuota_Bp),
+		),
+	).Methods("GET")
+
+	//router for the periods and the rooms of the timetable
+	subRouter.Handle(
+		"/periods",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.CreatePeriod_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/periods",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.GetPeriods_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/periods/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.DeletePeriod_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/rooms",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.CreateRoom_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/rooms",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.GetRooms_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/rooms/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.UpdateRoom_Bp),
+		),
+	).Methods("PATCH")
+
+	//router for the timetable solver, the pinned slots and the unavailable times
+	subRouter.Handle(
+		"/timetable/slots/{id}/pin",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.PinSlot_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/timetable/unavailability",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.CreateUnavailability_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/timetable/unavailability",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.GetUnavailability_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/timetable/unavailability/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.DeleteUnavailability_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/terms/{id}/timetable/generate",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(timetableService.GenerateTimetable_Bp),
+		),
+	).Methods("POST")
+
+	//router for the attendance of one lesson of the timetable
+	subRouter.Handle(
+		"/timetable/slots/{id}/attendance",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.SubmitLessonAttendance_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/timetable/slots/{id}/attendance",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.LessonAttendance_Bp),
+		),
+	).Methods("GET")
+
+	//router for the QR check in of the lesson
+	subRouter.Handle(
+		"/timetable/slots/{id}/checkin-code",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.IssueCheckInCode_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/timetable/slots/{id}/checkin-qr",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.CheckInQR_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/attendance/checkin",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.RedeemCheckIn_Bp),
+		),
+	).Methods("POST")
+
+	//router for the attendance summaries
+	subRouter.Handle(
+		"/students/{id}/attendance/summary",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.StudentSummary_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/classes/{id}/attendance/summary",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.ClassSummary_Bp),
+		),
+	).Methods("GET")
+
+	//router for the absence thresholds and the alerts
+	subRouter.Handle(
+		"/attendance/thresholds",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.CreateThreshold_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/attendance/thresholds",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.GetThresholds_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/attendance/thresholds/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.UpdateThreshold_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/attendance/alerts",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.GetAlerts_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/attendance/alerts/{id}/acknowledge",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(attendanceService.AcknowledgeAlert_Bp),
+		),
+	).Methods("POST")
+
+	//router for the subjects catalogue
+	subjectService := serviceSubject.NewHandlerSubject(subjectStore, academicStore)
+	subRouter.Handle(
+		"/subjects",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.CreateSubject_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/subjects",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.GetAllSubjects_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/subjects/curriculum",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.Curriculum_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/subjects/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.GetSubject_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/subjects/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.UpdateSubject_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/subjects/{id}/hours",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.SetSubjectHours_Bp),
+		),
+	).Methods("PUT")
+
+	//router for the teaching assignments of the guru
+	subRouter.Handle(
+		"/teaching-assignments",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.CreateAssignment_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/teaching-assignments",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.GetAssignments_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/teaching-assignments/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(subjectService.DeleteAssignment_Bp),
+		),
+	).Methods("DELETE")
+
+	//router for the calendar feeds and the events of the school calendar
+	calendarService := serviceCalendar.NewHandlerCalendar(serviceCalendar.NewCalendarStore(s.db), s.cfg)
+	subRouter.Handle(
+		"/calendar/token",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(calendarService.CreateFeedToken_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/calendar/token",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(calendarService.DeleteFeedToken_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/calendar/events",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(calendarService.CreateEvent_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/calendar/events",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(calendarService.GetEvents_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/calendar/events/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(calendarService.DeleteEvent_Bp),
+		),
+	).Methods("DELETE")
+	//the feed is public, the token of the url is the secret
+	subRouter.Handle(
+		"/calendar/feed/{token:[A-Za-z0-9_-]+}.ics",
+		http.HandlerFunc(
+			calendarService.Feed_Bp,
+		),
+	).Methods("GET")
+
+	//router for the gradebook, the assessments and the scores
+	gradeStore := serviceGrade.NewGradeStore(s.db)
+	gradeService := serviceGrade.NewHandlerGrade(gradeStore, academicStore, subjectStore, guardianStore, notifier, s.cfg)
+	subRouter.Handle(
+		"/classes/{id}/subjects/{subject_id}/gradebook",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.GetGradebook_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/classes/{id}/subjects/{subject_id}/gradebook/weights",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.SetWeights_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/classes/{id}/subjects/{subject_id}/gradebook/publish",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.Publish_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/classes/{id}/subjects/{subject_id}/gradebook/publish",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.Unpublish_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/classes/{id}/subjects/{subject_id}/assessments",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.CreateAssessment_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/assessments/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.DeleteAssessment_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/assessments/{id}/scores",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.SaveScores_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/grades/me",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.MyGrades_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/students/{id}/grades",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.StudentGrades_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/students/{id}/transcript",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.Transcript_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/classes/{id}/ranking",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.ClassRanking_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/grade-levels/{level:[0-9]+}/ranking",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.GradeLevelRanking_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/honor-roll",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.HonorRoll_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/grades/me/rank",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.MyRank_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/terms/{id}/ranking-visibility",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.SetRankingVisibility_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/assessments/{id}/remedials",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.CreateRemedial_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/assessments/{id}/remedials",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.GetRemedials_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/remedials/me",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.MyRemedials_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/remedials/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.DeleteRemedial_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/remedials/{id}/score",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.GradeRemedial_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/assessments/{id}/appeals",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.CreateAppeal_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/assessments/{id}/appeals",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.GetAppeals_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/appeals/me",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.MyAppeals_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/appeals/{id}/respond",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.RespondAppeal_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/students/{id}/grade-changes",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(gradeService.GradeChanges_Bp),
+		),
+	).Methods("GET")
+
+	//router for the report cards, the templates and the notes of the homeroom teacher
+	reportService := serviceReport.NewHandlerReport(serviceReport.NewReportStore(s.db), gradeStore, academicStore, s.files, s.cfg)
+	subRouter.Handle(
+		"/report-templates",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.CreateTemplate_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/report-templates",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.GetTemplates_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/report-templates/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.UpdateTemplate_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/report-templates/{id}/logo",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.UploadTemplateLogo_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/students/{id}/report-note",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.SaveNote_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/students/{id}/report-cards",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.GenerateReportCard_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/students/{id}/report-cards",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.GetReportCards_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/classes/{id}/report-cards",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.GenerateClassReportCards_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/report-cards/{id}/download",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(reportService.DownloadReportCard_Bp),
+		),
+	).Methods("GET")
+
+	//router for the homework and the submissions, the scores of the submissions flow into the gradebook
+	homeworkService := serviceHomework.NewHandlerHomework(serviceHomework.NewHomeworkStore(s.db), gradeStore, subjectStore, academicStore, notifier, s.files, s.cfg)
+	subRouter.Handle(
+		"/classes/{id}/subjects/{subject_id}/homework",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.CreateHomework_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/classes/{id}/homework",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.GetClassHomework_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/homework/me",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.MyHomework_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/homework/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.GetHomework_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/homework/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.DeleteHomework_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/homework/{id}/submissions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.Submit_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/homework/{id}/submissions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.GetSubmissions_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/submissions/{id}/grade",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.GradeSubmission_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/submission-files/{id}/download",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(homeworkService.DownloadFile_Bp),
+		),
+	).Methods("GET")
+
+	//router for the question banks and the quizzes, the attempts are timed on the server
+	quizService := serviceQuiz.NewHandlerQuiz(serviceQuiz.NewQuizStore(s.db), gradeStore, subjectStore, academicStore)
+	subRouter.Handle(
+		"/question-banks",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.CreateBank_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/question-banks",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GetBanks_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/question-banks/{id}/questions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.CreateQuestion_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/question-banks/{id}/questions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GetQuestions_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/questions/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.DeleteQuestion_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/classes/{id}/subjects/{subject_id}/quizzes",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.CreateQuiz_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/classes/{id}/quizzes",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GetClassQuizzes_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/quizzes/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GetQuiz_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/quizzes/{id}/attempts",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.StartAttempt_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/quizzes/{id}/attempts",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GetAttempts_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/quizzes/{id}/grading-queue",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GradingQueue_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/attempts/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GetAttempt_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/attempts/{id}/answers",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.SaveAnswers_Bp),
+		),
+	).Methods("PUT")
+	subRouter.Handle(
+		"/attempts/{id}/submit",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.SubmitAttempt_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/attempts/{id}/answers/{question_id}/grade",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(quizService.GradeAnswer_Bp),
+		),
+	).Methods("PUT")
+
+	//router for the exam sessions, the rooms, the proctors and the seat maps of the exam week
+	examService := serviceExam.NewHandlerExam(serviceExam.NewExamStore(s.db), gradeStore, academicStore)
+	subRouter.Handle(
+		"/exam-sessions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.CreateSession_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/exam-sessions",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.GetSessions_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/exam-sessions/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.GetSession_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/exam-sessions/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.DeleteSession_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/exam-sessions/{id}/rooms",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.AddRoom_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/exam-sessions/{id}/rooms/{room_id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.RemoveRoom_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/exam-sessions/{id}/rooms/{room_id}/seat-list",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.SeatList_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/exam-sessions/{id}/rooms/{room_id}/door-notice",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.DoorNotice_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/exam-sessions/{id}/proctors",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.AddProctor_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/exam-sessions/{id}/proctors/{teacher_id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.RemoveProctor_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/exam-sessions/{id}/seating",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.AllocateSeats_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/exam-sessions/{id}/seating",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.GetSeats_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/exams/me",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(examService.MyExams_Bp),
+		),
+	).Methods("GET")
+
+	//router for the guardians of the students, the orangtua read the attendance and the grades of their children
+	guardianService := serviceGuardian.NewHandlerGuardian(guardianStore)
+	subRouter.Handle(
+		"/guardians/me",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.MyGuardian_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/guardians",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.CreateGuardian_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/guardians",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.GetGuardians_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/guardians/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.GetGuardian_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/guardians/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.UpdateGuardian_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/guardians/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.DeleteGuardian_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/guardians/{id}/students",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.LinkStudent_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/guardians/{id}/students/{student_id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.UnlinkStudent_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/students/{id}/guardians",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(guardianService.StudentGuardians_Bp),
+		),
+	).Methods("GET")
+
+	//router for the announcements of the notice board, every list and detail honors the audience of the user
+	announcementService := serviceAnnouncement.NewHandlerAnnouncement(serviceAnnouncement.NewAnnouncementStore(s.db), subjectStore, s.files, s.cfg)
+	subRouter.Handle(
+		"/announcements",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.CreateAnnouncement_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/announcements",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.GetAnnouncements_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/announcements/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.GetAnnouncement_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/announcements/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.UpdateAnnouncement_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/announcements/{id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.DeleteAnnouncement_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/announcements/{id}/pin",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.PinAnnouncement_Bp),
+		),
+	).Methods("PATCH")
+	subRouter.Handle(
+		"/announcements/{id}/files",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.AddFiles_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/announcements/{id}/files/{file_id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.DownloadFile_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/announcements/{id}/files/{file_id}",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.DeleteFile_Bp),
+		),
+	).Methods("DELETE")
+	subRouter.Handle(
+		"/announcements/{id}/reads",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(announcementService.GetReads_Bp),
+		),
+	).Methods("GET")
+
+	//router for the notifications of the user, the services send their events through the notifier
+	notificationService := serviceNotification.NewHandlerNotification(notificationStore, notifier)
+	subRouter.Handle(
+		"/notifications",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(notificationService.GetNotifications_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/notifications/unread-count",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(notificationService.UnreadCount_Bp),
+		),
+	).Methods("GET")
+	subRouter.Handle(
+		"/notifications/read-all",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(notificationService.MarkAllRead_Bp),
+		),
+	).Methods("POST")
+	subRouter.Handle(
+		"/notifications/{id}/read",
+		middleware.TokenIdMiddleware(
+			http.HandlerFunc(notificationService.MarkRead_Bp),
+		),
+	).Methods("POST")
+
 	// Create HTTP server
 	s.server = &http.Server{
 		Addr:         s.Addr,
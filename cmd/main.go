@@ -15,6 +15,8 @@ import (
 	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
 	"github.com/ArkaniLoveCoding/Shcool-manajement/db"
 	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	serviceUser "github.com/ArkaniLoveCoding/Shcool-manajement/service/users"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/storage"
 )
 
 func main() {
@@ -27,6 +29,11 @@ func main() {
 
 	// Initialize configuration
 	cfg := config.ConfigInitialize()
+	if err := cfg.Validate(); err != nil {
+		logger.Log.Fatal("Invalid configuration",
+			zap.Error(err),
+		)
+	}
 
 	// Create database configuration from config
 	dbConfig := db.Config{
@@ -66,9 +73,37 @@ func main() {
 		}
 	}()
 
+	// Create the first admin when the database has no admin yet
+	seedCtx, seedCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	seeded, err := serviceUser.SeedAdmin(seedCtx, serviceUser.NewStore(database), cfg.AdminUsername, cfg.AdminEmail, cfg.AdminPassword)
+	seedCancel()
+	if err != nil {
+		logger.Log.Fatal("Failed to create the first admin",
+			zap.Error(err),
+		)
+	}
+	if seeded {
+		logger.Log.Info("First admin created",
+			zap.String("email", cfg.AdminEmail),
+		)
+	}
+
+	// Create the file storage (local disk or s3 compatible)
+	signer := storage.NewSigner(cfg.StorageSigningKey, cfg.StoragePublicURL)
+	files, err := storage.New(cfg, signer)
+	if err != nil {
+		logger.Log.Fatal("Failed to initialize the file storage",
+			zap.Error(err),
+		)
+	}
+
+	logger.Log.Info("File storage initialized",
+		zap.String("driver", cfg.StorageDriver),
+	)
+
 	// Create API server with database dependency
 	// Logger is already initialized at this point
-	server := api.ApiServerAddr(cfg.Port, database)
+	server := api.ApiServerAddr(cfg.Port, database, cfg, files, signer)
 
 	// Start server in a goroutine
 	go func() {
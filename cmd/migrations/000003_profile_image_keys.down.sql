@@ -0,0 +1,3 @@
+UPDATE public.users
+SET profile_image = 'uploads_user/' || profile_image
+WHERE profile_image <> '' AND profile_image !~ '^profiles/';
@@ -0,0 +1,5 @@
+-- profile_image now stores a storage key instead of a server-local path,
+-- the legacy files stay resolvable because the local storage root is uploads_user
+UPDATE public.users
+SET profile_image = regexp_replace(profile_image, '^(\./)?uploads_user[/\\]', '')
+WHERE profile_image ~ '^(\./)?uploads_user[/\\]';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.class_mappings;
+DROP INDEX IF EXISTS public.students_class_id_idx;
+ALTER TABLE public.students DROP COLUMN IF EXISTS class_id;
+DROP TABLE IF EXISTS public.classes;
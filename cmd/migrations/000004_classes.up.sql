@@ -0,0 +1,106 @@
+CREATE TABLE public.classes (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    name                VARCHAR(50) NOT NULL,
+    grade_level         INT NOT NULL CHECK (grade_level BETWEEN 1 AND 12),
+    major               VARCHAR(50) NOT NULL DEFAULT '',
+    section             VARCHAR(10) NOT NULL,
+    academic_year       VARCHAR(9) NOT NULL,
+    homeroom_teacher_id UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    UNIQUE (grade_level, major, section, academic_year)
+);
+
+ALTER TABLE public.students
+    ADD COLUMN class_id UUID NULL REFERENCES public.classes(id) ON DELETE SET NULL;
+
+CREATE INDEX students_class_id_idx ON public.students (class_id);
+
+-- the mapping report: one row per distinct free-text class found in students.class
+CREATE TABLE public.class_mappings (
+    raw_value       VARCHAR(50) PRIMARY KEY,
+    normalized      VARCHAR(50) NULL,
+    status          VARCHAR(20) NOT NULL,
+    reason          TEXT NOT NULL DEFAULT '',
+    student_count   INT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+-- same normalization as utils.ParseClassName: upper case, one space between the tokens
+-- and between letters and digits, then "<grade> <major> <section>" or "<grade> <section>"
+CREATE TEMP TABLE parsed_classes AS
+WITH normalized AS (
+    SELECT
+        s.id,
+        s.class AS raw_value,
+        s.created_at,
+        trim(regexp_replace(regexp_replace(regexp_replace(
+            upper(s.class), '[^A-Z0-9]+', ' ', 'g'),
+            '([0-9])([A-Z])', '\1 \2', 'g'),
+            '([A-Z])([0-9])', '\1 \2', 'g')) AS value
+    FROM public.students s
+), matched AS (
+    SELECT
+        n.*,
+        COALESCE(
+            regexp_match(n.value, '^([IVX]+|[0-9]{1,2}) ([A-Z]+) ([0-9]+)$'),
+            ARRAY[(regexp_match(n.value, '^([IVX]+|[0-9]{1,2}) ([A-Z])$'))[1], '', (regexp_match(n.value, '^([IVX]+|[0-9]{1,2}) ([A-Z])$'))[2]]
+        ) AS parts
+    FROM normalized n
+)
+SELECT
+    m.id,
+    m.raw_value,
+    CASE m.parts[1]
+        WHEN 'I' THEN 1 WHEN 'II' THEN 2 WHEN 'III' THEN 3 WHEN 'IV' THEN 4
+        WHEN 'V' THEN 5 WHEN 'VI' THEN 6 WHEN 'VII' THEN 7 WHEN 'VIII' THEN 8
+        WHEN 'IX' THEN 9 WHEN 'X' THEN 10 WHEN 'XI' THEN 11 WHEN 'XII' THEN 12
+        ELSE CASE WHEN m.parts[1] ~ '^[0-9]+$' THEN m.parts[1]::INT END
+    END AS grade_level,
+    m.parts[2] AS major,
+    m.parts[3] AS section,
+    CASE WHEN EXTRACT(MONTH FROM m.created_at) >= 7
+        THEN EXTRACT(YEAR FROM m.created_at)::INT || '/' || (EXTRACT(YEAR FROM m.created_at)::INT + 1)
+        ELSE (EXTRACT(YEAR FROM m.created_at)::INT - 1) || '/' || EXTRACT(YEAR FROM m.created_at)::INT
+    END AS academic_year
+FROM matched m;
+
+INSERT INTO public.classes (name, grade_level, major, section, academic_year, created_at, updated_at)
+SELECT DISTINCT
+    CASE p.grade_level
+        WHEN 1 THEN 'I' WHEN 2 THEN 'II' WHEN 3 THEN 'III' WHEN 4 THEN 'IV'
+        WHEN 5 THEN 'V' WHEN 6 THEN 'VI' WHEN 7 THEN 'VII' WHEN 8 THEN 'VIII'
+        WHEN 9 THEN 'IX' WHEN 10 THEN 'X' WHEN 11 THEN 'XI' WHEN 12 THEN 'XII'
+    END || CASE WHEN p.major = '' THEN '' ELSE ' ' || p.major END || ' ' || p.section,
+    p.grade_level, p.major, p.section, p.academic_year, NOW(), NOW()
+FROM parsed_classes p
+WHERE p.grade_level BETWEEN 1 AND 12 AND p.section IS NOT NULL
+ON CONFLICT (grade_level, major, section, academic_year) DO NOTHING;
+
+UPDATE public.students s
+SET class_id = c.id
+FROM parsed_classes p
+JOIN public.classes c
+    ON c.grade_level = p.grade_level
+    AND c.major = p.major
+    AND c.section = p.section
+    AND c.academic_year = p.academic_year
+WHERE s.id = p.id;
+
+-- the report: a raw value is mapped when every student of that value got a class
+INSERT INTO public.class_mappings (raw_value, normalized, status, reason, student_count, created_at, updated_at)
+SELECT
+    s.class,
+    MIN(c.name),
+    CASE WHEN COUNT(s.class_id) = COUNT(*) THEN 'mapped' ELSE 'ambiguous' END,
+    CASE WHEN COUNT(s.class_id) = COUNT(*) THEN ''
+        ELSE 'cannot read the grade, major and section of the value' END,
+    COUNT(*),
+    NOW(), NOW()
+FROM public.students s
+LEFT JOIN public.classes c ON c.id = s.class_id
+GROUP BY s.class;
+
+DROP TABLE parsed_classes;
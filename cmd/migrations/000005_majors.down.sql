@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public.major_waitlist;
+DROP INDEX IF EXISTS public.students_major_year_idx;
+ALTER TABLE public.students DROP COLUMN IF EXISTS major_id, DROP COLUMN IF EXISTS academic_year;
+DROP TABLE IF EXISTS public.major_capacities;
+DROP TABLE IF EXISTS public.majors;
@@ -0,0 +1,76 @@
+CREATE TABLE public.majors (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    code            VARCHAR(20) NOT NULL UNIQUE,
+    name            VARCHAR(100) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    is_active       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+-- a missing row means the major has no seat limit for that academic year
+CREATE TABLE public.major_capacities (
+    major_id        UUID NOT NULL REFERENCES public.majors(id) ON DELETE CASCADE,
+    academic_year   VARCHAR(9) NOT NULL,
+    seats           INT NOT NULL CHECK (seats >= 0),
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (major_id, academic_year)
+);
+
+ALTER TABLE public.students
+    ADD COLUMN major_id UUID NULL REFERENCES public.majors(id) ON DELETE SET NULL,
+    ADD COLUMN academic_year VARCHAR(9) NULL;
+
+CREATE INDEX students_major_year_idx ON public.students (major_id, academic_year);
+
+-- the registration is kept in the waitlist, so the student can be created when a seat is free
+CREATE TABLE public.major_waitlist (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    major_id        UUID NOT NULL REFERENCES public.majors(id) ON DELETE CASCADE,
+    academic_year   VARCHAR(9) NOT NULL,
+    requested_by    UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    name            VARCHAR(50) NOT NULL,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    address         TEXT NOT NULL,
+    student_profile TEXT NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'waiting',
+    student_id      UUID NULL REFERENCES public.students(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX major_waitlist_queue_idx ON public.major_waitlist (major_id, academic_year, status, created_at);
+
+-- the old free-text majors become the first entries of the catalogue. The text of the major is up to 255
+-- characters, so the longer text gets a short code with a number and the name keeps the text
+CREATE TEMP TABLE legacy_majors AS
+SELECT source, name,
+    CASE WHEN length(source) <= 20 THEN source
+    ELSE left(source, 14) || '-' || lpad((row_number() OVER (PARTITION BY length(source) <= 20 ORDER BY source))::text, 5, '0')
+    END AS code
+FROM (
+    SELECT DISTINCT ON (upper(trim(major))) upper(trim(major)) AS source, left(trim(major), 100) AS name
+    FROM public.students
+    WHERE trim(major) <> ''
+    ORDER BY upper(trim(major)), trim(major)
+) m;
+
+INSERT INTO public.majors (code, name, created_at, updated_at)
+SELECT code, name, NOW(), NOW()
+FROM legacy_majors;
+
+UPDATE public.students s
+SET major_id = m.id, major = m.code
+FROM legacy_majors l
+JOIN public.majors m ON m.code = l.code
+WHERE l.source = upper(trim(s.major));
+
+DROP TABLE legacy_majors;
+
+UPDATE public.students s
+SET academic_year = c.academic_year
+FROM public.classes c
+WHERE c.id = s.class_id;
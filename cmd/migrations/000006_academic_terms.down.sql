@@ -0,0 +1,4 @@
+ALTER TABLE public.classes DROP CONSTRAINT IF EXISTS classes_academic_year_fkey;
+DROP TABLE IF EXISTS public.class_enrollments;
+DROP TABLE IF EXISTS public.terms;
+DROP TABLE IF EXISTS public.academic_years;
@@ -0,0 +1,127 @@
+CREATE TABLE public.academic_years (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    name            VARCHAR(9) NOT NULL UNIQUE,
+    start_date      DATE NOT NULL,
+    end_date        DATE NOT NULL,
+    is_active       BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    CHECK (end_date > start_date)
+);
+
+-- only one academic year can be the active one
+CREATE UNIQUE INDEX academic_years_active_idx ON public.academic_years (is_active) WHERE is_active;
+
+CREATE TABLE public.terms (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    academic_year_id    UUID NOT NULL REFERENCES public.academic_years(id) ON DELETE CASCADE,
+    name                VARCHAR(50) NOT NULL,
+    semester            INT NOT NULL CHECK (semester BETWEEN 1 AND 2),
+    start_date          DATE NOT NULL,
+    end_date            DATE NOT NULL,
+    status              VARCHAR(20) NOT NULL DEFAULT 'planned',
+    opened_at           TIMESTAMP NULL,
+    closed_at           TIMESTAMP NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    UNIQUE (academic_year_id, semester),
+    CHECK (end_date > start_date),
+    CHECK (status IN ('planned', 'open', 'closed'))
+);
+
+-- only one term can be open (the active term)
+CREATE UNIQUE INDEX terms_open_idx ON public.terms (status) WHERE status = 'open';
+
+-- the class membership of a student in one term, so the history survives the year rollover
+CREATE TABLE public.class_enrollments (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (student_id, term_id)
+);
+
+CREATE INDEX class_enrollments_class_term_idx ON public.class_enrollments (class_id, term_id);
+
+-- the classes were only checked by the length of the year, so the year like 2024-2025 is written as
+-- 2024/2025 first. The row is kept when the same class or capacity already has the right year
+UPDATE public.classes c
+SET academic_year = regexp_replace(c.academic_year, '^([0-9]{4})[^0-9]([0-9]{4})$', '\1/\2')
+WHERE c.academic_year ~ '^[0-9]{4}[^0-9/][0-9]{4}$'
+AND NOT EXISTS (
+    SELECT 1 FROM public.classes o
+    WHERE o.grade_level = c.grade_level AND o.major = c.major AND o.section = c.section
+    AND o.academic_year = regexp_replace(c.academic_year, '^([0-9]{4})[^0-9]([0-9]{4})$', '\1/\2')
+);
+
+UPDATE public.major_capacities m
+SET academic_year = regexp_replace(m.academic_year, '^([0-9]{4})[^0-9]([0-9]{4})$', '\1/\2')
+WHERE m.academic_year ~ '^[0-9]{4}[^0-9/][0-9]{4}$'
+AND NOT EXISTS (
+    SELECT 1 FROM public.major_capacities o
+    WHERE o.major_id = m.major_id
+    AND o.academic_year = regexp_replace(m.academic_year, '^([0-9]{4})[^0-9]([0-9]{4})$', '\1/\2')
+);
+
+UPDATE public.students
+SET academic_year = regexp_replace(academic_year, '^([0-9]{4})[^0-9]([0-9]{4})$', '\1/\2')
+WHERE academic_year ~ '^[0-9]{4}[^0-9/][0-9]{4}$';
+
+UPDATE public.major_waitlist
+SET academic_year = regexp_replace(academic_year, '^([0-9]{4})[^0-9]([0-9]{4})$', '\1/\2')
+WHERE academic_year ~ '^[0-9]{4}[^0-9/][0-9]{4}$';
+
+-- the class with a year that cannot be read or with years that do not follow each other stops the migration with the list of the years, so the
+-- classes are fixed by hand before the academic years are made
+DO $$
+DECLARE
+    invalid TEXT;
+BEGIN
+    SELECT string_agg(DISTINCT academic_year, ', ') INTO invalid
+    FROM public.classes
+    WHERE CASE WHEN academic_year ~ '^[0-9]{4}/[0-9]{4}$'
+        THEN split_part(academic_year, '/', 2)::INT <> split_part(academic_year, '/', 1)::INT + 1
+        ELSE TRUE END;
+    IF invalid IS NOT NULL THEN
+        RAISE EXCEPTION 'The academic year of the classes must be like 2025/2026, fix these years first: %', invalid;
+    END IF;
+END $$;
+
+-- every academic year already used by the classes and the capacities becomes a row,
+-- with the two default semesters (july-december and january-june)
+INSERT INTO public.academic_years (name, start_date, end_date, created_at, updated_at)
+SELECT DISTINCT
+    y.name,
+    make_date(split_part(y.name, '/', 1)::INT, 7, 1),
+    make_date(split_part(y.name, '/', 2)::INT, 6, 30),
+    NOW(), NOW()
+FROM (
+    SELECT academic_year AS name FROM public.classes
+    UNION SELECT academic_year FROM public.major_capacities
+) y
+WHERE y.name ~ '^[0-9]{4}/[0-9]{4}$'
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO public.terms (academic_year_id, name, semester, start_date, end_date, created_at, updated_at)
+SELECT y.id, 'Semester Ganjil', 1, y.start_date, make_date(EXTRACT(YEAR FROM y.start_date)::INT, 12, 31), NOW(), NOW()
+FROM public.academic_years y
+UNION ALL
+SELECT y.id, 'Semester Genap', 2, make_date(EXTRACT(YEAR FROM y.end_date)::INT, 1, 1), y.end_date, NOW(), NOW()
+FROM public.academic_years y;
+
+-- the current class of the students is the membership of the first semester of the class year
+INSERT INTO public.class_enrollments (student_id, class_id, term_id, created_at, updated_at)
+SELECT s.id, s.class_id, t.id, NOW(), NOW()
+FROM public.students s
+JOIN public.classes c ON c.id = s.class_id
+JOIN public.academic_years y ON y.name = c.academic_year
+JOIN public.terms t ON t.academic_year_id = y.id AND t.semester = 1;
+
+ALTER TABLE public.classes
+    ADD CONSTRAINT classes_academic_year_fkey
+    FOREIGN KEY (academic_year) REFERENCES public.academic_years(name) ON UPDATE CASCADE;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS public.promotion_items;
+DROP TABLE IF EXISTS public.promotion_runs;
+DROP TABLE IF EXISTS public.student_retentions;
+
+ALTER TABLE public.students
+    DROP CONSTRAINT IF EXISTS students_status_check,
+    DROP COLUMN IF EXISTS graduated_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,51 @@
+ALTER TABLE public.students
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active',
+    ADD COLUMN graduated_at TIMESTAMP NULL,
+    ADD CONSTRAINT students_status_check CHECK (status IN ('active', 'alumni'));
+
+-- the students that stay in the same grade for the next academic year
+CREATE TABLE public.student_retentions (
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    academic_year   VARCHAR(9) NOT NULL REFERENCES public.academic_years(name) ON UPDATE CASCADE,
+    reason          TEXT NOT NULL,
+    created_by      UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (student_id, academic_year)
+);
+
+-- one committed promotion of an academic year, term_id is the term where it can be rolled back
+CREATE TABLE public.promotion_runs (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    from_year       VARCHAR(9) NOT NULL REFERENCES public.academic_years(name) ON UPDATE CASCADE,
+    to_year         VARCHAR(9) NOT NULL REFERENCES public.academic_years(name) ON UPDATE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id),
+    final_grade     INT NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'committed',
+    promoted        INT NOT NULL DEFAULT 0,
+    retained        INT NOT NULL DEFAULT 0,
+    graduated       INT NOT NULL DEFAULT 0,
+    created_by      UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    rolled_back_at  TIMESTAMP NULL,
+    CHECK (status IN ('committed', 'rolled_back'))
+);
+
+-- only one committed promotion for every academic year
+CREATE UNIQUE INDEX promotion_runs_committed_idx ON public.promotion_runs (from_year) WHERE status = 'committed';
+
+-- the previous class of every student is kept so the run can be rolled back
+CREATE TABLE public.promotion_items (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    run_id          UUID NOT NULL REFERENCES public.promotion_runs(id) ON DELETE CASCADE,
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    action          VARCHAR(20) NOT NULL,
+    from_class_id   UUID NOT NULL REFERENCES public.classes(id),
+    from_class      VARCHAR(50) NOT NULL,
+    to_class_id     UUID NULL REFERENCES public.classes(id) ON DELETE SET NULL,
+    to_class        VARCHAR(50) NOT NULL DEFAULT '',
+    create_class    BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (run_id, student_id),
+    CHECK (action IN ('promote', 'retain', 'graduate'))
+);
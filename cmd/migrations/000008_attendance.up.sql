@@ -0,0 +1,35 @@
+-- the daily attendance of a student, one row for every student and date
+CREATE TABLE public.attendance_records (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    date            DATE NOT NULL,
+    status          VARCHAR(10) NOT NULL,
+    note            TEXT NOT NULL DEFAULT '',
+    recorded_by     UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (student_id, date),
+    CHECK (status IN ('hadir', 'sakit', 'izin', 'alpa', 'late'))
+);
+
+CREATE INDEX attendance_records_class_date_idx ON public.attendance_records (class_id, date);
+CREATE INDEX attendance_records_term_idx ON public.attendance_records (term_id, student_id);
+
+-- every change of the record, the first row is the original entry (old_status is null)
+CREATE TABLE public.attendance_history (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    record_id       UUID NOT NULL REFERENCES public.attendance_records(id) ON DELETE CASCADE,
+    old_status      VARCHAR(10) NULL,
+    new_status      VARCHAR(10) NOT NULL,
+    old_note        TEXT NULL,
+    new_note        TEXT NOT NULL DEFAULT '',
+    reason          TEXT NOT NULL DEFAULT '',
+    changed_by      UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    changed_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX attendance_history_record_idx ON public.attendance_history (record_id, changed_at);
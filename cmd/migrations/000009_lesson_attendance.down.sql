@@ -0,0 +1,6 @@
+ALTER TABLE public.attendance_records
+    DROP CONSTRAINT IF EXISTS attendance_records_source_check,
+    DROP COLUMN IF EXISTS source;
+
+DROP TABLE IF EXISTS public.lesson_attendance;
+DROP TABLE IF EXISTS public.timetable_slots;
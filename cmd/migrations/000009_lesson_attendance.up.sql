@@ -0,0 +1,40 @@
+-- a weekly lesson of the class in one term, day_of_week is 1 (monday) to 7 (sunday)
+CREATE TABLE public.timetable_slots (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    day_of_week     INT NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
+    start_time      TIME NOT NULL,
+    end_time        TIME NOT NULL,
+    subject         VARCHAR(100) NOT NULL,
+    teacher_id      UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    CHECK (end_time > start_time)
+);
+
+CREATE INDEX timetable_slots_class_term_idx ON public.timetable_slots (class_id, term_id, day_of_week, start_time);
+
+-- the attendance of a student in one lesson
+CREATE TABLE public.lesson_attendance (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    slot_id         UUID NOT NULL REFERENCES public.timetable_slots(id) ON DELETE CASCADE,
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    date            DATE NOT NULL,
+    status          VARCHAR(10) NOT NULL,
+    note            TEXT NOT NULL DEFAULT '',
+    recorded_by     UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (slot_id, student_id, date),
+    CHECK (status IN ('hadir', 'sakit', 'izin', 'alpa', 'late'))
+);
+
+CREATE INDEX lesson_attendance_student_date_idx ON public.lesson_attendance (student_id, date);
+
+-- the daily status is recorded by hand or aggregated from the lessons
+ALTER TABLE public.attendance_records
+    ADD COLUMN source VARCHAR(10) NOT NULL DEFAULT 'manual',
+    ADD CONSTRAINT attendance_records_source_check CHECK (source IN ('manual', 'lesson'));
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.checkin_redemptions;
+
+ALTER TABLE public.students DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,25 @@
+-- the account of the student, so the student can check in with the own token
+ALTER TABLE public.students
+    ADD COLUMN user_id UUID NULL UNIQUE REFERENCES public.users(id) ON DELETE SET NULL;
+
+-- the requester of several promoted registrations only gets the student of the latest one
+UPDATE public.students s
+SET user_id = w.requested_by
+FROM (
+    SELECT DISTINCT ON (requested_by) requested_by, student_id
+    FROM public.major_waitlist
+    WHERE status = 'promoted' AND student_id IS NOT NULL
+    ORDER BY requested_by, updated_at DESC, created_at DESC
+) w
+WHERE w.student_id = s.id
+AND NOT EXISTS (SELECT 1 FROM public.students o WHERE o.user_id = w.requested_by);
+
+-- a code can be redeemed only once by every student
+CREATE TABLE public.checkin_redemptions (
+    nonce           VARCHAR(32) NOT NULL,
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    slot_id         UUID NOT NULL REFERENCES public.timetable_slots(id) ON DELETE CASCADE,
+    date            DATE NOT NULL,
+    redeemed_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (nonce, student_id)
+);
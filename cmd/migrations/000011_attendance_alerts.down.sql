@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.attendance_alerts;
+DROP TABLE IF EXISTS public.attendance_thresholds;
@@ -0,0 +1,38 @@
+-- a limit of absences, e.g. 3 alpa in a month; the status absent counts sakit, izin and alpa
+CREATE TABLE public.attendance_thresholds (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    name            VARCHAR(100) NOT NULL,
+    status          VARCHAR(10) NOT NULL,
+    count           INT NOT NULL CHECK (count > 0),
+    period          VARCHAR(10) NOT NULL,
+    is_active       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    CHECK (status IN ('sakit', 'izin', 'alpa', 'late', 'absent')),
+    CHECK (period IN ('week', 'month', 'term'))
+);
+
+INSERT INTO public.attendance_thresholds (name, status, count, period, created_at, updated_at)
+VALUES ('3 alpa in a month', 'alpa', 3, 'month', NOW(), NOW());
+
+-- one alert for every student, threshold and period
+CREATE TABLE public.attendance_alerts (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    threshold_id    UUID NOT NULL REFERENCES public.attendance_thresholds(id) ON DELETE CASCADE,
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    period_start    DATE NOT NULL,
+    period_end      DATE NOT NULL,
+    count           INT NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'open',
+    acknowledged_by UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    acknowledged_at TIMESTAMP NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (threshold_id, student_id, period_start),
+    CHECK (status IN ('open', 'acknowledged'))
+);
+
+CREATE INDEX attendance_alerts_class_idx ON public.attendance_alerts (class_id, status);
@@ -0,0 +1,6 @@
+ALTER TABLE public.timetable_slots
+    DROP COLUMN IF EXISTS subject_id;
+
+DROP TABLE IF EXISTS public.teaching_assignments;
+DROP TABLE IF EXISTS public.subject_hours;
+DROP TABLE IF EXISTS public.subjects;
@@ -0,0 +1,41 @@
+CREATE TABLE public.subjects (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    code            VARCHAR(20) NOT NULL UNIQUE,
+    name            VARCHAR(100) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    is_active       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+-- the weekly hours of the subject in one grade, an empty major means every major of the grade
+-- and a row of the major wins over the empty one
+CREATE TABLE public.subject_hours (
+    subject_id      UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    grade_level     INT NOT NULL CHECK (grade_level BETWEEN 1 AND 12),
+    major           VARCHAR(50) NOT NULL DEFAULT '',
+    hours_per_week  INT NOT NULL CHECK (hours_per_week > 0),
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (subject_id, grade_level, major)
+);
+
+-- the guru who teaches the subject in the class for one term
+CREATE TABLE public.teaching_assignments (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    teacher_id      UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    subject_id      UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (subject_id, class_id, term_id)
+);
+
+CREATE INDEX teaching_assignments_teacher_term_idx ON public.teaching_assignments (teacher_id, term_id);
+
+-- the lesson of the timetable can point to the subject of the catalogue
+ALTER TABLE public.timetable_slots
+    ADD COLUMN subject_id UUID NULL REFERENCES public.subjects(id) ON DELETE SET NULL;
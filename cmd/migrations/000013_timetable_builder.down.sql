@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS public.timetable_slots_room_idx;
+DROP INDEX IF EXISTS public.timetable_slots_teacher_idx;
+
+ALTER TABLE public.timetable_slots
+    DROP COLUMN IF EXISTS room_id,
+    DROP COLUMN IF EXISTS period_id;
+
+DROP TABLE IF EXISTS public.rooms;
+DROP TABLE IF EXISTS public.periods;
@@ -0,0 +1,31 @@
+-- the bell schedule of the school day, one period is one lesson hour
+CREATE TABLE public.periods (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    number          INT NOT NULL UNIQUE CHECK (number > 0),
+    name            VARCHAR(50) NOT NULL,
+    start_time      TIME NOT NULL,
+    end_time        TIME NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    CHECK (end_time > start_time)
+);
+
+CREATE TABLE public.rooms (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    code            VARCHAR(20) NOT NULL UNIQUE,
+    name            VARCHAR(100) NOT NULL,
+    capacity        INT NULL CHECK (capacity > 0),
+    is_active       BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+ALTER TABLE public.timetable_slots
+    ADD COLUMN period_id UUID NULL REFERENCES public.periods(id) ON DELETE SET NULL,
+    ADD COLUMN room_id UUID NULL REFERENCES public.rooms(id) ON DELETE SET NULL;
+
+-- the double booking of the teacher and the room is checked by the day of the term
+CREATE INDEX timetable_slots_teacher_idx ON public.timetable_slots (term_id, day_of_week, teacher_id);
+CREATE INDEX timetable_slots_room_idx ON public.timetable_slots (term_id, day_of_week, room_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.timetable_unavailability;
+
+ALTER TABLE public.timetable_slots
+    DROP COLUMN IF EXISTS is_pinned;
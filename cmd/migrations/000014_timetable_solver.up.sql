@@ -0,0 +1,20 @@
+-- a pinned slot is kept when the timetable is generated again
+ALTER TABLE public.timetable_slots
+    ADD COLUMN is_pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- the teacher or the room cannot be used in the day, a null period blocks the whole day
+CREATE TABLE public.timetable_unavailability (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    teacher_id      UUID NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    room_id         UUID NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+    day_of_week     INT NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
+    period_id       UUID NULL REFERENCES public.periods(id) ON DELETE CASCADE,
+    reason          VARCHAR(255) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    CHECK ((teacher_id IS NULL) <> (room_id IS NULL))
+);
+
+CREATE INDEX timetable_unavailability_teacher_idx ON public.timetable_unavailability (teacher_id);
+CREATE INDEX timetable_unavailability_room_idx ON public.timetable_unavailability (room_id);
@@ -0,0 +1,30 @@
+-- the events of the school calendar, an event without the time is an all day event and a holiday
+-- skips the weekly lessons in the calendar feeds
+CREATE TABLE public.calendar_events (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    title           VARCHAR(200) NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    start_date      DATE NOT NULL,
+    end_date        DATE NOT NULL,
+    start_time      TIME NULL,
+    end_time        TIME NULL,
+    is_holiday      BOOLEAN NOT NULL DEFAULT FALSE,
+    audience        VARCHAR(10) NOT NULL DEFAULT 'all',
+    created_by      UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    CHECK (end_date >= start_date),
+    CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CHECK (audience IN ('all', 'guru', 'siswa'))
+);
+
+CREATE INDEX calendar_events_dates_idx ON public.calendar_events (start_date, end_date);
+
+-- the secret of the calendar feed of the user, only the hash of the token is saved
+CREATE TABLE public.calendar_tokens (
+    user_id         UUID PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    token_hash      VARCHAR(64) NOT NULL UNIQUE,
+    created_at      TIMESTAMP NOT NULL,
+    last_used_at    TIMESTAMP NULL
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public.term_scores;
+DROP TABLE IF EXISTS public.gradebook_publications;
+DROP TABLE IF EXISTS public.assessment_scores;
+DROP TABLE IF EXISTS public.assessments;
+DROP TABLE IF EXISTS public.grade_weights;
@@ -0,0 +1,66 @@
+-- the weight of every category of the assessments in the gradebook of the subject in the class for one term,
+-- the gradebook without the weights uses the default weights
+CREATE TABLE public.grade_weights (
+    subject_id      UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    category        VARCHAR(20) NOT NULL,
+    weight          INT NOT NULL CHECK (weight BETWEEN 0 AND 100),
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (subject_id, class_id, term_id, category),
+    CHECK (category IN ('quiz', 'assignment', 'midterm', 'final'))
+);
+
+CREATE TABLE public.assessments (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    subject_id      UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    category        VARCHAR(20) NOT NULL,
+    title           VARCHAR(200) NOT NULL,
+    max_score       NUMERIC(6, 2) NOT NULL DEFAULT 100 CHECK (max_score > 0),
+    assessed_on     DATE NULL,
+    created_by      UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    CHECK (category IN ('quiz', 'assignment', 'midterm', 'final'))
+);
+
+CREATE INDEX assessments_gradebook_idx ON public.assessments (class_id, subject_id, term_id);
+
+CREATE TABLE public.assessment_scores (
+    assessment_id   UUID NOT NULL REFERENCES public.assessments(id) ON DELETE CASCADE,
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    score           NUMERIC(6, 2) NOT NULL CHECK (score >= 0),
+    graded_by       UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (assessment_id, student_id)
+);
+
+CREATE INDEX assessment_scores_student_idx ON public.assessment_scores (student_id);
+
+-- the published gradebook is locked for the changes and the siswa can see the scores
+CREATE TABLE public.gradebook_publications (
+    subject_id      UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    published_by    UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    published_at    TIMESTAMP NOT NULL,
+    PRIMARY KEY (subject_id, class_id, term_id)
+);
+
+-- the term scores of the students saved when the gradebook is published
+CREATE TABLE public.term_scores (
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    subject_id      UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    class_id        UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    score           NUMERIC(5, 2) NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (student_id, subject_id, term_id)
+);
+
+CREATE INDEX term_scores_class_term_idx ON public.term_scores (class_id, term_id);
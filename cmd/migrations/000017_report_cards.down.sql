@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.report_cards;
+DROP TABLE IF EXISTS public.report_notes;
+DROP TABLE IF EXISTS public.report_templates;
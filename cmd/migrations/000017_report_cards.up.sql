@@ -0,0 +1,50 @@
+-- the letterhead and the signatures of the printed report cards, the default template is used when
+-- the template is not chosen
+CREATE TABLE public.report_templates (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    name            VARCHAR(100) NOT NULL UNIQUE,
+    title           VARCHAR(200) NOT NULL DEFAULT 'LAPORAN HASIL BELAJAR',
+    school_name     VARCHAR(200) NOT NULL,
+    address         TEXT NOT NULL DEFAULT '',
+    contact         TEXT NOT NULL DEFAULT '',
+    city            VARCHAR(100) NOT NULL DEFAULT '',
+    principal_name  VARCHAR(100) NOT NULL DEFAULT '',
+    principal_nip   VARCHAR(50) NOT NULL DEFAULT '',
+    footer_text     TEXT NOT NULL DEFAULT '',
+    logo_key        TEXT NULL,
+    is_default      BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+-- only one template can be the default one
+CREATE UNIQUE INDEX report_templates_default_idx ON public.report_templates (is_default) WHERE is_default;
+
+-- the note of the homeroom teacher for the student in one term
+CREATE TABLE public.report_notes (
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    note            TEXT NOT NULL,
+    written_by      UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (student_id, term_id)
+);
+
+-- every generated report card, a reissue is a new version with the reason and the old files are kept
+CREATE TABLE public.report_cards (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    term_id         UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    version         INT NOT NULL CHECK (version > 0),
+    template_id     UUID NULL REFERENCES public.report_templates(id) ON DELETE SET NULL,
+    file_key        TEXT NOT NULL,
+    checksum        VARCHAR(64) NOT NULL,
+    size            BIGINT NOT NULL,
+    reason          TEXT NOT NULL DEFAULT '',
+    generated_by    UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL,
+    UNIQUE (student_id, term_id, version)
+);
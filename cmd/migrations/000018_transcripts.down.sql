@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS public.term_scores_student_idx;
+
+ALTER TABLE public.subjects
+    DROP COLUMN IF EXISTS kkm;
@@ -0,0 +1,5 @@
+-- the minimum passing score (kkm) of the subject, the subject without the kkm uses the default kkm
+ALTER TABLE public.subjects
+    ADD COLUMN kkm NUMERIC(5, 2) NULL CHECK (kkm BETWEEN 0 AND 100);
+
+CREATE INDEX term_scores_student_idx ON public.term_scores (student_id, term_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS public.term_scores_term_class_idx;
+DROP TABLE IF EXISTS public.ranking_settings;
@@ -0,0 +1,10 @@
+-- the ranks of the term are hidden from the siswa until the admin shows them
+CREATE TABLE public.ranking_settings (
+    term_id                 UUID PRIMARY KEY REFERENCES public.terms(id) ON DELETE CASCADE,
+    visible_to_students     BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by              UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at              TIMESTAMP NOT NULL,
+    updated_at              TIMESTAMP NOT NULL
+);
+
+CREATE INDEX term_scores_term_class_idx ON public.term_scores (term_id, class_id);
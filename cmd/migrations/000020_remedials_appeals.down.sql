@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.grade_changes;
+DROP TABLE IF EXISTS public.grade_appeals;
+DROP TABLE IF EXISTS public.remedials;
@@ -0,0 +1,65 @@
+-- the remedial work of the student that failed the assessment, the remedial score is capped so the
+-- final score of the assessment cannot be more than the cap score
+CREATE TABLE public.remedials (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    assessment_id       UUID NOT NULL REFERENCES public.assessments(id) ON DELETE CASCADE,
+    student_id          UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    title               VARCHAR(200) NOT NULL,
+    instructions        TEXT NOT NULL DEFAULT '',
+    due_on              DATE NULL,
+    cap_score           NUMERIC(6, 2) NOT NULL CHECK (cap_score >= 0),
+    original_score      NUMERIC(6, 2) NOT NULL,
+    remedial_score      NUMERIC(6, 2) NULL,
+    final_score         NUMERIC(6, 2) NULL,
+    status              VARCHAR(20) NOT NULL DEFAULT 'assigned',
+    assigned_by         UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    graded_by           UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    graded_at           TIMESTAMP NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    UNIQUE (assessment_id, student_id),
+    CHECK (status IN ('assigned', 'graded'))
+);
+
+CREATE INDEX remedials_student_idx ON public.remedials (student_id);
+
+CREATE TABLE public.grade_appeals (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    assessment_id       UUID NOT NULL REFERENCES public.assessments(id) ON DELETE CASCADE,
+    student_id          UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    submitted_by        UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    reason              TEXT NOT NULL,
+    status              VARCHAR(20) NOT NULL DEFAULT 'pending',
+    current_score       NUMERIC(6, 2) NOT NULL,
+    new_score           NUMERIC(6, 2) NULL,
+    response            TEXT NOT NULL DEFAULT '',
+    responded_by        UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    responded_at        TIMESTAMP NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    CHECK (status IN ('pending', 'accepted', 'rejected'))
+);
+
+-- only one pending appeal for the score of the student
+CREATE UNIQUE INDEX grade_appeals_pending_idx ON public.grade_appeals (assessment_id, student_id) WHERE status = 'pending';
+CREATE INDEX grade_appeals_student_idx ON public.grade_appeals (student_id);
+
+-- every change of the score after the remedial or the appeal with the reason
+CREATE TABLE public.grade_changes (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    assessment_id       UUID NOT NULL REFERENCES public.assessments(id) ON DELETE CASCADE,
+    student_id          UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    old_score           NUMERIC(6, 2) NULL,
+    new_score           NUMERIC(6, 2) NOT NULL,
+    source              VARCHAR(20) NOT NULL,
+    source_id           UUID NOT NULL,
+    reason              TEXT NOT NULL,
+    changed_by          UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP NOT NULL,
+    CHECK (source IN ('remedial', 'appeal'))
+);
+
+CREATE INDEX grade_changes_student_idx ON public.grade_changes (student_id, created_at);
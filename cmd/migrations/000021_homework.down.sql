@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.homework_files;
+DROP TABLE IF EXISTS public.homework_submissions;
+DROP TABLE IF EXISTS public.homework;
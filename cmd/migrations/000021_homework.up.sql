@@ -0,0 +1,50 @@
+-- the homework of the subject in the class, every homework is an assignment assessment of the gradebook
+-- so the score of the submission flows into the gradebook
+CREATE TABLE public.homework (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    assessment_id       UUID NOT NULL UNIQUE REFERENCES public.assessments(id) ON DELETE CASCADE,
+    subject_id          UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    class_id            UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id             UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    title               VARCHAR(200) NOT NULL,
+    description         TEXT NOT NULL DEFAULT '',
+    due_at              TIMESTAMP NOT NULL,
+    allow_late          BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by          UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX homework_class_term_idx ON public.homework (class_id, term_id, due_at);
+
+-- the submission of the student, the submission can be sent again until it is graded
+CREATE TABLE public.homework_submissions (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    homework_id         UUID NOT NULL REFERENCES public.homework(id) ON DELETE CASCADE,
+    student_id          UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    body                TEXT NOT NULL DEFAULT '',
+    submitted_at        TIMESTAMP NOT NULL,
+    is_late             BOOLEAN NOT NULL DEFAULT FALSE,
+    score               NUMERIC(6, 2) NULL,
+    feedback            TEXT NOT NULL DEFAULT '',
+    graded_by           UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    graded_at           TIMESTAMP NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    UNIQUE (homework_id, student_id)
+);
+
+CREATE TABLE public.homework_files (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    submission_id       UUID NOT NULL REFERENCES public.homework_submissions(id) ON DELETE CASCADE,
+    file_key            VARCHAR(255) NOT NULL,
+    file_name           VARCHAR(255) NOT NULL,
+    content_type        VARCHAR(100) NOT NULL,
+    size                BIGINT NOT NULL,
+    created_at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX homework_files_submission_idx ON public.homework_files (submission_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS public.attempt_answers;
+DROP TABLE IF EXISTS public.quiz_attempts;
+DROP TABLE IF EXISTS public.quiz_questions;
+DROP TABLE IF EXISTS public.quizzes;
+DROP TABLE IF EXISTS public.question_options;
+DROP TABLE IF EXISTS public.questions;
+DROP TABLE IF EXISTS public.question_banks;
@@ -0,0 +1,105 @@
+-- the question bank of the subject
+CREATE TABLE public.question_banks (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    subject_id          UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    name                VARCHAR(100) NOT NULL,
+    description         TEXT NOT NULL DEFAULT '',
+    created_by          UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX question_banks_subject_idx ON public.question_banks (subject_id);
+
+CREATE TABLE public.questions (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    bank_id             UUID NOT NULL REFERENCES public.question_banks(id) ON DELETE CASCADE,
+    type                VARCHAR(20) NOT NULL,
+    prompt              TEXT NOT NULL,
+    points              NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (points > 0),
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    CHECK (type IN ('multiple_choice', 'multi_select', 'short_answer', 'essay'))
+);
+
+CREATE INDEX questions_bank_idx ON public.questions (bank_id);
+
+-- the options of the choice questions, the options of the short answer question are the accepted answers
+CREATE TABLE public.question_options (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    question_id         UUID NOT NULL REFERENCES public.questions(id) ON DELETE CASCADE,
+    text                TEXT NOT NULL,
+    is_correct          BOOLEAN NOT NULL DEFAULT FALSE,
+    position            INT NOT NULL
+);
+
+CREATE INDEX question_options_question_idx ON public.question_options (question_id, position);
+
+CREATE TABLE public.quizzes (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    subject_id          UUID NOT NULL REFERENCES public.subjects(id) ON DELETE CASCADE,
+    class_id            UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    term_id             UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    title               VARCHAR(200) NOT NULL,
+    time_limit_minutes  INT NOT NULL CHECK (time_limit_minutes > 0),
+    opens_at            TIMESTAMP NOT NULL,
+    closes_at           TIMESTAMP NOT NULL,
+    shuffle_questions   BOOLEAN NOT NULL DEFAULT TRUE,
+    shuffle_options     BOOLEAN NOT NULL DEFAULT TRUE,
+    max_attempts        INT NOT NULL DEFAULT 1 CHECK (max_attempts > 0),
+    created_by          UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX quizzes_class_term_idx ON public.quizzes (class_id, term_id, opens_at);
+
+-- the questions of the quiz are kept when the question is deleted from the bank later
+CREATE TABLE public.quiz_questions (
+    quiz_id             UUID NOT NULL REFERENCES public.quizzes(id) ON DELETE CASCADE,
+    question_id         UUID NOT NULL REFERENCES public.questions(id) ON DELETE RESTRICT,
+    position            INT NOT NULL,
+    PRIMARY KEY (quiz_id, question_id)
+);
+
+-- the attempt of the student, the order of the questions and the options comes from the seed
+CREATE TABLE public.quiz_attempts (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    quiz_id             UUID NOT NULL REFERENCES public.quizzes(id) ON DELETE CASCADE,
+    student_id          UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    attempt_no          INT NOT NULL,
+    seed                BIGINT NOT NULL,
+    started_at          TIMESTAMP NOT NULL,
+    deadline_at         TIMESTAMP NOT NULL,
+    submitted_at        TIMESTAMP NULL,
+    status              VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    score               NUMERIC(8, 2) NULL,
+    max_score           NUMERIC(8, 2) NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    UNIQUE (quiz_id, student_id, attempt_no),
+    CHECK (status IN ('in_progress', 'submitted', 'graded'))
+);
+
+-- only one attempt in progress for the student
+CREATE UNIQUE INDEX quiz_attempts_progress_idx ON public.quiz_attempts (quiz_id, student_id) WHERE status = 'in_progress';
+
+CREATE TABLE public.attempt_answers (
+    attempt_id          UUID NOT NULL REFERENCES public.quiz_attempts(id) ON DELETE CASCADE,
+    question_id         UUID NOT NULL REFERENCES public.questions(id) ON DELETE CASCADE,
+    selected_options    UUID[] NOT NULL DEFAULT '{}',
+    text_answer         TEXT NOT NULL DEFAULT '',
+    auto_score          NUMERIC(6, 2) NULL,
+    manual_score        NUMERIC(6, 2) NULL,
+    feedback            TEXT NOT NULL DEFAULT '',
+    graded_by           UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    graded_at           TIMESTAMP NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    PRIMARY KEY (attempt_id, question_id)
+);
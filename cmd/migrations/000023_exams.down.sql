@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public.exam_seats;
+DROP TABLE IF EXISTS public.exam_proctors;
+DROP TABLE IF EXISTS public.exam_rooms;
+DROP TABLE IF EXISTS public.exam_session_classes;
+DROP TABLE IF EXISTS public.exam_sessions;
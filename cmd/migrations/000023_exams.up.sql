@@ -0,0 +1,66 @@
+-- the exam session is one exam of the exam week in one time block, the classes of the session sit the
+-- exam together and the students of the classes are mixed in the rooms
+CREATE TABLE public.exam_sessions (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    term_id             UUID NOT NULL REFERENCES public.terms(id) ON DELETE CASCADE,
+    kind                VARCHAR(10) NOT NULL,
+    title               VARCHAR(200) NOT NULL,
+    subject_id          UUID NULL REFERENCES public.subjects(id) ON DELETE SET NULL,
+    exam_date           DATE NOT NULL,
+    start_time          TIME NOT NULL,
+    end_time            TIME NOT NULL,
+    created_by          UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    CHECK (end_time > start_time),
+    CHECK (kind IN ('midterm', 'final'))
+);
+
+CREATE INDEX exam_sessions_term_date_idx ON public.exam_sessions (term_id, exam_date, start_time);
+CREATE INDEX exam_sessions_date_idx ON public.exam_sessions (exam_date);
+
+CREATE TABLE public.exam_session_classes (
+    session_id          UUID NOT NULL REFERENCES public.exam_sessions(id) ON DELETE CASCADE,
+    class_id            UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    PRIMARY KEY (session_id, class_id)
+);
+
+-- the rooms of the session in the order of the seating, the seats are the capacity of the room unless
+-- it is set for the exam
+CREATE TABLE public.exam_rooms (
+    session_id          UUID NOT NULL REFERENCES public.exam_sessions(id) ON DELETE CASCADE,
+    room_id             UUID NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+    seats               INT NOT NULL CHECK (seats > 0),
+    position            INT NOT NULL,
+    PRIMARY KEY (session_id, room_id)
+);
+
+CREATE INDEX exam_rooms_room_idx ON public.exam_rooms (room_id);
+
+-- the proctor watches one room of the session
+CREATE TABLE public.exam_proctors (
+    session_id          UUID NOT NULL,
+    room_id             UUID NOT NULL,
+    teacher_id          UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    created_at          TIMESTAMP NOT NULL,
+    PRIMARY KEY (session_id, teacher_id),
+    FOREIGN KEY (session_id, room_id) REFERENCES public.exam_rooms(session_id, room_id) ON DELETE CASCADE
+);
+
+CREATE INDEX exam_proctors_teacher_idx ON public.exam_proctors (teacher_id);
+
+-- the seat of the student, the class is the class of the student when the seats were allocated
+CREATE TABLE public.exam_seats (
+    session_id          UUID NOT NULL,
+    room_id             UUID NOT NULL,
+    seat_no             INT NOT NULL CHECK (seat_no > 0),
+    student_id          UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    class_id            UUID NOT NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    created_at          TIMESTAMP NOT NULL,
+    PRIMARY KEY (session_id, student_id),
+    UNIQUE (session_id, room_id, seat_no),
+    FOREIGN KEY (session_id, room_id) REFERENCES public.exam_rooms(session_id, room_id) ON DELETE CASCADE
+);
+
+CREATE INDEX exam_seats_student_idx ON public.exam_seats (student_id);
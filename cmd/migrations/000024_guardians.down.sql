@@ -0,0 +1,7 @@
+DELETE FROM public.calendar_events WHERE audience = 'orangtua';
+ALTER TABLE public.calendar_events
+    DROP CONSTRAINT IF EXISTS calendar_events_audience_check,
+    ADD CONSTRAINT calendar_events_audience_check CHECK (audience IN ('all', 'guru', 'siswa'));
+
+DROP TABLE IF EXISTS public.guardian_students;
+DROP TABLE IF EXISTS public.guardians;
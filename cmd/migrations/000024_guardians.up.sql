@@ -0,0 +1,31 @@
+-- the parent or the guardian of the students, the guardian logs in with the user of the role orangtua
+CREATE TABLE public.guardians (
+    id              UUID PRIMARY KEY DEFAULT
+                    gen_random_uuid(),
+    user_id         UUID NULL UNIQUE REFERENCES public.users(id) ON DELETE SET NULL,
+    full_name       VARCHAR(100) NOT NULL,
+    phone           VARCHAR(30) NOT NULL DEFAULT '',
+    email           VARCHAR(255) NOT NULL DEFAULT '',
+    address         TEXT NOT NULL DEFAULT '',
+    occupation      VARCHAR(100) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+
+-- the students of the guardian, one student can have many guardians and one guardian many students
+CREATE TABLE public.guardian_students (
+    guardian_id     UUID NOT NULL REFERENCES public.guardians(id) ON DELETE CASCADE,
+    student_id      UUID NOT NULL REFERENCES public.students(id) ON DELETE CASCADE,
+    relationship    VARCHAR(10) NOT NULL,
+    is_primary      BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (guardian_id, student_id),
+    CHECK (relationship IN ('ayah', 'ibu', 'wali'))
+);
+
+CREATE INDEX guardian_students_student_idx ON public.guardian_students (student_id);
+
+-- the calendar events for the parents
+ALTER TABLE public.calendar_events
+    DROP CONSTRAINT IF EXISTS calendar_events_audience_check,
+    ADD CONSTRAINT calendar_events_audience_check CHECK (audience IN ('all', 'guru', 'siswa', 'orangtua'));
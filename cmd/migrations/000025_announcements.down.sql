@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.announcement_reads;
+DROP TABLE IF EXISTS public.announcement_files;
+DROP TABLE IF EXISTS public.announcements;
@@ -0,0 +1,48 @@
+-- the announcements of the school, the target decides the audience: the whole school, one grade level,
+-- one class, one role or one user
+CREATE TABLE public.announcements (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    title               VARCHAR(200) NOT NULL,
+    body                TEXT NOT NULL,
+    target              VARCHAR(10) NOT NULL,
+    grade_level         INT NULL CHECK (grade_level BETWEEN 1 AND 12),
+    class_id            UUID NULL REFERENCES public.classes(id) ON DELETE CASCADE,
+    target_role         VARCHAR(20) NULL,
+    user_id             UUID NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    publish_at          TIMESTAMP NOT NULL,
+    expires_at          TIMESTAMP NULL,
+    is_pinned           BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by          UUID NULL REFERENCES public.users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    CHECK (target IN ('school', 'grade', 'class', 'role', 'user')),
+    CHECK ((grade_level IS NOT NULL) = (target = 'grade')),
+    CHECK ((class_id IS NOT NULL) = (target = 'class')),
+    CHECK ((target_role IS NOT NULL) = (target = 'role')),
+    CHECK ((user_id IS NOT NULL) = (target = 'user')),
+    CHECK (expires_at IS NULL OR expires_at > publish_at)
+);
+
+CREATE INDEX announcements_publish_idx ON public.announcements (publish_at);
+
+CREATE TABLE public.announcement_files (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    announcement_id     UUID NOT NULL REFERENCES public.announcements(id) ON DELETE CASCADE,
+    file_key            VARCHAR(255) NOT NULL,
+    file_name           VARCHAR(255) NOT NULL,
+    content_type        VARCHAR(100) NOT NULL,
+    size                BIGINT NOT NULL,
+    created_at          TIMESTAMP NOT NULL
+);
+
+CREATE INDEX announcement_files_announcement_idx ON public.announcement_files (announcement_id);
+
+-- the read receipt of the user, the first read is kept
+CREATE TABLE public.announcement_reads (
+    announcement_id     UUID NOT NULL REFERENCES public.announcements(id) ON DELETE CASCADE,
+    user_id             UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    read_at             TIMESTAMP NOT NULL,
+    PRIMARY KEY (announcement_id, user_id)
+);
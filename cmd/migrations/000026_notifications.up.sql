@@ -0,0 +1,18 @@
+-- the notifications of the user, the message is formatted from the event and the payload keeps the data
+-- of the event for the client. The dedupe key makes one notification of the same thing for the user
+CREATE TABLE public.notifications (
+    id                  UUID PRIMARY KEY DEFAULT
+                        gen_random_uuid(),
+    user_id             UUID NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    type                VARCHAR(40) NOT NULL,
+    title               VARCHAR(200) NOT NULL,
+    body                TEXT NOT NULL,
+    payload             JSONB NOT NULL DEFAULT '{}',
+    dedupe_key          VARCHAR(200) NULL,
+    read_at             TIMESTAMP NULL,
+    created_at          TIMESTAMP NOT NULL,
+    UNIQUE (user_id, dedupe_key)
+);
+
+CREATE INDEX notifications_user_idx ON public.notifications (user_id, created_at DESC);
+CREATE INDEX notifications_unread_idx ON public.notifications (user_id) WHERE read_at IS NULL;
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
 	"time"
@@ -22,9 +23,37 @@ type ConfigParams struct {
 	PostgresConnMaxLifetime time.Duration
 	PostgresConnMaxIdleTime time.Duration
 	PostgresSSLMode         string
+	// First admin settings
+	AdminUsername string
+	AdminEmail    string
+	AdminPassword string
 	// Retry settings
 	DbMaxRetries  int
 	DbRetryDelay  time.Duration
+	// File storage settings
+	StorageDriver     string
+	StorageLocalRoot  string
+	StoragePublicURL  string
+	StorageSigningKey string
+	StorageURLTTL     time.Duration
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKey       string
+	S3SecretKey       string
+	// Attendance settings
+	LessonAttendanceLock time.Duration
+	CheckinSigningKey    string
+	CheckinCodeTTL       time.Duration
+	CheckinLateAfter     time.Duration
+	// Calendar settings
+	SchoolTimezone  string
+	CalendarFeedURL string
+	// Grading settings
+	GradeRounding string
+	GradeDecimals int
+	DefaultKKM    int
+	HonorRollMin  int
 }
 
 func ConfigInitialize() ConfigParams {
@@ -45,13 +74,60 @@ func ConfigInitialize() ConfigParams {
 		PostgresConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 		PostgresConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
 		PostgresSSLMode:         KeyEnvLookUp("DB_SSL_MODE", "disable"),
+		// First admin settings
+		AdminUsername: KeyEnvLookUp("ADMIN_USERNAME", "admin"),
+		AdminEmail:    KeyEnvLookUp("ADMIN_EMAIL", ""),
+		AdminPassword: KeyEnvLookUp("ADMIN_PASSWORD", ""),
 		// Retry settings
 		DbMaxRetries: getEnvInt("DB_MAX_RETRIES", 3),
 		DbRetryDelay: getEnvDuration("DB_RETRY_DELAY", 5*time.Second),
+		// File storage settings
+		StorageDriver:     KeyEnvLookUp("STORAGE_DRIVER", "local"),
+		StorageLocalRoot:  KeyEnvLookUp("STORAGE_LOCAL_ROOT", "uploads_user"),
+		StoragePublicURL:  KeyEnvLookUp("STORAGE_PUBLIC_URL", "http://localhost:8080/api/v1/files"),
+		StorageSigningKey: KeyEnvLookUp("STORAGE_SIGNING_KEY", ""),
+		StorageURLTTL:     getEnvDuration("STORAGE_URL_TTL", 15*time.Minute),
+		S3Endpoint:        KeyEnvLookUp("S3_ENDPOINT", "http://localhost:9000"),
+		S3Region:          KeyEnvLookUp("S3_REGION", "us-east-1"),
+		S3Bucket:          KeyEnvLookUp("S3_BUCKET", "school-manajement"),
+		S3AccessKey:       KeyEnvLookUp("S3_ACCESS_KEY", ""),
+		S3SecretKey:       KeyEnvLookUp("S3_SECRET_KEY", ""),
+		// Attendance settings
+		LessonAttendanceLock: getEnvDuration("LESSON_ATTENDANCE_LOCK", 24*time.Hour),
+		CheckinSigningKey:    KeyEnvLookUp("CHECKIN_SIGNING_KEY", ""),
+		CheckinCodeTTL:       getEnvDuration("CHECKIN_CODE_TTL", 30*time.Second),
+		CheckinLateAfter:     getEnvDuration("CHECKIN_LATE_AFTER", 15*time.Minute),
+		// Calendar settings
+		SchoolTimezone:  KeyEnvLookUp("SCHOOL_TIMEZONE", "Asia/Jakarta"),
+		CalendarFeedURL: KeyEnvLookUp("CALENDAR_FEED_URL", "http://localhost:8080/api/v1/calendar/feed"),
+		// Grading settings
+		GradeRounding: KeyEnvLookUp("GRADE_ROUNDING", "half_up"),
+		GradeDecimals: getEnvInt("GRADE_DECIMALS", 0),
+		DefaultKKM:    getEnvInt("DEFAULT_KKM", 75),
+		HonorRollMin:  getEnvInt("HONOR_ROLL_MIN_AVERAGE", 85),
 	}
 
 }
 
+// Validate checks the secrets of the config before the server starts, every signing key is required and is
+// its own secret, not the secret of the jwt
+func (c ConfigParams) Validate() error {
+	jwtSecret := os.Getenv("JWT_SECRET_KEY")
+	if c.StorageSigningKey == "" {
+		return errors.New("STORAGE_SIGNING_KEY is required")
+	}
+	if c.StorageSigningKey == jwtSecret {
+		return errors.New("STORAGE_SIGNING_KEY must not be the JWT_SECRET_KEY")
+	}
+	if c.CheckinSigningKey == "" {
+		return errors.New("CHECKIN_SIGNING_KEY is required")
+	}
+	if c.CheckinSigningKey == jwtSecret || c.CheckinSigningKey == c.StorageSigningKey {
+		return errors.New("CHECKIN_SIGNING_KEY must not be the JWT_SECRET_KEY or the STORAGE_SIGNING_KEY")
+	}
+	return nil
+}
+
 func KeyEnvLookUp(key string, fallback string) string {
 
 	if value, ok := os.LookupEnv(key); ok {
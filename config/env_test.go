@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestValidateSigningKeys(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "jwt-secret")
+
+	cfg := ConfigParams{StorageSigningKey: "storage-secret", CheckinSigningKey: "checkin-secret"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	cfg.StorageSigningKey = ""
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("the empty storage signing key should fail")
+	}
+	cfg.StorageSigningKey = "jwt-secret"
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("the storage signing key should not be the jwt secret")
+	}
+
+	cfg.StorageSigningKey = "storage-secret"
+
+	for _, key := range []string{"", "jwt-secret", "storage-secret"} {
+		cfg.CheckinSigningKey = key
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("the checkin signing key %q should fail", key)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Package dbtest is a scripted database for the tests of the stores. The test tells which rows every query
+// returns and checks the statements that the store executed, in the order and with the transactions.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// the statements of the transaction in the log
+const (
+	Begin 		= "BEGIN"
+	Commit 		= "COMMIT"
+	Rollback 	= "ROLLBACK"
+)
+
+// Result is the answer of one query, the values are the values of the driver (string for the uuid,
+// int64, float64, bool, time.Time, []byte or nil)
+type Result struct {
+	Columns 	[]string
+	Rows 		[][]driver.Value
+	Affected 	int64
+	Err 		error
+}
+
+// Rows is the result with the rows of the columns
+func Rows(columns []string, rows ...[]driver.Value) Result {
+	return Result{Columns: columns, Rows: rows, Affected: int64(len(rows))}
+}
+
+// Affected is the result of the statement without rows
+func Affected(n int64) Result {
+	return Result{Affected: n}
+}
+
+// Statement is the executed query with its arguments
+type Statement struct {
+	Query 	string
+	Args 	[]any
+}
+
+type handler struct {
+	fragment string
+	answer func(args []any) Result
+}
+
+// DB is the scripted database, the first handler with its fragment in the query answers the query and the
+// query without a handler fails the test
+type DB struct {
+	t testing.TB
+	mu sync.Mutex
+	handlers []handler
+	log []Statement
+}
+
+var (
+	register sync.Once
+	databases sync.Map
+	counter atomic.Int64
+)
+
+// New opens the scripted database for the test
+func New(t testing.TB) (*DB, *sqlx.DB) {
+	t.Helper()
+	register.Do(func() {
+		sql.Register("dbtest", fakeDriver{})
+	})
+	fake := &DB{t: t}
+	name := fmt.Sprintf("db-%d", counter.Add(1))
+	databases.Store(name, fake)
+	db := sqlx.NewDb(sql.OpenDB(connector{name: name}), "postgres")
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+		databases.Delete(name)
+	})
+	return fake, db
+}
+
+// On answers every query that contains the fragment
+func (d *DB) On(fragment string, answer func(args []any) Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers = append(d.handlers, handler{fragment: fragment, answer: answer})
+}
+
+// Statements is the log of the executed statements with the transactions
+func (d *DB) Statements() []Statement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Statement(nil), d.log...)
+}
+
+// Index is the position of the first statement after from that contains the fragment, -1 when there is not
+func (d *DB) Index(fragment string, from int) int {
+	statements := d.Statements()
+	for i := max(from, 0); i < len(statements); i++ {
+		if strings.Contains(statements[i].Query, fragment) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Count is the number of the statements that contain the fragment
+func (d *DB) Count(fragment string) int {
+	count := 0
+	for _, statement := range d.Statements() {
+		if strings.Contains(statement.Query, fragment) {
+			count++
+		}
+	}
+	return count
+}
+
+func (d *DB) run(query string, args []driver.NamedValue) Result {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	d.mu.Lock()
+	d.log = append(d.log, Statement{Query: query, Args: values})
+	var answer func(args []any) Result
+	for _, h := range d.handlers {
+		if strings.Contains(query, h.fragment) {
+			answer = h.answer
+			break
+		}
+	}
+	d.mu.Unlock()
+
+	if answer == nil {
+		d.t.Errorf("dbtest: unexpected query %s", strings.Join(strings.Fields(query), " "))
+		return Result{Err: fmt.Errorf("dbtest: unexpected query")}
+	}
+	return answer(values)
+}
+
+func (d *DB) mark(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, Statement{Query: statement})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return connector{name: name}.Connect(context.Background())
+}
+
+type connector struct {
+	name string
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	fake, ok := databases.Load(c.name)
+	if !ok {
+		return nil, fmt.Errorf("dbtest: the database %s is closed", c.name)
+	}
+	return &conn{db: fake.(*DB)}, nil
+}
+
+func (connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type conn struct {
+	db *DB
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("dbtest: prepared statements are not supported")
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.db.mark(Begin)
+	return tx{db: c.db}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+// the arguments are given to the handlers as they are
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result := c.db.run(query, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return &rows{result: result}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result := c.db.run(query, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return driver.RowsAffected(result.Affected), nil
+}
+
+type tx struct {
+	db *DB
+}
+
+func (t tx) Commit() error {
+	t.db.mark(Commit)
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.db.mark(Rollback)
+	return nil
+}
+
+type rows struct {
+	result Result
+	next int
+}
+
+func (r *rows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.next])
+	r.next++
+	return nil
+}
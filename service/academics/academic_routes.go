@@ -0,0 +1,585 @@
+package academics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the academic store for a database logic
+type HandleRequest struct {
+	db types.AcademicStore
+}
+
+//func that declare the handler for academic years and terms
+func NewHandlerAcademic(db types.AcademicStore) *HandleRequest {
+	return &HandleRequest{db: db}
+}
+
+//helper to make the response of the academic year
+func academicYearResponse(year types.AcademicYear) types.AcademicYearResponse {
+	return types.AcademicYearResponse{
+		Id: year.Id,
+		Name: year.Name,
+		StartDate: year.StartDate.Format("2006-01-02"),
+		EndDate: year.EndDate.Format("2006-01-02"),
+		IsActive: year.IsActive,
+		Created_at: year.Created_at.Format("2006-01-02"),
+		Updated_at: year.Updated_at.Format("2006-01-02"),
+	}
+}
+
+//helper to make the response of the term
+func termResponse(term types.Term) types.TermResponse {
+	return types.TermResponse{
+		Id: term.Id,
+		AcademicYearId: term.AcademicYearId,
+		AcademicYear: term.AcademicYear,
+		Name: term.Name,
+		Semester: term.Semester,
+		StartDate: term.StartDate.Format("2006-01-02"),
+		EndDate: term.EndDate.Format("2006-01-02"),
+		Status: term.Status,
+		OpenedAt: term.OpenedAt,
+		ClosedAt: term.ClosedAt,
+	}
+}
+
+//helper to parse the start and the end date of the payload
+func parseDateRange(start, end string) (time.Time, time.Time, error) {
+	start_date, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end_date, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !end_date.After(start_date) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the end date must be after the start date")
+	}
+	return start_date, end_date, nil
+}
+
+//func to create a new academic year
+func (h *HandleRequest) CreateAcademicYear_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the academic years
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateAcademicYear
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	if !utils.ValidAcademicYear(payload.Name) {
+		utils.ResponseError(w, http.StatusBadRequest, "The name of the academic year must be like 2025/2026!", false)
+		return 
+	}
+	start_date, end_date, err := parseDateRange(payload.StartDate, payload.EndDate)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid date of the academic year!", err.Error())
+		return 
+	}
+
+	//the name must be unique
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	exist, err := h.db.GetAcademicYearByName(ctx, payload.Name)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the academic year!", err.Error())
+		return 
+	}
+	if exist != nil {
+		utils.ResponseError(w, http.StatusConflict, "The academic year has been already exist!", false)
+		return 
+	}
+
+	//make the struct of the academic year and execute the query
+	year := &types.AcademicYear{
+		Id: uuid.New(),
+		Name: payload.Name,
+		StartDate: start_date,
+		EndDate: end_date,
+		IsActive: false,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	if err := h.db.CreateAcademicYear(ctx, year); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new academic year", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the academic year!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new academic year has been successfully", academicYearResponse(*year))
+
+}
+
+//func to get all the academic years
+func (h *HandleRequest) GetAllAcademicYears_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	years, err := h.db.GetAllAcademicYears(ctx)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get all the academic years", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the academic years!", err.Error())
+		return 
+	}
+
+	//make the response
+	response := make([]types.AcademicYearResponse, 0, len(years))
+	for _, year := range years {
+		response = append(response, academicYearResponse(year))
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get all academic years has been successfully", response)
+
+}
+
+//func to make the academic year the active one
+func (h *HandleRequest) ActivateAcademicYear_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the academic years
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	year_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.ActivateAcademicYear(ctx, year_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to activate the academic year", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to activate the academic year!", err.Error())
+		return 
+	}
+	year, err := h.db.GetAcademicYearById(ctx, year_id)
+	if err != nil || year == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the academic year!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Activate the academic year has been successfully", academicYearResponse(*year))
+
+}
+
+//func to create a new term of the academic year
+func (h *HandleRequest) CreateTerm_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the terms
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	year_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateTerm
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	start_date, end_date, err := parseDateRange(payload.StartDate, payload.EndDate)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid date of the term!", err.Error())
+		return 
+	}
+
+	//the term must be inside of the year and must not overlap the other terms
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	year, err := h.db.GetAcademicYearById(ctx, year_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the academic year!", err.Error())
+		return 
+	}
+	if year == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The academic year is not exist!", false)
+		return 
+	}
+	if start_date.Before(year.StartDate) || end_date.After(year.EndDate) {
+		utils.ResponseError(w, http.StatusBadRequest, "The term must be inside of the academic year!", false)
+		return 
+	}
+	terms, err := h.db.GetTermsByAcademicYear(ctx, year_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the terms!", err.Error())
+		return 
+	}
+	for _, term := range terms {
+		if term.Semester == payload.Semester {
+			utils.ResponseError(w, http.StatusConflict, "The semester has been already exist in this academic year!", false)
+			return 
+		}
+		if !start_date.After(term.EndDate) && !end_date.Before(term.StartDate) {
+			utils.ResponseError(w, http.StatusConflict, "The term overlaps the other term!", termResponse(term))
+			return 
+		}
+	}
+
+	//make the struct of the term and execute the query
+	term := &types.Term{
+		Id: uuid.New(),
+		AcademicYearId: year.Id,
+		AcademicYear: year.Name,
+		Name: payload.Name,
+		Semester: payload.Semester,
+		StartDate: start_date,
+		EndDate: end_date,
+		Status: "planned",
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	if err := h.db.CreateTerm(ctx, term); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new term", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the term!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new term has been successfully", termResponse(*term))
+
+}
+
+//func to get the terms of the academic year
+func (h *HandleRequest) GetTerms_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	year_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	terms, err := h.db.GetTermsByAcademicYear(ctx, year_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the terms", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the terms!", err.Error())
+		return 
+	}
+
+	//make the response
+	response := make([]types.TermResponse, 0, len(terms))
+	for _, term := range terms {
+		response = append(response, termResponse(term))
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the terms has been successfully", response)
+
+}
+
+//func to get the open term
+func (h *HandleRequest) GetActiveTerm_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term, err := h.db.GetActiveTerm(ctx)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	if term == nil {
+		utils.ResponseError(w, http.StatusNotFound, "There is no open term!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the open term has been successfully", termResponse(*term))
+
+}
+
+//func to open the term, the class membership of the students is saved for the term
+func (h *HandleRequest) OpenTerm_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the terms
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	term_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	enrolled, err := h.db.OpenTerm(ctx, term_id)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to open the term", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to open the term!", err.Error())
+		return 
+	}
+	term, err := h.db.GetTermById(ctx, term_id)
+	if err != nil || term == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Open the term has been successfully", map[string]interface{}{
+		"term": termResponse(*term),
+		"enrolled": enrolled,
+	})
+
+}
+
+//func to close the open term
+func (h *HandleRequest) CloseTerm_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the terms
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	term_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CloseTerm(ctx, term_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to close the term", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to close the term!", err.Error())
+		return 
+	}
+	term, err := h.db.GetTermById(ctx, term_id)
+	if err != nil || term == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Close the term has been successfully", termResponse(*term))
+
+}
@@ -0,0 +1,300 @@
+package academics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store academic year and term
+type AcademicStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewAcademicStore(db *sqlx.DB) *AcademicStore {
+	return &AcademicStore{db: db}
+}
+
+//the column that we select in every query
+const (
+	academicYearColumns = `id, name, start_date, end_date, is_active, created_at, updated_at`
+	termColumns = `
+		t.id, t.academic_year_id, y.name AS academic_year, t.name, t.semester, t.start_date, t.end_date,
+		t.status, t.opened_at, t.closed_at, t.created_at, t.updated_at
+	`
+)
+
+//func to create a new academic year
+func (s *AcademicStore) CreateAcademicYear(ctx context.Context, year *types.AcademicYear) error {
+
+	//base query
+	query := `
+		INSERT INTO academic_years (id, name, start_date, end_date, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		year.Id,
+		year.Name,
+		year.StartDate,
+		year.EndDate,
+		year.IsActive,
+		year.Created_at,
+		year.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new academic year! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the academic year by id
+func (s *AcademicStore) GetAcademicYearById(ctx context.Context, id uuid.UUID) (*types.AcademicYear, error) {
+	return s.getAcademicYear(ctx, `SELECT `+academicYearColumns+` FROM academic_years WHERE id = $1;`, id)
+}
+
+//func to get the academic year by the name (2025/2026)
+func (s *AcademicStore) GetAcademicYearByName(ctx context.Context, name string) (*types.AcademicYear, error) {
+	return s.getAcademicYear(ctx, `SELECT `+academicYearColumns+` FROM academic_years WHERE name = $1;`, name)
+}
+
+//func to get the active academic year, nil if there is no active year
+func (s *AcademicStore) GetActiveAcademicYear(ctx context.Context) (*types.AcademicYear, error) {
+	return s.getAcademicYear(ctx, `SELECT `+academicYearColumns+` FROM academic_years WHERE is_active;`)
+}
+
+//helper to get one academic year
+func (s *AcademicStore) getAcademicYear(ctx context.Context, query string, args ...interface{}) (*types.AcademicYear, error) {
+
+	var year types.AcademicYear
+	if err := s.db.GetContext(ctx, &year, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the academic year: %w", err)
+	}
+
+	return &year, nil
+
+}
+
+//func to get all of the academic years
+func (s *AcademicStore) GetAllAcademicYears(ctx context.Context) ([]types.AcademicYear, error) {
+
+	//execute the query
+	years := []types.AcademicYear{}
+	if err := s.db.SelectContext(ctx, &years, `SELECT `+academicYearColumns+` FROM academic_years ORDER BY start_date DESC;`); err != nil {
+		return nil, fmt.Errorf("failed to get the academic years: %w", err)
+	}
+
+	return years, nil
+
+}
+
+//func to make the academic year the active one
+func (s *AcademicStore) ActivateAcademicYear(ctx context.Context, id uuid.UUID) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	if err := activateAcademicYear(ctx, tx, id); err != nil {
+		return err
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//helper that deactivate the other years first, because of the unique index
+func activateAcademicYear(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+
+	if _, err := tx.ExecContext(ctx, `UPDATE academic_years SET is_active = FALSE, updated_at = $1 WHERE is_active AND id <> $2;`, time.Now().UTC(), id); err != nil {
+		return errors.New("Failed to deactivate the academic year! " + err.Error())
+	}
+	rows, err := tx.ExecContext(ctx, `UPDATE academic_years SET is_active = TRUE, updated_at = $1 WHERE id = $2;`, time.Now().UTC(), id)
+	if err != nil {
+		return errors.New("Failed to activate the academic year! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The academic year is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to create a new term
+func (s *AcademicStore) CreateTerm(ctx context.Context, term *types.Term) error {
+
+	//base query
+	query := `
+		INSERT INTO terms (id, academic_year_id, name, semester, start_date, end_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		term.Id,
+		term.AcademicYearId,
+		term.Name,
+		term.Semester,
+		term.StartDate,
+		term.EndDate,
+		term.Status,
+		term.Created_at,
+		term.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new term! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the term by id
+func (s *AcademicStore) GetTermById(ctx context.Context, id uuid.UUID) (*types.Term, error) {
+	return s.getTerm(ctx, `SELECT `+termColumns+` FROM terms t JOIN academic_years y ON y.id = t.academic_year_id WHERE t.id = $1;`, id)
+}
+
+//func to get the open term, nil if no term is open
+func (s *AcademicStore) GetActiveTerm(ctx context.Context) (*types.Term, error) {
+	return s.getTerm(ctx, `SELECT `+termColumns+` FROM terms t JOIN academic_years y ON y.id = t.academic_year_id WHERE t.status = 'open';`)
+}
+
+//helper to get one term
+func (s *AcademicStore) getTerm(ctx context.Context, query string, args ...interface{}) (*types.Term, error) {
+
+	var term types.Term
+	if err := s.db.GetContext(ctx, &term, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the term: %w", err)
+	}
+
+	return &term, nil
+
+}
+
+//func to get the terms of the academic year
+func (s *AcademicStore) GetTermsByAcademicYear(ctx context.Context, academicYearId uuid.UUID) ([]types.Term, error) {
+
+	//base query
+	query := `
+		SELECT ` + termColumns + ` FROM terms t JOIN academic_years y ON y.id = t.academic_year_id
+		WHERE t.academic_year_id = $1 ORDER BY t.semester;
+	`
+
+	//execute the query
+	terms := []types.Term{}
+	if err := s.db.SelectContext(ctx, &terms, query, academicYearId); err != nil {
+		return nil, fmt.Errorf("failed to get the terms: %w", err)
+	}
+
+	return terms, nil
+
+}
+
+//func to open the term: the year becomes active and the current class of every student of that
+//year is saved as the membership of the term, it return the count of the new enrollments
+func (s *AcademicStore) OpenTerm(ctx context.Context, id uuid.UUID) (int64, error) {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return 0, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//only a planned term can be opened, and only when no other term is open
+	var term types.Term
+	if err := tx.GetContext(ctx, &term, `SELECT `+termColumns+` FROM terms t JOIN academic_years y ON y.id = t.academic_year_id WHERE t.id = $1 FOR UPDATE OF t;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("The term is not exist!")
+		}
+		return 0, errors.New("Failed to get the term! " + err.Error())
+	}
+	if term.Status != "planned" {
+		return 0, fmt.Errorf("The term is already %s!", term.Status)
+	}
+	var open int
+	if err := tx.GetContext(ctx, &open, `SELECT COUNT(*) FROM terms WHERE status = 'open';`); err != nil {
+		return 0, errors.New("Failed to check the open term! " + err.Error())
+	}
+	if open > 0 {
+		return 0, errors.New("Close the open term before opening a new one!")
+	}
+
+	//open the term and activate the year
+	if _, err := tx.ExecContext(ctx, `UPDATE terms SET status = 'open', opened_at = $1, updated_at = $1 WHERE id = $2;`, time.Now().UTC(), id); err != nil {
+		return 0, errors.New("Failed to open the term! " + err.Error())
+	}
+	if err := activateAcademicYear(ctx, tx, term.AcademicYearId); err != nil {
+		return 0, err
+	}
+
+	//save the class membership of the students for this term
+	rows, err := tx.ExecContext(ctx, `
+		INSERT INTO class_enrollments (id, student_id, class_id, term_id, created_at, updated_at)
+		SELECT gen_random_uuid(), s.id, s.class_id, $1, $2, $2
+		FROM students s JOIN classes c ON c.id = s.class_id
+		WHERE c.academic_year = $3 AND s.status = 'active'
+		ON CONFLICT (student_id, term_id) DO NOTHING;
+	`, id, time.Now().UTC(), term.AcademicYear)
+	if err != nil {
+		return 0, errors.New("Failed to save the class membership! " + err.Error())
+	}
+	total, err := rows.RowsAffected()
+	if err != nil {
+		return 0, errors.New("Failed to save the class membership! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return 0, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return total, nil
+
+}
+
+//func to close the open term, the records of the term stay as history
+func (s *AcademicStore) CloseTerm(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `
+		UPDATE terms SET status = 'closed', closed_at = $1, updated_at = $1 WHERE id = $2 AND status = 'open';
+	`, time.Now().UTC(), id)
+	if err != nil {
+		return errors.New("Failed to close the term! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The term is not exist or not open!")
+	}
+
+	return nil
+
+}
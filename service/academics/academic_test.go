@@ -0,0 +1,99 @@
+package academics
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+)
+
+func TestParseDateRange(t *testing.T) {
+	start, end, err := parseDateRange("2025-07-14", "2026-06-20")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !start.Equal(time.Date(2025, time.July, 14, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, time.June, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected range %v - %v", start, end)
+	}
+	for _, dates := range [][2]string{{"2025-07-14", "2025-07-14"}, {"2026-06-20", "2025-07-14"}, {"14-07-2025", "2026-06-20"}} {
+		if _, _, err := parseDateRange(dates[0], dates[1]); err == nil {
+			t.Fatalf("the range %v should fail", dates)
+		}
+	}
+}
+
+var termRow = []string{
+	"id", "academic_year_id", "academic_year", "name", "semester", "start_date", "end_date",
+	"status", "opened_at", "closed_at", "created_at", "updated_at",
+}
+
+//script the term of the year 2025/2026 with the status and the count of the open terms
+func scriptTerm(fake *dbtest.DB, id uuid.UUID, year uuid.UUID, status string, open int64) {
+	now := time.Now().UTC()
+	fake.On("FROM terms t JOIN academic_years y", func(args []any) dbtest.Result {
+		return dbtest.Rows(termRow, []driver.Value{
+			id.String(), year.String(), "2025/2026", "Ganjil", int64(1), now, now.AddDate(0, 6, 0),
+			status, nil, nil, now, now,
+		})
+	})
+	fake.On("SELECT COUNT(*) FROM terms WHERE status = 'open'", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"count"}, []driver.Value{open})
+	})
+	fake.On("UPDATE terms SET status = 'open'", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("UPDATE academic_years SET is_active", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("INSERT INTO class_enrollments", func(args []any) dbtest.Result { return dbtest.Affected(32) })
+}
+
+func TestOpenTermEnrollsTheStudentsOfTheYear(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewAcademicStore(db)
+
+	term, year := uuid.New(), uuid.New()
+	scriptTerm(fake, term, year, "planned", 0)
+
+	total, err := store.OpenTerm(context.Background(), term)
+	if err != nil || total != 32 {
+		t.Fatalf("expected 32 enrollments, got %d %v", total, err)
+	}
+
+	//the year is activated and the classes of the year are saved as the membership of the term
+	statements := fake.Statements()
+	activated := fake.Index("UPDATE academic_years SET is_active = TRUE", 0)
+	enrolled := fake.Index("INSERT INTO class_enrollments", activated)
+	if activated < 0 || enrolled < 0 || statements[len(statements)-1].Query != dbtest.Commit {
+		t.Fatalf("unexpected statements %v", statements)
+	}
+	if statements[activated].Args[1] != year {
+		t.Fatalf("expected the year of the term to be activated, got %v", statements[activated].Args)
+	}
+	if args := statements[enrolled].Args; args[0] != term || args[2] != "2025/2026" {
+		t.Fatalf("expected the membership of the classes of 2025/2026, got %v", args)
+	}
+}
+
+func TestOpenTermRejects(t *testing.T) {
+	cases := []struct {
+		name string
+		status string
+		open int64
+	}{
+		{"the term that is already open", "open", 0},
+		{"the closed term", "closed", 0},
+		{"another open term", "planned", 1},
+	}
+	for _, c := range cases {
+		fake, db := dbtest.New(t)
+		scriptTerm(fake, uuid.New(), uuid.New(), c.status, c.open)
+
+		if _, err := NewAcademicStore(db).OpenTerm(context.Background(), uuid.New()); err == nil {
+			t.Fatalf("%s: expected an error", c.name)
+		}
+		if fake.Count("UPDATE terms SET status = 'open'") != 0 || fake.Count(dbtest.Commit) != 0 {
+			t.Fatalf("%s: the term should not be opened", c.name)
+		}
+	}
+}
@@ -0,0 +1,805 @@
+package announcements
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/service/homework"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/storage"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the announcement store for a database logic
+type HandleRequest struct {
+	db types.AnnouncementStore
+	subjects types.SubjectStore
+	files storage.Storage
+	urlTTL time.Duration
+}
+
+//func that declare the handler for the announcements with the storage of the attachments
+func NewHandlerAnnouncement(db types.AnnouncementStore, subjects types.SubjectStore, files storage.Storage, cfg config.ConfigParams) *HandleRequest {
+	return &HandleRequest{
+		db: db,
+		subjects: subjects,
+		files: files,
+		urlTTL: cfg.StorageURLTTL,
+	}
+}
+
+//helper to get the user of the token with the audience, the viewer of the admin is nil because the admin
+//sees every announcement
+func (h *HandleRequest) viewerOf(ctx context.Context, w http.ResponseWriter, r *http.Request) (uuid.UUID, *types.AnnouncementViewer, bool) {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return uuid.Nil, nil, false
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return uuid.Nil, nil, false
+	}
+	if role == "admin" {
+		return user_id, nil, true
+	}
+	viewer, err := h.db.GetViewer(ctx, user_id, role)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the audience of the user!", err.Error())
+		return uuid.Nil, nil, false
+	}
+	return user_id, viewer, true
+}
+
+//helper to get the announcement of the id parameters that the user can see, the other user only sees the
+//active announcement of their audience so the rest is not found
+func (h *HandleRequest) announcementParam(ctx context.Context, w http.ResponseWriter, r *http.Request) (*types.Announcement, uuid.UUID, *types.AnnouncementViewer, bool) {
+	announcement_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, uuid.Nil, nil, false
+	}
+	user_id, viewer, ok := h.viewerOf(ctx, w, r)
+	if !ok {
+		return nil, uuid.Nil, nil, false
+	}
+	announcement, err := h.db.GetAnnouncementById(ctx, announcement_id, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the announcement!", err.Error())
+		return nil, uuid.Nil, nil, false
+	}
+	if announcement != nil {
+		announcement.State = StateAt(*announcement, time.Now().UTC())
+	}
+	if announcement == nil || !CanSee(*announcement, viewer) ||
+		(viewer != nil && !isAuthor(*announcement, user_id) && announcement.State != types.AnnouncementActive) {
+		utils.ResponseError(w, http.StatusNotFound, "The announcement is not exist!", false)
+		return nil, uuid.Nil, nil, false
+	}
+	return announcement, user_id, viewer, true
+}
+
+//helper to check that the user wrote the announcement
+func isAuthor(announcement types.Announcement, userId uuid.UUID) bool {
+	return announcement.CreatedBy != nil && *announcement.CreatedBy == userId
+}
+
+//helper to allow only the admin or the author of the announcement to change it
+func canEdit(w http.ResponseWriter, announcement types.Announcement, userId uuid.UUID, viewer *types.AnnouncementViewer) bool {
+	if viewer == nil || isAuthor(announcement, userId) {
+		return true
+	}
+	utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+	return false
+}
+
+//func to create the announcement, the admin writes for every audience and the guru only for the classes
+//that they teach, the empty publish time publishes the announcement now
+func (h *HandleRequest) CreateAnnouncement_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can write the announcement
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.CreateAnnouncement
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+	if err := CheckTarget(payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, err.Error(), false)
+		return
+	}
+	now := time.Now().UTC()
+	publish_at := now
+	if payload.PublishAt != nil {
+		publish_at = payload.PublishAt.UTC()
+	}
+	var expires_at *time.Time
+	if payload.ExpiresAt != nil {
+		expiry := payload.ExpiresAt.UTC()
+		expires_at = &expiry
+	}
+	if err := CheckSchedule(publish_at, expires_at); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, err.Error(), false)
+		return
+	}
+
+	//the guru only writes for the class that they teach and cannot pin
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if role == "guru" {
+		if payload.Target != types.TargetClass {
+			utils.ResponseError(w, http.StatusForbidden, "The guru can only write the announcement for the class!", false)
+			return
+		}
+		if payload.IsPinned {
+			utils.ResponseError(w, http.StatusForbidden, "Only the admin can pin the announcement!", false)
+			return
+		}
+		allowed, err := h.subjects.CanTeach(ctx, user_id, *payload.ClassId, nil, nil)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to check the teaching assignment!", err.Error())
+			return
+		}
+		if !allowed {
+			utils.ResponseError(w, http.StatusForbidden, "You are not assigned to teach this class!", false)
+			return
+		}
+	}
+
+	//execute the query
+	announcement := types.Announcement{
+		Id: uuid.New(),
+		Title: payload.Title,
+		Body: payload.Body,
+		Target: payload.Target,
+		GradeLevel: payload.GradeLevel,
+		ClassId: payload.ClassId,
+		TargetRole: payload.TargetRole,
+		UserId: payload.UserId,
+		PublishAt: publish_at,
+		ExpiresAt: expires_at,
+		IsPinned: payload.IsPinned,
+		CreatedBy: &user_id,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if err := h.db.CreateAnnouncement(ctx, &announcement); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the announcement",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the announcement!", err.Error())
+		return
+	}
+	created, err := h.db.GetAnnouncementById(ctx, announcement.Id, user_id)
+	if err != nil || created == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the announcement!", false)
+		return
+	}
+	created.State = StateAt(*created, now)
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the announcement has been successfully", created)
+
+}
+
+//func to get the announcements of the audience of the user (?state=active|scheduled|expired|all&target=),
+//the other states than active only list the announcements that the user wrote, the admin sees every one
+func (h *HandleRequest) GetAnnouncements_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//declare the query params
+	state := r.URL.Query().Get("state")
+	switch state {
+	case "":
+		state = types.AnnouncementActive
+	case "all":
+		state = ""
+	case types.AnnouncementActive, types.AnnouncementScheduled, types.AnnouncementExpired:
+	default:
+		utils.ResponseError(w, http.StatusBadRequest, "The state must be active, scheduled, expired or all!", false)
+		return
+	}
+	target := r.URL.Query().Get("target")
+	switch target {
+	case "", types.TargetSchool, types.TargetGrade, types.TargetClass, types.TargetRole, types.TargetUser:
+	default:
+		utils.ResponseError(w, http.StatusBadRequest, "The target must be school, grade, class, role or user!", false)
+		return
+	}
+
+	//the audience of the user
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	user_id, viewer, ok := h.viewerOf(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	//execute the query
+	now := time.Now().UTC()
+	announcements, err := h.db.GetAnnouncements(ctx, types.AnnouncementFilter{
+		Viewer: viewer,
+		UserId: user_id,
+		State: state,
+		Target: target,
+		At: now,
+	})
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the announcements",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the announcements!", err.Error())
+		return
+	}
+	for i := range announcements {
+		announcements[i].State = StateAt(announcements[i], now)
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the announcements has been successfully", announcements)
+
+}
+
+//func to get the announcement by the id, the read receipt of the user is saved
+func (h *HandleRequest) GetAnnouncement_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//get the announcement
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	announcement, user_id, _, ok := h.announcementParam(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	//save the read receipt, the author does not read their own announcement
+	if !isAuthor(*announcement, user_id) && announcement.State == types.AnnouncementActive && !announcement.IsRead {
+		if err := h.db.MarkRead(ctx, announcement.Id, user_id, time.Now().UTC()); err != nil {
+			//logger if some error is detected
+			logger.Log.Error("Failed to save the read receipt",
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to save the read receipt!", err.Error())
+			return
+		}
+		announcement.IsRead = true
+		announcement.Reads++
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the announcement has been successfully", announcement)
+
+}
+
+//func to update the title, the body or the schedule of the announcement by the admin or the author
+func (h *HandleRequest) UpdateAnnouncement_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.UpdateAnnouncement
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	//get the announcement and merge the changes
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	announcement, user_id, viewer, ok := h.announcementParam(ctx, w, r)
+	if !ok || !canEdit(w, *announcement, user_id, viewer) {
+		return
+	}
+	changes := types.AnnouncementChanges{
+		Title: announcement.Title,
+		Body: announcement.Body,
+		PublishAt: announcement.PublishAt,
+		ExpiresAt: announcement.ExpiresAt,
+	}
+	if payload.Title != nil {
+		changes.Title = *payload.Title
+	}
+	if payload.Body != nil {
+		changes.Body = *payload.Body
+	}
+	if payload.PublishAt != nil {
+		changes.PublishAt = payload.PublishAt.UTC()
+	}
+	if payload.ExpiresAt != nil {
+		expiry := payload.ExpiresAt.UTC()
+		changes.ExpiresAt = &expiry
+	}
+	if payload.ClearExpiry {
+		changes.ExpiresAt = nil
+	}
+	if err := CheckSchedule(changes.PublishAt, changes.ExpiresAt); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, err.Error(), false)
+		return
+	}
+
+	//execute the query
+	if err := h.db.UpdateAnnouncement(ctx, announcement.Id, changes); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to update the announcement",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the announcement!", err.Error())
+		return
+	}
+	updated, err := h.db.GetAnnouncementById(ctx, announcement.Id, user_id)
+	if err != nil || updated == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the announcement!", false)
+		return
+	}
+	updated.State = StateAt(*updated, time.Now().UTC())
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Update the announcement has been successfully", updated)
+
+}
+
+//func to pin or unpin the announcement, the pinned announcements are on the top of the list
+func (h *HandleRequest) PinAnnouncement_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can pin the announcement
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return
+	}
+
+	//declare the id of the parameters
+	announcement_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.SetAnnouncementPinned
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.SetPinned(ctx, announcement_id, *payload.Pinned); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to pin the announcement",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to pin the announcement!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Pin the announcement has been successfully", map[string]any{
+		"id": announcement_id,
+		"pinned": *payload.Pinned,
+	})
+
+}
+
+//func to delete the announcement by the admin or the author
+func (h *HandleRequest) DeleteAnnouncement_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//get the announcement
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	announcement, user_id, viewer, ok := h.announcementParam(ctx, w, r)
+	if !ok || !canEdit(w, *announcement, user_id, viewer) {
+		return
+	}
+
+	//execute the query
+	if err := h.db.DeleteAnnouncement(ctx, announcement.Id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the announcement",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the announcement!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the announcement has been successfully", announcement.Id)
+
+}
+
+//func to attach the files (files) into the announcement by the admin or the author
+func (h *HandleRequest) AddFiles_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//get the announcement
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	announcement, user_id, viewer, ok := h.announcementParam(ctx, w, r)
+	if !ok || !canEdit(w, *announcement, user_id, viewer) {
+		return
+	}
+
+	//declare the form validaton for the size of the files
+	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize)
+	if err := r.ParseMultipartForm(MaxUploadSize); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to parse the multipart form data for a request!", err.Error())
+		return
+	}
+	headers := r.MultipartForm.File["files"]
+	if len(headers) == 0 {
+		utils.ResponseError(w, http.StatusBadRequest, "The files are required!", false)
+		return
+	}
+	if len(announcement.Files)+len(headers) > MaxFiles {
+		utils.ResponseError(w, http.StatusBadRequest, fmt.Sprintf("The announcement can have %d files at most!", MaxFiles), false)
+		return
+	}
+
+	//save every file in the storage
+	files := make([]types.AnnouncementFile, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to open the file!", err.Error())
+			return
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil || len(data) == 0 {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to read the file!", false)
+			return
+		}
+		content_type := http.DetectContentType(data)
+		ext, ok := homework.FileExt(content_type, header.Filename)
+		if !ok {
+			//logger the data response if the type of file is failed
+			logger.Log.Error("Failed because the file of the announcement is invalid!",
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.String("filename", header.Filename),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed content file type, use pdf, image, text, zip or office documents!", header.Filename)
+			return
+		}
+		key := storage.ContentKey("announcements", data, ext)
+		if err := storage.PutBytes(ctx, h.files, key, data, content_type); err != nil {
+			//logger the data response if the storage is failed
+			logger.Log.Error("Failed to save the file of the announcement",
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.String("key", key),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusInternalServerError, "Failed to save the file!", err.Error())
+			return
+		}
+		files = append(files, types.AnnouncementFile{
+			FileKey: key,
+			FileName: homework.CleanFileName(header.Filename),
+			ContentType: content_type,
+			Size: int64(len(data)),
+		})
+	}
+
+	//execute the query
+	saved, err := h.db.AddFiles(ctx, announcement.Id, files)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the files of the announcement",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to save the files!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Attach the files has been successfully", saved)
+
+}
+
+//func to remove the file from the announcement by the admin or the author
+func (h *HandleRequest) DeleteFile_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//declare the id of the parameters
+	file_id, err := uuid.Parse(mux.Vars(r)["file_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//get the announcement
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	announcement, user_id, viewer, ok := h.announcementParam(ctx, w, r)
+	if !ok || !canEdit(w, *announcement, user_id, viewer) {
+		return
+	}
+
+	//execute the query
+	if err := h.db.DeleteFile(ctx, announcement.Id, file_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the file of the announcement",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the file!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the file has been successfully", file_id)
+
+}
+
+//func to download the file of the announcement with a signed url, the user must see the announcement
+func (h *HandleRequest) DownloadFile_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//declare the id of the parameters
+	file_id, err := uuid.Parse(mux.Vars(r)["file_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//get the announcement and the file of it
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	announcement, _, _, ok := h.announcementParam(ctx, w, r)
+	if !ok {
+		return
+	}
+	file, err := h.db.GetFileById(ctx, file_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the file!", err.Error())
+		return
+	}
+	if file == nil || file.AnnouncementId != announcement.Id {
+		utils.ResponseError(w, http.StatusNotFound, "The file is not exist!", false)
+		return
+	}
+
+	//make the signed url and redirect the client into it
+	signed_url, err := h.files.SignedURL(ctx, file.FileKey, h.urlTTL)
+	if err != nil {
+		//logger the data response if the signed url is failed
+		logger.Log.Error("Failed to sign the url of the file",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to make the url of the file!", err.Error())
+		return
+	}
+	http.Redirect(w, r, signed_url, http.StatusFound)
+
+}
+
+//func to get the read receipts of the announcement for the admin or the author
+func (h *HandleRequest) GetReads_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//get the announcement
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	announcement, user_id, viewer, ok := h.announcementParam(ctx, w, r)
+	if !ok || !canEdit(w, *announcement, user_id, viewer) {
+		return
+	}
+
+	//execute the query
+	reads, err := h.db.GetReads(ctx, announcement.Id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the read receipts",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the read receipts!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the read receipts has been successfully", reads)
+
+}
@@ -0,0 +1,402 @@
+package announcements
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store announcement
+type AnnouncementStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewAnnouncementStore(db *sqlx.DB) *AnnouncementStore {
+	return &AnnouncementStore{db: db}
+}
+
+//the announcement with the name of the class, the read state of the user ($1) and the count of the reads
+const announcementSelect = `
+	SELECT a.id, a.title, a.body, a.target, a.grade_level, a.class_id, c.name AS class_name, a.target_role,
+	a.user_id, a.publish_at, a.expires_at, a.is_pinned, a.created_by, a.created_at, a.updated_at,
+	EXISTS (SELECT 1 FROM announcement_reads ar WHERE ar.announcement_id = a.id AND ar.user_id = $1) AS is_read,
+	(SELECT COUNT(*) FROM announcement_reads ar WHERE ar.announcement_id = a.id) AS reads
+	FROM announcements a
+	LEFT JOIN classes c ON c.id = a.class_id
+`
+
+//the column of the file that we select in every query
+const fileColumns = `id, announcement_id, file_key, file_name, content_type, size, created_at`
+
+//helper to attach the files into the announcements
+func (s *AnnouncementStore) attachFiles(ctx context.Context, announcements []types.Announcement) error {
+
+	if len(announcements) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(announcements))
+	index := make(map[uuid.UUID]int, len(announcements))
+	for i := range announcements {
+		announcements[i].Files = []types.AnnouncementFile{}
+		ids = append(ids, announcements[i].Id.String())
+		index[announcements[i].Id] = i
+	}
+
+	//execute the query
+	files := []types.AnnouncementFile{}
+	if err := s.db.SelectContext(ctx, &files, `
+		SELECT `+fileColumns+` FROM announcement_files WHERE announcement_id = ANY($1::uuid[]) ORDER BY created_at, file_name;
+	`, pq.StringArray(ids)); err != nil {
+		return fmt.Errorf("failed to get the files of the announcements: %w", err)
+	}
+	for _, file := range files {
+		i := index[file.AnnouncementId]
+		announcements[i].Files = append(announcements[i].Files, file)
+	}
+
+	return nil
+
+}
+
+//func to create the announcement
+func (s *AnnouncementStore) CreateAnnouncement(ctx context.Context, announcement *types.Announcement) error {
+
+	//execute the query
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO announcements
+		(id, title, body, target, grade_level, class_id, target_role, user_id, publish_at, expires_at, is_pinned, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+	`,
+		announcement.Id,
+		announcement.Title,
+		announcement.Body,
+		announcement.Target,
+		announcement.GradeLevel,
+		announcement.ClassId,
+		announcement.TargetRole,
+		announcement.UserId,
+		announcement.PublishAt,
+		announcement.ExpiresAt,
+		announcement.IsPinned,
+		announcement.CreatedBy,
+		announcement.Created_at,
+		announcement.Updated_at,
+	); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return errors.New("The class or the user of the announcement is not exist!")
+		}
+		return errors.New("Failed to create the announcement! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the announcement by the id with the files, the read state is of the user
+func (s *AnnouncementStore) GetAnnouncementById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*types.Announcement, error) {
+
+	//execute the query
+	announcements := []types.Announcement{}
+	if err := s.db.SelectContext(ctx, &announcements, announcementSelect+` WHERE a.id = $2;`, userId, id); err != nil {
+		return nil, fmt.Errorf("failed to get the announcement: %w", err)
+	}
+	if len(announcements) == 0 {
+		return nil, nil
+	}
+	if err := s.attachFiles(ctx, announcements); err != nil {
+		return nil, err
+	}
+
+	return &announcements[0], nil
+
+}
+
+//func to get the announcements of the filter, the pinned announcements are the first. The viewer only
+//gets the active announcements of their audience and every announcement that they wrote
+func (s *AnnouncementStore) GetAnnouncements(ctx context.Context, filter types.AnnouncementFilter) ([]types.Announcement, error) {
+
+	//the audience of the viewer, the nil viewer is the admin
+	everyone := filter.Viewer == nil
+	role := ""
+	classIds := []string{}
+	gradeLevels := []int64{}
+	if filter.Viewer != nil {
+		role = filter.Viewer.Role
+		for _, classId := range filter.Viewer.ClassIds {
+			classIds = append(classIds, classId.String())
+		}
+		for _, level := range filter.Viewer.GradeLevels {
+			gradeLevels = append(gradeLevels, int64(level))
+		}
+	}
+
+	//execute the query
+	announcements := []types.Announcement{}
+	query := announcementSelect + `
+		WHERE ($3 = ''
+			OR ($3 = 'active' AND a.publish_at <= $2 AND (a.expires_at IS NULL OR a.expires_at > $2))
+			OR ($3 = 'scheduled' AND a.publish_at > $2)
+			OR ($3 = 'expired' AND a.expires_at <= $2))
+		AND ($4 = '' OR a.target = $4)
+		AND ($5 OR a.created_by = $1 OR (
+			a.publish_at <= $2 AND (a.expires_at IS NULL OR a.expires_at > $2) AND (
+				a.target = 'school'
+				OR (a.target = 'role' AND a.target_role = $6)
+				OR (a.target = 'user' AND a.user_id = $1)
+				OR (a.target = 'class' AND a.class_id = ANY($7::uuid[]))
+				OR (a.target = 'grade' AND a.grade_level = ANY($8::int[]))
+			)
+		))
+		ORDER BY a.is_pinned DESC, a.publish_at DESC, a.id;
+	`
+	if err := s.db.SelectContext(ctx, &announcements, query,
+		filter.UserId,
+		filter.At,
+		filter.State,
+		filter.Target,
+		everyone,
+		role,
+		pq.StringArray(classIds),
+		pq.Int64Array(gradeLevels),
+	); err != nil {
+		return nil, fmt.Errorf("failed to get the announcements: %w", err)
+	}
+	if err := s.attachFiles(ctx, announcements); err != nil {
+		return nil, err
+	}
+
+	return announcements, nil
+
+}
+
+//func to update the title, the body and the schedule of the announcement
+func (s *AnnouncementStore) UpdateAnnouncement(ctx context.Context, id uuid.UUID, changes types.AnnouncementChanges) error {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE announcements SET title = $1, body = $2, publish_at = $3, expires_at = $4, updated_at = $5 WHERE id = $6;
+	`, changes.Title, changes.Body, changes.PublishAt, changes.ExpiresAt, time.Now().UTC(), id)
+	if err != nil {
+		return errors.New("Failed to update the announcement! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to get the rows affected! " + err.Error())
+	}
+	if rows == 0 {
+		return errors.New("The announcement is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to pin or unpin the announcement
+func (s *AnnouncementStore) SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE announcements SET is_pinned = $1, updated_at = $2 WHERE id = $3;
+	`, pinned, time.Now().UTC(), id)
+	if err != nil {
+		return errors.New("Failed to pin the announcement! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to get the rows affected! " + err.Error())
+	}
+	if rows == 0 {
+		return errors.New("The announcement is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to delete the announcement with the files and the reads, the stored files are kept because the
+//content key can be shared
+func (s *AnnouncementStore) DeleteAnnouncement(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `DELETE FROM announcements WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the announcement! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to get the rows affected! " + err.Error())
+	}
+	if rows == 0 {
+		return errors.New("The announcement is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to add the files into the announcement
+func (s *AnnouncementStore) AddFiles(ctx context.Context, announcementId uuid.UUID, files []types.AnnouncementFile) ([]types.AnnouncementFile, error) {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//execute the query
+	now := time.Now().UTC()
+	saved := make([]types.AnnouncementFile, 0, len(files))
+	for _, file := range files {
+		file.Id = uuid.New()
+		file.AnnouncementId = announcementId
+		file.Created_at = now
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO announcement_files (`+fileColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7);
+		`, file.Id, file.AnnouncementId, file.FileKey, file.FileName, file.ContentType, file.Size, file.Created_at); err != nil {
+			return nil, errors.New("Failed to save the file of the announcement! " + err.Error())
+		}
+		saved = append(saved, file)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!")
+	}
+
+	return saved, nil
+
+}
+
+//func to get the file of the announcement by the id
+func (s *AnnouncementStore) GetFileById(ctx context.Context, id uuid.UUID) (*types.AnnouncementFile, error) {
+
+	//execute the query
+	var file types.AnnouncementFile
+	if err := s.db.GetContext(ctx, &file, `SELECT `+fileColumns+` FROM announcement_files WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the file: %w", err)
+	}
+
+	return &file, nil
+
+}
+
+//func to remove the file from the announcement
+func (s *AnnouncementStore) DeleteFile(ctx context.Context, announcementId uuid.UUID, fileId uuid.UUID) error {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM announcement_files WHERE id = $1 AND announcement_id = $2;
+	`, fileId, announcementId)
+	if err != nil {
+		return errors.New("Failed to delete the file! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to get the rows affected! " + err.Error())
+	}
+	if rows == 0 {
+		return errors.New("The file is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to save the read receipt of the user, the first read is kept
+func (s *AnnouncementStore) MarkRead(ctx context.Context, announcementId uuid.UUID, userId uuid.UUID, at time.Time) error {
+
+	//execute the query
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO announcement_reads (announcement_id, user_id, read_at) VALUES ($1, $2, $3)
+		ON CONFLICT (announcement_id, user_id) DO NOTHING;
+	`, announcementId, userId, at); err != nil {
+		return errors.New("Failed to save the read receipt! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the read receipts of the announcement
+func (s *AnnouncementStore) GetReads(ctx context.Context, announcementId uuid.UUID) ([]types.AnnouncementRead, error) {
+
+	//execute the query
+	reads := []types.AnnouncementRead{}
+	if err := s.db.SelectContext(ctx, &reads, `
+		SELECT ar.user_id, u.username, u.role, ar.read_at
+		FROM announcement_reads ar
+		JOIN users u ON u.id = ar.user_id
+		WHERE ar.announcement_id = $1
+		ORDER BY ar.read_at, u.username;
+	`, announcementId); err != nil {
+		return nil, fmt.Errorf("failed to get the reads: %w", err)
+	}
+
+	return reads, nil
+
+}
+
+//func to get the audience of the user, the siswa get their class, the orangtua the classes of their
+//children and the guru the classes that they teach in the open term or their homeroom class
+func (s *AnnouncementStore) GetViewer(ctx context.Context, userId uuid.UUID, role string) (*types.AnnouncementViewer, error) {
+
+	//execute the query
+	rows := []struct {
+		ClassId 	uuid.UUID 	`db:"class_id"`
+		GradeLevel 	int 		`db:"grade_level"`
+	}{}
+	if err := s.db.SelectContext(ctx, &rows, `
+		WITH viewer_classes AS (
+			SELECT s.class_id FROM students s
+			WHERE $2 = 'siswa' AND s.user_id = $1 AND s.class_id IS NOT NULL
+			UNION
+			SELECT e.class_id FROM class_enrollments e
+			JOIN students s ON s.id = e.student_id
+			JOIN terms t ON t.id = e.term_id
+			WHERE $2 = 'siswa' AND s.user_id = $1 AND t.status = 'open'
+			UNION
+			SELECT s.class_id FROM guardian_students gs
+			JOIN guardians g ON g.id = gs.guardian_id
+			JOIN students s ON s.id = gs.student_id
+			WHERE $2 = 'orangtua' AND g.user_id = $1 AND s.class_id IS NOT NULL
+			UNION
+			SELECT ta.class_id FROM teaching_assignments ta
+			JOIN terms t ON t.id = ta.term_id
+			WHERE $2 = 'guru' AND ta.teacher_id = $1 AND t.status = 'open'
+			UNION
+			SELECT c.id FROM classes c
+			WHERE $2 = 'guru' AND c.homeroom_teacher_id = $1
+		)
+		SELECT c.id AS class_id, c.grade_level FROM classes c
+		JOIN viewer_classes v ON v.class_id = c.id
+		ORDER BY c.grade_level, c.name;
+	`, userId, role); err != nil {
+		return nil, fmt.Errorf("failed to get the audience of the user: %w", err)
+	}
+
+	viewer := types.AnnouncementViewer{UserId: userId, Role: role, ClassIds: []uuid.UUID{}, GradeLevels: []int{}}
+	seen := make(map[int]bool)
+	for _, row := range rows {
+		viewer.ClassIds = append(viewer.ClassIds, row.ClassId)
+		if !seen[row.GradeLevel] {
+			seen[row.GradeLevel] = true
+			viewer.GradeLevels = append(viewer.GradeLevels, row.GradeLevel)
+		}
+	}
+
+	return &viewer, nil
+
+}
@@ -0,0 +1,102 @@
+package announcements
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// the limits of the attachments of one announcement
+const (
+	MaxFiles 		= 5
+	MaxUploadSize 	= 10 << 20
+)
+
+// CheckTarget reports if the payload sets exactly the field of its target, the grade level for the grade,
+// the class for the class, the role for the role and the user for the user
+func CheckTarget(payload types.CreateAnnouncement) error {
+
+	fields := []struct {
+		target string
+		set bool
+	}{
+		{types.TargetGrade, payload.GradeLevel != nil},
+		{types.TargetClass, payload.ClassId != nil},
+		{types.TargetRole, payload.TargetRole != nil},
+		{types.TargetUser, payload.UserId != nil},
+	}
+	for _, field := range fields {
+		target, set := field.target, field.set
+		if target == payload.Target && !set {
+			return errors.New("The " + target + " of the announcement is required!")
+		}
+		if target != payload.Target && set {
+			return errors.New("The " + target + " cannot be set for the target " + payload.Target + "!")
+		}
+	}
+
+	return nil
+
+}
+
+// CheckSchedule reports if the expiry of the announcement is after the publish time
+func CheckSchedule(publishAt time.Time, expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(publishAt) {
+		return errors.New("The expiry must be after the publish time!")
+	}
+	return nil
+}
+
+// StateAt returns the state of the announcement at the time, it is scheduled before the publish time and
+// expired from the expiry
+func StateAt(announcement types.Announcement, at time.Time) string {
+	if at.Before(announcement.PublishAt) {
+		return types.AnnouncementScheduled
+	}
+	if announcement.ExpiresAt != nil && !at.Before(*announcement.ExpiresAt) {
+		return types.AnnouncementExpired
+	}
+	return types.AnnouncementActive
+}
+
+// CanSee reports if the announcement is for the viewer, the nil viewer is the admin that sees every
+// announcement. The author always sees their own announcement.
+func CanSee(announcement types.Announcement, viewer *types.AnnouncementViewer) bool {
+
+	if viewer == nil {
+		return true
+	}
+	if announcement.CreatedBy != nil && *announcement.CreatedBy == viewer.UserId {
+		return true
+	}
+	switch announcement.Target {
+	case types.TargetSchool:
+		return true
+	case types.TargetRole:
+		return announcement.TargetRole != nil && *announcement.TargetRole == viewer.Role
+	case types.TargetUser:
+		return announcement.UserId != nil && *announcement.UserId == viewer.UserId
+	case types.TargetClass:
+		if announcement.ClassId == nil {
+			return false
+		}
+		for _, classId := range viewer.ClassIds {
+			if classId == *announcement.ClassId {
+				return true
+			}
+		}
+	case types.TargetGrade:
+		if announcement.GradeLevel == nil {
+			return false
+		}
+		for _, level := range viewer.GradeLevels {
+			if level == *announcement.GradeLevel {
+				return true
+			}
+		}
+	}
+
+	return false
+
+}
@@ -0,0 +1,81 @@
+package announcements
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestCheckTarget(t *testing.T) {
+	level := 10
+	role := "orangtua"
+	class := uuid.New()
+	cases := []struct {
+		payload types.CreateAnnouncement
+		ok      bool
+	}{
+		{types.CreateAnnouncement{Target: types.TargetSchool}, true},
+		{types.CreateAnnouncement{Target: types.TargetGrade, GradeLevel: &level}, true},
+		{types.CreateAnnouncement{Target: types.TargetGrade}, false},
+		{types.CreateAnnouncement{Target: types.TargetClass, ClassId: &class}, true},
+		{types.CreateAnnouncement{Target: types.TargetRole, TargetRole: &role}, true},
+		{types.CreateAnnouncement{Target: types.TargetSchool, TargetRole: &role}, false},
+		{types.CreateAnnouncement{Target: types.TargetUser, ClassId: &class}, false},
+	}
+	for i, c := range cases {
+		if err := CheckTarget(c.payload); (err == nil) != c.ok {
+			t.Fatalf("case %d: expected ok %v, got %v", i, c.ok, err)
+		}
+	}
+}
+
+func TestStateAt(t *testing.T) {
+	publish := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	expires := publish.Add(48 * time.Hour)
+	announcement := types.Announcement{PublishAt: publish, ExpiresAt: &expires}
+
+	if got := StateAt(announcement, publish.Add(-time.Minute)); got != types.AnnouncementScheduled {
+		t.Fatalf("expected scheduled, got %s", got)
+	}
+	if got := StateAt(announcement, publish); got != types.AnnouncementActive {
+		t.Fatalf("expected active, got %s", got)
+	}
+	if got := StateAt(announcement, expires); got != types.AnnouncementExpired {
+		t.Fatalf("expected expired, got %s", got)
+	}
+	if err := CheckSchedule(publish, &publish); err == nil {
+		t.Fatalf("the expiry at the publish time should fail")
+	}
+}
+
+func TestCanSee(t *testing.T) {
+	class := uuid.New()
+	other := uuid.New()
+	level := 11
+	role := "siswa"
+	viewer := &types.AnnouncementViewer{UserId: uuid.New(), Role: "orangtua", ClassIds: []uuid.UUID{class}, GradeLevels: []int{11}}
+
+	cases := []struct {
+		announcement types.Announcement
+		want         bool
+	}{
+		{types.Announcement{Target: types.TargetSchool}, true},
+		{types.Announcement{Target: types.TargetClass, ClassId: &class}, true},
+		{types.Announcement{Target: types.TargetClass, ClassId: &other}, false},
+		{types.Announcement{Target: types.TargetGrade, GradeLevel: &level}, true},
+		{types.Announcement{Target: types.TargetRole, TargetRole: &role}, false},
+		{types.Announcement{Target: types.TargetUser, UserId: &other}, false},
+		{types.Announcement{Target: types.TargetUser, UserId: &other, CreatedBy: &viewer.UserId}, true},
+	}
+	for i, c := range cases {
+		if got := CanSee(c.announcement, viewer); got != c.want {
+			t.Fatalf("case %d: expected %v, got %v", i, c.want, got)
+		}
+	}
+	if !CanSee(types.Announcement{Target: types.TargetUser, UserId: &other}, nil) {
+		t.Fatalf("the admin should see every announcement")
+	}
+}
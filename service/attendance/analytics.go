@@ -0,0 +1,92 @@
+package attendance
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// Summarize builds the summary of every student and the totals from the count of the statuses
+func Summarize(counts []types.AttendanceStatusCount) ([]types.AttendanceSummary, types.AttendanceSummary) {
+
+	var totals types.AttendanceSummary
+	students := []types.AttendanceSummary{}
+	index := map[uuid.UUID]int{}
+
+	for _, count := range counts {
+		i, ok := index[count.StudentId]
+		if !ok {
+			student_id := count.StudentId
+			students = append(students, types.AttendanceSummary{StudentId: &student_id, StudentName: count.StudentName})
+			i = len(students) - 1
+			index[count.StudentId] = i
+		}
+		addCount(&students[i], count.Status, count.Count)
+		addCount(&totals, count.Status, count.Count)
+	}
+
+	for i := range students {
+		students[i].Rate = attendanceRate(students[i])
+	}
+	totals.Rate = attendanceRate(totals)
+
+	return students, totals
+
+}
+
+//helper to add the count of one status into the summary
+func addCount(summary *types.AttendanceSummary, status string, count int) {
+	switch status {
+	case types.AttendanceHadir:
+		summary.Hadir += count
+	case types.AttendanceSakit:
+		summary.Sakit += count
+	case types.AttendanceIzin:
+		summary.Izin += count
+	case types.AttendanceAlpa:
+		summary.Alpa += count
+	case types.AttendanceLate:
+		summary.Late += count
+	default:
+		return
+	}
+	summary.Total += count
+}
+
+//helper for the rate of the presence (hadir and late) in percent with two decimals
+func attendanceRate(summary types.AttendanceSummary) float64 {
+	if summary.Total == 0 {
+		return 0
+	}
+	rate := float64(summary.Hadir + summary.Late) * 100 / float64(summary.Total)
+	return float64(int(rate * 100 + 0.5)) / 100
+}
+
+// PeriodBounds returns the first and the last day of the period of the threshold that contains the
+// date: the week starts on monday, the month on the first day, and the term is the term of the record
+func PeriodBounds(period string, date time.Time, termStart time.Time, termEnd time.Time) (time.Time, time.Time) {
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case "week":
+		offset := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 6)
+	case "month":
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, -1)
+	default:
+		return termStart, termEnd
+	}
+
+}
+
+// ThresholdStatuses returns the statuses counted by the status of the threshold
+func ThresholdStatuses(status string) []string {
+	if status == "absent" {
+		return []string{types.AttendanceSakit, types.AttendanceIzin, types.AttendanceAlpa}
+	}
+	return []string{status}
+}
@@ -0,0 +1,465 @@
+package attendance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//func to get the attendance summary of the student (?from=2025-07-01&to=2025-07-31)
+func (h *HandleRequest) StudentSummary_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the siswa cannot see the attendance of the other student, the orangtua only of their children
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	from, to, err := dateRangeParams(r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid date range!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if !h.canSeeStudent(ctx, w, r, role, student_id) {
+		return 
+	}
+	counts, err := h.db.GetStatusCounts(ctx, &student_id, nil, from, to)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the attendance summary", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attendance summary!", err.Error())
+		return 
+	}
+	_, summary := Summarize(counts)
+	summary.StudentId = &student_id
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the attendance summary has been successfully", map[string]interface{}{
+		"from": from.Format("2006-01-02"),
+		"to": to.Format("2006-01-02"),
+		"summary": summary,
+	})
+
+}
+
+//func to get the attendance summary of the class with every student (?from=2025-07-01&to=2025-07-31)
+func (h *HandleRequest) ClassSummary_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the siswa cannot see the attendance of the other student
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	from, to, err := dateRangeParams(r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid date range!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	counts, err := h.db.GetStatusCounts(ctx, nil, &class_id, from, to)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the attendance summary", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attendance summary!", err.Error())
+		return 
+	}
+	students, totals := Summarize(counts)
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the attendance summary has been successfully", types.ClassAttendanceSummary{
+		ClassId: class_id,
+		From: from.Format("2006-01-02"),
+		To: to.Format("2006-01-02"),
+		Totals: totals,
+		Students: students,
+	})
+
+}
+
+//func to create a new threshold of absences
+func (h *HandleRequest) CreateThreshold_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the thresholds
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateThreshold
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//make the struct of the threshold and execute the query
+	threshold := &types.AttendanceThreshold{
+		Id: uuid.New(),
+		Name: payload.Name,
+		Status: payload.Status,
+		Count: payload.Count,
+		Period: payload.Period,
+		IsActive: true,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CreateThreshold(ctx, threshold); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new threshold", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the threshold!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new threshold has been successfully", threshold)
+
+}
+
+//func to get all the thresholds
+func (h *HandleRequest) GetThresholds_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	thresholds, err := h.db.GetThresholds(ctx)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the thresholds!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the thresholds has been successfully", thresholds)
+
+}
+
+//func to update the threshold (name, count, is_active)
+func (h *HandleRequest) UpdateThreshold_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the thresholds
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	threshold_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.UpdateThreshold
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	threshold, err := h.db.UpdateThreshold(ctx, threshold_id, payload)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to update the threshold", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the threshold!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Update the threshold has been successfully", threshold)
+
+}
+
+//func to get the absence alerts (?class_id=&status=open), a guru sees the alerts of the own
+//homeroom classes unless ?all=true (the counselor)
+func (h *HandleRequest) GetAlerts_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the alerts
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//make the filter
+	filter := types.AlertFilter{Status: r.URL.Query().Get("status")}
+	if value := r.URL.Query().Get("class_id"); value != "" {
+		class_id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid class id!", err.Error())
+			return 
+		}
+		filter.ClassId = &class_id
+	}
+	if role == "guru" && r.URL.Query().Get("all") != "true" {
+		user_id, err := middleware.GetIdMiddleware(w, r)
+		if err != nil || user_id == uuid.Nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+			return 
+		}
+		filter.HomeroomTeacherId = &user_id
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	alerts, err := h.db.GetAlerts(ctx, filter)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the alerts", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the alerts!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the alerts has been successfully", alerts)
+
+}
+
+//func to acknowledge the alert, by the homeroom teacher of the class or the admin
+func (h *HandleRequest) AcknowledgeAlert_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the role and the user id
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	alert_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//the guru must be the homeroom teacher of the class of the alert
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	alert, err := h.db.GetAlertById(ctx, alert_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the alert!", err.Error())
+		return 
+	}
+	if alert == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The alert is not exist!", false)
+		return 
+	}
+	if role == "guru" {
+		homeroom, err := h.db.IsHomeroomTeacher(ctx, alert.ClassId, user_id)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to check the homeroom teacher!", err.Error())
+			return 
+		}
+		if !homeroom {
+			utils.ResponseError(w, http.StatusForbidden, "Only the homeroom teacher can acknowledge the alert!", false)
+			return 
+		}
+	}
+
+	//execute the query
+	if err := h.db.AcknowledgeAlert(ctx, alert_id, user_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to acknowledge the alert", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to acknowledge the alert!", err.Error())
+		return 
+	}
+	alert, err = h.db.GetAlertById(ctx, alert_id)
+	if err != nil || alert == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the alert!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Acknowledge the alert has been successfully", alert)
+
+}
@@ -0,0 +1,51 @@
+package attendance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestSummarize(t *testing.T) {
+	andi, budi := uuid.New(), uuid.New()
+	students, totals := Summarize([]types.AttendanceStatusCount{
+		{StudentId: andi, StudentName: "Andi", Status: "hadir", Count: 18},
+		{StudentId: andi, StudentName: "Andi", Status: "late", Count: 1},
+		{StudentId: andi, StudentName: "Andi", Status: "alpa", Count: 1},
+		{StudentId: budi, StudentName: "Budi", Status: "sakit", Count: 2},
+	})
+
+	if len(students) != 2 || students[0].Total != 20 || students[0].Rate != 95 {
+		t.Fatalf("got %+v", students)
+	}
+	if students[1].Sakit != 2 || students[1].Rate != 0 {
+		t.Fatalf("got %+v", students[1])
+	}
+	if totals.Total != 22 || totals.Alpa != 1 || totals.Rate != 86.36 {
+		t.Fatalf("got totals %+v", totals)
+	}
+}
+
+func TestPeriodBounds(t *testing.T) {
+	wednesday := time.Date(2025, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	start, end := PeriodBounds("week", wednesday, time.Time{}, time.Time{})
+	if start.Format("2006-01-02") != "2025-08-04" || end.Format("2006-01-02") != "2025-08-10" {
+		t.Fatalf("week got %s - %s", start, end)
+	}
+
+	start, end = PeriodBounds("month", wednesday, time.Time{}, time.Time{})
+	if start.Format("2006-01-02") != "2025-08-01" || end.Format("2006-01-02") != "2025-08-31" {
+		t.Fatalf("month got %s - %s", start, end)
+	}
+
+	termStart := time.Date(2025, time.July, 14, 0, 0, 0, 0, time.UTC)
+	termEnd := time.Date(2025, time.December, 20, 0, 0, 0, 0, time.UTC)
+	start, end = PeriodBounds("term", wednesday, termStart, termEnd)
+	if !start.Equal(termStart) || !end.Equal(termEnd) {
+		t.Fatalf("term got %s - %s", start, end)
+	}
+}
@@ -0,0 +1,964 @@
+package attendance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils/checkin"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils/qr"
+)
+
+//type handlerequest that declare the attendance store for a database logic
+type HandleRequest struct {
+	db types.AttendanceStore
+	slots types.TimetableStore
+	subjects types.SubjectStore
+	guardians types.GuardianStore
+	notifier types.Notifier
+	lockWindow time.Duration
+	checkinKey []byte
+	checkinTTL time.Duration
+	lateAfter time.Duration
+	location *time.Location
+}
+
+//func that declare the handler for attendance with the settings of the lesson lock and the check in,
+//the lessons are scheduled in the timezone of the school and the utc is used when the timezone is not valid
+func NewHandlerAttendance(db types.AttendanceStore, slots types.TimetableStore, subjects types.SubjectStore, guardians types.GuardianStore, notifier types.Notifier, cfg config.ConfigParams) *HandleRequest {
+	location, err := time.LoadLocation(cfg.SchoolTimezone)
+	if err != nil {
+		logger.Log.Warn("Invalid timezone of the school, use utc", 
+			zap.String("timezone", cfg.SchoolTimezone),
+			zap.Error(err),
+	)
+		location = time.UTC
+	}
+	return &HandleRequest{
+		db: db,
+		slots: slots,
+		subjects: subjects,
+		guardians: guardians,
+		notifier: notifier,
+		lockWindow: cfg.LessonAttendanceLock,
+		checkinKey: []byte(cfg.CheckinSigningKey),
+		checkinTTL: cfg.CheckinCodeTTL,
+		lateAfter: cfg.CheckinLateAfter,
+		location: location,
+	}
+}
+
+//helper to check if the user can see the attendance of the student, the guru and the admin can see every
+//student and the orangtua only the children that are linked to them
+func (h *HandleRequest) canSeeStudent(ctx context.Context, w http.ResponseWriter, r *http.Request, role string, studentId uuid.UUID) bool {
+	if role == "guru" || role == "admin" {
+		return true
+	}
+	if role != types.RoleOrangtua {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	linked, err := h.guardians.IsGuardianOf(ctx, user_id, studentId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the guardian of the student!", err.Error())
+		return false
+	}
+	if !linked {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check if the user can record the attendance of the class, the admin can record every class
+//and the guru only the class that they teach in the open term or the homeroom class
+func (h *HandleRequest) canRecordClass(ctx context.Context, w http.ResponseWriter, r *http.Request, role string, classId uuid.UUID) bool {
+	if role == "admin" {
+		return true
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	allowed, err := h.subjects.CanTeach(ctx, user_id, classId, nil, nil)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the teaching assignment!", err.Error())
+		return false
+	}
+	if !allowed {
+		utils.ResponseError(w, http.StatusForbidden, "You are not assigned to teach this class!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check if the user can record the attendance of the lesson, the guru must be the teacher
+//of the slot. The slot without a teacher is recorded by the guru assigned to the subject of the slot in the
+//class, or by any guru who teaches the class when the slot has no subject either
+func (h *HandleRequest) canRecordSlot(ctx context.Context, w http.ResponseWriter, r *http.Request, role string, slot *types.TimetableSlot) bool {
+	if role == "admin" {
+		return true
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	if slot.TeacherId != nil {
+		if *slot.TeacherId != user_id {
+			utils.ResponseError(w, http.StatusForbidden, "You are not assigned to teach this lesson!", false)
+			return false
+		}
+		return true
+	}
+	allowed, err := h.subjects.CanTeach(ctx, user_id, slot.ClassId, &slot.TermId, slot.SubjectId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the teaching assignment!", err.Error())
+		return false
+	}
+	if !allowed {
+		utils.ResponseError(w, http.StatusForbidden, "You are not assigned to teach this lesson!", false)
+		return false
+	}
+	return true
+}
+
+//helper to read the date range (?from=2025-07-01&to=2025-07-31), the default is the last 30 days
+func dateRangeParams(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -30)
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the date from must be before the date to")
+	}
+	return from, to, nil
+}
+
+//func to submit the attendance of the whole roster of the class in one date
+func (h *HandleRequest) SubmitAttendance_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can record the attendance
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SubmitAttendance
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	date, _ := time.Parse("2006-01-02", payload.Date)
+	if date.After(time.Now().UTC()) {
+		utils.ResponseError(w, http.StatusBadRequest, "The attendance cannot be recorded for a future date!", false)
+		return 
+	}
+
+	//make the struct of the submit
+	submit := &types.AttendanceSubmit{
+		ClassId: class_id,
+		Date: date,
+		Entries: payload.Entries,
+		Reason: payload.Reason,
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		submit.RecordedBy = &user_id
+	}
+
+	//the guru can only record the class that they teach
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	if !h.canRecordClass(ctx, w, r, role, class_id) {
+		return 
+	}
+
+	//execute the query
+	records, err := h.db.SubmitAttendance(ctx, submit)
+	if err != nil {
+		//logger if some error is detected when we want to save it
+		logger.Log.Error("Failed to submit the attendance", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to submit the attendance!", err.Error())
+		return 
+	}
+
+	//tell the students that are not present and their guardians
+	h.notifyAbsences(ctx, records, requestID)
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Submit the attendance has been successfully", records)
+
+}
+
+//func to get the attendance of the class (?date=2025-07-14), the default is today
+func (h *HandleRequest) ClassAttendance_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the siswa cannot see the attendance of the other student
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := r.URL.Query().Get("date"); value != "" {
+		date, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid date!", err.Error())
+			return 
+		}
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	records, err := h.db.GetClassAttendance(ctx, class_id, date)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the attendance of the class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attendance!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the attendance of the class has been successfully", records)
+
+}
+
+//func to get the attendance of the student (?from=2025-07-01&to=2025-07-31)
+func (h *HandleRequest) StudentAttendance_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the siswa cannot see the attendance of the other student, the orangtua only of their children
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	from, to, err := dateRangeParams(r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid date range!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if !h.canSeeStudent(ctx, w, r, role, student_id) {
+		return 
+	}
+	records, err := h.db.GetStudentAttendance(ctx, student_id, from, to)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the attendance of the student", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attendance!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the attendance of the student has been successfully", records)
+
+}
+
+//func to correct one attendance record, the reason is required
+func (h *HandleRequest) CorrectAttendance_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can correct the attendance
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	record_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CorrectAttendance
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//execute the query
+	var changed_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		changed_by = &user_id
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+
+	//the guru can only correct the attendance of the class that they teach
+	current, err := h.db.GetAttendanceRecord(ctx, record_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attendance!", err.Error())
+		return 
+	}
+	if current == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The attendance is not exist!", false)
+		return 
+	}
+	if !h.canRecordClass(ctx, w, r, role, current.ClassId) {
+		return 
+	}
+	record, err := h.db.CorrectAttendance(ctx, record_id, payload, changed_by)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to correct the attendance", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to correct the attendance!", err.Error())
+		return 
+	}
+
+	//tell the student and their guardians when the correction is an absence
+	h.notifyAbsences(ctx, []types.AttendanceRecord{*record}, requestID)
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Correct the attendance has been successfully", record)
+
+}
+
+//helper to send the event of the absence for every record that is not hadir, the attendance is already saved
+//so the failure is only logged. The same status of the same record is only notified once
+func (h *HandleRequest) notifyAbsences(ctx context.Context, records []types.AttendanceRecord, requestID string) {
+	for _, record := range records {
+		if record.Status == types.AttendanceHadir {
+			continue
+		}
+		if err := h.notifier.Notify(ctx, types.NotificationEvent{
+			Type: types.NotifyAbsenceRecorded,
+			Audience: types.NotificationAudience{StudentIds: []uuid.UUID{record.StudentId}, Guardians: true},
+			Payload: map[string]any{
+				"attendance_id": record.Id.String(),
+				"student_id": record.StudentId.String(),
+				"student_name": record.StudentName,
+				"class_id": record.ClassId.String(),
+				"status": record.Status,
+				"date": record.Date,
+			},
+			DedupeKey: "absence_recorded:" + record.Id.String() + ":" + record.Status,
+			At: time.Now().UTC(),
+		}); err != nil {
+			logger.Log.Warn("Failed to notify the absence",
+				zap.String("request_id", requestID),
+				zap.String("attendance_id", record.Id.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+//func to get the history of the corrections of one attendance record
+func (h *HandleRequest) History_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the history
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	record_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//get the record and the history
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	record, err := h.db.GetAttendanceRecord(ctx, record_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attendance!", err.Error())
+		return 
+	}
+	if record == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The attendance is not exist!", false)
+		return 
+	}
+	history, err := h.db.GetAttendanceHistory(ctx, record_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attendance history!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the attendance history has been successfully", map[string]interface{}{
+		"attendance": record,
+		"history": history,
+	})
+
+}
+
+//func to submit the attendance of one lesson of the timetable, locked after the window of the lesson
+func (h *HandleRequest) SubmitLessonAttendance_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can record the attendance, the admin can change a locked lesson
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	slot_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload, the reason is not used for a lesson
+	var payload types.SubmitAttendance
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	date, _ := time.Parse("2006-01-02", payload.Date)
+	if date.After(time.Now().UTC()) {
+		utils.ResponseError(w, http.StatusBadRequest, "The attendance cannot be recorded for a future date!", false)
+		return 
+	}
+
+	//make the struct of the submit
+	submit := &types.LessonAttendanceSubmit{
+		SlotId: slot_id,
+		Date: date,
+		Entries: payload.Entries,
+		LockWindow: h.lockWindow,
+		Location: h.location,
+		Override: role == "admin",
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		submit.RecordedBy = &user_id
+	}
+
+	//the guru can only record the lesson that they teach
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	slot, err := h.slots.GetSlotById(ctx, slot_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the lesson!", err.Error())
+		return 
+	}
+	if slot == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The lesson is not exist!", false)
+		return 
+	}
+	if !h.canRecordSlot(ctx, w, r, role, slot) {
+		return 
+	}
+
+	//execute the query
+	lessons, err := h.db.SubmitLessonAttendance(ctx, submit)
+	if err != nil {
+		//logger if some error is detected when we want to save it
+		logger.Log.Error("Failed to submit the lesson attendance", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to submit the lesson attendance!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Submit the lesson attendance has been successfully", lessons)
+
+}
+
+//func to get the attendance of one lesson (?date=2025-07-14), the default is today
+func (h *HandleRequest) LessonAttendance_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the siswa cannot see the attendance of the other student
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	slot_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := r.URL.Query().Get("date"); value != "" {
+		date, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid date!", err.Error())
+			return 
+		}
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	lessons, err := h.db.GetLessonAttendance(ctx, slot_id, date)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the lesson attendance", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the lesson attendance!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the lesson attendance has been successfully", lessons)
+
+}
+
+//helper to issue a new check in code of the lesson today, shared by the json and the qr endpoint
+func (h *HandleRequest) issueCode(w http.ResponseWriter, r *http.Request) (*types.CheckInCode, bool) {
+
+	//only guru and admin can show the check in code
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return nil, false
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return nil, false
+	}
+
+	//declare the id of the parameters
+	slot_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, false
+	}
+
+	//the lesson must be scheduled today
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	slot, err := h.slots.GetSlotById(ctx, slot_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the lesson!", err.Error())
+		return nil, false
+	}
+	if slot == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The lesson is not exist!", false)
+		return nil, false
+	}
+	if !h.canRecordSlot(ctx, w, r, role, slot) {
+		return nil, false
+	}
+	now := time.Now().In(h.location)
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	if weekday != slot.DayOfWeek {
+		utils.ResponseError(w, http.StatusBadRequest, "The lesson is not scheduled today!", false)
+		return nil, false
+	}
+
+	code, claims, err := checkin.Issue(h.checkinKey, slot.Id, now, h.checkinTTL, now)
+	if err != nil {
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to issue the check in code!", err.Error())
+		return nil, false
+	}
+
+	return &types.CheckInCode{
+		Code: code,
+		SlotId: slot.Id,
+		Date: claims.Date.Format("2006-01-02"),
+		ExpiresAt: claims.ExpiresAt,
+	}, true
+
+}
+
+//func to issue a short-lived check in code of the lesson
+func (h *HandleRequest) IssueCheckInCode_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	code, ok := h.issueCode(w, r)
+	if !ok {
+		return 
+	}
+
+	//the code must not be cached, it rotates
+	w.Header().Set("Cache-Control", "no-store")
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Issue the check in code has been successfully", code)
+
+}
+
+//func to show a new check in code of the lesson as a QR image (?format=png|svg&size=256)
+func (h *HandleRequest) CheckInQR_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//read the format before the code is issued
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" {
+		utils.ResponseError(w, http.StatusBadRequest, "The format must be png or svg!", false)
+		return 
+	}
+	size := 256
+	if value := r.URL.Query().Get("size"); value != "" {
+		if _, err := fmt.Sscanf(value, "%d", &size); err != nil || size < 64 || size > 1024 {
+			utils.ResponseError(w, http.StatusBadRequest, "The size must be between 64 and 1024!", false)
+			return 
+		}
+	}
+
+	code, ok := h.issueCode(w, r)
+	if !ok {
+		return 
+	}
+
+	//render the image
+	var image []byte
+	var err error
+	if format == "svg" {
+		image, err = qr.SVG(code.Code)
+		w.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		image, err = qr.PNG(code.Code, size)
+		w.Header().Set("Content-Type", "image/png")
+	}
+	if err != nil {
+		//logger if the render is failed
+		logger.Log.Error("Failed to render the qr code", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to render the QR code!", err.Error())
+		return 
+	}
+
+	//the code must not be cached, it rotates
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Checkin-Expires-At", code.ExpiresAt.Format(time.RFC3339))
+	w.WriteHeader(http.StatusOK)
+	w.Write(image)
+
+}
+
+//func for the siswa to check in the lesson with the code of the QR
+func (h *HandleRequest) RedeemCheckIn_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the siswa can check in
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "siswa" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.RedeemCheckIn
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//verify the signature and the expiry of the code, the lesson starts at the clock of the school
+	now := time.Now().In(h.location)
+	claims, err := checkin.Parse(h.checkinKey, payload.Code, now)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid check in code!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	lesson, err := h.db.RedeemCheckIn(ctx, &types.CheckInRedeem{
+		SlotId: claims.SlotId,
+		Date: claims.Date,
+		Nonce: claims.Nonce,
+		UserId: user_id,
+		LateAfter: h.lateAfter,
+		Now: now,
+	})
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to check in", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check in!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Check in has been successfully", lesson)
+
+}
@@ -0,0 +1,66 @@
+package attendance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//subjectStore allows every guru who teaches the class, it records the subject of the check
+type subjectStore struct {
+	types.SubjectStore
+	teaches bool
+	checked []*uuid.UUID
+}
+
+func (s *subjectStore) CanTeach(ctx context.Context, teacherId uuid.UUID, classId uuid.UUID, termId *uuid.UUID, subjectId *uuid.UUID) (bool, error) {
+	s.checked = append(s.checked, subjectId)
+	return s.teaches, nil
+}
+
+func TestCanRecordSlot(t *testing.T) {
+	guru, other := uuid.New(), uuid.New()
+	subject := uuid.New()
+
+	cases := []struct {
+		name string
+		user uuid.UUID
+		teacher *uuid.UUID
+		subject *uuid.UUID
+		want bool
+		checked bool
+	}{
+		{"the teacher of the slot", guru, &guru, nil, true, false},
+		{"another guru of the class", other, &guru, nil, false, false},
+		{"another guru of the subject", other, &guru, &subject, false, false},
+		{"the guru of the subject without a teacher", other, nil, &subject, true, true},
+		{"the guru of the class without a teacher and a subject", other, nil, nil, true, true},
+	}
+	for _, c := range cases {
+		subjects := &subjectStore{teaches: true}
+		h := &HandleRequest{subjects: subjects}
+		slot := &types.TimetableSlot{Id: uuid.New(), ClassId: uuid.New(), TermId: uuid.New(), TeacherId: c.teacher, SubjectId: c.subject}
+
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), "user_id", c.user))
+		w := httptest.NewRecorder()
+
+		if got := h.canRecordSlot(r.Context(), w, r, "guru", slot); got != c.want {
+			t.Fatalf("%s: got %v, want %v", c.name, got, c.want)
+		}
+		if !c.want && w.Code != http.StatusForbidden {
+			t.Fatalf("%s: expected forbidden, got %d", c.name, w.Code)
+		}
+		if (len(subjects.checked) > 0) != c.checked {
+			t.Fatalf("%s: expected the teaching assignment check to be %v", c.name, c.checked)
+		}
+		if c.checked && subjects.checked[0] != c.subject {
+			t.Fatalf("%s: expected the subject of the slot in the check, got %v", c.name, subjects.checked[0])
+		}
+	}
+}
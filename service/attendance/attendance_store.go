@@ -0,0 +1,833 @@
+package attendance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store attendance
+type AttendanceStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewAttendanceStore(db *sqlx.DB) *AttendanceStore {
+	return &AttendanceStore{db: db}
+}
+
+//the column that we select in every query
+const recordColumns = `
+	a.id, a.student_id, s.name AS student_name, a.class_id, a.term_id, a.date, a.status, a.note,
+	a.source, a.recorded_by, a.created_at, a.updated_at
+	FROM attendance_records a JOIN students s ON s.id = a.student_id
+`
+
+//helper to get the open term of the date
+func termOfDate(ctx context.Context, tx *sqlx.Tx, date time.Time) (uuid.UUID, error) {
+
+	var id uuid.UUID
+	err := tx.GetContext(ctx, &id, `
+		SELECT id FROM terms WHERE status = 'open' AND $1::DATE BETWEEN start_date AND end_date;
+	`, date)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("There is no open term on %s!", date.Format("2006-01-02"))
+	}
+	if err != nil {
+		return uuid.Nil, errors.New("Failed to get the term! " + err.Error())
+	}
+
+	return id, nil
+
+}
+
+//helper to get the active students of the class in the term, by the current class or the term membership
+func classMembers(ctx context.Context, tx *sqlx.Tx, classId uuid.UUID, termId uuid.UUID) (map[uuid.UUID]bool, error) {
+
+	var ids []uuid.UUID
+	if err := tx.SelectContext(ctx, &ids, `
+		SELECT s.id FROM students s
+		WHERE s.status = 'active' AND (s.class_id = $1 OR EXISTS (
+			SELECT 1 FROM class_enrollments e WHERE e.student_id = s.id AND e.class_id = $1 AND e.term_id = $2
+		));
+	`, classId, termId); err != nil {
+		return nil, errors.New("Failed to get the students of the class! " + err.Error())
+	}
+
+	members := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		members[id] = true
+	}
+
+	return members, nil
+
+}
+
+//helper to save the attendance of one student, a change of an old record is written in the history,
+//the status aggregated from the lessons never replace a status recorded by hand. The insert takes the row of
+//the student and the date in one statement, the conflict locks the old row and returns its values without
+//changing them so two submits of the same day can not both insert or both lose the old value
+func upsertRecord(ctx context.Context, tx *sqlx.Tx, record *types.AttendanceRecord, reason string, changedBy *uuid.UUID) error {
+
+	now := time.Now().UTC()
+
+	var old struct {
+		Id 			uuid.UUID 	`db:"id"`
+		ClassId 	uuid.UUID 	`db:"class_id"`
+		Status 		string 		`db:"status"`
+		Note 		string 		`db:"note"`
+		Source 		string 		`db:"source"`
+		Inserted 	bool 		`db:"inserted"`
+	}
+	if err := tx.GetContext(ctx, &old, `
+		INSERT INTO attendance_records
+		(id, student_id, class_id, term_id, date, status, note, source, recorded_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		ON CONFLICT (student_id, date) DO UPDATE SET updated_at = attendance_records.updated_at
+		RETURNING id, class_id, status, note, source, (xmax = 0) AS inserted;
+	`, uuid.New(), record.StudentId, record.ClassId, record.TermId, record.Date,
+		record.Status, record.Note, record.Source, record.RecordedBy, now); err != nil {
+		return errors.New("Failed to save the attendance! " + err.Error())
+	}
+	record.Id = old.Id
+
+	//a new record, the history keeps the original entry
+	if old.Inserted {
+		if err := insertHistory(ctx, tx, record.Id, nil, nil, record.Status, record.Note, reason, changedBy, now); err != nil {
+			return err
+		}
+		return evaluateThresholds(ctx, tx, record)
+	}
+
+	//nothing changed
+	if record.Source == types.SourceLesson && old.Source == types.SourceManual {
+		return nil
+	}
+	if old.Status == record.Status && old.Note == record.Note && old.ClassId == record.ClassId && old.Source == record.Source {
+		return nil
+	}
+
+	//the correction of the locked row, the old value stays in the history
+	var changed struct {
+		Status 		string 		`db:"status"`
+		Note 		string 		`db:"note"`
+	}
+	if err := tx.GetContext(ctx, &changed, `
+		UPDATE attendance_records SET class_id = $1, term_id = $2, status = $3, note = $4, source = $5, recorded_by = $6, updated_at = $7
+		WHERE id = $8
+		RETURNING status, note;
+	`, record.ClassId, record.TermId, record.Status, record.Note, record.Source, record.RecordedBy, now, old.Id); err != nil {
+		return errors.New("Failed to correct the attendance! " + err.Error())
+	}
+
+	if err := insertHistory(ctx, tx, old.Id, &old.Status, &old.Note, changed.Status, changed.Note, reason, changedBy, now); err != nil {
+		return err
+	}
+
+	return evaluateThresholds(ctx, tx, record)
+
+}
+
+//helper to create the alerts of the thresholds crossed by the student in the periods of the record,
+//the alert of a period is made once and only the count is updated after that
+func evaluateThresholds(ctx context.Context, tx *sqlx.Tx, record *types.AttendanceRecord) error {
+
+	var thresholds []types.AttendanceThreshold
+	if err := tx.SelectContext(ctx, &thresholds, `
+		SELECT id, name, status, count, period, is_active, created_at, updated_at
+		FROM attendance_thresholds WHERE is_active;
+	`); err != nil {
+		return errors.New("Failed to get the attendance thresholds! " + err.Error())
+	}
+	if len(thresholds) == 0 {
+		return nil
+	}
+
+	var term struct {
+		StartDate time.Time `db:"start_date"`
+		EndDate   time.Time `db:"end_date"`
+	}
+	if err := tx.GetContext(ctx, &term, `SELECT start_date, end_date FROM terms WHERE id = $1;`, record.TermId); err != nil {
+		return errors.New("Failed to get the term of the attendance! " + err.Error())
+	}
+
+	now := time.Now().UTC()
+	for _, threshold := range thresholds {
+		start, end := PeriodBounds(threshold.Period, record.Date, term.StartDate, term.EndDate)
+
+		var count int
+		if err := tx.GetContext(ctx, &count, `
+			SELECT COUNT(*) FROM attendance_records
+			WHERE student_id = $1 AND date BETWEEN $2 AND $3 AND status = ANY($4);
+		`, record.StudentId, start, end, pq.Array(ThresholdStatuses(threshold.Status))); err != nil {
+			return errors.New("Failed to count the attendance! " + err.Error())
+		}
+
+		if count >= threshold.Count {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO attendance_alerts
+				(id, threshold_id, student_id, class_id, period_start, period_end, count, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+				ON CONFLICT (threshold_id, student_id, period_start) DO UPDATE SET count = EXCLUDED.count, updated_at = EXCLUDED.updated_at;
+			`, uuid.New(), threshold.Id, record.StudentId, record.ClassId, start, end, count, now); err != nil {
+				return errors.New("Failed to save the attendance alert! " + err.Error())
+			}
+			continue
+		}
+
+		//a correction can bring the count under the threshold again
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE attendance_alerts SET count = $1, updated_at = $2
+			WHERE threshold_id = $3 AND student_id = $4 AND period_start = $5;
+		`, count, now, threshold.Id, record.StudentId, start); err != nil {
+			return errors.New("Failed to update the attendance alert! " + err.Error())
+		}
+	}
+
+	return nil
+
+}
+
+//helper to write one row of the history
+func insertHistory(ctx context.Context, tx *sqlx.Tx, recordId uuid.UUID, oldStatus, oldNote *string, newStatus, newNote, reason string, changedBy *uuid.UUID, changedAt time.Time) error {
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO attendance_history
+		(id, record_id, old_status, new_status, old_note, new_note, reason, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+	`, uuid.New(), recordId, oldStatus, newStatus, oldNote, newNote, reason, changedBy, changedAt); err != nil {
+		return errors.New("Failed to save the attendance history! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to submit the attendance of the roster in one call, all the entries are saved or none
+func (s *AttendanceStore) SubmitAttendance(ctx context.Context, submit *types.AttendanceSubmit) ([]types.AttendanceRecord, error) {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the date must be inside of the open term
+	term_id, err := termOfDate(ctx, tx, submit.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	//every student must be a member of the class
+	members, err := classMembers(ctx, tx, submit.ClassId, term_id)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uuid.UUID]bool, len(submit.Entries))
+	for _, entry := range submit.Entries {
+		if !members[entry.StudentId] {
+			return nil, fmt.Errorf("The student %s is not a member of the class!", entry.StudentId)
+		}
+		if seen[entry.StudentId] {
+			return nil, fmt.Errorf("The student %s is submitted twice!", entry.StudentId)
+		}
+		seen[entry.StudentId] = true
+	}
+
+	//save every entry
+	reason := submit.Reason
+	if reason == "" {
+		reason = "resubmitted"
+	}
+	for _, entry := range submit.Entries {
+		record := &types.AttendanceRecord{
+			StudentId: entry.StudentId,
+			ClassId: submit.ClassId,
+			TermId: term_id,
+			Date: submit.Date,
+			Status: entry.Status,
+			Note: entry.Note,
+			Source: types.SourceManual,
+			RecordedBy: submit.RecordedBy,
+		}
+		if err := upsertRecord(ctx, tx, record, reason, submit.RecordedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	//get the saved attendance of the class
+	records := []types.AttendanceRecord{}
+	if err := tx.SelectContext(ctx, &records, `
+		SELECT `+recordColumns+` WHERE a.class_id = $1 AND a.date = $2 ORDER BY s.name;
+	`, submit.ClassId, submit.Date); err != nil {
+		return nil, errors.New("Failed to get the attendance! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return records, nil
+
+}
+
+//func to get the attendance of the class in one date
+func (s *AttendanceStore) GetClassAttendance(ctx context.Context, classId uuid.UUID, date time.Time) ([]types.AttendanceRecord, error) {
+
+	//execute the query
+	records := []types.AttendanceRecord{}
+	if err := s.db.SelectContext(ctx, &records, `
+		SELECT `+recordColumns+` WHERE a.class_id = $1 AND a.date = $2 ORDER BY s.name;
+	`, classId, date); err != nil {
+		return nil, fmt.Errorf("failed to get the attendance: %w", err)
+	}
+
+	return records, nil
+
+}
+
+//func to get the attendance of the student between two dates
+func (s *AttendanceStore) GetStudentAttendance(ctx context.Context, studentId uuid.UUID, from time.Time, to time.Time) ([]types.AttendanceRecord, error) {
+
+	//execute the query
+	records := []types.AttendanceRecord{}
+	if err := s.db.SelectContext(ctx, &records, `
+		SELECT `+recordColumns+` WHERE a.student_id = $1 AND a.date BETWEEN $2 AND $3 ORDER BY a.date;
+	`, studentId, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get the attendance: %w", err)
+	}
+
+	return records, nil
+
+}
+
+//func to get one attendance record
+func (s *AttendanceStore) GetAttendanceRecord(ctx context.Context, id uuid.UUID) (*types.AttendanceRecord, error) {
+
+	//execute the query
+	var record types.AttendanceRecord
+	if err := s.db.GetContext(ctx, &record, `SELECT `+recordColumns+` WHERE a.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the attendance: %w", err)
+	}
+
+	return &record, nil
+
+}
+
+//func to correct one attendance record, the old value is kept in the history
+func (s *AttendanceStore) CorrectAttendance(ctx context.Context, id uuid.UUID, payload types.CorrectAttendance, changedBy *uuid.UUID) (*types.AttendanceRecord, error) {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	var record types.AttendanceRecord
+	if err := tx.GetContext(ctx, &record, `SELECT `+recordColumns+` WHERE a.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("The attendance is not exist!")
+		}
+		return nil, errors.New("Failed to get the attendance! " + err.Error())
+	}
+
+	record.Status = payload.Status
+	if payload.Note != nil {
+		record.Note = *payload.Note
+	}
+	record.Source = types.SourceManual
+	record.RecordedBy = changedBy
+	if err := upsertRecord(ctx, tx, &record, payload.Reason, changedBy); err != nil {
+		return nil, err
+	}
+	if err := tx.GetContext(ctx, &record, `SELECT `+recordColumns+` WHERE a.id = $1;`, id); err != nil {
+		return nil, errors.New("Failed to get the attendance! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return &record, nil
+
+}
+
+//func to get the history of the attendance record
+func (s *AttendanceStore) GetAttendanceHistory(ctx context.Context, recordId uuid.UUID) ([]types.AttendanceHistory, error) {
+
+	//base query
+	query := `
+		SELECT id, record_id, old_status, new_status, old_note, new_note, reason, changed_by, changed_at
+		FROM attendance_history WHERE record_id = $1 ORDER BY changed_at;
+	`
+
+	//execute the query
+	history := []types.AttendanceHistory{}
+	if err := s.db.SelectContext(ctx, &history, query, recordId); err != nil {
+		return nil, fmt.Errorf("failed to get the attendance history: %w", err)
+	}
+
+	return history, nil
+
+}
+
+//the column that we select for the lesson attendance
+const lessonColumns = `
+	l.id, l.slot_id, l.student_id, s.name AS student_name, t.subject, l.date, l.status, l.note,
+	l.recorded_by, l.created_at, l.updated_at
+	FROM lesson_attendance l
+	JOIN students s ON s.id = l.student_id
+	JOIN timetable_slots t ON t.id = l.slot_id
+`
+
+//helper to get the slot of the timetable, the slot must be scheduled on the day of the date
+func lessonOfDate(ctx context.Context, tx *sqlx.Tx, slotId uuid.UUID, date time.Time) (types.TimetableSlot, error) {
+
+	var slot types.TimetableSlot
+	if err := tx.GetContext(ctx, &slot, `
+		SELECT id, class_id, term_id, day_of_week, start_time, end_time, subject, subject_id, teacher_id, period_id, room_id, is_pinned, created_at, updated_at
+		FROM timetable_slots WHERE id = $1;
+	`, slotId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return slot, errors.New("The lesson is not exist!")
+		}
+		return slot, errors.New("Failed to get the lesson! " + err.Error())
+	}
+
+	weekday := int(date.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	if weekday != slot.DayOfWeek {
+		return slot, fmt.Errorf("The lesson is not scheduled on %s!", date.Format("2006-01-02"))
+	}
+
+	return slot, nil
+
+}
+
+//helper to save the attendance of the student in the lesson and aggregate the daily status again
+func saveLessonEntry(ctx context.Context, tx *sqlx.Tx, slot *types.TimetableSlot, termId uuid.UUID, date time.Time, entry types.AttendanceEntry, recordedBy *uuid.UUID) error {
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO lesson_attendance (id, slot_id, student_id, date, status, note, recorded_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (slot_id, student_id, date) DO UPDATE
+		SET status = EXCLUDED.status, note = EXCLUDED.note, recorded_by = EXCLUDED.recorded_by, updated_at = EXCLUDED.updated_at;
+	`, uuid.New(), slot.Id, entry.StudentId, date, entry.Status, entry.Note, recordedBy, time.Now().UTC()); err != nil {
+		return errors.New("Failed to save the lesson attendance! " + err.Error())
+	}
+
+	//the daily status is made from every lesson of the day in the order of the time
+	var statuses []string
+	if err := tx.SelectContext(ctx, &statuses, `
+		SELECT l.status FROM lesson_attendance l JOIN timetable_slots t ON t.id = l.slot_id
+		WHERE l.student_id = $1 AND l.date = $2 ORDER BY t.start_time;
+	`, entry.StudentId, date); err != nil {
+		return errors.New("Failed to get the lessons of the day! " + err.Error())
+	}
+	record := &types.AttendanceRecord{
+		StudentId: entry.StudentId,
+		ClassId: slot.ClassId,
+		TermId: termId,
+		Date: date,
+		Status: AggregateDaily(statuses),
+		Source: types.SourceLesson,
+		RecordedBy: recordedBy,
+	}
+
+	return upsertRecord(ctx, tx, record, "aggregated from the lesson attendance", recordedBy)
+
+}
+
+//func to submit the attendance of one lesson, the daily status of the students is aggregated again
+func (s *AttendanceStore) SubmitLessonAttendance(ctx context.Context, submit *types.LessonAttendanceSubmit) ([]types.LessonAttendance, error) {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the lesson must be in the timetable on the day of the date
+	slot, err := lessonOfDate(ctx, tx, submit.SlotId, submit.Date)
+	if err != nil {
+		return nil, err
+	}
+	if !submit.Override && LessonLocked(submit.Date, slot.EndTime, submit.LockWindow, time.Now(), submit.Location) {
+		return nil, errors.New("The attendance of the lesson has been locked!")
+	}
+
+	//the date must be inside of the open term of the lesson
+	term_id, err := termOfDate(ctx, tx, submit.Date)
+	if err != nil {
+		return nil, err
+	}
+	if term_id != slot.TermId {
+		return nil, errors.New("The lesson is not in the open term!")
+	}
+
+	//every student must be a member of the class
+	members, err := classMembers(ctx, tx, slot.ClassId, term_id)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uuid.UUID]bool, len(submit.Entries))
+	for _, entry := range submit.Entries {
+		if !members[entry.StudentId] {
+			return nil, fmt.Errorf("The student %s is not a member of the class!", entry.StudentId)
+		}
+		if seen[entry.StudentId] {
+			return nil, fmt.Errorf("The student %s is submitted twice!", entry.StudentId)
+		}
+		seen[entry.StudentId] = true
+	}
+
+	//save every entry
+	for _, entry := range submit.Entries {
+		if err := saveLessonEntry(ctx, tx, &slot, term_id, submit.Date, entry, submit.RecordedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	//get the saved attendance of the lesson
+	lessons := []types.LessonAttendance{}
+	if err := tx.SelectContext(ctx, &lessons, `
+		SELECT `+lessonColumns+` WHERE l.slot_id = $1 AND l.date = $2 ORDER BY s.name;
+	`, slot.Id, submit.Date); err != nil {
+		return nil, errors.New("Failed to get the lesson attendance! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return lessons, nil
+
+}
+
+//func to get the attendance of one lesson in one date
+func (s *AttendanceStore) GetLessonAttendance(ctx context.Context, slotId uuid.UUID, date time.Time) ([]types.LessonAttendance, error) {
+
+	//execute the query
+	lessons := []types.LessonAttendance{}
+	if err := s.db.SelectContext(ctx, &lessons, `
+		SELECT `+lessonColumns+` WHERE l.slot_id = $1 AND l.date = $2 ORDER BY s.name;
+	`, slotId, date); err != nil {
+		return nil, fmt.Errorf("failed to get the lesson attendance: %w", err)
+	}
+
+	return lessons, nil
+
+}
+
+//func to check in the student of the user with a code of the lesson, every code can be used
+//only once by a student and the student can check in only once in the lesson
+func (s *AttendanceStore) RedeemCheckIn(ctx context.Context, redeem *types.CheckInRedeem) (*types.LessonAttendance, error) {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the student of the caller
+	var student_id uuid.UUID
+	if err := tx.GetContext(ctx, &student_id, `
+		SELECT id FROM students WHERE user_id = $1 AND status = 'active';
+	`, redeem.UserId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("The user is not registered as an active student!")
+		}
+		return nil, errors.New("Failed to get the student! " + err.Error())
+	}
+
+	//the lesson of the code
+	slot, err := lessonOfDate(ctx, tx, redeem.SlotId, redeem.Date)
+	if err != nil {
+		return nil, err
+	}
+	term_id, err := termOfDate(ctx, tx, redeem.Date)
+	if err != nil {
+		return nil, err
+	}
+	if term_id != slot.TermId {
+		return nil, errors.New("The lesson is not in the open term!")
+	}
+	members, err := classMembers(ctx, tx, slot.ClassId, term_id)
+	if err != nil {
+		return nil, err
+	}
+	if !members[student_id] {
+		return nil, errors.New("The student is not a member of the class of the lesson!")
+	}
+
+	//anti replay, the same code cannot be redeemed twice by the student
+	rows, err := tx.ExecContext(ctx, `
+		INSERT INTO checkin_redemptions (nonce, student_id, slot_id, date, redeemed_at)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (nonce, student_id) DO NOTHING;
+	`, redeem.Nonce, student_id, slot.Id, redeem.Date, redeem.Now)
+	if err != nil {
+		return nil, errors.New("Failed to save the check in! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return nil, errors.New("The code has been already used!")
+	}
+	var checked bool
+	if err := tx.GetContext(ctx, &checked, `
+		SELECT EXISTS (SELECT 1 FROM lesson_attendance WHERE slot_id = $1 AND student_id = $2 AND date = $3);
+	`, slot.Id, student_id, redeem.Date); err != nil {
+		return nil, errors.New("Failed to check the lesson attendance! " + err.Error())
+	}
+	if checked {
+		return nil, errors.New("The attendance of the lesson has been already recorded!")
+	}
+
+	//late when the check in is after the start of the lesson and the grace time
+	entry := types.AttendanceEntry{StudentId: student_id, Status: types.AttendanceHadir, Note: "check in"}
+	start := slot.StartTime
+	if len(start) > 5 {
+		start = start[:5]
+	}
+	if begin, err := time.Parse("15:04", start); err == nil {
+		lessonStart := time.Date(redeem.Date.Year(), redeem.Date.Month(), redeem.Date.Day(), begin.Hour(), begin.Minute(), 0, 0, redeem.Now.Location())
+		if redeem.Now.After(lessonStart.Add(redeem.LateAfter)) {
+			entry.Status = types.AttendanceLate
+		}
+	}
+	if err := saveLessonEntry(ctx, tx, &slot, term_id, redeem.Date, entry, &redeem.UserId); err != nil {
+		return nil, err
+	}
+
+	var lesson types.LessonAttendance
+	if err := tx.GetContext(ctx, &lesson, `
+		SELECT `+lessonColumns+` WHERE l.slot_id = $1 AND l.student_id = $2 AND l.date = $3;
+	`, slot.Id, student_id, redeem.Date); err != nil {
+		return nil, errors.New("Failed to get the lesson attendance! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return &lesson, nil
+
+}
+
+//func to count the statuses of the student or of the class between two dates
+func (s *AttendanceStore) GetStatusCounts(ctx context.Context, studentId *uuid.UUID, classId *uuid.UUID, from time.Time, to time.Time) ([]types.AttendanceStatusCount, error) {
+
+	//base query
+	query := `
+		SELECT a.student_id, s.name AS student_name, a.status, COUNT(*) AS count
+		FROM attendance_records a JOIN students s ON s.id = a.student_id
+		WHERE a.date BETWEEN $1 AND $2
+		AND ($3::UUID IS NULL OR a.student_id = $3)
+		AND ($4::UUID IS NULL OR a.class_id = $4)
+		GROUP BY a.student_id, s.name, a.status
+		ORDER BY s.name, a.student_id, a.status;
+	`
+
+	//execute the query
+	counts := []types.AttendanceStatusCount{}
+	if err := s.db.SelectContext(ctx, &counts, query, from, to, studentId, classId); err != nil {
+		return nil, fmt.Errorf("failed to count the attendance: %w", err)
+	}
+
+	return counts, nil
+
+}
+
+//the column that we select for the threshold
+const thresholdColumns = `id, name, status, count, period, is_active, created_at, updated_at`
+
+//func to create a new threshold of absences
+func (s *AttendanceStore) CreateThreshold(ctx context.Context, threshold *types.AttendanceThreshold) error {
+
+	//base query
+	query := `
+		INSERT INTO attendance_thresholds (id, name, status, count, period, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		threshold.Id,
+		threshold.Name,
+		threshold.Status,
+		threshold.Count,
+		threshold.Period,
+		threshold.IsActive,
+		threshold.Created_at,
+		threshold.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new threshold! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get all the thresholds
+func (s *AttendanceStore) GetThresholds(ctx context.Context) ([]types.AttendanceThreshold, error) {
+
+	//execute the query
+	thresholds := []types.AttendanceThreshold{}
+	if err := s.db.SelectContext(ctx, &thresholds, `SELECT `+thresholdColumns+` FROM attendance_thresholds ORDER BY created_at;`); err != nil {
+		return nil, fmt.Errorf("failed to get the thresholds: %w", err)
+	}
+
+	return thresholds, nil
+
+}
+
+//func to update the threshold
+func (s *AttendanceStore) UpdateThreshold(ctx context.Context, id uuid.UUID, payload types.UpdateThreshold) (*types.AttendanceThreshold, error) {
+
+	//setup the args and args id
+	var settings []string
+	argsId := 1
+	var args []interface{}
+
+	if payload.Name != nil {
+		settings = append(settings, fmt.Sprintf("name=$%d", argsId))
+		args = append(args, *payload.Name)
+		argsId++
+	}
+	if payload.Count != nil {
+		settings = append(settings, fmt.Sprintf("count=$%d", argsId))
+		args = append(args, *payload.Count)
+		argsId++
+	}
+	if payload.IsActive != nil {
+		settings = append(settings, fmt.Sprintf("is_active=$%d", argsId))
+		args = append(args, *payload.IsActive)
+		argsId++
+	}
+	if len(settings) == 0 {
+		return nil, errors.New("Nothing to update!")
+	}
+	settings = append(settings, fmt.Sprintf("updated_at=$%d", argsId))
+	args = append(args, time.Now().UTC())
+	argsId++
+	args = append(args, id)
+
+	//execute the query
+	query := fmt.Sprintf(`UPDATE attendance_thresholds SET %s WHERE id=$%d RETURNING `+thresholdColumns+`;`, strings.Join(settings, ", "), argsId)
+	var threshold types.AttendanceThreshold
+	if err := s.db.GetContext(ctx, &threshold, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("The threshold is not exist!")
+		}
+		return nil, errors.New("Failed to update the threshold! " + err.Error())
+	}
+
+	return &threshold, nil
+
+}
+
+//the column that we select for the alert
+const alertColumns = `
+	al.id, al.threshold_id, t.name AS threshold_name, al.student_id, s.name AS student_name,
+	al.class_id, c.name AS class_name, al.period_start, al.period_end, al.count, al.status,
+	al.acknowledged_by, al.acknowledged_at, al.created_at, al.updated_at
+	FROM attendance_alerts al
+	JOIN attendance_thresholds t ON t.id = al.threshold_id
+	JOIN students s ON s.id = al.student_id
+	JOIN classes c ON c.id = al.class_id
+`
+
+//func to get the alerts, the alerts under the threshold after a correction are not shown
+func (s *AttendanceStore) GetAlerts(ctx context.Context, filter types.AlertFilter) ([]types.AttendanceAlert, error) {
+
+	//base query
+	query := `
+		SELECT ` + alertColumns + `
+		WHERE al.count >= t.count
+		AND ($1::UUID IS NULL OR al.class_id = $1)
+		AND ($2 = '' OR al.status = $2)
+		AND ($3::UUID IS NULL OR c.homeroom_teacher_id = $3)
+		ORDER BY al.status DESC, al.updated_at DESC;
+	`
+
+	//execute the query
+	alerts := []types.AttendanceAlert{}
+	if err := s.db.SelectContext(ctx, &alerts, query, filter.ClassId, filter.Status, filter.HomeroomTeacherId); err != nil {
+		return nil, fmt.Errorf("failed to get the alerts: %w", err)
+	}
+
+	return alerts, nil
+
+}
+
+//func to get the alert by id
+func (s *AttendanceStore) GetAlertById(ctx context.Context, id uuid.UUID) (*types.AttendanceAlert, error) {
+
+	//execute the query
+	var alert types.AttendanceAlert
+	if err := s.db.GetContext(ctx, &alert, `SELECT `+alertColumns+` WHERE al.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the alert: %w", err)
+	}
+
+	return &alert, nil
+
+}
+
+//func to acknowledge the alert
+func (s *AttendanceStore) AcknowledgeAlert(ctx context.Context, id uuid.UUID, by uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `
+		UPDATE attendance_alerts SET status = 'acknowledged', acknowledged_by = $1, acknowledged_at = $2, updated_at = $2
+		WHERE id = $3 AND status = 'open';
+	`, by, time.Now().UTC(), id)
+	if err != nil {
+		return errors.New("Failed to acknowledge the alert! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The alert is not exist or already acknowledged!")
+	}
+
+	return nil
+
+}
+
+//func to check if the user is the homeroom teacher of the class
+func (s *AttendanceStore) IsHomeroomTeacher(ctx context.Context, classId uuid.UUID, userId uuid.UUID) (bool, error) {
+
+	//execute the query
+	var exists bool
+	if err := s.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (SELECT 1 FROM classes WHERE id = $1 AND homeroom_teacher_id = $2);
+	`, classId, userId); err != nil {
+		return false, fmt.Errorf("failed to check the homeroom teacher: %w", err)
+	}
+
+	return exists, nil
+
+}
@@ -0,0 +1,140 @@
+package attendance
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+var upsertRow = []string{"id", "class_id", "status", "note", "source", "inserted"}
+
+//helper to run the upsert of the record in one transaction, the thresholds are not active
+func runUpsert(t *testing.T, fake *dbtest.DB, store *AttendanceStore, record *types.AttendanceRecord) {
+	t.Helper()
+	fake.On("FROM attendance_thresholds WHERE is_active", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"id", "name", "status", "count", "period", "is_active", "created_at", "updated_at"})
+	})
+	fake.On("INSERT INTO attendance_history", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+
+	tx, err := store.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if err := upsertRecord(context.Background(), tx, record, "koreksi", nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newRecord(status string, source string) *types.AttendanceRecord {
+	return &types.AttendanceRecord{
+		StudentId: uuid.New(),
+		ClassId: uuid.New(),
+		TermId: uuid.New(),
+		Date: time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC),
+		Status: status,
+		Source: source,
+	}
+}
+
+func TestUpsertRecordInsertsTheOriginalEntry(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewAttendanceStore(db)
+
+	id := uuid.New()
+	record := newRecord("hadir", types.SourceManual)
+	fake.On("ON CONFLICT (student_id, date) DO UPDATE", func(args []any) dbtest.Result {
+		return dbtest.Rows(upsertRow, []driver.Value{id.String(), record.ClassId.String(), "hadir", "", types.SourceManual, true})
+	})
+	runUpsert(t, fake, store, record)
+
+	if record.Id != id || fake.Count("UPDATE attendance_records SET") != 0 {
+		t.Fatalf("expected only the insert of the record")
+	}
+	history := fake.Statements()[fake.Index("INSERT INTO attendance_history", 0)]
+	if history.Args[2] != (*string)(nil) || history.Args[3] != "hadir" {
+		t.Fatalf("expected the original entry in the history, got %v", history.Args)
+	}
+}
+
+func TestUpsertRecordCorrectsFromTheOldValues(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewAttendanceStore(db)
+
+	id := uuid.New()
+	record := newRecord("sakit", types.SourceManual)
+	fake.On("ON CONFLICT (student_id, date) DO UPDATE", func(args []any) dbtest.Result {
+		return dbtest.Rows(upsertRow, []driver.Value{id.String(), record.ClassId.String(), "alpa", "", types.SourceManual, false})
+	})
+	fake.On("UPDATE attendance_records SET", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"status", "note"}, []driver.Value{"sakit", ""})
+	})
+	runUpsert(t, fake, store, record)
+
+	//the conflict returns the locked row and the correction is written from the old and the new values
+	upsert := fake.Index("ON CONFLICT (student_id, date) DO UPDATE", 0)
+	update := fake.Index("UPDATE attendance_records SET", upsert+1)
+	history := fake.Index("INSERT INTO attendance_history", update)
+	if upsert < 0 || update < 0 || history < 0 {
+		t.Fatalf("expected the upsert, the correction and the history, got %v", fake.Statements())
+	}
+	args := fake.Statements()[history].Args
+	if old := args[2].(*string); *old != "alpa" || args[3] != "sakit" {
+		t.Fatalf("expected the history from alpa to sakit, got %v %v", *old, args[3])
+	}
+}
+
+func TestUpsertRecordKeepsTheManualStatus(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewAttendanceStore(db)
+
+	record := newRecord("alpa", types.SourceLesson)
+	fake.On("ON CONFLICT (student_id, date) DO UPDATE", func(args []any) dbtest.Result {
+		return dbtest.Rows(upsertRow, []driver.Value{uuid.NewString(), record.ClassId.String(), "izin", "", types.SourceManual, false})
+	})
+	runUpsert(t, fake, store, record)
+
+	if fake.Count("UPDATE attendance_records SET") != 0 || fake.Count("INSERT INTO attendance_history") != 0 {
+		t.Fatalf("the lesson status should not replace the manual status")
+	}
+}
+
+func TestRedeemCheckInRefusesTheLessonOfAnotherTerm(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewAttendanceStore(db)
+
+	//the code is for a lesson of the previous term, on a monday of the open term
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	now := time.Now().UTC()
+	fake.On("SELECT id FROM students WHERE user_id", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"id"}, []driver.Value{uuid.NewString()})
+	})
+	fake.On("FROM timetable_slots WHERE id = $1", func(args []any) dbtest.Result {
+		return dbtest.Rows(
+			[]string{"id", "class_id", "term_id", "day_of_week", "start_time", "end_time", "subject", "subject_id", "teacher_id", "period_id", "room_id", "is_pinned", "created_at", "updated_at"},
+			[]driver.Value{uuid.NewString(), uuid.NewString(), uuid.NewString(), int64(1), "07:00:00", "08:30:00", "Matematika", nil, nil, nil, nil, false, now, now},
+		)
+	})
+	fake.On("SELECT id FROM terms WHERE status = 'open'", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"id"}, []driver.Value{uuid.NewString()})
+	})
+
+	_, err := store.RedeemCheckIn(context.Background(), &types.CheckInRedeem{
+		SlotId: uuid.New(), Date: date, Nonce: "nonce", UserId: uuid.New(), Now: date.Add(7 * time.Hour),
+	})
+	if err == nil || err.Error() != "The lesson is not in the open term!" {
+		t.Fatalf("expected the lesson of another term to be refused, got %v", err)
+	}
+	if fake.Count("INSERT INTO checkin_redemptions") != 0 || fake.Count("INSERT INTO lesson_attendance") != 0 {
+		t.Fatalf("expected nothing to be recorded")
+	}
+}
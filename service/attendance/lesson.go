@@ -0,0 +1,64 @@
+package attendance
+
+import (
+	"time"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// AggregateDaily makes the daily status from the statuses of the lessons ordered by the time.
+// The student is present (hadir) when present in any lesson, and late when the first lesson is
+// missed or late. Without any presence the daily status is sakit, then izin, then alpa.
+func AggregateDaily(statuses []string) string {
+
+	if len(statuses) == 0 {
+		return ""
+	}
+
+	present := false
+	excused := ""
+	for _, status := range statuses {
+		switch status {
+		case types.AttendanceHadir, types.AttendanceLate:
+			present = true
+		case types.AttendanceSakit:
+			excused = types.AttendanceSakit
+		case types.AttendanceIzin:
+			if excused == "" {
+				excused = types.AttendanceIzin
+			}
+		}
+	}
+
+	if present {
+		if statuses[0] == types.AttendanceHadir {
+			return types.AttendanceHadir
+		}
+		return types.AttendanceLate
+	}
+	if excused != "" {
+		return excused
+	}
+	return types.AttendanceAlpa
+
+}
+
+// LessonLocked reports if the attendance of the lesson can not be changed anymore, the lock starts
+// when the window after the end of the lesson is over. The end time is the clock of the school in the location,
+// the utc is used when the location is nil.
+func LessonLocked(date time.Time, endTime string, window time.Duration, now time.Time, location *time.Location) bool {
+
+	end, err := time.Parse("15:04:05", endTime)
+	if err != nil {
+		if end, err = time.Parse("15:04", endTime); err != nil {
+			return true
+		}
+	}
+
+	if location == nil {
+		location = time.UTC
+	}
+	lessonEnd := time.Date(date.Year(), date.Month(), date.Day(), end.Hour(), end.Minute(), end.Second(), 0, location)
+	return now.After(lessonEnd.Add(window))
+
+}
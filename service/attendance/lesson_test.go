@@ -0,0 +1,58 @@
+package attendance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateDaily(t *testing.T) {
+	cases := []struct {
+		statuses []string
+		want     string
+	}{
+		{nil, ""},
+		{[]string{"hadir", "hadir", "alpa"}, "hadir"},
+		{[]string{"alpa", "hadir", "hadir"}, "late"},
+		{[]string{"late", "hadir"}, "late"},
+		{[]string{"izin", "sakit", "alpa"}, "sakit"},
+		{[]string{"alpa", "izin"}, "izin"},
+		{[]string{"alpa", "alpa"}, "alpa"},
+	}
+	for _, c := range cases {
+		if got := AggregateDaily(c.statuses); got != c.want {
+			t.Fatalf("%v: got %q, want %q", c.statuses, got, c.want)
+		}
+	}
+}
+
+func TestLessonLocked(t *testing.T) {
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, time.August, 4, 11, 30, 0, 0, time.UTC)
+	after := time.Date(2025, time.August, 4, 12, 30, 0, 0, time.UTC)
+
+	if LessonLocked(date, "10:00:00", 2*time.Hour, before, time.UTC) {
+		t.Fatalf("the lesson should be still open")
+	}
+	if !LessonLocked(date, "10:00:00", 2*time.Hour, after, time.UTC) {
+		t.Fatalf("the lesson should be locked")
+	}
+	if !LessonLocked(date, "", time.Hour, before, time.UTC) {
+		t.Fatalf("an invalid end time should be locked")
+	}
+}
+
+func TestLessonLockedAtTheClockOfTheSchool(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	date := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	//the lesson ends at 10:00 WIB (03:00 UTC) and locks at 12:00 WIB (05:00 UTC), the server runs in utc
+	open := time.Date(2025, time.August, 4, 4, 30, 0, 0, time.UTC)
+	locked := time.Date(2025, time.August, 4, 5, 30, 0, 0, time.UTC)
+
+	if LessonLocked(date, "10:00:00", 2*time.Hour, open, jakarta) {
+		t.Fatalf("the lesson should be still open at 11:30 WIB")
+	}
+	if !LessonLocked(date, "10:00:00", 2*time.Hour, locked, jakarta) {
+		t.Fatalf("the lesson should be locked at 12:30 WIB")
+	}
+}
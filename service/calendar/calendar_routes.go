@@ -0,0 +1,476 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils/ical"
+)
+
+//type handlerequest that declare the calendar store for a database logic
+type HandleRequest struct {
+	db types.CalendarStore
+	location *time.Location
+	feedURL string
+}
+
+//func that declare the handler for calendar with the timezone of the school and the base url of the feed,
+//the utc is used when the timezone is not valid
+func NewHandlerCalendar(db types.CalendarStore, cfg config.ConfigParams) *HandleRequest {
+	location, err := time.LoadLocation(cfg.SchoolTimezone)
+	if err != nil {
+		logger.Log.Warn("Invalid timezone of the school, use utc", 
+			zap.String("timezone", cfg.SchoolTimezone),
+			zap.Error(err),
+	)
+		location = time.UTC
+	}
+	return &HandleRequest{
+		db: db,
+		location: location,
+		feedURL: strings.TrimRight(cfg.CalendarFeedURL, "/"),
+	}
+}
+
+//helper to hash the feed token, only the hash is saved in the database
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+//helper to get the audience of the events from the role, the admin can see every event
+func audienceOf(role string) string {
+	switch role {
+	case "guru":
+		return types.AudienceGuru
+	case "siswa":
+		return types.AudienceSiswa
+	case types.RoleOrangtua:
+		return types.AudienceOrangtua
+	}
+	return ""
+}
+
+//func to make a new feed token of the user, the old feed url is not valid anymore
+func (h *HandleRequest) CreateFeedToken_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the user id from the token
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+
+	//make the random token
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to make the token!", err.Error())
+		return 
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.SaveFeedToken(ctx, user_id, hashToken(token)); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the feed token", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to save the feed token!", err.Error())
+		return 
+	}
+
+	//return a final result, the token is only shown once
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the feed token has been successfully", types.CalendarFeedToken{
+		Token: token,
+		URL: h.feedURL + "/" + token + ".ics",
+	})
+
+}
+
+//func to delete the feed token of the user
+func (h *HandleRequest) DeleteFeedToken_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the user id from the token
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.DeleteFeedToken(ctx, user_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the feed token", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the feed token!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the feed token has been successfully", user_id)
+
+}
+
+//func to create a new event of the school calendar
+func (h *HandleRequest) CreateEvent_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the school calendar
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateCalendarEvent
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//the end date is the start date for the event of one day
+	start_date, _ := time.Parse("2006-01-02", payload.StartDate)
+	end_date := start_date
+	if payload.EndDate != "" {
+		end_date, _ = time.Parse("2006-01-02", payload.EndDate)
+	}
+	if end_date.Before(start_date) {
+		utils.ResponseError(w, http.StatusBadRequest, "The end date must not be before the start date!", false)
+		return 
+	}
+	if payload.StartTime != nil && start_date.Equal(end_date) && *payload.EndTime <= *payload.StartTime {
+		utils.ResponseError(w, http.StatusBadRequest, "The end time must be after the start time!", false)
+		return 
+	}
+	if payload.Audience == "" {
+		payload.Audience = types.AudienceAll
+	}
+
+	//make the struct of the event and execute the query
+	event := &types.CalendarEvent{
+		Id: uuid.New(),
+		Title: payload.Title,
+		Description: payload.Description,
+		StartDate: start_date,
+		EndDate: end_date,
+		StartTime: payload.StartTime,
+		EndTime: payload.EndTime,
+		IsHoliday: payload.IsHoliday,
+		Audience: payload.Audience,
+		CreatedBy: &user_id,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CreateEvent(ctx, event); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new event", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the event!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new event has been successfully", event)
+
+}
+
+//func to get the events of the school calendar (?from=&to=), the default is the current month
+func (h *HandleRequest) GetEvents_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the user only see the events of their audience
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+
+	//declare the range of the dates
+	now := time.Now().In(h.location)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1)
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid from date, use 2006-01-02!", err.Error())
+			return 
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid to date, use 2006-01-02!", err.Error())
+			return 
+		}
+	}
+	if to.Before(from) {
+		utils.ResponseError(w, http.StatusBadRequest, "The to date must not be before the from date!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	events, err := h.db.GetEvents(ctx, from, to, audienceOf(role))
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the events", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the events!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the events has been successfully", events)
+
+}
+
+//func to delete the event of the school calendar
+func (h *HandleRequest) DeleteEvent_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the school calendar
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	event_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.DeleteEvent(ctx, event_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the event", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the event!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the event has been successfully", event_id)
+
+}
+
+//func to serve the .ics feed of the owner of the token, the feed is public so the calendar apps can
+//subscribe to it without the login, the token is the secret of the url
+func (h *HandleRequest) Feed_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//find the owner of the token
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	owner, err := h.db.GetFeedOwner(ctx, hashToken(mux.Vars(r)["token"]))
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the feed!", err.Error())
+		return 
+	}
+	if owner == nil {
+		//logger the unknown token, it can be a revoked url
+		logger.Log.Warn("Unknown feed token", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusNotFound, "The feed is not exist!", false)
+		return 
+	}
+
+	//the lessons of the open terms, the events and the exams from a half year ago until a year later
+	lessons, err := h.db.GetLessons(ctx, owner)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the lessons!", err.Error())
+		return 
+	}
+	now := time.Now().In(h.location)
+	from := now.AddDate(0, -6, 0)
+	to := now.AddDate(1, 0, 0)
+	for _, lesson := range lessons {
+		if lesson.TermStart.Before(from) {
+			from = lesson.TermStart
+		}
+		if lesson.TermEnd.After(to) {
+			to = lesson.TermEnd
+		}
+	}
+	calendarEvents, err := h.db.GetEvents(ctx, from, to, audienceOf(owner.Role))
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the events of the feed", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the events!", err.Error())
+		return 
+	}
+
+	exams, err := h.db.GetExams(ctx, owner, from, to)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the exams of the feed", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the exams!", err.Error())
+		return 
+	}
+
+	//write the calendar
+	events := LessonEvents(lessons, calendarEvents, h.location)
+	events = append(events, SchoolEvents(calendarEvents, h.location)...)
+	events = append(events, ExamEvents(exams, h.location)...)
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="schedule.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	if err := ical.Write(w, ical.Calendar{
+		Name: "Jadwal " + owner.Username,
+		Stamp: time.Now().UTC(),
+		Events: events,
+	}); err != nil {
+		logger.Log.Error("Failed to write the feed", 
+			zap.String("request_id", requestID),
+			zap.Error(err),
+	)
+	}
+
+}
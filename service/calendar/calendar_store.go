@@ -0,0 +1,288 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store calendar
+type CalendarStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewCalendarStore(db *sqlx.DB) *CalendarStore {
+	return &CalendarStore{db: db}
+}
+
+//the column of the event that we select in every query
+const eventColumns = `id, title, description, start_date, end_date, start_time, end_time, is_holiday, audience, created_by, created_at, updated_at`
+
+//the column of the lesson of the feed with the class, the room and the dates of the term
+const lessonColumns = `
+	ts.id AS slot_id, c.name AS class_name, ts.subject, ts.day_of_week, ts.start_time, ts.end_time,
+	COALESCE(r.code, '') AS room_code, t.start_date AS term_start, t.end_date AS term_end
+`
+
+//the join of the lesson, only the terms that are not closed yet
+const lessonJoins = `
+	FROM timetable_slots ts
+	JOIN terms t ON t.id = ts.term_id AND t.status <> 'closed'
+	JOIN classes c ON c.id = ts.class_id
+	LEFT JOIN rooms r ON r.id = ts.room_id
+`
+
+//func to create a new event of the school calendar
+func (s *CalendarStore) CreateEvent(ctx context.Context, event *types.CalendarEvent) error {
+
+	//base query
+	query := `
+		INSERT INTO calendar_events 
+		(id, title, description, start_date, end_date, start_time, end_time, is_holiday, audience, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		event.Id,
+		event.Title,
+		event.Description,
+		event.StartDate,
+		event.EndDate,
+		event.StartTime,
+		event.EndTime,
+		event.IsHoliday,
+		event.Audience,
+		event.CreatedBy,
+		event.Created_at,
+		event.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new event! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the event by id
+func (s *CalendarStore) GetEventById(ctx context.Context, id uuid.UUID) (*types.CalendarEvent, error) {
+
+	//execute the query
+	var event types.CalendarEvent
+	if err := s.db.GetContext(ctx, &event, `SELECT `+eventColumns+` FROM calendar_events WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the event: %w", err)
+	}
+
+	return &event, nil
+
+}
+
+//func to get the events that touch the range of the dates, the empty audience gets the events of every audience
+//and the other audience gets its own events and the events for all
+func (s *CalendarStore) GetEvents(ctx context.Context, from time.Time, to time.Time, audience string) ([]types.CalendarEvent, error) {
+
+	//base query
+	query := `
+		SELECT ` + eventColumns + ` FROM calendar_events
+		WHERE start_date <= $2 AND end_date >= $1
+		AND ($3 = '' OR audience IN ('all', $3))
+		ORDER BY start_date, start_time NULLS FIRST;
+	`
+
+	//execute the query
+	events := []types.CalendarEvent{}
+	if err := s.db.SelectContext(ctx, &events, query, from, to, audience); err != nil {
+		return nil, fmt.Errorf("failed to get the events: %w", err)
+	}
+
+	return events, nil
+
+}
+
+//func to delete the event
+func (s *CalendarStore) DeleteEvent(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM calendar_events WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the event! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The event is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to save the hash of the feed token of the user, the old token is replaced
+func (s *CalendarStore) SaveFeedToken(ctx context.Context, userId uuid.UUID, tokenHash string) error {
+
+	//base query
+	query := `
+		INSERT INTO calendar_tokens (user_id, token_hash, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at, last_used_at = NULL;
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(ctx, query, userId, tokenHash, time.Now().UTC()); err != nil {
+		return errors.New("Failed to save the feed token! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to delete the feed token of the user, so the old feed url is not valid anymore
+func (s *CalendarStore) DeleteFeedToken(ctx context.Context, userId uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM calendar_tokens WHERE user_id = $1;`, userId)
+	if err != nil {
+		return errors.New("Failed to delete the feed token! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The feed token is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to get the owner of the feed token and mark the token as used
+func (s *CalendarStore) GetFeedOwner(ctx context.Context, tokenHash string) (*types.CalendarOwner, error) {
+
+	//base query
+	query := `
+		UPDATE calendar_tokens ct SET last_used_at = $2
+		FROM users u
+		WHERE u.id = ct.user_id AND ct.token_hash = $1
+		RETURNING ct.user_id, u.username, u.role;
+	`
+
+	//execute the query
+	var owner types.CalendarOwner
+	if err := s.db.GetContext(ctx, &owner, query, tokenHash, time.Now().UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the owner of the feed: %w", err)
+	}
+
+	return &owner, nil
+
+}
+
+//func to get the weekly lessons of the user, the guru gets the lessons that they teach, the siswa the
+//lessons of their class in the term (the current class when there is no enrollment of the term) and the
+//orangtua the lessons of the classes of their linked children
+func (s *CalendarStore) GetLessons(ctx context.Context, owner *types.CalendarOwner) ([]types.CalendarLesson, error) {
+
+	var filter string
+	switch owner.Role {
+	case "guru":
+		filter = `WHERE ts.teacher_id = $1`
+	case "siswa":
+		filter = `
+			WHERE ts.class_id = COALESCE(
+				(SELECT e.class_id FROM class_enrollments e JOIN students st ON st.id = e.student_id
+				WHERE st.user_id = $1 AND e.term_id = ts.term_id),
+				(SELECT st.class_id FROM students st WHERE st.user_id = $1)
+			)`
+	case types.RoleOrangtua:
+		filter = `
+			WHERE ts.class_id IN (
+				SELECT COALESCE(
+					(SELECT e.class_id FROM class_enrollments e WHERE e.student_id = gs.student_id AND e.term_id = ts.term_id),
+					st.class_id
+				)
+				FROM guardian_students gs
+				JOIN guardians g ON g.id = gs.guardian_id
+				JOIN students st ON st.id = gs.student_id
+				WHERE g.user_id = $1
+			)`
+	default:
+		return []types.CalendarLesson{}, nil
+	}
+
+	//execute the query
+	lessons := []types.CalendarLesson{}
+	query := `SELECT ` + lessonColumns + lessonJoins + filter + ` ORDER BY t.start_date, ts.day_of_week, ts.start_time;`
+	if err := s.db.SelectContext(ctx, &lessons, query, owner.UserId); err != nil {
+		return nil, fmt.Errorf("failed to get the lessons: %w", err)
+	}
+
+	return lessons, nil
+
+}
+
+//func to get the exam sessions of the user in the range of the dates, the siswa gets their seat, the orangtua
+//the seats of their linked children and the guru the rooms that they proctor
+func (s *CalendarStore) GetExams(ctx context.Context, owner *types.CalendarOwner, from time.Time, to time.Time) ([]types.CalendarExam, error) {
+
+	var query string
+	switch owner.Role {
+	case "guru":
+		query = `
+			SELECT es.id AS session_id, es.title, COALESCE(sb.name, '') AS subject, es.exam_date,
+			es.start_time, es.end_time, r.code AS room_code, NULL::int AS seat_no, '' AS student_name
+			FROM exam_proctors ep
+			JOIN exam_sessions es ON es.id = ep.session_id
+			JOIN rooms r ON r.id = ep.room_id
+			LEFT JOIN subjects sb ON sb.id = es.subject_id
+			WHERE ep.teacher_id = $1 AND es.exam_date BETWEEN $2 AND $3
+			ORDER BY es.exam_date, es.start_time;
+		`
+	case "siswa":
+		query = `
+			SELECT es.id AS session_id, es.title, COALESCE(sb.name, '') AS subject, es.exam_date,
+			es.start_time, es.end_time, r.code AS room_code, se.seat_no, '' AS student_name
+			FROM exam_seats se
+			JOIN students st ON st.id = se.student_id
+			JOIN exam_sessions es ON es.id = se.session_id
+			JOIN rooms r ON r.id = se.room_id
+			LEFT JOIN subjects sb ON sb.id = es.subject_id
+			WHERE st.user_id = $1 AND es.exam_date BETWEEN $2 AND $3
+			ORDER BY es.exam_date, es.start_time;
+		`
+	case types.RoleOrangtua:
+		query = `
+			SELECT es.id AS session_id, es.title, COALESCE(sb.name, '') AS subject, es.exam_date,
+			es.start_time, es.end_time, r.code AS room_code, se.seat_no, st.name AS student_name
+			FROM exam_seats se
+			JOIN guardian_students gs ON gs.student_id = se.student_id
+			JOIN guardians g ON g.id = gs.guardian_id
+			JOIN students st ON st.id = se.student_id
+			JOIN exam_sessions es ON es.id = se.session_id
+			JOIN rooms r ON r.id = se.room_id
+			LEFT JOIN subjects sb ON sb.id = es.subject_id
+			WHERE g.user_id = $1 AND es.exam_date BETWEEN $2 AND $3
+			ORDER BY es.exam_date, es.start_time, st.name;
+		`
+	default:
+		return []types.CalendarExam{}, nil
+	}
+
+	//execute the query
+	exams := []types.CalendarExam{}
+	if err := s.db.SelectContext(ctx, &exams, query, owner.UserId, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get the exams: %w", err)
+	}
+
+	return exams, nil
+
+}
@@ -0,0 +1,179 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils/ical"
+)
+
+// the domain of the uid of every event of the feed
+const uidDomain = "@school-manajement"
+
+// LessonEvents makes one weekly event for every lesson, from the first day of the lesson in the term
+// until the last day of the term. The days of the holidays inside the term are skipped with the
+// exception dates. The lesson with an invalid time is ignored.
+func LessonEvents(lessons []types.CalendarLesson, holidays []types.CalendarEvent, location *time.Location) []ical.Event {
+
+	events := []ical.Event{}
+	for _, lesson := range lessons {
+		termStart := localDate(lesson.TermStart, location)
+		termEnd := localDate(lesson.TermEnd, location)
+
+		//the first day of the lesson on or after the start of the term, day_of_week 7 is sunday
+		first := termStart
+		for int(first.Weekday()) != lesson.DayOfWeek%7 {
+			first = first.AddDate(0, 0, 1)
+		}
+		if first.After(termEnd) {
+			continue
+		}
+		start, err := atClock(first, lesson.StartTime, location)
+		if err != nil {
+			continue
+		}
+		end, err := atClock(first, lesson.EndTime, location)
+		if err != nil || !end.After(start) {
+			continue
+		}
+
+		//skip every day of the lesson inside the holidays
+		exDates := []time.Time{}
+		for _, holiday := range holidays {
+			if !holiday.IsHoliday {
+				continue
+			}
+			day := localDate(holiday.StartDate, location)
+			last := localDate(holiday.EndDate, location)
+			for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+				if day.Before(first) || day.After(termEnd) || day.Weekday() != first.Weekday() {
+					continue
+				}
+				exDate, _ := atClock(day, lesson.StartTime, location)
+				exDates = append(exDates, exDate)
+			}
+		}
+
+		summary := lesson.Subject
+		if lesson.ClassName != "" {
+			summary = fmt.Sprintf("%s - %s", lesson.Subject, lesson.ClassName)
+		}
+		events = append(events, ical.Event{
+			UID: "lesson-" + lesson.SlotId.String() + uidDomain,
+			Summary: summary,
+			Location: lesson.RoomCode,
+			Categories: "LESSON",
+			Start: start,
+			End: end,
+			RRule: ical.WeeklyUntil(termEnd.Add(24*time.Hour - time.Second)),
+			ExDates: exDates,
+		})
+	}
+
+	return events
+
+}
+
+// SchoolEvents makes the events of the school calendar, the event without the time is an all day event
+// and the end of the all day event is the day after the last day
+func SchoolEvents(calendarEvents []types.CalendarEvent, location *time.Location) []ical.Event {
+
+	events := []ical.Event{}
+	for _, calendarEvent := range calendarEvents {
+		category := "EVENT"
+		if calendarEvent.IsHoliday {
+			category = "HOLIDAY"
+		}
+		event := ical.Event{
+			UID: "event-" + calendarEvent.Id.String() + uidDomain,
+			Summary: calendarEvent.Title,
+			Description: calendarEvent.Description,
+			Categories: category,
+		}
+
+		startDate := localDate(calendarEvent.StartDate, location)
+		endDate := localDate(calendarEvent.EndDate, location)
+		if calendarEvent.StartTime == nil || calendarEvent.EndTime == nil {
+			event.AllDay = true
+			event.Start = startDate
+			event.End = endDate.AddDate(0, 0, 1)
+		} else {
+			start, err := atClock(startDate, *calendarEvent.StartTime, location)
+			if err != nil {
+				continue
+			}
+			end, err := atClock(endDate, *calendarEvent.EndTime, location)
+			if err != nil || !end.After(start) {
+				continue
+			}
+			event.Start = start
+			event.End = end
+		}
+		events = append(events, event)
+	}
+
+	return events
+
+}
+
+// ExamEvents makes one event for every exam session, the location is the room of the exam and the seat of
+// the siswa is in the description. The seat of every child of the orangtua is its own event with the name of
+// the child. The session with an invalid time is ignored.
+func ExamEvents(exams []types.CalendarExam, location *time.Location) []ical.Event {
+
+	events := []ical.Event{}
+	for _, exam := range exams {
+		day := localDate(exam.ExamDate, location)
+		start, err := atClock(day, exam.StartTime, location)
+		if err != nil {
+			continue
+		}
+		end, err := atClock(day, exam.EndTime, location)
+		if err != nil || !end.After(start) {
+			continue
+		}
+
+		summary := exam.Title
+		if exam.Subject != "" && exam.Subject != exam.Title {
+			summary = fmt.Sprintf("%s - %s", exam.Title, exam.Subject)
+		}
+		if exam.StudentName != "" {
+			summary = fmt.Sprintf("%s: %s", exam.StudentName, summary)
+		}
+		uid := "exam-" + exam.SessionId.String()
+		description := "Proctor of the room " + exam.RoomCode
+		if exam.SeatNo != nil {
+			uid = fmt.Sprintf("%s-%s-%d", uid, exam.RoomCode, *exam.SeatNo)
+			description = fmt.Sprintf("Room %s, seat %d", exam.RoomCode, *exam.SeatNo)
+		}
+		events = append(events, ical.Event{
+			UID: uid + uidDomain,
+			Summary: summary,
+			Description: description,
+			Location: exam.RoomCode,
+			Categories: "EXAM",
+			Start: start,
+			End: end,
+		})
+	}
+
+	return events
+
+}
+
+//helper to take the calendar date of the database date in the school location
+func localDate(date time.Time, location *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+}
+
+//helper to set the clock ("15:04" or "15:04:05") of the date
+func atClock(date time.Time, clock string, location *time.Location) (time.Time, error) {
+	value, err := time.Parse("15:04:05", clock)
+	if err != nil {
+		if value, err = time.Parse("15:04", clock); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), value.Hour(), value.Minute(), value.Second(), 0, location), nil
+}
@@ -0,0 +1,153 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestLessonEvents(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	lesson := types.CalendarLesson{
+		SlotId: uuid.New(),
+		ClassName: "X IPA 1",
+		Subject: "Matematika",
+		DayOfWeek: 3,
+		StartTime: "07:30:00",
+		EndTime: "09:00:00",
+		RoomCode: "R101",
+		//the term starts on a friday
+		TermStart: time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC),
+		TermEnd: time.Date(2025, time.December, 19, 0, 0, 0, 0, time.UTC),
+	}
+	holidays := []types.CalendarEvent{
+		//two wednesdays inside the holiday
+		{IsHoliday: true, StartDate: time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, time.August, 22, 0, 0, 0, 0, time.UTC)},
+		//not a holiday
+		{StartDate: time.Date(2025, time.September, 3, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, time.September, 3, 0, 0, 0, 0, time.UTC)},
+		//after the term
+		{IsHoliday: true, StartDate: time.Date(2025, time.December, 24, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	events := LessonEvents([]types.CalendarLesson{lesson}, holidays, jakarta)
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if want := time.Date(2025, time.August, 6, 7, 30, 0, 0, jakarta); !event.Start.Equal(want) {
+		t.Fatalf("start %v, want %v", event.Start, want)
+	}
+	if want := time.Date(2025, time.August, 6, 9, 0, 0, 0, jakarta); !event.End.Equal(want) {
+		t.Fatalf("end %v, want %v", event.End, want)
+	}
+	if want := "FREQ=WEEKLY;UNTIL=20251219T165959Z"; event.RRule != want {
+		t.Fatalf("rrule %q, want %q", event.RRule, want)
+	}
+	if len(event.ExDates) != 2 {
+		t.Fatalf("got %d exception dates, want 2", len(event.ExDates))
+	}
+	if want := time.Date(2025, time.August, 13, 7, 30, 0, 0, jakarta); !event.ExDates[0].Equal(want) {
+		t.Fatalf("exception %v, want %v", event.ExDates[0], want)
+	}
+	if event.Summary != "Matematika - X IPA 1" || event.Location != "R101" {
+		t.Fatalf("unexpected summary %q or location %q", event.Summary, event.Location)
+	}
+}
+
+func TestLessonEventsOutsideTerm(t *testing.T) {
+	lesson := types.CalendarLesson{
+		SlotId: uuid.New(),
+		DayOfWeek: 7,
+		StartTime: "08:00:00",
+		EndTime: "09:00:00",
+		TermStart: time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC),
+		TermEnd: time.Date(2025, time.August, 8, 0, 0, 0, 0, time.UTC),
+	}
+	if events := LessonEvents([]types.CalendarLesson{lesson}, nil, time.UTC); len(events) != 0 {
+		t.Fatalf("the sunday lesson is outside the term, got %d events", len(events))
+	}
+}
+
+func TestSchoolEvents(t *testing.T) {
+	start, end := "08:00", "12:00"
+	calendarEvents := []types.CalendarEvent{
+		{Id: uuid.New(), Title: "Libur semester", IsHoliday: true, StartDate: time.Date(2025, time.December, 22, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{Id: uuid.New(), Title: "Rapat guru", StartDate: time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC), StartTime: &start, EndTime: &end},
+	}
+
+	events := SchoolEvents(calendarEvents, time.UTC)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if !events[0].AllDay || events[0].Categories != "HOLIDAY" {
+		t.Fatalf("the holiday should be an all day event")
+	}
+	if want := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC); !events[0].End.Equal(want) {
+		t.Fatalf("the end of the all day event %v, want %v", events[0].End, want)
+	}
+	if events[1].AllDay || events[1].End.Sub(events[1].Start) != 4*time.Hour {
+		t.Fatalf("unexpected timed event %v - %v", events[1].Start, events[1].End)
+	}
+}
+
+func TestExamEvents(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	seat := 12
+	student := types.CalendarExam{
+		SessionId: uuid.New(),
+		Title: "UTS Ganjil",
+		Subject: "Matematika",
+		ExamDate: time.Date(2025, time.October, 6, 0, 0, 0, 0, time.UTC),
+		StartTime: "07:30:00",
+		EndTime: "09:30:00",
+		RoomCode: "R101",
+		SeatNo: &seat,
+	}
+	proctor := types.CalendarExam{
+		SessionId: uuid.New(),
+		Title: "UTS Ganjil",
+		ExamDate: time.Date(2025, time.October, 7, 0, 0, 0, 0, time.UTC),
+		StartTime: "10:00",
+		EndTime: "12:00",
+		RoomCode: "R202",
+	}
+	invalid := types.CalendarExam{SessionId: uuid.New(), StartTime: "10:00", EndTime: "09:00"}
+
+	events := ExamEvents([]types.CalendarExam{student, proctor, invalid}, jakarta)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if want := time.Date(2025, time.October, 6, 7, 30, 0, 0, jakarta); !events[0].Start.Equal(want) || events[0].End.Sub(events[0].Start) != 2*time.Hour {
+		t.Fatalf("unexpected exam %v - %v", events[0].Start, events[0].End)
+	}
+	if events[0].Summary != "UTS Ganjil - Matematika" || events[0].Location != "R101" || events[0].Description != "Room R101, seat 12" {
+		t.Fatalf("unexpected exam of the siswa %q %q %q", events[0].Summary, events[0].Location, events[0].Description)
+	}
+	if events[0].Categories != "EXAM" || events[0].RRule != "" {
+		t.Fatalf("the exam should be a single exam event")
+	}
+	if events[1].Location != "R202" || events[1].Description != "Proctor of the room R202" {
+		t.Fatalf("unexpected proctoring duty %q %q", events[1].Location, events[1].Description)
+	}
+}
+
+func TestExamEventsOfChildren(t *testing.T) {
+	session := uuid.New()
+	first, second := 3, 3
+	exams := []types.CalendarExam{
+		{SessionId: session, Title: "UAS", ExamDate: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), StartTime: "08:00", EndTime: "10:00", RoomCode: "R101", SeatNo: &first, StudentName: "Budi"},
+		{SessionId: session, Title: "UAS", ExamDate: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), StartTime: "08:00", EndTime: "10:00", RoomCode: "R102", SeatNo: &second, StudentName: "Sari"},
+	}
+
+	//two children in the same session are two events
+	events := ExamEvents(exams, time.UTC)
+	if len(events) != 2 || events[0].UID == events[1].UID {
+		t.Fatalf("expected an event for every child, got %+v", events)
+	}
+	if events[0].Summary != "Budi: UAS" || events[1].Summary != "Sari: UAS" {
+		t.Fatalf("unexpected summary %q %q", events[0].Summary, events[1].Summary)
+	}
+}
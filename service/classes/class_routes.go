@@ -0,0 +1,653 @@
+package classes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the class store for a database logic
+type HandleRequest struct {
+	db types.ClassStore
+}
+
+//func that declare the handler for class
+func NewHandlerClass(db types.ClassStore) *HandleRequest {
+	return &HandleRequest{db: db}
+}
+
+//helper to make the response of the class
+func classResponse(class types.Class) types.ClassResponse {
+	return types.ClassResponse{
+		Id: class.Id,
+		Name: class.Name,
+		GradeLevel: class.GradeLevel,
+		Major: class.Major,
+		Section: class.Section,
+		AcademicYear: class.AcademicYear,
+		HomeroomTeacherId: class.HomeroomTeacherId,
+		Created_at: class.Created_at.Format("2006-01-02"),
+		Updated_at: class.Updated_at.Format("2006-01-02"),
+	}
+}
+
+//func to create a new class
+func (h *HandleRequest) CreateClass_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the classes
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode the payload of the class
+	var payload types.CreateClass
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+
+	//make the validator of the payload
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		logger.Log.Warn("Validation failed",
+			zap.String("request_id", requestID),
+			zap.Strings("errors", errors),
+		)
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	if !utils.ValidAcademicYear(payload.AcademicYear) {
+		utils.ResponseError(w, http.StatusBadRequest, "The academic year must be like 2025/2026!", false)
+		return 
+	}
+
+	//the name of the class is always made from the parts, so "xi-ipa-1" and "XI IPA 1" is the same class
+	name := utils.ClassName{
+		GradeLevel: payload.GradeLevel,
+		Major: strings.ToUpper(strings.TrimSpace(payload.Major)),
+		Section: strings.ToUpper(strings.TrimSpace(payload.Section)),
+	}
+
+	//declare the context for the queries
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+
+	//validate the class is not exist yet
+	exist, err := h.db.GetClassByName(ctx, name.GradeLevel, name.Major, name.Section, payload.AcademicYear)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class!", err.Error())
+		return 
+	}
+	if exist != nil {
+		utils.ResponseError(w, http.StatusConflict, "The class has been already exist!", classResponse(*exist))
+		return 
+	}
+
+	//validate the homeroom teacher
+	if payload.HomeroomTeacherId != nil {
+		is_teacher, err := h.db.IsTeacher(ctx, *payload.HomeroomTeacherId)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the homeroom teacher!", err.Error())
+			return 
+		}
+		if !is_teacher {
+			utils.ResponseError(w, http.StatusBadRequest, "The homeroom teacher must be a guru!", false)
+			return 
+		}
+	}
+
+	//make the struct of the class
+	class := &types.Class{
+		Id: uuid.New(),
+		Name: name.String(),
+		GradeLevel: name.GradeLevel,
+		Major: name.Major,
+		Section: name.Section,
+		AcademicYear: payload.AcademicYear,
+		HomeroomTeacherId: payload.HomeroomTeacherId,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+
+	//execute the query
+	if err := h.db.CreateClass(ctx, class); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the class!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new class has been successfully", classResponse(*class))
+
+}
+
+//func to get all of the classes (?academic_year=2025/2026&grade_level=11)
+func (h *HandleRequest) GetAllClasses_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//define the query params
+	academic_year := r.URL.Query().Get("academic_year")
+	grade_level := 0
+	if value := r.URL.Query().Get("grade_level"); value != "" {
+		convert, err := strconv.Atoi(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to convert the grade level!", err.Error())
+			return 
+		}
+		grade_level = convert
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	classes, err := h.db.GetAllClasses(ctx, academic_year, grade_level)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get all the classes", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the classes!", err.Error())
+		return 
+	}
+
+	//make the response
+	response := make([]types.ClassResponse, 0, len(classes))
+	for _, class := range classes {
+		response = append(response, classResponse(class))
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get all classes has been successfully", response)
+
+}
+
+//func to get one class by id
+func (h *HandleRequest) GetClass_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	class, err := h.db.GetClassById(ctx, class_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class!", err.Error())
+		return 
+	}
+	if class == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The class is not exist!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the class has been successfully", classResponse(*class))
+
+}
+
+//func to update the class
+func (h *HandleRequest) UpdateClass_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the classes
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode the payload of the class
+	var payload types.UpdateClass
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", err.Error())
+		return 
+	}
+	if payload.AcademicYear != nil && !utils.ValidAcademicYear(*payload.AcademicYear) {
+		utils.ResponseError(w, http.StatusBadRequest, "The academic year must be like 2025/2026!", false)
+		return 
+	}
+
+	//get the class that we want to update
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	class, err := h.db.GetClassById(ctx, class_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class!", err.Error())
+		return 
+	}
+	if class == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The class is not exist!", false)
+		return 
+	}
+
+	//the name is made again if one of the part is changed
+	name := utils.ClassName{GradeLevel: class.GradeLevel, Major: class.Major, Section: class.Section}
+	if payload.GradeLevel != nil {
+		name.GradeLevel = *payload.GradeLevel
+	}
+	if payload.Major != nil {
+		major := strings.ToUpper(strings.TrimSpace(*payload.Major))
+		payload.Major = &major
+		name.Major = major
+	}
+	if payload.Section != nil {
+		section := strings.ToUpper(strings.TrimSpace(*payload.Section))
+		payload.Section = &section
+		name.Section = section
+	}
+	if full_name := name.String(); full_name != class.Name {
+		payload.Name = &full_name
+	}
+
+	//validate the homeroom teacher
+	if payload.HomeroomTeacherId != nil {
+		is_teacher, err := h.db.IsTeacher(ctx, *payload.HomeroomTeacherId)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the homeroom teacher!", err.Error())
+			return 
+		}
+		if !is_teacher {
+			utils.ResponseError(w, http.StatusBadRequest, "The homeroom teacher must be a guru!", false)
+			return 
+		}
+	}
+
+	//execute the query
+	if err := h.db.UpdateClass(ctx, class_id, payload); err != nil {
+		//logger if the update is failed
+		logger.Log.Error("Failed to update the class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the class!", err.Error())
+		return 
+	}
+
+	//get the class again for the response
+	class, err = h.db.GetClassById(ctx, class_id)
+	if err != nil || class == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Update the class has been successfully", classResponse(*class))
+
+}
+
+//func to delete the class
+func (h *HandleRequest) DeleteClass_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the classes
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.DeleteClass(ctx, class_id); err != nil {
+		//logger if the delete is failed
+		logger.Log.Error("Failed to delete the class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the class!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the class has been successfully", class_id)
+
+}
+
+//func to get the roster (the students) of the class, or the roster of a past term (?term_id=)
+func (h *HandleRequest) Roster_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the siswa cannot see the other student
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//get the class and the students
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	class, err := h.db.GetClassById(ctx, class_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class!", err.Error())
+		return 
+	}
+	if class == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The class is not exist!", false)
+		return 
+	}
+	var term_id *uuid.UUID
+	if term := r.URL.Query().Get("term_id"); term != "" {
+		parsed, err := uuid.Parse(term)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid term id!", err.Error())
+			return 
+		}
+		term_id = &parsed
+	}
+	students, err := h.db.GetClassRoster(ctx, class_id, term_id)
+	if err != nil {
+		//logger if the roster is failed
+		logger.Log.Error("Failed to get the roster", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the students of the class!", err.Error())
+		return 
+	}
+
+	//make the response
+	students_response := make([]types.StudentResponse, 0, len(students))
+	for _, student := range students {
+		students_response = append(students_response, types.StudentResponse{
+			Id: student.Id,
+			Name: student.Name,
+			Class: student.Class,
+			ClassId: student.ClassId,
+			Address: student.Address,
+			Major: student.Major,
+			StudentProfile: student.StudentProfile,
+			Created_at: student.Created_at.Format("2006-01-02"),
+			Updated_at: student.Updated_at.Format("2006-01-02"),
+		})
+	}
+	response := map[string]interface{}{
+		"class": classResponse(*class),
+		"students": students_response,
+		"total": len(students_response),
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the roster has been successfully", response)
+
+}
+
+//func to see the report of the migration from the free-text class (?status=ambiguous)
+func (h *HandleRequest) MappingReport_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the report
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	mappings, err := h.db.GetClassMappings(ctx, r.URL.Query().Get("status"))
+	if err != nil {
+		//logger if the report is failed
+		logger.Log.Error("Failed to get the mapping report", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the mapping report!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the mapping report has been successfully", mappings)
+
+}
+
+//func to map an ambiguous raw value into a class by hand
+func (h *HandleRequest) ResolveMapping_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can resolve the report
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.ResolveClassMapping
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", err.Error())
+		return 
+	}
+
+	//get the class
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	class, err := h.db.GetClassById(ctx, payload.ClassId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class!", err.Error())
+		return 
+	}
+	if class == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The class is not exist!", false)
+		return 
+	}
+
+	//execute the query
+	total, err := h.db.ResolveClassMapping(ctx, payload.RawValue, class)
+	if err != nil {
+		//logger if the resolve is failed
+		logger.Log.Error("Failed to resolve the mapping", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to resolve the mapping!", err.Error())
+		return 
+	}
+
+	//return a final result
+	response := map[string]interface{}{
+		"raw_value": payload.RawValue,
+		"class": classResponse(*class),
+		"students_updated": total,
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "Resolve the mapping has been successfully", response)
+
+}
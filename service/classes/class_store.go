@@ -0,0 +1,347 @@
+package classes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store class
+type ClassStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewClassStore(db *sqlx.DB) *ClassStore {
+	return &ClassStore{db: db}
+}
+
+//the column of the class that we select in every query
+const classColumns = `id, name, grade_level, major, section, academic_year, homeroom_teacher_id, created_at, updated_at`
+
+//func to create a new class
+func (s *ClassStore) CreateClass(ctx context.Context, class *types.Class) error {
+
+	//base query
+	query := `
+		INSERT INTO classes 
+		(id, name, grade_level, major, section, academic_year, homeroom_teacher_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		class.Id,
+		class.Name,
+		class.GradeLevel,
+		class.Major,
+		class.Section,
+		class.AcademicYear,
+		class.HomeroomTeacherId,
+		class.Created_at,
+		class.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new class! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the class by id
+func (s *ClassStore) GetClassById(ctx context.Context, id uuid.UUID) (*types.Class, error) {
+
+	//base query
+	query := `SELECT ` + classColumns + ` FROM classes WHERE id = $1;`
+
+	//execute the query
+	var class types.Class
+	if err := s.db.GetContext(ctx, &class, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get class by id: %w", err)
+	}
+
+	return &class, nil
+
+}
+
+//func to get the class from the parts of the name in one academic year
+func (s *ClassStore) GetClassByName(
+	ctx context.Context,
+	gradeLevel int,
+	major string,
+	section string,
+	academicYear string,
+	) (*types.Class, error) {
+
+	//base query
+	query := `
+		SELECT ` + classColumns + ` FROM classes 
+		WHERE grade_level = $1 AND major = $2 AND section = $3 AND academic_year = $4;
+	`
+
+	//execute the query
+	var class types.Class
+	if err := s.db.GetContext(ctx, &class, query, gradeLevel, major, section, academicYear); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get class by name: %w", err)
+	}
+
+	return &class, nil
+
+}
+
+//func to get all of the classes, the filter is optional
+func (s *ClassStore) GetAllClasses(ctx context.Context, academicYear string, gradeLevel int) ([]types.Class, error) {
+
+	//base query
+	query := `
+		SELECT ` + classColumns + ` FROM classes 
+		WHERE ($1 = '' OR academic_year = $1) AND ($2 = 0 OR grade_level = $2)
+		ORDER BY academic_year DESC, grade_level, major, section;
+	`
+
+	//execute the query
+	classes := []types.Class{}
+	if err := s.db.SelectContext(ctx, &classes, query, academicYear, gradeLevel); err != nil {
+		return nil, fmt.Errorf("failed to get the classes: %w", err)
+	}
+
+	return classes, nil
+
+}
+
+//func to update the class
+func (s *ClassStore) UpdateClass(ctx context.Context, id uuid.UUID, payload types.UpdateClass) error {
+
+	//setup the args and args id
+	var settings []string
+	argsId := 1
+	var args []interface{}
+
+	//if the name is changed
+	if payload.Name != nil {
+		settings = append(settings, fmt.Sprintf("name=$%d", argsId))
+		args = append(args, *payload.Name)
+		argsId++
+	}
+
+	//if the grade level is changed
+	if payload.GradeLevel != nil {
+		settings = append(settings, fmt.Sprintf("grade_level=$%d", argsId))
+		args = append(args, *payload.GradeLevel)
+		argsId++
+	}
+
+	//if the major is changed
+	if payload.Major != nil {
+		settings = append(settings, fmt.Sprintf("major=$%d", argsId))
+		args = append(args, *payload.Major)
+		argsId++
+	}
+
+	//if the section is changed
+	if payload.Section != nil {
+		settings = append(settings, fmt.Sprintf("section=$%d", argsId))
+		args = append(args, *payload.Section)
+		argsId++
+	}
+
+	//if the academic year is changed
+	if payload.AcademicYear != nil {
+		settings = append(settings, fmt.Sprintf("academic_year=$%d", argsId))
+		args = append(args, *payload.AcademicYear)
+		argsId++
+	}
+
+	//if the homeroom teacher is changed
+	if payload.HomeroomTeacherId != nil {
+		settings = append(settings, fmt.Sprintf("homeroom_teacher_id=$%d", argsId))
+		args = append(args, *payload.HomeroomTeacherId)
+		argsId++
+	}
+
+	//validate if the no one field changes
+	if len(args) == 0 {
+		return errors.New("No one data changes")
+	}
+
+	//update the updated at
+	settings = append(settings, fmt.Sprintf("updated_at=$%d", argsId))
+	args = append(args, time.Now().UTC())
+	argsId++
+
+	//define a fullquery and execute it
+	fullquery := fmt.Sprintf("UPDATE classes SET %s WHERE id = $%d", strings.Join(settings, ", "), argsId)
+	args = append(args, id)
+	rows, err := s.db.ExecContext(ctx, fullquery, args...)
+	if err != nil {
+		return errors.New("Failed to update the class! " + err.Error())
+	}
+	result, err := rows.RowsAffected()
+	if err != nil {
+		return errors.New("No one changes in db, error: " + err.Error())
+	}
+	if result == 0 {
+		return errors.New("The class is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to delete the class, a class that still has students cannot be deleted
+func (s *ClassStore) DeleteClass(ctx context.Context, id uuid.UUID) error {
+
+	//base query
+	query := `
+		DELETE FROM classes 
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM students WHERE class_id = $1);
+	`
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.New("Failed to delete the class! " + err.Error())
+	}
+	result, err := rows.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to delete the class! " + err.Error())
+	}
+	if result == 0 {
+		return errors.New("The class is not exist or still has students!")
+	}
+
+	return nil
+
+}
+
+//func to get the students of the class
+func (s *ClassStore) GetClassRoster(ctx context.Context, id uuid.UUID, termId *uuid.UUID) ([]types.Student, error) {
+
+	//base query, with a term the roster is read from the class membership of that term
+	query := `
+		SELECT id, name, class, class_id, address, major, major_id, academic_year, student_profile, created_at, updated_at
+		FROM students WHERE class_id = $1 ORDER BY name;
+	`
+	args := []interface{}{id}
+	if termId != nil {
+		query = `
+			SELECT s.id, s.name, s.class, e.class_id, s.address, s.major, s.major_id, s.academic_year, s.student_profile, s.created_at, s.updated_at
+			FROM class_enrollments e JOIN students s ON s.id = e.student_id
+			WHERE e.class_id = $1 AND e.term_id = $2 ORDER BY s.name;
+		`
+		args = append(args, *termId)
+	}
+
+	//execute the query
+	students := []types.Student{}
+	if err := s.db.SelectContext(ctx, &students, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get the roster: %w", err)
+	}
+
+	return students, nil
+
+}
+
+//func to get the mapping report of the old free-text classes
+func (s *ClassStore) GetClassMappings(ctx context.Context, status string) ([]types.ClassMapping, error) {
+
+	//base query
+	query := `
+		SELECT raw_value, normalized, status, reason, student_count, created_at, updated_at
+		FROM class_mappings WHERE ($1 = '' OR status = $1)
+		ORDER BY status, raw_value;
+	`
+
+	//execute the query
+	mappings := []types.ClassMapping{}
+	if err := s.db.SelectContext(ctx, &mappings, query, status); err != nil {
+		return nil, fmt.Errorf("failed to get the class mappings: %w", err)
+	}
+
+	return mappings, nil
+
+}
+
+//func to map every student with the raw class value into the class, by hand
+func (s *ClassStore) ResolveClassMapping(ctx context.Context, rawValue string, class *types.Class) (int64, error) {
+
+	//make the options of transaction
+	options := &sql.TxOptions{
+		ReadOnly: false,
+		Isolation: sql.LevelSerializable,
+	}
+
+	//make the new begintxx for transaction
+	tx, err := s.db.BeginTxx(ctx, options)
+	if err != nil {
+		return 0, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//update the students of the raw value
+	rows, err := tx.ExecContext(
+		ctx,
+		`UPDATE students SET class_id = $1, class = $2, updated_at = $3 WHERE class = $4;`,
+		class.Id, class.Name, time.Now().UTC(), rawValue,
+	)
+	if err != nil {
+		return 0, errors.New("Failed to update the students! " + err.Error())
+	}
+	total, err := rows.RowsAffected()
+	if err != nil {
+		return 0, errors.New("Failed to update the students! " + err.Error())
+	}
+
+	//mark the raw value as mapped in the report
+	report, err := tx.ExecContext(
+		ctx,
+		`UPDATE class_mappings SET normalized = $1, status = 'mapped', reason = 'resolved by hand', updated_at = $2 WHERE raw_value = $3;`,
+		class.Name, time.Now().UTC(), rawValue,
+	)
+	if err != nil {
+		return 0, errors.New("Failed to update the mapping report! " + err.Error())
+	}
+	if result, err := report.RowsAffected(); err != nil || result == 0 {
+		return 0, errors.New("The raw value is not exist in the mapping report!")
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return 0, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return total, nil
+
+}
+
+//func to check that the user is a guru, used for the homeroom teacher
+func (s *ClassStore) IsTeacher(ctx context.Context, userId uuid.UUID) (bool, error) {
+
+	//base query
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND role = 'guru');`
+
+	//execute the query
+	var exists bool
+	if err := s.db.GetContext(ctx, &exists, query, userId); err != nil {
+		return false, fmt.Errorf("failed to check the teacher: %w", err)
+	}
+
+	return exists, nil
+
+}
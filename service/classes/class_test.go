@@ -0,0 +1,124 @@
+package classes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//the store of the handler tests, the classes are kept in memory
+type classStore struct {
+	types.ClassStore
+	created []*types.Class
+	existing *types.Class
+	teachers map[uuid.UUID]bool
+}
+
+func (s *classStore) GetClassByName(ctx context.Context, gradeLevel int, major string, section string, academicYear string) (*types.Class, error) {
+	return s.existing, nil
+}
+
+func (s *classStore) IsTeacher(ctx context.Context, userId uuid.UUID) (bool, error) {
+	return s.teachers[userId], nil
+}
+
+func (s *classStore) CreateClass(ctx context.Context, class *types.Class) error {
+	s.created = append(s.created, class)
+	return nil
+}
+
+func classRequest(body string, role string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/classes", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(r.Context(), "request_id", "test")
+	ctx = context.WithValue(ctx, "role_user", role)
+	return r.WithContext(ctx)
+}
+
+func TestCreateClassNormalizesTheName(t *testing.T) {
+	logger.Log = zap.NewNop()
+	store := &classStore{}
+	w := httptest.NewRecorder()
+	NewHandlerClass(store).CreateClass_Bp(w, classRequest(`{"grade_level":11,"major":" ipa ","section":"1","academic_year":"2025/2026"}`, "admin"))
+
+	if w.Code != http.StatusCreated || len(store.created) != 1 {
+		t.Fatalf("expected the class to be created, got %d: %s", w.Code, w.Body.String())
+	}
+	if class := store.created[0]; class.Name != "XI IPA 1" || class.Major != "IPA" || class.AcademicYear != "2025/2026" {
+		t.Fatalf("unexpected class %+v", class)
+	}
+}
+
+func TestCreateClassRejects(t *testing.T) {
+	logger.Log = zap.NewNop()
+	student := uuid.New()
+	cases := []struct {
+		name string
+		role string
+		body string
+		store *classStore
+		code int
+	}{
+		{"the siswa", "siswa", `{"grade_level":10,"section":"1","academic_year":"2025/2026"}`, &classStore{}, http.StatusForbidden},
+		{"the year that is not consecutive", "admin", `{"grade_level":10,"section":"1","academic_year":"2025/2027"}`, &classStore{}, http.StatusBadRequest},
+		{"the year with another separator", "admin", `{"grade_level":10,"section":"1","academic_year":"2025-2026"}`, &classStore{}, http.StatusBadRequest},
+		{"the existing class", "guru", `{"grade_level":10,"section":"1","academic_year":"2025/2026"}`, &classStore{existing: &types.Class{Id: uuid.New(), Name: "X 1"}}, http.StatusConflict},
+		{"the homeroom that is not a guru", "admin", `{"grade_level":10,"section":"1","academic_year":"2025/2026","homeroom_teacher_id":"` + student.String() + `"}`, &classStore{}, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		NewHandlerClass(c.store).CreateClass_Bp(w, classRequest(c.body, c.role))
+		if w.Code != c.code || len(c.store.created) != 0 {
+			t.Fatalf("%s: expected %d without the class, got %d: %s", c.name, c.code, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestResolveClassMappingOfUnknownValue(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewClassStore(db)
+
+	fake.On("UPDATE students SET class_id", func(args []any) dbtest.Result { return dbtest.Affected(3) })
+	fake.On("UPDATE class_mappings", func(args []any) dbtest.Result { return dbtest.Affected(0) })
+
+	_, err := store.ResolveClassMapping(context.Background(), "kelas 11 ipa", &types.Class{Id: uuid.New(), Name: "XI IPA 1"})
+	if err == nil {
+		t.Fatalf("expected the unknown raw value to fail")
+	}
+	//the students are not moved without the report
+	if fake.Count(dbtest.Commit) != 0 || fake.Count(dbtest.Rollback) != 1 {
+		t.Fatalf("expected the rollback, got %v", fake.Statements())
+	}
+}
+
+func TestResolveClassMapping(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewClassStore(db)
+
+	class := &types.Class{Id: uuid.New(), Name: "XI IPA 1"}
+	fake.On("UPDATE students SET class_id", func(args []any) dbtest.Result { return dbtest.Affected(3) })
+	fake.On("UPDATE class_mappings", func(args []any) dbtest.Result {
+		if args[0] != class.Name || args[2] != "kelas 11 ipa" {
+			return dbtest.Affected(0)
+		}
+		return dbtest.Affected(1)
+	})
+
+	total, err := store.ResolveClassMapping(context.Background(), "kelas 11 ipa", class)
+	if err != nil || total != 3 {
+		t.Fatalf("expected 3 students, got %d %v", total, err)
+	}
+	moved := fake.Statements()[fake.Index("UPDATE students SET class_id", 0)]
+	if moved.Args[0] != class.Id || moved.Args[1] != class.Name || moved.Args[3] != "kelas 11 ipa" {
+		t.Fatalf("unexpected arguments %v", moved.Args)
+	}
+}
@@ -0,0 +1,904 @@
+package exams
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/service/timetable"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the exam store for a database logic
+type HandleRequest struct {
+	db types.ExamStore
+	grades types.GradeStore
+	academics types.AcademicStore
+}
+
+//func that declare the handler for the exam sessions, the rooms, the proctors and the seats
+func NewHandlerExam(db types.ExamStore, grades types.GradeStore, academics types.AcademicStore) *HandleRequest {
+	return &HandleRequest{
+		db: db,
+		grades: grades,
+		academics: academics,
+	}
+}
+
+//helper for the term query params, the default is the open term
+func (h *HandleRequest) termParam(ctx context.Context, r *http.Request) (uuid.UUID, error) {
+	if value := r.URL.Query().Get("term_id"); value != "" {
+		return uuid.Parse(value)
+	}
+	term, err := h.academics.GetActiveTerm(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if term == nil {
+		return uuid.Nil, fmt.Errorf("there is no open term")
+	}
+	return term.Id, nil
+}
+
+//helper to allow only the admin, the admin plans the exam week
+func adminOnly(w http.ResponseWriter, r *http.Request) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//helper to allow only the guru and the admin
+func staffOnly(w http.ResponseWriter, r *http.Request) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//helper to get the exam session by the id of the parameters
+func (h *HandleRequest) sessionParam(ctx context.Context, w http.ResponseWriter, r *http.Request) (*types.ExamSession, bool) {
+	session_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, false
+	}
+	session, err := h.db.GetSessionById(ctx, session_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the exam session!", err.Error())
+		return nil, false
+	}
+	if session == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The exam session is not exist!", false)
+		return nil, false
+	}
+	return session, true
+}
+
+//helper to check that the term of the session is not closed
+func (h *HandleRequest) termNotClosed(ctx context.Context, w http.ResponseWriter, termId uuid.UUID) (*types.Term, bool) {
+	term, err := h.academics.GetTermById(ctx, termId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return nil, false
+	}
+	if term == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The term is not exist!", false)
+		return nil, false
+	}
+	if term.Status == "closed" {
+		utils.ResponseError(w, http.StatusBadRequest, "The exams of a closed term cannot be changed!", false)
+		return nil, false
+	}
+	return term, true
+}
+
+//helper to get the room of the session by the room id of the parameters with the proctors
+func (h *HandleRequest) roomParam(ctx context.Context, w http.ResponseWriter, r *http.Request, sessionId uuid.UUID) (*types.ExamRoom, bool) {
+	room_id, err := uuid.Parse(mux.Vars(r)["room_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, false
+	}
+	rooms, err := h.db.GetRooms(ctx, sessionId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the rooms of the session!", err.Error())
+		return nil, false
+	}
+	for i := range rooms {
+		if rooms[i].RoomId == room_id {
+			return &rooms[i], true
+		}
+	}
+	utils.ResponseError(w, http.StatusNotFound, "The room is not used by the session!", false)
+	return nil, false
+}
+
+//func to create the exam session of the classes, the classes cannot sit another exam at the same time
+func (h *HandleRequest) CreateSession_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can plan the exams
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.CreateExamSession
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+	start, _ := timetable.ClockMinutes(payload.StartTime)
+	end, _ := timetable.ClockMinutes(payload.EndTime)
+	if end <= start {
+		utils.ResponseError(w, http.StatusBadRequest, "The end time must be after the start time!", false)
+		return
+	}
+	exam_date, _ := time.Parse("2006-01-02", payload.ExamDate)
+
+	//the term of the session, the date must be in the term
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	var term_id uuid.UUID
+	if payload.TermId != nil {
+		term_id = *payload.TermId
+	} else {
+		active, err := h.termParam(ctx, r)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+			return
+		}
+		term_id = active
+	}
+	term, ok := h.termNotClosed(ctx, w, term_id)
+	if !ok {
+		return
+	}
+	if exam_date.Before(term.StartDate) || exam_date.After(term.EndDate) {
+		utils.ResponseError(w, http.StatusBadRequest, "The exam date is outside of the term!", false)
+		return
+	}
+
+	//the classes cannot sit two exams at the same time
+	session := types.ExamSession{
+		Id: uuid.New(),
+		TermId: term_id,
+		Kind: payload.Kind,
+		Title: payload.Title,
+		SubjectId: payload.SubjectId,
+		ExamDate: exam_date,
+		StartTime: payload.StartTime,
+		EndTime: payload.EndTime,
+	}
+	busy, err := h.db.GetClassBusy(ctx, payload.ClassIds, exam_date)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the exams of the classes!", err.Error())
+		return
+	}
+	if conflicts := FindConflicts(session.Id, session.StartTime, session.EndTime, busy, "the class"); len(conflicts) > 0 {
+		utils.ResponseError(w, http.StatusConflict, "The session is rejected because of the conflicts!", conflicts)
+		return
+	}
+
+	//execute the query
+	now := time.Now().UTC()
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		session.CreatedBy = &user_id
+	}
+	session.Created_at = now
+	session.Updated_at = now
+	if err := h.db.CreateSession(ctx, &session, payload.ClassIds); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the exam session",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the exam session!", err.Error())
+		return
+	}
+	created, err := h.db.GetSessionById(ctx, session.Id)
+	if err != nil || created == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the exam session!", false)
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the exam session has been successfully", created)
+
+}
+
+//func to get the exam sessions of the term (?term_id=&kind=)
+func (h *HandleRequest) GetSessions_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can see every session
+	if !staffOnly(w, r) {
+		return
+	}
+	kind := r.URL.Query().Get("kind")
+	if kind != "" && kind != types.ExamMidterm && kind != types.ExamFinal {
+		utils.ResponseError(w, http.StatusBadRequest, "The kind must be midterm or final!", false)
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return
+	}
+
+	//execute the query
+	sessions, err := h.db.GetSessions(ctx, term_id, kind)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the exam sessions",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the exam sessions!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the exam sessions has been successfully", sessions)
+
+}
+
+//func to get the exam session by the id with the rooms and the proctors
+func (h *HandleRequest) GetSession_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can see the session
+	if !staffOnly(w, r) {
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	//execute the query
+	rooms, err := h.db.GetRooms(ctx, session.Id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the rooms of the session",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the rooms of the session!", err.Error())
+		return
+	}
+	session.Rooms = rooms
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the exam session has been successfully", session)
+
+}
+
+//func to delete the exam session with the rooms, the proctors and the seats
+func (h *HandleRequest) DeleteSession_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can delete the session
+	if !adminOnly(w, r) {
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.termNotClosed(ctx, w, session.TermId); !ok {
+		return
+	}
+
+	//execute the query
+	if err := h.db.DeleteSession(ctx, session.Id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the exam session",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the exam session!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the exam session has been successfully", session.Id)
+
+}
+
+//func to add the room into the session, the room cannot be used by another exam at the same time
+func (h *HandleRequest) AddRoom_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can add the room
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.AddExamRoom
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	//get the session and check the other exams of the room
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.termNotClosed(ctx, w, session.TermId); !ok {
+		return
+	}
+	busy, err := h.db.GetRoomBusy(ctx, payload.RoomId, session.ExamDate)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the exams of the room!", err.Error())
+		return
+	}
+	if conflicts := FindConflicts(session.Id, session.StartTime, session.EndTime, busy, "the room"); len(conflicts) > 0 {
+		utils.ResponseError(w, http.StatusConflict, "The room is rejected because of the conflicts!", conflicts)
+		return
+	}
+
+	//execute the query
+	room := types.ExamRoom{SessionId: session.Id, RoomId: payload.RoomId}
+	if payload.Seats != nil {
+		room.Seats = *payload.Seats
+	}
+	if err := h.db.AddRoom(ctx, &room); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to add the room into the session",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to add the room!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Add the room has been successfully", room)
+
+}
+
+//func to remove the room from the session, the proctors and the seats of the room are removed too
+func (h *HandleRequest) RemoveRoom_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can remove the room
+	if !adminOnly(w, r) {
+		return
+	}
+	room_id, err := uuid.Parse(mux.Vars(r)["room_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.termNotClosed(ctx, w, session.TermId); !ok {
+		return
+	}
+
+	//execute the query
+	if err := h.db.RemoveRoom(ctx, session.Id, room_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to remove the room from the session",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to remove the room!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Remove the room has been successfully", room_id)
+
+}
+
+//func to add the proctor into the room of the session, the guru cannot watch the exam when they have a
+//lesson in the timetable, they are not available or they watch another exam at the same time
+func (h *HandleRequest) AddProctor_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can add the proctor
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.AddExamProctor
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	//get the session and check the busy time of the guru
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.termNotClosed(ctx, w, session.TermId); !ok {
+		return
+	}
+	busy, err := h.db.GetTeacherBusy(ctx, payload.TeacherId, session.TermId, session.ExamDate)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the schedule of the guru!", err.Error())
+		return
+	}
+	if conflicts := FindConflicts(session.Id, session.StartTime, session.EndTime, busy, "the guru"); len(conflicts) > 0 {
+		utils.ResponseError(w, http.StatusConflict, "The proctor is rejected because of the conflicts!", conflicts)
+		return
+	}
+
+	//execute the query
+	proctor := types.ExamProctor{
+		SessionId: session.Id,
+		RoomId: payload.RoomId,
+		TeacherId: payload.TeacherId,
+		Created_at: time.Now().UTC(),
+	}
+	if err := h.db.AddProctor(ctx, &proctor); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to add the proctor",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to add the proctor!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Add the proctor has been successfully", proctor)
+
+}
+
+//func to remove the proctor from the session
+func (h *HandleRequest) RemoveProctor_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can remove the proctor
+	if !adminOnly(w, r) {
+		return
+	}
+	teacher_id, err := uuid.Parse(mux.Vars(r)["teacher_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	//execute the query
+	if err := h.db.RemoveProctor(ctx, session.Id, teacher_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to remove the proctor",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to remove the proctor!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Remove the proctor has been successfully", teacher_id)
+
+}
+
+//func to allocate the seats of the session again, the students of the classes are mixed in the rooms
+func (h *HandleRequest) AllocateSeats_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can allocate the seats
+	if !adminOnly(w, r) {
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.termNotClosed(ctx, w, session.TermId); !ok {
+		return
+	}
+
+	//the students and the rooms of the session
+	candidates, err := h.db.GetCandidates(ctx, session.Id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the students of the session!", err.Error())
+		return
+	}
+	if len(candidates) == 0 {
+		utils.ResponseError(w, http.StatusBadRequest, "The classes of the session have no students!", false)
+		return
+	}
+	rooms, err := h.db.GetRooms(ctx, session.Id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the rooms of the session!", err.Error())
+		return
+	}
+	seats, err := AllocateSeats(session.Id, candidates, rooms)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to allocate the seats!", err.Error())
+		return
+	}
+
+	//execute the query
+	if err := h.db.SaveSeats(ctx, session.Id, seats); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the seats",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to save the seats!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Allocate the seats has been successfully", seats)
+
+}
+
+//func to get the seats of the session (?room_id=)
+func (h *HandleRequest) GetSeats_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can see the seats
+	if !staffOnly(w, r) {
+		return
+	}
+	var room_id *uuid.UUID
+	if value := r.URL.Query().Get("room_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+			return
+		}
+		room_id = &id
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	//execute the query
+	seats, err := h.db.GetSeats(ctx, session.Id, room_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the seats",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the seats!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the seats has been successfully", seats)
+
+}
+
+//helper to write the printable document of the room of the session
+func (h *HandleRequest) roomDocument(w http.ResponseWriter, r *http.Request, name string, render func(types.ExamSession, types.ExamRoom, []types.ExamSeat, time.Time) ([]byte, error)) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can print the documents of the room
+	if !staffOnly(w, r) {
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	session, ok := h.sessionParam(ctx, w, r)
+	if !ok {
+		return
+	}
+	room, ok := h.roomParam(ctx, w, r, session.Id)
+	if !ok {
+		return
+	}
+	seats, err := h.db.GetSeats(ctx, session.Id, &room.RoomId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the seats!", err.Error())
+		return
+	}
+
+	//render the document
+	document, err := render(*session, *room, seats, time.Now().UTC())
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to render the document of the room",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("document", name),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to render the document!", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-%s.pdf"`, name, session.ExamDate.Format("2006-01-02"), room.RoomCode))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+
+}
+
+//func to print the seat list of the room with the column for the signature of the students
+func (h *HandleRequest) SeatList_Bp(w http.ResponseWriter, r *http.Request) {
+	h.roomDocument(w, r, "daftar-hadir", RenderSeatList)
+}
+
+//func to print the notice for the door of the room with the seats of every class
+func (h *HandleRequest) DoorNotice_Bp(w http.ResponseWriter, r *http.Request) {
+	h.roomDocument(w, r, "denah-ruang", RenderDoorNotice)
+}
+
+//func to get the exams of the siswa of the token in the term (?term_id=) with the room and the seat
+func (h *HandleRequest) MyExams_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//get the student of the user
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	student_id, err := h.grades.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The user is not registered as a student!", false)
+		return
+	}
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return
+	}
+
+	//execute the query
+	exams, err := h.db.GetStudentExams(ctx, *student_id, term_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the exams of the student",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the exams!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the exams has been successfully", exams)
+
+}
@@ -0,0 +1,504 @@
+package exams
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store exam
+type ExamStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewExamStore(db *sqlx.DB) *ExamStore {
+	return &ExamStore{db: db}
+}
+
+//the session with the name of the subject
+const sessionSelect = `
+	SELECT es.id, es.term_id, es.kind, es.title, es.subject_id, sb.name AS subject_name, es.exam_date, es.start_time,
+	es.end_time, es.created_by, es.created_at, es.updated_at
+	FROM exam_sessions es
+	LEFT JOIN subjects sb ON sb.id = es.subject_id
+`
+
+//the seat with the room, the student and the class
+const seatSelect = `
+	SELECT st.session_id, st.room_id, r.code AS room_code, st.seat_no, st.student_id, s.name AS student_name,
+	st.class_id, c.name AS class_name
+	FROM exam_seats st
+	JOIN exam_rooms er ON er.session_id = st.session_id AND er.room_id = st.room_id
+	JOIN rooms r ON r.id = st.room_id
+	JOIN students s ON s.id = st.student_id
+	JOIN classes c ON c.id = st.class_id
+`
+
+//helper to convert the list of the uuid into the array of the query
+func idArray(ids []uuid.UUID) pq.StringArray {
+	array := make(pq.StringArray, 0, len(ids))
+	for _, id := range ids {
+		array = append(array, id.String())
+	}
+	return array
+}
+
+//helper to attach the classes into the sessions
+func (s *ExamStore) attachClasses(ctx context.Context, sessions []types.ExamSession) error {
+
+	if len(sessions) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(sessions))
+	index := make(map[uuid.UUID]int, len(sessions))
+	for i := range sessions {
+		sessions[i].Classes = []types.ExamClass{}
+		ids = append(ids, sessions[i].Id)
+		index[sessions[i].Id] = i
+	}
+
+	//execute the query
+	classes := []types.ExamClass{}
+	if err := s.db.SelectContext(ctx, &classes, `
+		SELECT esc.session_id, esc.class_id, c.name AS class_name FROM exam_session_classes esc
+		JOIN classes c ON c.id = esc.class_id
+		WHERE esc.session_id = ANY($1::uuid[]) ORDER BY c.grade_level, c.name;
+	`, idArray(ids)); err != nil {
+		return fmt.Errorf("failed to get the classes of the sessions: %w", err)
+	}
+	for _, class := range classes {
+		i := index[class.SessionId]
+		sessions[i].Classes = append(sessions[i].Classes, class)
+	}
+
+	return nil
+
+}
+
+//func to create the exam session with the classes
+func (s *ExamStore) CreateSession(ctx context.Context, session *types.ExamSession, classIds []uuid.UUID) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//save the session
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO exam_sessions
+		(id, term_id, kind, title, subject_id, exam_date, start_time, end_time, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+	`,
+		session.Id,
+		session.TermId,
+		session.Kind,
+		session.Title,
+		session.SubjectId,
+		session.ExamDate,
+		session.StartTime,
+		session.EndTime,
+		session.CreatedBy,
+		session.Created_at,
+		session.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new exam session! " + err.Error())
+	}
+
+	//save the classes of the session
+	for _, classId := range classIds {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO exam_session_classes (session_id, class_id) VALUES ($1, $2);
+		`, session.Id, classId); err != nil {
+			return errors.New("Failed to save the class of the session! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the exam session by id with the classes, nil when the session is not exist
+func (s *ExamStore) GetSessionById(ctx context.Context, id uuid.UUID) (*types.ExamSession, error) {
+
+	//execute the query
+	sessions := []types.ExamSession{}
+	if err := s.db.SelectContext(ctx, &sessions, sessionSelect+` WHERE es.id = $1;`, id); err != nil {
+		return nil, fmt.Errorf("failed to get the exam session: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+	if err := s.attachClasses(ctx, sessions); err != nil {
+		return nil, err
+	}
+
+	return &sessions[0], nil
+
+}
+
+//func to get the exam sessions of the term in the order of the time, every kind when the kind is empty
+func (s *ExamStore) GetSessions(ctx context.Context, termId uuid.UUID, kind string) ([]types.ExamSession, error) {
+
+	//execute the query
+	sessions := []types.ExamSession{}
+	if err := s.db.SelectContext(ctx, &sessions, sessionSelect+`
+		WHERE es.term_id = $1 AND ($2 = '' OR es.kind = $2)
+		ORDER BY es.exam_date, es.start_time, es.title;
+	`, termId, kind); err != nil {
+		return nil, fmt.Errorf("failed to get the exam sessions: %w", err)
+	}
+	if err := s.attachClasses(ctx, sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+
+}
+
+//func to delete the exam session with the rooms, the proctors and the seats
+func (s *ExamStore) DeleteSession(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM exam_sessions WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the exam session! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The exam session is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to add the room into the session after the last room, the seats are the capacity of the room when
+//the seats are zero
+func (s *ExamStore) AddRoom(ctx context.Context, room *types.ExamRoom) error {
+
+	//execute the query
+	if err := s.db.GetContext(ctx, room, `
+		INSERT INTO exam_rooms (session_id, room_id, seats, position)
+		SELECT $1, r.id, COALESCE(NULLIF($3, 0), r.capacity),
+		(SELECT COALESCE(MAX(position) + 1, 0) FROM exam_rooms WHERE session_id = $1)
+		FROM rooms r WHERE r.id = $2 AND r.is_active
+		RETURNING session_id, room_id, seats, position,
+		(SELECT code FROM rooms WHERE id = $2) AS room_code, (SELECT name FROM rooms WHERE id = $2) AS room_name, 0 AS seated;
+	`, room.SessionId, room.RoomId, room.Seats); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("The room is not exist or not active!")
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23502" {
+			return errors.New("The room has no capacity, set the seats of the room for the exam!")
+		}
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return errors.New("The room is already used by the session!")
+		}
+		return errors.New("Failed to add the room into the session! " + err.Error())
+	}
+	room.Proctors = []types.ExamProctor{}
+
+	return nil
+
+}
+
+//func to get the rooms of the session with the proctors and the count of the seated students
+func (s *ExamStore) GetRooms(ctx context.Context, sessionId uuid.UUID) ([]types.ExamRoom, error) {
+
+	//execute the query
+	rooms := []types.ExamRoom{}
+	if err := s.db.SelectContext(ctx, &rooms, `
+		SELECT er.session_id, er.room_id, r.code AS room_code, r.name AS room_name, er.seats, er.position,
+		(SELECT COUNT(*) FROM exam_seats st WHERE st.session_id = er.session_id AND st.room_id = er.room_id) AS seated
+		FROM exam_rooms er
+		JOIN rooms r ON r.id = er.room_id
+		WHERE er.session_id = $1 ORDER BY er.position;
+	`, sessionId); err != nil {
+		return nil, fmt.Errorf("failed to get the rooms of the session: %w", err)
+	}
+
+	//the proctors of the rooms
+	proctors := []types.ExamProctor{}
+	if err := s.db.SelectContext(ctx, &proctors, `
+		SELECT ep.session_id, ep.room_id, ep.teacher_id, u.username AS teacher_name, ep.created_at
+		FROM exam_proctors ep
+		JOIN users u ON u.id = ep.teacher_id
+		WHERE ep.session_id = $1 ORDER BY u.username;
+	`, sessionId); err != nil {
+		return nil, fmt.Errorf("failed to get the proctors of the session: %w", err)
+	}
+	index := make(map[uuid.UUID]int, len(rooms))
+	for i := range rooms {
+		rooms[i].Proctors = []types.ExamProctor{}
+		index[rooms[i].RoomId] = i
+	}
+	for _, proctor := range proctors {
+		i := index[proctor.RoomId]
+		rooms[i].Proctors = append(rooms[i].Proctors, proctor)
+	}
+
+	return rooms, nil
+
+}
+
+//func to remove the room from the session with the proctors and the seats of the room
+func (s *ExamStore) RemoveRoom(ctx context.Context, sessionId uuid.UUID, roomId uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM exam_rooms WHERE session_id = $1 AND room_id = $2;`, sessionId, roomId)
+	if err != nil {
+		return errors.New("Failed to remove the room from the session! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The room is not used by the session!")
+	}
+
+	return nil
+
+}
+
+//func to add the proctor into the room of the session, the proctor must be a guru
+func (s *ExamStore) AddProctor(ctx context.Context, proctor *types.ExamProctor) error {
+
+	//execute the query
+	if err := s.db.GetContext(ctx, &proctor.TeacherName, `
+		INSERT INTO exam_proctors (session_id, room_id, teacher_id, created_at)
+		SELECT $1, $2, u.id, $4 FROM users u WHERE u.id = $3 AND u.role = 'guru'
+		RETURNING (SELECT username FROM users WHERE id = $3);
+	`, proctor.SessionId, proctor.RoomId, proctor.TeacherId, proctor.Created_at); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("The proctor must be a guru!")
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return errors.New("The room is not used by the session!")
+		}
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return errors.New("The guru already watches a room of the session!")
+		}
+		return errors.New("Failed to add the proctor! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to remove the proctor from the session
+func (s *ExamStore) RemoveProctor(ctx context.Context, sessionId uuid.UUID, teacherId uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM exam_proctors WHERE session_id = $1 AND teacher_id = $2;`, sessionId, teacherId)
+	if err != nil {
+		return errors.New("Failed to remove the proctor! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The guru is not a proctor of the session!")
+	}
+
+	return nil
+
+}
+
+//func to get the busy time of the guru in the date: the lessons of the timetable in the day of the week,
+//the unavailability of the day (the whole day without the period) and the other exams that they watch
+func (s *ExamStore) GetTeacherBusy(ctx context.Context, teacherId uuid.UUID, termId uuid.UUID, date time.Time) ([]types.ExamBusy, error) {
+
+	//base query
+	query := `
+		SELECT 'lesson' AS kind, ts.id AS source_id, ts.subject || ' ' || c.name AS label, ts.start_time, ts.end_time
+		FROM timetable_slots ts
+		JOIN classes c ON c.id = ts.class_id
+		WHERE ts.teacher_id = $1 AND ts.term_id = $2 AND ts.day_of_week = EXTRACT(ISODOW FROM $3::date)
+		UNION ALL
+		SELECT 'unavailable', u.id, u.reason, COALESCE(p.start_time, TIME '00:00'), COALESCE(p.end_time, TIME '23:59:59')
+		FROM timetable_unavailability u
+		LEFT JOIN periods p ON p.id = u.period_id
+		WHERE u.teacher_id = $1 AND u.day_of_week = EXTRACT(ISODOW FROM $3::date)
+		UNION ALL
+		SELECT 'exam', es.id, es.title, es.start_time, es.end_time
+		FROM exam_proctors ep
+		JOIN exam_sessions es ON es.id = ep.session_id
+		WHERE ep.teacher_id = $1 AND es.exam_date = $3::date
+		ORDER BY start_time;
+	`
+
+	//execute the query
+	busy := []types.ExamBusy{}
+	if err := s.db.SelectContext(ctx, &busy, query, teacherId, termId, date); err != nil {
+		return nil, fmt.Errorf("failed to get the busy time of the guru: %w", err)
+	}
+
+	return busy, nil
+
+}
+
+//func to get the exams that use the room in the date
+func (s *ExamStore) GetRoomBusy(ctx context.Context, roomId uuid.UUID, date time.Time) ([]types.ExamBusy, error) {
+
+	//execute the query
+	busy := []types.ExamBusy{}
+	if err := s.db.SelectContext(ctx, &busy, `
+		SELECT 'exam' AS kind, es.id AS source_id, es.title AS label, es.start_time, es.end_time
+		FROM exam_rooms er
+		JOIN exam_sessions es ON es.id = er.session_id
+		WHERE er.room_id = $1 AND es.exam_date = $2::date
+		ORDER BY es.start_time;
+	`, roomId, date); err != nil {
+		return nil, fmt.Errorf("failed to get the exams of the room: %w", err)
+	}
+
+	return busy, nil
+
+}
+
+//func to get the exams of the classes in the date
+func (s *ExamStore) GetClassBusy(ctx context.Context, classIds []uuid.UUID, date time.Time) ([]types.ExamBusy, error) {
+
+	//execute the query
+	busy := []types.ExamBusy{}
+	if err := s.db.SelectContext(ctx, &busy, `
+		SELECT 'exam' AS kind, es.id AS source_id, es.title || ' (' || c.name || ')' AS label, es.start_time, es.end_time
+		FROM exam_session_classes esc
+		JOIN exam_sessions es ON es.id = esc.session_id
+		JOIN classes c ON c.id = esc.class_id
+		WHERE esc.class_id = ANY($1::uuid[]) AND es.exam_date = $2::date
+		ORDER BY es.start_time;
+	`, idArray(classIds), date); err != nil {
+		return nil, fmt.Errorf("failed to get the exams of the classes: %w", err)
+	}
+
+	return busy, nil
+
+}
+
+//func to get the students of the classes of the session, the class of the student is the class of the
+//term or the class of the active student
+func (s *ExamStore) GetCandidates(ctx context.Context, sessionId uuid.UUID) ([]types.ExamCandidate, error) {
+
+	//base query
+	query := `
+		WITH member AS (
+			SELECT s.id AS student_id, s.name AS student_name, COALESCE(
+				(SELECT e.class_id FROM class_enrollments e WHERE e.student_id = s.id AND e.term_id = es.term_id),
+				CASE WHEN s.status = 'active' THEN s.class_id END
+			) AS class_id
+			FROM students s, exam_sessions es
+			WHERE es.id = $1
+		)
+		SELECT m.student_id, m.student_name, c.id AS class_id, c.name AS class_name
+		FROM member m
+		JOIN exam_session_classes esc ON esc.session_id = $1 AND esc.class_id = m.class_id
+		JOIN classes c ON c.id = m.class_id
+		ORDER BY c.name, m.student_name;
+	`
+
+	//execute the query
+	candidates := []types.ExamCandidate{}
+	if err := s.db.SelectContext(ctx, &candidates, query, sessionId); err != nil {
+		return nil, fmt.Errorf("failed to get the students of the session: %w", err)
+	}
+
+	return candidates, nil
+
+}
+
+//func to replace the seats of the session
+func (s *ExamStore) SaveSeats(ctx context.Context, sessionId uuid.UUID, seats []types.ExamSeat) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//remove the old seats
+	if _, err := tx.ExecContext(ctx, `DELETE FROM exam_seats WHERE session_id = $1;`, sessionId); err != nil {
+		return errors.New("Failed to delete the old seats! " + err.Error())
+	}
+
+	//save the new seats
+	now := time.Now().UTC()
+	for _, seat := range seats {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO exam_seats (session_id, room_id, seat_no, student_id, class_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6);
+		`, sessionId, seat.RoomId, seat.SeatNo, seat.StudentId, seat.ClassId, now); err != nil {
+			return errors.New("Failed to save the seat! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the seats of the session in the order of the room and the seat, only the seats of the room
+//when the room id is not nil
+func (s *ExamStore) GetSeats(ctx context.Context, sessionId uuid.UUID, roomId *uuid.UUID) ([]types.ExamSeat, error) {
+
+	//execute the query
+	seats := []types.ExamSeat{}
+	if err := s.db.SelectContext(ctx, &seats, seatSelect+`
+		WHERE st.session_id = $1 AND ($2::uuid IS NULL OR st.room_id = $2)
+		ORDER BY er.position, st.seat_no;
+	`, sessionId, roomId); err != nil {
+		return nil, fmt.Errorf("failed to get the seats: %w", err)
+	}
+
+	return seats, nil
+
+}
+
+//func to get the exam sessions of the class of the student in the term with the room and the seat
+func (s *ExamStore) GetStudentExams(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) ([]types.StudentExam, error) {
+
+	//base query
+	query := `
+		WITH member AS (
+			SELECT COALESCE(
+				(SELECT e.class_id FROM class_enrollments e WHERE e.student_id = $1 AND e.term_id = $2),
+				(SELECT class_id FROM students WHERE id = $1)
+			) AS class_id
+		)
+		SELECT es.id AS session_id, es.kind, es.title, sb.name AS subject_name, es.exam_date, es.start_time, es.end_time,
+		r.code AS room_code, r.name AS room_name, st.seat_no
+		FROM exam_sessions es
+		JOIN exam_session_classes esc ON esc.session_id = es.id
+		JOIN member m ON m.class_id = esc.class_id
+		LEFT JOIN subjects sb ON sb.id = es.subject_id
+		LEFT JOIN exam_seats st ON st.session_id = es.id AND st.student_id = $1
+		LEFT JOIN rooms r ON r.id = st.room_id
+		WHERE es.term_id = $2
+		ORDER BY es.exam_date, es.start_time;
+	`
+
+	//execute the query
+	exams := []types.StudentExam{}
+	if err := s.db.SelectContext(ctx, &exams, query, studentId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the exams of the student: %w", err)
+	}
+
+	return exams, nil
+
+}
@@ -0,0 +1,239 @@
+package exams
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// the names of the months and the days for the date of the exam
+var (
+	monthNames = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+	dayNames = []string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+)
+
+// the title of the kind of the exam
+var kindTitles = map[string]string{
+	types.ExamMidterm: "PENILAIAN TENGAH SEMESTER",
+	types.ExamFinal: "PENILAIAN AKHIR SEMESTER",
+}
+
+// RoomClassRange is the seats of one class in the room for the door notice
+type RoomClassRange struct {
+	ClassName 		string
+	Count 			int
+	Seats 			[]int
+}
+
+// ClassRanges groups the seats of the room by the class in the order of the class name
+func ClassRanges(seats []types.ExamSeat) []RoomClassRange {
+	index := make(map[string]int)
+	ranges := []RoomClassRange{}
+	for _, seat := range seats {
+		i, ok := index[seat.ClassName]
+		if !ok {
+			i = len(ranges)
+			index[seat.ClassName] = i
+			ranges = append(ranges, RoomClassRange{ClassName: seat.ClassName})
+		}
+		ranges[i].Count++
+		ranges[i].Seats = append(ranges[i].Seats, seat.SeatNo)
+	}
+	for i := range ranges {
+		sort.Ints(ranges[i].Seats)
+	}
+	sort.SliceStable(ranges, func(a, b int) bool { return ranges[a].ClassName < ranges[b].ClassName })
+	return ranges
+}
+
+// CompactSeats writes the seat numbers as the ranges, 1, 3, 4, 5 is written as "1, 3-5"
+func CompactSeats(seats []int) string {
+	parts := []string{}
+	for i := 0; i < len(seats); {
+		j := i
+		for j+1 < len(seats) && seats[j+1] == seats[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, fmt.Sprintf("%d", seats[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", seats[i], seats[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+//helper to start the document of the room with the title and the identity of the exam
+func newRoomDocument(title string, session types.ExamSession, room types.ExamRoom, issuedAt time.Time) (*fpdf.Fpdf, func(string) string, float64) {
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(issuedAt)
+	pdf.SetModificationDate(issuedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle(title+" - "+room.RoomCode, true)
+	pdf.SetMargins(20, 15, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, tr(session.Title+" - "+room.RoomCode), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	content := width - left - right
+
+	return pdf, tr, content
+
+}
+
+//helper to write the date and the time of the session (Senin, 2 Maret 2026, 07:30-09:30)
+func sessionWhen(session types.ExamSession) string {
+	date := session.ExamDate
+	return fmt.Sprintf("%s, %d %s %d, %s-%s", dayNames[date.Weekday()], date.Day(), monthNames[date.Month()-1], date.Year(),
+		shortClock(session.StartTime), shortClock(session.EndTime))
+}
+
+//helper to write the names of the proctors of the room
+func proctorNames(room types.ExamRoom) string {
+	names := []string{}
+	for _, proctor := range room.Proctors {
+		names = append(names, proctor.TeacherName)
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+//helper to write the document into the bytes
+func output(pdf *fpdf.Fpdf, name string) ([]byte, error) {
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("failed to render the %s: %w", name, err)
+	}
+	return out.Bytes(), nil
+}
+
+// RenderSeatList writes the list of the seats of the room as an A4 PDF with the column for the signature
+// of the student, it is the attendance list of the proctor
+func RenderSeatList(session types.ExamSession, room types.ExamRoom, seats []types.ExamSeat, issuedAt time.Time) ([]byte, error) {
+
+	pdf, tr, content := newRoomDocument("Daftar Hadir Peserta", session, room, issuedAt)
+
+	//the title and the identity of the session
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(content, 7, "DAFTAR HADIR PESERTA", "", 1, "C", false, 0, "")
+	if kind, ok := kindTitles[session.Kind]; ok {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(content, 6, kind, "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(3)
+	pdf.SetFont("Helvetica", "", 10)
+	subject := "-"
+	if session.SubjectName != nil {
+		subject = *session.SubjectName
+	}
+	identity := [][2]string{
+		{"Ujian", session.Title},
+		{"Mata Pelajaran", subject},
+		{"Waktu", sessionWhen(session)},
+		{"Ruang", room.RoomCode + " - " + room.RoomName},
+		{"Pengawas", proctorNames(room)},
+	}
+	for _, row := range identity {
+		pdf.CellFormat(35, 6, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(content-35, 6, tr(": "+row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	//the seats of the room
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(15, 7, "Kursi", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(content-85, 7, "Nama Siswa", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(30, 7, "Kelas", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(40, 7, "Tanda Tangan", "1", 1, "C", true, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	if len(seats) == 0 {
+		pdf.CellFormat(content, 8, tr("Belum ada peserta di ruang ini"), "1", 1, "C", false, 0, "")
+	}
+	for _, seat := range seats {
+		pdf.CellFormat(15, 8, fmt.Sprintf("%d", seat.SeatNo), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(content-85, 8, tr(seat.StudentName), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 8, tr(seat.ClassName), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(40, 8, "", "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(4)
+	pdf.CellFormat(content, 6, tr(fmt.Sprintf("Jumlah peserta: %d    Hadir: ......    Tidak hadir: ......", len(seats))), "", 1, "L", false, 0, "")
+	pdf.Ln(8)
+
+	//the signature of the proctor
+	half := content / 2
+	pdf.CellFormat(half, 6, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(half, 6, "Pengawas Ruang", "", 1, "C", false, 0, "")
+	pdf.Ln(18)
+	pdf.CellFormat(half, 6, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(half, 6, "( ................................ )", "", 1, "C", false, 0, "")
+
+	return output(pdf, "seat list")
+
+}
+
+// RenderDoorNotice writes the notice for the door of the room as an A4 PDF, the large room code with the
+// session and the seats of every class so the students find their room
+func RenderDoorNotice(session types.ExamSession, room types.ExamRoom, seats []types.ExamSeat, issuedAt time.Time) ([]byte, error) {
+
+	pdf, tr, content := newRoomDocument("Denah Ruang Ujian", session, room, issuedAt)
+
+	//the room
+	if kind, ok := kindTitles[session.Kind]; ok {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(content, 8, kind, "", 1, "C", false, 0, "")
+	}
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(content, 7, tr(session.Title), "", 1, "C", false, 0, "")
+	pdf.CellFormat(content, 7, tr(sessionWhen(session)), "", 1, "C", false, 0, "")
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(content, 10, "RUANG", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 72)
+	pdf.CellFormat(content, 32, tr(room.RoomCode), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.CellFormat(content, 8, tr(room.RoomName), "", 1, "C", false, 0, "")
+	pdf.Ln(10)
+
+	//the seats of every class
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(50, 9, "Kelas", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(30, 9, "Jumlah", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(content-80, 9, "Nomor Kursi", "1", 1, "C", true, 0, "")
+	pdf.SetFont("Helvetica", "", 12)
+	ranges := ClassRanges(seats)
+	if len(ranges) == 0 {
+		pdf.CellFormat(content, 9, tr("Belum ada peserta di ruang ini"), "1", 1, "C", false, 0, "")
+	}
+	for _, item := range ranges {
+		pdf.CellFormat(50, 9, tr(item.ClassName), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(30, 9, fmt.Sprintf("%d", item.Count), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(content-80, 9, CompactSeats(item.Seats), "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(content, 8, tr(fmt.Sprintf("Jumlah peserta: %d", len(seats))), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(content, 7, tr("Pengawas: "+proctorNames(room)), "", 1, "C", false, 0, "")
+
+	return output(pdf, "door notice")
+
+}
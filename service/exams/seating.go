@@ -0,0 +1,179 @@
+package exams
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/service/timetable"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// MixClasses orders the students so the neighbours come from different classes when it is possible. The
+// next student always comes from the class with the most students left that is not the class of the
+// previous student, the tie goes to the class name and the students of one class keep the order of the name.
+func MixClasses(candidates []types.ExamCandidate) []types.ExamCandidate {
+
+	sorted := append([]types.ExamCandidate(nil), candidates...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if sorted[a].ClassName != sorted[b].ClassName {
+			return sorted[a].ClassName < sorted[b].ClassName
+		}
+		return sorted[a].StudentName < sorted[b].StudentName
+	})
+	queues := [][]types.ExamCandidate{}
+	index := make(map[uuid.UUID]int)
+	for _, candidate := range sorted {
+		i, ok := index[candidate.ClassId]
+		if !ok {
+			i = len(queues)
+			index[candidate.ClassId] = i
+			queues = append(queues, []types.ExamCandidate{})
+		}
+		queues[i] = append(queues[i], candidate)
+	}
+
+	mixed := make([]types.ExamCandidate, 0, len(sorted))
+	previous := -1
+	for len(mixed) < len(sorted) {
+		pick := -1
+		for i, queue := range queues {
+			if len(queue) == 0 || (i == previous && len(queues[i]) < len(sorted)-len(mixed)) {
+				continue
+			}
+			if pick == -1 || len(queue) > len(queues[pick]) {
+				pick = i
+			}
+		}
+		mixed = append(mixed, queues[pick][0])
+		queues[pick] = queues[pick][1:]
+		previous = pick
+	}
+
+	return mixed
+
+}
+
+// SplitSeats divides the students between the rooms in the proportion of the seats, so every room is
+// filled at the same rate. The rest of the division goes to the rooms with the largest remainder.
+func SplitSeats(students int, rooms []types.ExamRoom) ([]int, error) {
+
+	total := 0
+	for _, room := range rooms {
+		total += room.Seats
+	}
+	if students > total {
+		return nil, fmt.Errorf("the rooms have %d seats for %d students, add more rooms or seats", total, students)
+	}
+
+	counts := make([]int, len(rooms))
+	remainders := make([]int, len(rooms))
+	given := 0
+	for i, room := range rooms {
+		counts[i] = students * room.Seats / total
+		remainders[i] = students * room.Seats % total
+		given += counts[i]
+	}
+	order := make([]int, len(rooms))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for _, i := range order {
+		if given == students {
+			break
+		}
+		if counts[i] < rooms[i].Seats {
+			counts[i]++
+			given++
+		}
+	}
+
+	return counts, nil
+
+}
+
+// AllocateSeats gives every student a seat in the rooms of the session. The students are mixed first and
+// every room takes the next part of the mixed order in the position of the rooms, the seats start from 1.
+func AllocateSeats(sessionId uuid.UUID, candidates []types.ExamCandidate, rooms []types.ExamRoom) ([]types.ExamSeat, error) {
+
+	if len(rooms) == 0 {
+		return nil, fmt.Errorf("the session has no rooms")
+	}
+	ordered := append([]types.ExamRoom(nil), rooms...)
+	sort.SliceStable(ordered, func(a, b int) bool { return ordered[a].Position < ordered[b].Position })
+	counts, err := SplitSeats(len(candidates), ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	mixed := MixClasses(candidates)
+	seats := make([]types.ExamSeat, 0, len(mixed))
+	next := 0
+	for i, room := range ordered {
+		for seat := 1; seat <= counts[i]; seat++ {
+			candidate := mixed[next]
+			next++
+			seats = append(seats, types.ExamSeat{
+				SessionId: sessionId,
+				RoomId: room.RoomId,
+				RoomCode: room.RoomCode,
+				SeatNo: seat,
+				StudentId: candidate.StudentId,
+				StudentName: candidate.StudentName,
+				ClassId: candidate.ClassId,
+				ClassName: candidate.ClassName,
+			})
+		}
+	}
+
+	return seats, nil
+
+}
+
+// FindConflicts reports every busy time that overlaps the time of the session, the session itself is
+// skipped so the room or the proctor can be saved again. The who is the subject of the message.
+func FindConflicts(sessionId uuid.UUID, startTime string, endTime string, busy []types.ExamBusy, who string) []types.ExamConflict {
+
+	conflicts := []types.ExamConflict{}
+	for _, item := range busy {
+		if item.Kind == types.BusyExam && item.SourceId == sessionId {
+			continue
+		}
+		if !timetable.Overlaps(startTime, endTime, item.StartTime, item.EndTime) {
+			continue
+		}
+		when := fmt.Sprintf("%s-%s", shortClock(item.StartTime), shortClock(item.EndTime))
+		var message string
+		switch item.Kind {
+		case types.BusyLesson:
+			message = fmt.Sprintf("%s has the lesson %s at %s", who, item.Label, when)
+		case types.BusyUnavailable:
+			message = fmt.Sprintf("%s is not available at %s", who, when)
+			if item.Label != "" {
+				message += " (" + item.Label + ")"
+			}
+		default:
+			message = fmt.Sprintf("%s is already in the exam %s at %s", who, item.Label, when)
+		}
+		conflicts = append(conflicts, types.ExamConflict{
+			Kind: item.Kind,
+			Message: message,
+			SourceId: item.SourceId,
+			StartTime: item.StartTime,
+			EndTime: item.EndTime,
+		})
+	}
+
+	return conflicts
+
+}
+
+//helper to print the clock without the seconds
+func shortClock(value string) string {
+	if len(value) >= 5 {
+		return value[:5]
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package exams
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func testCandidates(classes map[string]int) []types.ExamCandidate {
+	candidates := []types.ExamCandidate{}
+	for name, count := range classes {
+		classId := uuid.New()
+		for i := 0; i < count; i++ {
+			candidates = append(candidates, types.ExamCandidate{
+				StudentId: uuid.New(),
+				StudentName: fmt.Sprintf("%s siswa %02d", name, i),
+				ClassId: classId,
+				ClassName: name,
+			})
+		}
+	}
+	return candidates
+}
+
+func TestMixClasses(t *testing.T) {
+	candidates := testCandidates(map[string]int{"X IPA 1": 5, "X IPA 2": 4, "X IPS 1": 3})
+	mixed := MixClasses(candidates)
+	if len(mixed) != len(candidates) {
+		t.Fatalf("expected %d students, got %d", len(candidates), len(mixed))
+	}
+	seen := make(map[uuid.UUID]bool)
+	for i, candidate := range mixed {
+		if seen[candidate.StudentId] {
+			t.Fatalf("the student %s is seated twice", candidate.StudentName)
+		}
+		seen[candidate.StudentId] = true
+		if i > 0 && mixed[i-1].ClassId == candidate.ClassId {
+			t.Fatalf("the neighbours %d and %d come from the same class", i-1, i)
+		}
+	}
+
+	//the same input gives the same order
+	again := MixClasses(candidates)
+	for i := range mixed {
+		if mixed[i].StudentId != again[i].StudentId {
+			t.Fatalf("the mixing should be deterministic")
+		}
+	}
+
+	//one big class cannot be mixed completely but every student is kept
+	if got := MixClasses(testCandidates(map[string]int{"X IPA 1": 4, "X IPA 2": 1})); len(got) != 5 {
+		t.Fatalf("expected 5 students, got %d", len(got))
+	}
+}
+
+func TestSplitSeats(t *testing.T) {
+	rooms := []types.ExamRoom{{Seats: 20}, {Seats: 20}, {Seats: 10}}
+	counts, err := SplitSeats(25, rooms)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if counts[0] != 10 || counts[1] != 10 || counts[2] != 5 {
+		t.Fatalf("the students should fill the rooms at the same rate, got %v", counts)
+	}
+	counts, _ = SplitSeats(49, rooms)
+	if counts[0]+counts[1]+counts[2] != 49 || counts[2] > 10 {
+		t.Fatalf("unexpected split %v", counts)
+	}
+	if _, err := SplitSeats(51, rooms); err == nil {
+		t.Fatalf("the students over the seats should fail")
+	}
+}
+
+func TestAllocateSeats(t *testing.T) {
+	session := uuid.New()
+	candidates := testCandidates(map[string]int{"XI IPA 1": 6, "XI IPA 2": 6})
+	rooms := []types.ExamRoom{
+		{RoomId: uuid.New(), RoomCode: "R2", Seats: 6, Position: 1},
+		{RoomId: uuid.New(), RoomCode: "R1", Seats: 6, Position: 0},
+	}
+	seats, err := AllocateSeats(session, candidates, rooms)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(seats) != 12 || seats[0].RoomCode != "R1" || seats[0].SeatNo != 1 || seats[6].RoomCode != "R2" || seats[6].SeatNo != 1 {
+		t.Fatalf("the rooms should be filled in the position order, got %v", seats)
+	}
+	classes := make(map[string]map[string]bool)
+	for _, seat := range seats {
+		if classes[seat.RoomCode] == nil {
+			classes[seat.RoomCode] = make(map[string]bool)
+		}
+		classes[seat.RoomCode][seat.ClassName] = true
+	}
+	for room, names := range classes {
+		if len(names) != 2 {
+			t.Fatalf("the room %s should mix the classes, got %v", room, names)
+		}
+	}
+	if _, err := AllocateSeats(session, candidates, nil); err == nil {
+		t.Fatalf("the session without the rooms should fail")
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	session := uuid.New()
+	busy := []types.ExamBusy{
+		{Kind: types.BusyLesson, SourceId: uuid.New(), Label: "Matematika X IPA 1", StartTime: "07:00:00", EndTime: "08:30:00"},
+		{Kind: types.BusyExam, SourceId: session, Label: "PTS Fisika", StartTime: "08:00:00", EndTime: "10:00:00"},
+		{Kind: types.BusyExam, SourceId: uuid.New(), Label: "PTS Kimia", StartTime: "10:00:00", EndTime: "12:00:00"},
+		{Kind: types.BusyUnavailable, SourceId: uuid.New(), Label: "rapat", StartTime: "00:00:00", EndTime: "23:59:59"},
+	}
+	conflicts := FindConflicts(session, "08:00", "10:00", busy, "the teacher")
+	if len(conflicts) != 2 || conflicts[0].Kind != types.BusyLesson || conflicts[1].Kind != types.BusyUnavailable {
+		t.Fatalf("expected the lesson and the unavailability, got %v", conflicts)
+	}
+}
+
+func TestCompactSeats(t *testing.T) {
+	if got := CompactSeats([]int{1, 3, 4, 5, 8, 9}); got != "1, 3-5, 8-9" {
+		t.Fatalf("unexpected seats %q", got)
+	}
+}
+
+func TestRenderRoomDocuments(t *testing.T) {
+	subject := "Matematika"
+	session := types.ExamSession{
+		Id: uuid.New(),
+		Kind: types.ExamMidterm,
+		Title: "PTS Matematika",
+		SubjectName: &subject,
+		ExamDate: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC),
+		StartTime: "07:30:00",
+		EndTime: "09:30:00",
+	}
+	room := types.ExamRoom{RoomId: uuid.New(), RoomCode: "R101", RoomName: "Ruang 101", Seats: 20,
+		Proctors: []types.ExamProctor{{TeacherName: "Budi Santoso"}}}
+	seats, _ := AllocateSeats(session.Id, testCandidates(map[string]int{"X IPA 1": 3, "X IPA 2": 2}), []types.ExamRoom{room})
+	issuedAt := time.Date(2026, time.February, 20, 9, 0, 0, 0, time.UTC)
+
+	for name, render := range map[string]func(types.ExamSession, types.ExamRoom, []types.ExamSeat, time.Time) ([]byte, error){
+		"seat list": RenderSeatList,
+		"door notice": RenderDoorNotice,
+	} {
+		first, err := render(session, room, seats, issuedAt)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", name, err)
+		}
+		if !bytes.HasPrefix(first, []byte("%PDF-")) {
+			t.Fatalf("%s: the output is not a pdf", name)
+		}
+		second, _ := render(session, room, seats, issuedAt)
+		if !bytes.Equal(first, second) {
+			t.Fatalf("%s: the rendering should be deterministic", name)
+		}
+	}
+}
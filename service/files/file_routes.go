@@ -0,0 +1,103 @@
+package files
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/storage"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that serve the files from the storage with a signed url
+type HandleRequest struct {
+	files  storage.Storage
+	signer *storage.Signer
+}
+
+//func that declare the handler for files
+func NewHandlerFile(files storage.Storage, signer *storage.Signer) *HandleRequest {
+	return &HandleRequest{files: files, signer: signer}
+}
+
+//func to download a file with the signed url (expires + signature)
+func (h *HandleRequest) Download_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//logger the data response if the request id value is zero!
+		logger.Log.Info("Failed to get the request id!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+		)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//get the key of the file and validate the signature of the url
+	key := mux.Vars(r)["key"]
+	if !storage.ValidKey(key) {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid key of the file!", false)
+		return
+	}
+	query := r.URL.Query()
+	if err := h.signer.Verify(key, query.Get("expires"), query.Get("signature")); err != nil {
+		//logger the data response if the signature is invalid
+		logger.Log.Warn("Invalid signed url",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+		)
+		utils.ResponseError(w, http.StatusForbidden, "Failed to verify the url!", err.Error())
+		return
+	}
+
+	//open the file from the storage
+	body, object, err := h.files.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			utils.ResponseError(w, http.StatusNotFound, "The file is not exist!", false)
+			return
+		}
+		//logger the data response if the storage is failed
+		logger.Log.Error("Failed to open the file",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+		)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to open the file!", err.Error())
+		return
+	}
+	defer body.Close()
+
+	//the keys are content addressed, so the content of a key never changes
+	if object.ContentType != "" {
+		w.Header().Set("Content-Type", object.ContentType)
+	}
+	w.Header().Set("Cache-Control", "private, max-age=86400, immutable")
+	w.Header().Set("ETag", `"`+key+`"`)
+
+	//serve the file, serve content also handle the range and if-none-match headers
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, path.Base(key), object.ModTime, seeker)
+		return
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && match == w.Header().Get("ETag") {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if object.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, body)
+
+}
@@ -0,0 +1,60 @@
+package grades
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+type guardianStub struct {
+	types.GuardianStore
+	guardian uuid.UUID
+	children []uuid.UUID
+}
+
+func (s guardianStub) IsGuardianOf(ctx context.Context, userId uuid.UUID, studentId uuid.UUID) (bool, error) {
+	if userId != s.guardian {
+		return false, nil
+	}
+	for _, child := range s.children {
+		if child == studentId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func guardianRequest(userId uuid.UUID) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/assessments/1/appeals", nil)
+	ctx := context.WithValue(r.Context(), "role_user", types.RoleOrangtua)
+	ctx = context.WithValue(ctx, "user_id", userId)
+	return r.WithContext(ctx)
+}
+
+func TestStudentOfAppealForGuardian(t *testing.T) {
+	guardian := uuid.New()
+	child := uuid.New()
+	h := &HandleRequest{guardians: guardianStub{guardian: guardian, children: []uuid.UUID{child}}}
+
+	w := httptest.NewRecorder()
+	student_id, ok := h.studentOfAppeal(context.Background(), w, guardianRequest(guardian), &child)
+	if !ok || student_id != child {
+		t.Fatalf("expected the linked child, got %v %d", student_id, w.Code)
+	}
+
+	other := uuid.New()
+	w = httptest.NewRecorder()
+	if _, ok := h.studentOfAppeal(context.Background(), w, guardianRequest(guardian), &other); ok || w.Code != http.StatusForbidden {
+		t.Fatalf("expected the forbidden student of the other family, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	if _, ok := h.studentOfAppeal(context.Background(), w, guardianRequest(guardian), nil); ok || w.Code != http.StatusBadRequest {
+		t.Fatalf("expected the student id to be required, got %d", w.Code)
+	}
+}
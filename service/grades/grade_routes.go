@@ -0,0 +1,867 @@
+package grades
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the grade store for a database logic
+type HandleRequest struct {
+	db types.GradeStore
+	academics types.AcademicStore
+	subjects types.SubjectStore
+	guardians types.GuardianStore
+	notifier types.Notifier
+	rules types.TranscriptRules
+	honorRollMin float64
+}
+
+//func that declare the handler for grades with the default rounding and kkm of the transcripts and
+//the minimum average of the honor roll
+func NewHandlerGrade(db types.GradeStore, academics types.AcademicStore, subjects types.SubjectStore, guardians types.GuardianStore, notifier types.Notifier, cfg config.ConfigParams) *HandleRequest {
+	return &HandleRequest{
+		db: db,
+		academics: academics,
+		subjects: subjects,
+		guardians: guardians,
+		notifier: notifier,
+		rules: types.TranscriptRules{
+			Rounding: cfg.GradeRounding,
+			Decimals: cfg.GradeDecimals,
+			DefaultKkm: float64(cfg.DefaultKKM),
+		},
+		honorRollMin: float64(cfg.HonorRollMin),
+	}
+}
+
+//helper for the term query params, the default is the open term
+func (h *HandleRequest) termParam(ctx context.Context, r *http.Request) (uuid.UUID, error) {
+	if value := r.URL.Query().Get("term_id"); value != "" {
+		return uuid.Parse(value)
+	}
+	term, err := h.academics.GetActiveTerm(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if term == nil {
+		return uuid.Nil, fmt.Errorf("there is no open term")
+	}
+	return term.Id, nil
+}
+
+//helper to get the gradebook of the path (/classes/{id}/subjects/{subject_id}) and the term params
+func (h *HandleRequest) gradebookKey(ctx context.Context, w http.ResponseWriter, r *http.Request) (types.GradebookKey, bool) {
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return types.GradebookKey{}, false
+	}
+	subject_id, err := uuid.Parse(mux.Vars(r)["subject_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return types.GradebookKey{}, false
+	}
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return types.GradebookKey{}, false
+	}
+	return types.GradebookKey{SubjectId: subject_id, ClassId: class_id, TermId: term_id}, true
+}
+
+//helper to check if the user can manage the gradebook, the admin can manage every gradebook and
+//the guru only the gradebook of the subject that they teach in the class for the term
+func (h *HandleRequest) canGrade(ctx context.Context, w http.ResponseWriter, r *http.Request, key types.GradebookKey) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role == "admin" {
+		return true
+	}
+	if role != "guru" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	allowed, err := h.subjects.CanTeach(ctx, user_id, key.ClassId, &key.TermId, &key.SubjectId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the teaching assignment!", err.Error())
+		return false
+	}
+	if !allowed {
+		utils.ResponseError(w, http.StatusForbidden, "The guru is not assigned to the subject of this class!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check that the user can read the grades of the student, the guru and the admin can read
+//every student and the siswa only their own
+func (h *HandleRequest) canReadStudent(ctx context.Context, w http.ResponseWriter, r *http.Request, studentId uuid.UUID) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role == "guru" || role == "admin" {
+		return true
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	own, err := h.db.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return false
+	}
+	if own == nil || *own != studentId {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check that the term of the gradebook is not closed
+func (h *HandleRequest) termNotClosed(ctx context.Context, w http.ResponseWriter, termId uuid.UUID) bool {
+	term, err := h.academics.GetTermById(ctx, termId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return false
+	}
+	if term == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The term is not exist!", false)
+		return false
+	}
+	if term.Status == "closed" {
+		utils.ResponseError(w, http.StatusBadRequest, "The gradebook of a closed term cannot be changed!", false)
+		return false
+	}
+	return true
+}
+
+//helper to respond the error of the store, the change of a published gradebook or of a resolved
+//remedial or appeal is a conflict
+func storeError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, ErrGradebookLocked) || errors.Is(err, ErrRemedialExists) || errors.Is(err, ErrAppealPending) || errors.Is(err, ErrAlreadyResolved) {
+		utils.ResponseError(w, http.StatusConflict, message, err.Error())
+		return
+	}
+	utils.ResponseError(w, http.StatusBadRequest, message, err.Error())
+}
+
+//helper to load the gradebook with the computed term scores of every student
+func (h *HandleRequest) loadGradebook(ctx context.Context, key types.GradebookKey) (*types.Gradebook, error) {
+
+	saved, err := h.db.GetWeights(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	assessments, err := h.db.GetAssessments(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	students, err := h.db.GetGradebookStudents(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := h.db.GetScores(ctx, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	publication, err := h.db.GetPublication(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	//group the scores by the student
+	byStudent := make(map[uuid.UUID]map[uuid.UUID]float64)
+	for _, score := range scores {
+		if byStudent[score.StudentId] == nil {
+			byStudent[score.StudentId] = make(map[uuid.UUID]float64)
+		}
+		byStudent[score.StudentId][score.AssessmentId] = score.Score
+	}
+
+	weights := EffectiveWeights(saved)
+	gradebook := &types.Gradebook{
+		GradebookKey: key,
+		Weights: weights,
+		Assessments: assessments,
+		Students: make([]types.TermScore, 0, len(students)),
+		Published: publication,
+	}
+	for _, student := range students {
+		result := ComputeTermScore(weights, assessments, byStudent[student.StudentId])
+		result.StudentId = student.StudentId
+		result.Name = student.Name
+		result.Scores = byStudent[student.StudentId]
+		gradebook.Students = append(gradebook.Students, result)
+	}
+
+	return gradebook, nil
+
+}
+
+//func to get the gradebook of the subject in the class (?term_id=) with the computed term scores
+func (h *HandleRequest) GetGradebook_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can see the gradebook
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	key, ok := h.gradebookKey(ctx, w, r)
+	if !ok || !h.canGrade(ctx, w, r, key) {
+		return 
+	}
+
+	//execute the query
+	gradebook, err := h.loadGradebook(ctx, key)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the gradebook", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the gradebook!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the gradebook has been successfully", gradebook)
+
+}
+
+//func to replace the weights of the categories of the gradebook, the weights must make 100
+func (h *HandleRequest) SetWeights_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can manage the gradebook
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	key, ok := h.gradebookKey(ctx, w, r)
+	if !ok || !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SetGradeWeights
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	if err := ValidateWeights(payload.Weights); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid weights of the gradebook!", err.Error())
+		return 
+	}
+
+	//execute the query
+	if err := h.db.SetWeights(ctx, key, payload.Weights); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to set the weights", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to set the weights!", err)
+		return 
+	}
+
+	//return a final result
+	saved, err := h.db.GetWeights(ctx, key)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the weights!", err.Error())
+		return 
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "Set the weights has been successfully", EffectiveWeights(saved))
+
+}
+
+//func to create a new assessment in the gradebook of the subject in the class
+func (h *HandleRequest) CreateAssessment_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can manage the gradebook
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	key, ok := h.gradebookKey(ctx, w, r)
+	if !ok || !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateAssessment
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	if payload.MaxScore == 0 {
+		payload.MaxScore = 100
+	}
+	var assessed_on *time.Time
+	if payload.AssessedOn != "" {
+		date, _ := time.Parse("2006-01-02", payload.AssessedOn)
+		assessed_on = &date
+	}
+
+	//make the struct of the assessment and execute the query
+	assessment := &types.Assessment{
+		Id: uuid.New(),
+		SubjectId: key.SubjectId,
+		ClassId: key.ClassId,
+		TermId: key.TermId,
+		Category: payload.Category,
+		Title: payload.Title,
+		MaxScore: payload.MaxScore,
+		AssessedOn: assessed_on,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		assessment.CreatedBy = &user_id
+	}
+	if err := h.db.CreateAssessment(ctx, assessment); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new assessment", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to create the assessment!", err)
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new assessment has been successfully", assessment)
+
+}
+
+//func to delete the assessment with the scores
+func (h *HandleRequest) DeleteAssessment_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	assessment_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//only the admin and the assigned guru can manage the gradebook of the assessment
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	assessment, err := h.db.GetAssessmentById(ctx, assessment_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the assessment!", err.Error())
+		return 
+	}
+	if assessment == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The assessment is not exist!", false)
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//execute the query
+	if err := h.db.DeleteAssessment(ctx, assessment_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the assessment", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to delete the assessment!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the assessment has been successfully", assessment_id)
+
+}
+
+//func to save the scores of the students of the class in one assessment at once
+func (h *HandleRequest) SaveScores_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	assessment_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SaveScores
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the admin and the assigned guru can grade the assessment
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	assessment, err := h.db.GetAssessmentById(ctx, assessment_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the assessment!", err.Error())
+		return 
+	}
+	if assessment == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The assessment is not exist!", false)
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//execute the query
+	var graded_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		graded_by = &user_id
+	}
+	if err := h.db.SaveScores(ctx, assessment, payload.Scores, graded_by); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the scores", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to save the scores!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Save the scores has been successfully", len(payload.Scores))
+
+}
+
+//func to publish the gradebook, the gradebook is locked and the siswa can see the scores
+func (h *HandleRequest) Publish_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can publish the gradebook
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	key, ok := h.gradebookKey(ctx, w, r)
+	if !ok || !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//compute the term scores of the students
+	gradebook, err := h.loadGradebook(ctx, key)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the gradebook!", err.Error())
+		return 
+	}
+	if len(gradebook.Assessments) == 0 {
+		utils.ResponseError(w, http.StatusBadRequest, "The gradebook without any assessment cannot be published!", false)
+		return 
+	}
+
+	//execute the query
+	var published_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		published_by = &user_id
+	}
+	if err := h.db.Publish(ctx, key, published_by, gradebook.Students); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to publish the gradebook", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to publish the gradebook!", err)
+		return 
+	}
+
+	//tell the students and their guardians, the gradebook is already published so the failure is only logged
+	h.notifyPublished(ctx, key, gradebook.Students, requestID)
+
+	//return a final result
+	if gradebook.Published, err = h.db.GetPublication(ctx, key); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the publication!", err.Error())
+		return 
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "Publish the gradebook has been successfully", gradebook)
+
+}
+
+//helper to send the event of the published gradebook to the students of the gradebook and their guardians
+func (h *HandleRequest) notifyPublished(ctx context.Context, key types.GradebookKey, students []types.TermScore, requestID string) {
+	subject_name := ""
+	if subject, err := h.subjects.GetSubjectById(ctx, key.SubjectId); err == nil && subject != nil {
+		subject_name = subject.Name
+	}
+	student_ids := make([]uuid.UUID, 0, len(students))
+	for _, student := range students {
+		student_ids = append(student_ids, student.StudentId)
+	}
+	if err := h.notifier.Notify(ctx, types.NotificationEvent{
+		Type: types.NotifyGradesPublished,
+		Audience: types.NotificationAudience{StudentIds: student_ids, Guardians: true},
+		Payload: map[string]any{
+			"subject_id": key.SubjectId.String(),
+			"subject_name": subject_name,
+			"class_id": key.ClassId.String(),
+			"term_id": key.TermId.String(),
+		},
+		At: time.Now().UTC(),
+	}); err != nil {
+		logger.Log.Warn("Failed to notify the published gradebook",
+			zap.String("request_id", requestID),
+			zap.Error(err),
+		)
+	}
+}
+
+//func to unlock the published gradebook, only the admin can unlock it
+func (h *HandleRequest) Unpublish_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin can unlock the gradebook
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	key, ok := h.gradebookKey(ctx, w, r)
+	if !ok || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//execute the query
+	if err := h.db.Unpublish(ctx, key); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to unpublish the gradebook", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to unpublish the gradebook!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Unpublish the gradebook has been successfully", key)
+
+}
+
+//helper to load the grades of the student in every gradebook of the term
+func (h *HandleRequest) studentGrades(ctx context.Context, studentId uuid.UUID, termId uuid.UUID, onlyPublished bool) ([]types.StudentGrade, error) {
+
+	gradebooks, err := h.db.GetStudentGradebooks(ctx, studentId, termId, onlyPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	grades := make([]types.StudentGrade, 0, len(gradebooks))
+	for _, gradebook := range gradebooks {
+		saved, err := h.db.GetWeights(ctx, gradebook.GradebookKey)
+		if err != nil {
+			return nil, err
+		}
+		assessments, err := h.db.GetAssessments(ctx, gradebook.GradebookKey)
+		if err != nil {
+			return nil, err
+		}
+		scores, err := h.db.GetScores(ctx, gradebook.GradebookKey, &studentId)
+		if err != nil {
+			return nil, err
+		}
+		byAssessment := make(map[uuid.UUID]float64, len(scores))
+		for _, score := range scores {
+			byAssessment[score.AssessmentId] = score.Score
+		}
+		result := ComputeTermScore(EffectiveWeights(saved), assessments, byAssessment)
+		result.StudentId = studentId
+		result.Scores = byAssessment
+		grades = append(grades, types.StudentGrade{
+			StudentGradebook: gradebook,
+			Assessments: assessments,
+			Result: result,
+		})
+	}
+
+	return grades, nil
+
+}
+
+//func to get the grades of the student in the term (?term_id=), the guru and the admin can see the
+//gradebooks that are not published yet and the orangtua only the published grades of their children
+func (h *HandleRequest) StudentGrades_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the siswa use their own grades, the orangtua see the grades of their children
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" && role != types.RoleOrangtua {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if role == types.RoleOrangtua {
+		user_id, err := middleware.GetIdMiddleware(w, r)
+		if err != nil || user_id == uuid.Nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+			return 
+		}
+		linked, err := h.guardians.IsGuardianOf(ctx, user_id, student_id)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to check the guardian of the student!", err.Error())
+			return 
+		}
+		if !linked {
+			utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+			return 
+		}
+	}
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//execute the query
+	grades, err := h.studentGrades(ctx, student_id, term_id, role == types.RoleOrangtua)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the grades of the student", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the grades!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the grades has been successfully", grades)
+
+}
+
+//func to get the published grades of the siswa of the token in the term (?term_id=)
+func (h *HandleRequest) MyGrades_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the student of the user
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	student_id, err := h.db.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return 
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The user is not registered as a student!", false)
+		return 
+	}
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//execute the query, only the published gradebooks
+	grades, err := h.studentGrades(ctx, *student_id, term_id, true)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the grades of the student", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the grades!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the grades has been successfully", grades)
+
+}
@@ -0,0 +1,633 @@
+package grades
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store grade
+type GradeStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewGradeStore(db *sqlx.DB) *GradeStore {
+	return &GradeStore{db: db}
+}
+
+//the column of the assessment that we select in every query
+const assessmentColumns = `id, subject_id, class_id, term_id, category, title, max_score, assessed_on, created_by, created_at, updated_at`
+
+//the students of the gradebook, the active students of the class or the members of the class in the term
+const gradebookStudents = `
+	SELECT s.id AS student_id, s.name FROM students s
+	WHERE (s.status = 'active' AND s.class_id = $1) OR EXISTS (
+		SELECT 1 FROM class_enrollments e WHERE e.student_id = s.id AND e.class_id = $1 AND e.term_id = $2
+	)
+`
+
+//helper to check that the gradebook is not published yet
+func lockedGradebook(ctx context.Context, tx *sqlx.Tx, key types.GradebookKey) error {
+
+	var published bool
+	if err := tx.GetContext(ctx, &published, `
+		SELECT EXISTS (
+			SELECT 1 FROM gradebook_publications WHERE subject_id = $1 AND class_id = $2 AND term_id = $3
+		);
+	`, key.SubjectId, key.ClassId, key.TermId); err != nil {
+		return errors.New("Failed to check the publication of the gradebook! " + err.Error())
+	}
+	if published {
+		return ErrGradebookLocked
+	}
+
+	return nil
+
+}
+
+//func to get the saved weights of the gradebook
+func (s *GradeStore) GetWeights(ctx context.Context, key types.GradebookKey) ([]types.GradeWeight, error) {
+
+	return selectWeights(ctx, s.db, key)
+
+}
+
+//helper to select the weights with the db or the transaction
+func selectWeights(ctx context.Context, db sqlx.QueryerContext, key types.GradebookKey) ([]types.GradeWeight, error) {
+
+	//base query
+	query := `
+		SELECT category, weight FROM grade_weights
+		WHERE subject_id = $1 AND class_id = $2 AND term_id = $3;
+	`
+
+	//execute the query
+	weights := []types.GradeWeight{}
+	if err := sqlx.SelectContext(ctx, db, &weights, query, key.SubjectId, key.ClassId, key.TermId); err != nil {
+		return nil, fmt.Errorf("failed to get the weights: %w", err)
+	}
+
+	return weights, nil
+
+}
+
+//func to replace the weights of the gradebook
+func (s *GradeStore) SetWeights(ctx context.Context, key types.GradebookKey, weights []types.GradeWeightEntry) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	if err := lockedGradebook(ctx, tx, key); err != nil {
+		return err
+	}
+
+	//replace the old weights
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM grade_weights WHERE subject_id = $1 AND class_id = $2 AND term_id = $3;
+	`, key.SubjectId, key.ClassId, key.TermId); err != nil {
+		return errors.New("Failed to delete the old weights! " + err.Error())
+	}
+	now := time.Now().UTC()
+	for _, weight := range weights {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO grade_weights (subject_id, class_id, term_id, category, weight, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7);
+		`, key.SubjectId, key.ClassId, key.TermId, weight.Category, weight.Weight, now, now); err != nil {
+			return errors.New("Failed to save the weight! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to create a new assessment in the gradebook
+func (s *GradeStore) CreateAssessment(ctx context.Context, assessment *types.Assessment) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if err := lockedGradebook(ctx, tx, key); err != nil {
+		return err
+	}
+
+	//base query
+	query := `
+		INSERT INTO assessments 
+		(id, subject_id, class_id, term_id, category, title, max_score, assessed_on, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+	`
+
+	//execute the query
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		assessment.Id,
+		assessment.SubjectId,
+		assessment.ClassId,
+		assessment.TermId,
+		assessment.Category,
+		assessment.Title,
+		assessment.MaxScore,
+		assessment.AssessedOn,
+		assessment.CreatedBy,
+		assessment.Created_at,
+		assessment.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new assessment! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the assessment by id
+func (s *GradeStore) GetAssessmentById(ctx context.Context, id uuid.UUID) (*types.Assessment, error) {
+
+	//execute the query
+	var assessment types.Assessment
+	if err := s.db.GetContext(ctx, &assessment, `SELECT `+assessmentColumns+` FROM assessments WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the assessment: %w", err)
+	}
+
+	return &assessment, nil
+
+}
+
+//func to get the assessments of the gradebook
+func (s *GradeStore) GetAssessments(ctx context.Context, key types.GradebookKey) ([]types.Assessment, error) {
+
+	return selectAssessments(ctx, s.db, key)
+
+}
+
+//helper to select the assessments with the db or the transaction
+func selectAssessments(ctx context.Context, db sqlx.QueryerContext, key types.GradebookKey) ([]types.Assessment, error) {
+
+	//base query
+	query := `
+		SELECT ` + assessmentColumns + ` FROM assessments
+		WHERE subject_id = $1 AND class_id = $2 AND term_id = $3
+		ORDER BY assessed_on NULLS LAST, created_at;
+	`
+
+	//execute the query
+	assessments := []types.Assessment{}
+	if err := sqlx.SelectContext(ctx, db, &assessments, query, key.SubjectId, key.ClassId, key.TermId); err != nil {
+		return nil, fmt.Errorf("failed to get the assessments: %w", err)
+	}
+
+	return assessments, nil
+
+}
+
+//func to delete the assessment with the scores
+func (s *GradeStore) DeleteAssessment(ctx context.Context, id uuid.UUID) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//get the gradebook of the assessment
+	var key types.GradebookKey
+	if err := tx.GetContext(ctx, &key, `SELECT subject_id, class_id, term_id FROM assessments WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("The assessment is not exist!")
+		}
+		return errors.New("Failed to get the assessment! " + err.Error())
+	}
+	if err := lockedGradebook(ctx, tx, key); err != nil {
+		return err
+	}
+
+	//execute the query
+	if _, err := tx.ExecContext(ctx, `DELETE FROM assessments WHERE id = $1;`, id); err != nil {
+		return errors.New("Failed to delete the assessment! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to save the scores of the students in one assessment, every student must be a student of the
+//gradebook and the score cannot be more than the max score of the assessment
+func (s *GradeStore) SaveScores(ctx context.Context, assessment *types.Assessment, entries []types.ScoreEntry, gradedBy *uuid.UUID) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if err := lockedGradebook(ctx, tx, key); err != nil {
+		return err
+	}
+
+	//the students of the gradebook
+	students := []types.GradebookStudent{}
+	if err := tx.SelectContext(ctx, &students, gradebookStudents+`;`, key.ClassId, key.TermId); err != nil {
+		return errors.New("Failed to get the students of the class! " + err.Error())
+	}
+	members := make(map[uuid.UUID]bool, len(students))
+	for _, student := range students {
+		members[student.StudentId] = true
+	}
+
+	//save every score
+	now := time.Now().UTC()
+	for _, entry := range entries {
+		if !members[entry.StudentId] {
+			return fmt.Errorf("The student %s is not a student of the class!", entry.StudentId)
+		}
+		if entry.Score > assessment.MaxScore {
+			return fmt.Errorf("The score of the student %s is more than the max score %.2f!", entry.StudentId, assessment.MaxScore)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO assessment_scores (assessment_id, student_id, score, graded_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			ON CONFLICT (assessment_id, student_id) DO UPDATE
+			SET score = EXCLUDED.score, graded_by = EXCLUDED.graded_by, updated_at = EXCLUDED.updated_at;
+		`, assessment.Id, entry.StudentId, entry.Score, gradedBy, now); err != nil {
+			return errors.New("Failed to save the score! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the scores of the gradebook, only the scores of one student when the student id is not nil
+func (s *GradeStore) GetScores(ctx context.Context, key types.GradebookKey, studentId *uuid.UUID) ([]types.AssessmentScore, error) {
+
+	return selectScores(ctx, s.db, key, studentId)
+
+}
+
+//helper to select the scores with the db or the transaction
+func selectScores(ctx context.Context, db sqlx.QueryerContext, key types.GradebookKey, studentId *uuid.UUID) ([]types.AssessmentScore, error) {
+
+	//base query
+	query := `
+		SELECT sc.assessment_id, sc.student_id, sc.score, sc.graded_by, sc.updated_at
+		FROM assessment_scores sc
+		JOIN assessments a ON a.id = sc.assessment_id
+		WHERE a.subject_id = $1 AND a.class_id = $2 AND a.term_id = $3
+		AND ($4::UUID IS NULL OR sc.student_id = $4);
+	`
+
+	//execute the query
+	scores := []types.AssessmentScore{}
+	if err := sqlx.SelectContext(ctx, db, &scores, query, key.SubjectId, key.ClassId, key.TermId, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the scores: %w", err)
+	}
+
+	return scores, nil
+
+}
+
+//func to get the students of the gradebook
+func (s *GradeStore) GetGradebookStudents(ctx context.Context, key types.GradebookKey) ([]types.GradebookStudent, error) {
+
+	//execute the query
+	students := []types.GradebookStudent{}
+	if err := s.db.SelectContext(ctx, &students, gradebookStudents+` ORDER BY s.name;`, key.ClassId, key.TermId); err != nil {
+		return nil, fmt.Errorf("failed to get the students of the class: %w", err)
+	}
+
+	return students, nil
+
+}
+
+//func to get the publication of the gradebook, nil when the gradebook is not published
+func (s *GradeStore) GetPublication(ctx context.Context, key types.GradebookKey) (*types.GradebookPublication, error) {
+
+	//base query
+	query := `
+		SELECT published_by, published_at FROM gradebook_publications
+		WHERE subject_id = $1 AND class_id = $2 AND term_id = $3;
+	`
+
+	//execute the query
+	var publication types.GradebookPublication
+	if err := s.db.GetContext(ctx, &publication, query, key.SubjectId, key.ClassId, key.TermId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the publication: %w", err)
+	}
+
+	return &publication, nil
+
+}
+
+//func to publish and lock the gradebook, the term scores of the students are saved
+func (s *GradeStore) Publish(ctx context.Context, key types.GradebookKey, publishedBy *uuid.UUID, scores []types.TermScore) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the gradebook can be published only once
+	now := time.Now().UTC()
+	rows, err := tx.ExecContext(ctx, `
+		INSERT INTO gradebook_publications (subject_id, class_id, term_id, published_by, published_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING;
+	`, key.SubjectId, key.ClassId, key.TermId, publishedBy, now)
+	if err != nil {
+		return errors.New("Failed to publish the gradebook! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return ErrGradebookLocked
+	}
+
+	//save the term scores
+	for _, score := range scores {
+		if score.Score == nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO term_scores (student_id, subject_id, class_id, term_id, score, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (student_id, subject_id, term_id) DO UPDATE
+			SET class_id = EXCLUDED.class_id, score = EXCLUDED.score, created_at = EXCLUDED.created_at;
+		`, score.StudentId, key.SubjectId, key.ClassId, key.TermId, *score.Score, now); err != nil {
+			return errors.New("Failed to save the term score! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to unlock the published gradebook, the saved term scores are deleted
+func (s *GradeStore) Unpublish(ctx context.Context, key types.GradebookKey) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//execute the query
+	rows, err := tx.ExecContext(ctx, `
+		DELETE FROM gradebook_publications WHERE subject_id = $1 AND class_id = $2 AND term_id = $3;
+	`, key.SubjectId, key.ClassId, key.TermId)
+	if err != nil {
+		return errors.New("Failed to unpublish the gradebook! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The gradebook is not published!")
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM term_scores WHERE subject_id = $1 AND class_id = $2 AND term_id = $3;
+	`, key.SubjectId, key.ClassId, key.TermId); err != nil {
+		return errors.New("Failed to delete the term scores! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the student of the user, nil when the user is not a student
+func (s *GradeStore) GetStudentIdByUser(ctx context.Context, userId uuid.UUID) (*uuid.UUID, error) {
+
+	//execute the query
+	var id uuid.UUID
+	if err := s.db.GetContext(ctx, &id, `SELECT id FROM students WHERE user_id = $1;`, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the student of the user: %w", err)
+	}
+
+	return &id, nil
+
+}
+
+//func to get the gradebooks of the student in the term, the class of the student is the membership
+//of the term or the current class
+func (s *GradeStore) GetStudentGradebooks(ctx context.Context, studentId uuid.UUID, termId uuid.UUID, onlyPublished bool) ([]types.StudentGradebook, error) {
+
+	//base query
+	query := `
+		WITH member AS (
+			SELECT COALESCE(
+				(SELECT e.class_id FROM class_enrollments e WHERE e.student_id = $1 AND e.term_id = $2),
+				(SELECT class_id FROM students WHERE id = $1)
+			) AS class_id
+		)
+		SELECT DISTINCT a.subject_id, a.class_id, a.term_id, sb.code AS subject_code, sb.name AS subject_name, p.published_at
+		FROM assessments a
+		JOIN member m ON m.class_id = a.class_id
+		JOIN subjects sb ON sb.id = a.subject_id
+		LEFT JOIN gradebook_publications p ON p.subject_id = a.subject_id AND p.class_id = a.class_id AND p.term_id = a.term_id
+		WHERE a.term_id = $2 AND (NOT $3 OR p.published_at IS NOT NULL)
+		ORDER BY sb.name;
+	`
+
+	//execute the query
+	gradebooks := []types.StudentGradebook{}
+	if err := s.db.SelectContext(ctx, &gradebooks, query, studentId, termId, onlyPublished); err != nil {
+		return nil, fmt.Errorf("failed to get the gradebooks of the student: %w", err)
+	}
+
+	return gradebooks, nil
+
+}
+
+//func to get the student of the transcript, nil when the student is not exist
+func (s *GradeStore) GetTranscriptStudent(ctx context.Context, studentId uuid.UUID) (*types.TranscriptStudent, error) {
+
+	//execute the query
+	var student types.TranscriptStudent
+	if err := s.db.GetContext(ctx, &student, `
+		SELECT id AS student_id, name, status, graduated_at FROM students WHERE id = $1;
+	`, studentId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the student: %w", err)
+	}
+
+	return &student, nil
+
+}
+
+//func to get every published term score of the student, ordered by the academic year and the semester
+func (s *GradeStore) GetTranscriptScores(ctx context.Context, studentId uuid.UUID) ([]types.TranscriptScore, error) {
+
+	//base query
+	query := `
+		SELECT ts.term_id, t.name AS term_name, t.semester, y.name AS academic_year, c.name AS class_name,
+		ts.subject_id, sb.code AS subject_code, sb.name AS subject_name, ts.score, sb.kkm
+		FROM term_scores ts
+		JOIN terms t ON t.id = ts.term_id
+		JOIN academic_years y ON y.id = t.academic_year_id
+		JOIN classes c ON c.id = ts.class_id
+		JOIN subjects sb ON sb.id = ts.subject_id
+		WHERE ts.student_id = $1
+		ORDER BY y.start_date, t.semester, sb.name;
+	`
+
+	//execute the query
+	scores := []types.TranscriptScore{}
+	if err := s.db.SelectContext(ctx, &scores, query, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the term scores: %w", err)
+	}
+
+	return scores, nil
+
+}
+
+//func to get the published term scores of the students in the term for the ranking of the class or
+//of the grade level
+func (s *GradeStore) GetRankingScores(ctx context.Context, termId uuid.UUID, classId *uuid.UUID, gradeLevel *int) ([]types.RankingScore, error) {
+
+	//base query
+	query := `
+		SELECT ts.student_id, s.name, ts.class_id, c.name AS class_name, ts.subject_id, ts.score, sb.kkm
+		FROM term_scores ts
+		JOIN students s ON s.id = ts.student_id
+		JOIN classes c ON c.id = ts.class_id
+		JOIN subjects sb ON sb.id = ts.subject_id
+		WHERE ts.term_id = $1
+		AND ($2::uuid IS NULL OR ts.class_id = $2)
+		AND ($3::int IS NULL OR c.grade_level = $3)
+		ORDER BY s.name, ts.student_id;
+	`
+
+	//execute the query
+	scores := []types.RankingScore{}
+	if err := s.db.SelectContext(ctx, &scores, query, termId, classId, gradeLevel); err != nil {
+		return nil, fmt.Errorf("failed to get the term scores of the ranking: %w", err)
+	}
+
+	return scores, nil
+
+}
+
+//func to get the ranking settings of the term, nil when the settings are not saved yet
+func (s *GradeStore) GetRankingSettings(ctx context.Context, termId uuid.UUID) (*types.RankingSettings, error) {
+
+	//execute the query
+	var settings types.RankingSettings
+	if err := s.db.GetContext(ctx, &settings, `
+		SELECT term_id, visible_to_students, updated_by, created_at, updated_at FROM ranking_settings WHERE term_id = $1;
+	`, termId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the ranking settings: %w", err)
+	}
+
+	return &settings, nil
+
+}
+
+//func to show or hide the ranks of the term from the siswa
+func (s *GradeStore) SetRankingSettings(ctx context.Context, termId uuid.UUID, visible bool, updatedBy *uuid.UUID) (*types.RankingSettings, error) {
+
+	//base query
+	query := `
+		INSERT INTO ranking_settings (term_id, visible_to_students, updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (term_id) DO UPDATE
+		SET visible_to_students = EXCLUDED.visible_to_students, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		RETURNING term_id, visible_to_students, updated_by, created_at, updated_at;
+	`
+
+	//execute the query
+	var settings types.RankingSettings
+	if err := s.db.GetContext(ctx, &settings, query, termId, visible, updatedBy, time.Now().UTC()); err != nil {
+		return nil, errors.New("Failed to save the ranking settings! " + err.Error())
+	}
+
+	return &settings, nil
+
+}
+
+//func to get the class of the student in the term, the membership of the term or the current class,
+//nil when the student has no class
+func (s *GradeStore) GetStudentClass(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) (*types.RankingClass, error) {
+
+	//base query
+	query := `
+		SELECT c.id AS class_id, c.grade_level FROM classes c
+		WHERE c.id = COALESCE(
+			(SELECT e.class_id FROM class_enrollments e WHERE e.student_id = $1 AND e.term_id = $2),
+			(SELECT class_id FROM students WHERE id = $1)
+		);
+	`
+
+	//execute the query
+	var class types.RankingClass
+	if err := s.db.GetContext(ctx, &class, query, studentId, termId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the class of the student: %w", err)
+	}
+
+	return &class, nil
+
+}
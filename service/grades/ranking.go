@@ -0,0 +1,107 @@
+package grades
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// RankStudents ranks the students by the average of their published term scores. The ties are broken by
+// the rules of types.RankTieBreaks in order: the higher average, the fewer subjects under the kkm and the
+// higher lowest score. The students that are still tied share the rank and the next rank is skipped.
+func RankStudents(rows []types.RankingScore, defaultKkm float64) []types.RankEntry {
+
+	index := make(map[uuid.UUID]int)
+	totals := []float64{}
+	entries := []types.RankEntry{}
+	for _, row := range rows {
+		kkm := defaultKkm
+		if row.Kkm != nil {
+			kkm = *row.Kkm
+		}
+		i, ok := index[row.StudentId]
+		if !ok {
+			i = len(entries)
+			index[row.StudentId] = i
+			entries = append(entries, types.RankEntry{
+				StudentId: row.StudentId,
+				Name: row.Name,
+				ClassId: row.ClassId,
+				ClassName: row.ClassName,
+				Lowest: row.Score,
+			})
+			totals = append(totals, 0)
+		}
+		entry := &entries[i]
+		entry.Subjects++
+		totals[i] += row.Score
+		if row.Score < entry.Lowest {
+			entry.Lowest = row.Score
+		}
+		if row.Score < kkm {
+			entry.BelowKkm++
+		}
+	}
+	for i := range entries {
+		entries[i].Average = Round2(totals[i] / float64(entries[i].Subjects))
+	}
+
+	//the order of the tie breaks, the name and the id only keep the order of the shared ranks stable
+	sort.SliceStable(entries, func(a, b int) bool {
+		x, y := entries[a], entries[b]
+		if x.Average != y.Average {
+			return x.Average > y.Average
+		}
+		if x.BelowKkm != y.BelowKkm {
+			return x.BelowKkm < y.BelowKkm
+		}
+		if x.Lowest != y.Lowest {
+			return x.Lowest > y.Lowest
+		}
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		return x.StudentId.String() < y.StudentId.String()
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i == 0 {
+			continue
+		}
+		prev := &entries[i-1]
+		if prev.Average == entries[i].Average && prev.BelowKkm == entries[i].BelowKkm && prev.Lowest == entries[i].Lowest {
+			entries[i].Rank = prev.Rank
+			entries[i].Tied = true
+			prev.Tied = true
+		}
+	}
+
+	return entries
+
+}
+
+// HonorRollOf returns the ranked students with the average on the minimum and without a subject under the kkm
+func HonorRollOf(entries []types.RankEntry, minAverage float64) []types.RankEntry {
+
+	honors := []types.RankEntry{}
+	for _, entry := range entries {
+		if entry.Average >= minAverage && entry.BelowKkm == 0 {
+			honors = append(honors, entry)
+		}
+	}
+
+	return honors
+
+}
+
+// FindRank returns the rank of the student in the ranked students
+func FindRank(entries []types.RankEntry, studentId uuid.UUID) *types.RankEntry {
+	for i := range entries {
+		if entries[i].StudentId == studentId {
+			return &entries[i]
+		}
+	}
+	return nil
+}
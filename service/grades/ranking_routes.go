@@ -0,0 +1,444 @@
+package grades
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//helper to load the ranking of the class or of the grade level in the term
+func (h *HandleRequest) loadRanking(ctx context.Context, termId uuid.UUID, classId *uuid.UUID, gradeLevel *int) (*types.Ranking, error) {
+
+	scores, err := h.db.GetRankingScores(ctx, termId, classId, gradeLevel)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := h.db.GetRankingSettings(ctx, termId)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := &types.Ranking{
+		Scope: types.RankScopeClass,
+		TermId: termId,
+		ClassId: classId,
+		GradeLevel: gradeLevel,
+		TieBreaks: types.RankTieBreaks,
+		Visible: settings != nil && settings.VisibleToStudents,
+		Students: RankStudents(scores, h.rules.DefaultKkm),
+	}
+	if classId == nil {
+		ranking.Scope = types.RankScopeGradeLevel
+	}
+
+	return ranking, nil
+
+}
+
+//helper to check that the user is a guru or an admin
+func staffOnly(w http.ResponseWriter, r *http.Request) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//func to get the ranking of the class in the term (?term_id=) from the published term scores
+func (h *HandleRequest) ClassRanking_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the ranks are only for the guru and the admin, the siswa use their own rank
+	if !staffOnly(w, r) {
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ranking, err := h.loadRanking(ctx, term_id, &class_id, nil)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the ranking of the class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the ranking!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the ranking has been successfully", ranking)
+
+}
+
+//func to get the ranking of every class of the grade level in the term (?term_id=)
+func (h *HandleRequest) GradeLevelRanking_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the ranks are only for the guru and the admin, the siswa use their own rank
+	if !staffOnly(w, r) {
+		return 
+	}
+
+	//declare the grade level of the parameters
+	level, err := strconv.Atoi(mux.Vars(r)["level"])
+	if err != nil || level < 1 || level > 12 {
+		utils.ResponseError(w, http.StatusBadRequest, "The grade level must be between 1 and 12!", false)
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ranking, err := h.loadRanking(ctx, term_id, nil, &level)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the ranking of the grade level", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the ranking!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the ranking has been successfully", ranking)
+
+}
+
+//func to get the honor roll of the class (?class_id=) or of the grade level (?grade_level=) in the
+//term (?term_id=), the minimum average can be changed with ?min_average=. The siswa can see the honor
+//roll but the ranks are removed while the ranks of the term are hidden
+func (h *HandleRequest) HonorRoll_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the scope of the honor roll
+	var class_id *uuid.UUID
+	var grade_level *int
+	query := r.URL.Query()
+	if value := query.Get("class_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+			return 
+		}
+		class_id = &id
+	} else if value := query.Get("grade_level"); value != "" {
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 1 || level > 12 {
+			utils.ResponseError(w, http.StatusBadRequest, "The grade level must be between 1 and 12!", false)
+			return 
+		}
+		grade_level = &level
+	} else {
+		utils.ResponseError(w, http.StatusBadRequest, "The class_id or the grade_level is required!", false)
+		return 
+	}
+	min_average := h.honorRollMin
+	if value := query.Get("min_average"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 100 {
+			utils.ResponseError(w, http.StatusBadRequest, "The min_average must be between 0 and 100!", false)
+			return 
+		}
+		min_average = parsed
+	}
+
+	//get the role of the user
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ranking, err := h.loadRanking(ctx, term_id, class_id, grade_level)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the honor roll", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the honor roll!", err.Error())
+		return 
+	}
+	honors := HonorRollOf(ranking.Students, min_average)
+	if role != "guru" && role != "admin" && !ranking.Visible {
+		for i := range honors {
+			honors[i].Rank = 0
+			honors[i].Tied = false
+		}
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the honor roll has been successfully", types.HonorRoll{
+		Scope: ranking.Scope,
+		TermId: term_id,
+		ClassId: class_id,
+		GradeLevel: grade_level,
+		MinAverage: min_average,
+		Students: honors,
+	})
+
+}
+
+//func to get the ranks of the siswa of the token in the class and in the grade level for the term
+//(?term_id=), only when the ranks of the term are shown to the siswa
+func (h *HandleRequest) MyRank_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the student of the user
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	student_id, err := h.db.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return 
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The user is not registered as a student!", false)
+		return 
+	}
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//the ranks must be shown to the siswa
+	settings, err := h.db.GetRankingSettings(ctx, term_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the ranking settings!", err.Error())
+		return 
+	}
+	if settings == nil || !settings.VisibleToStudents {
+		utils.ResponseError(w, http.StatusForbidden, "The ranks of this term are hidden!", false)
+		return 
+	}
+	class, err := h.db.GetStudentClass(ctx, *student_id, term_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class of the student!", err.Error())
+		return 
+	}
+	if class == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The student has no class in this term!", false)
+		return 
+	}
+
+	//execute the query
+	classRanking, err := h.loadRanking(ctx, term_id, &class.ClassId, nil)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the ranking of the class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the rank!", err.Error())
+		return 
+	}
+	levelRanking, err := h.loadRanking(ctx, term_id, nil, &class.GradeLevel)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the ranking of the grade level", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the rank!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the rank has been successfully", types.MyRank{
+		TermId: term_id,
+		Class: FindRank(classRanking.Students, *student_id),
+		ClassSize: len(classRanking.Students),
+		GradeLevel: FindRank(levelRanking.Students, *student_id),
+		GradeLevelSize: len(levelRanking.Students),
+	})
+
+}
+
+//func to show or hide the ranks of the term from the siswa, only the admin can change it
+func (h *HandleRequest) SetRankingVisibility_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin can show the ranks
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	term_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SetRankingVisibility
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//the term must exist
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term, err := h.academics.GetTermById(ctx, term_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	if term == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The term is not exist!", false)
+		return 
+	}
+
+	//execute the query
+	var updated_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		updated_by = &user_id
+	}
+	settings, err := h.db.SetRankingSettings(ctx, term_id, *payload.VisibleToStudents, updated_by)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to save the ranking settings", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to save the ranking settings!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Save the ranking settings has been successfully", settings)
+
+}
@@ -0,0 +1,80 @@
+package grades
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func rankingRows(student uuid.UUID, name string, scores ...float64) []types.RankingScore {
+	rows := []types.RankingScore{}
+	for _, score := range scores {
+		rows = append(rows, types.RankingScore{StudentId: student, Name: name, SubjectId: uuid.New(), Score: score})
+	}
+	return rows
+}
+
+func TestRankStudents(t *testing.T) {
+	ani, budi, citra, dedi, eka := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	rows := []types.RankingScore{}
+	//the same average of 80, budi has a subject under the kkm
+	rows = append(rows, rankingRows(budi, "Budi", 70, 90)...)
+	rows = append(rows, rankingRows(ani, "Ani", 78, 82)...)
+	//the same average and kkm as ani but the lowest score is higher
+	rows = append(rows, rankingRows(citra, "Citra", 79, 81)...)
+	//the same as ani in every rule
+	rows = append(rows, rankingRows(dedi, "Dedi", 82, 78)...)
+	rows = append(rows, rankingRows(eka, "Eka", 95, 93)...)
+
+	entries := RankStudents(rows, 75)
+	want := []struct {
+		id   uuid.UUID
+		rank int
+		tied bool
+	}{
+		{eka, 1, false},
+		{citra, 2, false},
+		{ani, 3, true},
+		{dedi, 3, true},
+		{budi, 5, false},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d students, got %d", len(want), len(entries))
+	}
+	for i, w := range want {
+		if entries[i].StudentId != w.id || entries[i].Rank != w.rank || entries[i].Tied != w.tied {
+			t.Fatalf("unexpected entry %d: %+v", i, entries[i])
+		}
+	}
+	if entries[4].BelowKkm != 1 || entries[4].Lowest != 70 || entries[4].Subjects != 2 {
+		t.Fatalf("unexpected summary of budi %+v", entries[4])
+	}
+}
+
+func TestRankStudentsKkmOfSubject(t *testing.T) {
+	student := uuid.New()
+	kkm := 85.0
+	rows := []types.RankingScore{{StudentId: student, Name: "Ani", Score: 80, Kkm: &kkm}}
+	entries := RankStudents(rows, 75)
+	if entries[0].BelowKkm != 1 {
+		t.Fatalf("the kkm of the subject should be used, got %+v", entries[0])
+	}
+}
+
+func TestHonorRollOf(t *testing.T) {
+	entries := []types.RankEntry{
+		{Rank: 1, Average: 92},
+		{Rank: 2, Average: 88, BelowKkm: 1},
+		{Rank: 3, Average: 85},
+		{Rank: 4, Average: 84.99},
+	}
+	honors := HonorRollOf(entries, 85)
+	if len(honors) != 2 || honors[0].Rank != 1 || honors[1].Rank != 3 {
+		t.Fatalf("unexpected honor roll %+v", honors)
+	}
+	if FindRank(entries, uuid.New()) != nil {
+		t.Fatalf("the unknown student should not have a rank")
+	}
+}
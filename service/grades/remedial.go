@@ -0,0 +1,30 @@
+package grades
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrRemedialExists is returned when the student already has the remedial of the assessment
+var ErrRemedialExists = errors.New("The student already has the remedial of this assessment!")
+
+// ErrAppealPending is returned when the score of the student already has a pending appeal
+var ErrAppealPending = errors.New("The score already has a pending appeal!")
+
+// ErrAlreadyResolved is returned when the graded remedial or the answered appeal is changed again
+var ErrAlreadyResolved = errors.New("The remedial or the appeal is already resolved!")
+
+// Failed reports if the score of the assessment is under the kkm, the kkm is in the scale of 100
+func Failed(score float64, maxScore float64, kkm float64) bool {
+	return score*100 < kkm*maxScore
+}
+
+// DefaultRemedialCap returns the kkm in the scale of the max score of the assessment
+func DefaultRemedialCap(maxScore float64, kkm float64) float64 {
+	return Round2(maxScore * kkm / 100)
+}
+
+// RemedialFinalScore caps the remedial score at the cap score, the final score never goes under the original score
+func RemedialFinalScore(original float64, remedial float64, capScore float64) float64 {
+	return math.Max(original, math.Min(remedial, capScore))
+}
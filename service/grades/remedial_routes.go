@@ -0,0 +1,858 @@
+package grades
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//helper to get the assessment by the id of the parameters
+func (h *HandleRequest) assessmentParam(ctx context.Context, w http.ResponseWriter, r *http.Request) (*types.Assessment, bool) {
+	assessment_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, false
+	}
+	return h.assessmentById(ctx, w, assessment_id)
+}
+
+//helper to get the assessment by the id
+func (h *HandleRequest) assessmentById(ctx context.Context, w http.ResponseWriter, id uuid.UUID) (*types.Assessment, bool) {
+	assessment, err := h.db.GetAssessmentById(ctx, id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the assessment!", err.Error())
+		return nil, false
+	}
+	if assessment == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The assessment is not exist!", false)
+		return nil, false
+	}
+	return assessment, true
+}
+
+//helper to get the student of the siswa of the token
+func (h *HandleRequest) studentOfUser(ctx context.Context, w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return uuid.Nil, false
+	}
+	student_id, err := h.db.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return uuid.Nil, false
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The user is not registered as a student!", false)
+		return uuid.Nil, false
+	}
+	return *student_id, true
+}
+
+//helper to get the student of the appeal, the siswa appeals their own score and the orangtua appeals the
+//score of their linked child
+func (h *HandleRequest) studentOfAppeal(ctx context.Context, w http.ResponseWriter, r *http.Request, studentId *uuid.UUID) (uuid.UUID, bool) {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return uuid.Nil, false
+	}
+	if role != types.RoleOrangtua {
+		return h.studentOfUser(ctx, w, r)
+	}
+	if studentId == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "The student id is required for the orangtua!", false)
+		return uuid.Nil, false
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return uuid.Nil, false
+	}
+	linked, err := h.guardians.IsGuardianOf(ctx, user_id, *studentId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the guardian of the student!", err.Error())
+		return uuid.Nil, false
+	}
+	if !linked {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return uuid.Nil, false
+	}
+	return *studentId, true
+}
+
+//helper to get the kkm of the subject, the subject without the kkm uses the default kkm
+func (h *HandleRequest) kkmOf(ctx context.Context, subjectId uuid.UUID) (float64, error) {
+	subject, err := h.subjects.GetSubjectById(ctx, subjectId)
+	if err != nil {
+		return 0, err
+	}
+	if subject != nil && subject.Kkm != nil {
+		return *subject.Kkm, nil
+	}
+	return h.rules.DefaultKkm, nil
+}
+
+//func to assign the remedial work to the student that failed the assessment, the default cap score is
+//the kkm of the subject in the scale of the assessment
+func (h *HandleRequest) CreateRemedial_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateRemedial
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the admin and the assigned guru can assign the remedial
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	assessment, ok := h.assessmentParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//the student must fail the assessment
+	score, err := h.db.GetAssessmentScore(ctx, assessment.Id, payload.StudentId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the score of the student!", err.Error())
+		return 
+	}
+	if score == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "The student has no score in this assessment!", false)
+		return 
+	}
+	kkm, err := h.kkmOf(ctx, assessment.SubjectId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the kkm of the subject!", err.Error())
+		return 
+	}
+	if !Failed(*score, assessment.MaxScore, kkm) {
+		utils.ResponseError(w, http.StatusBadRequest, "The student has passed the kkm of this assessment!", false)
+		return 
+	}
+	cap_score := DefaultRemedialCap(assessment.MaxScore, kkm)
+	if payload.CapScore != nil {
+		cap_score = *payload.CapScore
+	}
+	if cap_score > assessment.MaxScore {
+		utils.ResponseError(w, http.StatusBadRequest, "The cap score cannot be more than the max score of the assessment!", false)
+		return 
+	}
+
+	//execute the query
+	remedial := types.Remedial{
+		AssessmentId: assessment.Id,
+		AssessmentTitle: assessment.Title,
+		StudentId: payload.StudentId,
+		Title: payload.Title,
+		Instructions: payload.Instructions,
+		DueOn: payload.DueOn,
+		CapScore: cap_score,
+		OriginalScore: *score,
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		remedial.AssignedBy = &user_id
+	}
+	if err := h.db.CreateRemedial(ctx, &remedial); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the remedial", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to create the remedial!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the remedial has been successfully", remedial)
+
+}
+
+//func to get the remedials of the assessment
+func (h *HandleRequest) GetRemedials_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can see the remedials
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	assessment, ok := h.assessmentParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) {
+		return 
+	}
+
+	//execute the query
+	remedials, err := h.db.GetRemedials(ctx, &assessment.Id, nil)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the remedials", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the remedials!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the remedials has been successfully", remedials)
+
+}
+
+//func to get the remedials of the siswa of the token
+func (h *HandleRequest) MyRemedials_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the student of the user
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	student_id, ok := h.studentOfUser(ctx, w, r)
+	if !ok {
+		return 
+	}
+
+	//execute the query
+	remedials, err := h.db.GetRemedials(ctx, nil, &student_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the remedials", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the remedials!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the remedials has been successfully", remedials)
+
+}
+
+//func to delete the remedial that is not graded yet
+func (h *HandleRequest) DeleteRemedial_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	remedial, err := h.db.GetRemedialById(ctx, id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the remedial!", err.Error())
+		return 
+	}
+	if remedial == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The remedial is not exist!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can delete the remedial
+	assessment, ok := h.assessmentById(ctx, w, remedial.AssessmentId)
+	if !ok {
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) {
+		return 
+	}
+
+	//execute the query
+	if err := h.db.DeleteRemedial(ctx, id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the remedial", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to delete the remedial!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the remedial has been successfully", id)
+
+}
+
+//func to grade the remedial, the remedial score is capped at the cap score and the final score replaces
+//the score of the assessment with the recorded reason
+func (h *HandleRequest) GradeRemedial_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.GradeRemedial
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//get the remedial
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	remedial, err := h.db.GetRemedialById(ctx, id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the remedial!", err.Error())
+		return 
+	}
+	if remedial == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The remedial is not exist!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can grade the remedial
+	assessment, ok := h.assessmentById(ctx, w, remedial.AssessmentId)
+	if !ok {
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+	if *payload.Score > assessment.MaxScore {
+		utils.ResponseError(w, http.StatusBadRequest, fmt.Sprintf("The score cannot be more than the max score %.2f!", assessment.MaxScore), false)
+		return 
+	}
+
+	//the final score starts from the current score of the student
+	current, err := h.db.GetAssessmentScore(ctx, assessment.Id, remedial.StudentId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the score of the student!", err.Error())
+		return 
+	}
+	base := remedial.OriginalScore
+	if current != nil {
+		base = *current
+	}
+	final := RemedialFinalScore(base, *payload.Score, remedial.CapScore)
+	reason := payload.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Remedial %s, score %.2f capped at %.2f", remedial.Title, *payload.Score, remedial.CapScore)
+	}
+
+	//execute the query
+	remedial.RemedialScore = payload.Score
+	remedial.FinalScore = &final
+	change := types.GradeChange{
+		AssessmentId: assessment.Id,
+		StudentId: remedial.StudentId,
+		NewScore: final,
+		Source: types.ChangeByRemedial,
+		SourceId: remedial.Id,
+		Reason: reason,
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		remedial.GradedBy = &user_id
+		change.ChangedBy = &user_id
+	}
+	if err := h.db.GradeRemedial(ctx, remedial, &change); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to grade the remedial", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to grade the remedial!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Grade the remedial has been successfully", remedial)
+
+}
+
+//func to submit the appeal of the published score of the siswa of the token, or of the child of the
+//orangtua with the student id
+func (h *HandleRequest) CreateAppeal_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateGradeAppeal
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//get the student of the user and the assessment
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	student_id, ok := h.studentOfAppeal(ctx, w, r, payload.StudentId)
+	if !ok {
+		return 
+	}
+	assessment, ok := h.assessmentParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//the siswa can only appeal the published score
+	publication, err := h.db.GetPublication(ctx, key)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the publication!", err.Error())
+		return 
+	}
+	score, err := h.db.GetAssessmentScore(ctx, assessment.Id, student_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the score!", err.Error())
+		return 
+	}
+	if publication == nil || score == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The score is not published!", false)
+		return 
+	}
+
+	//execute the query
+	appeal := types.GradeAppeal{
+		AssessmentId: assessment.Id,
+		AssessmentTitle: assessment.Title,
+		StudentId: student_id,
+		Reason: payload.Reason,
+		CurrentScore: *score,
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		appeal.SubmittedBy = &user_id
+	}
+	if err := h.db.CreateAppeal(ctx, &appeal); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to submit the appeal", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to submit the appeal!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Submit the appeal has been successfully", appeal)
+
+}
+
+//func to get the appeals of the assessment
+func (h *HandleRequest) GetAppeals_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can see the appeals
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	assessment, ok := h.assessmentParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) {
+		return 
+	}
+
+	//execute the query
+	appeals, err := h.db.GetAppeals(ctx, &assessment.Id, nil)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the appeals", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the appeals!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the appeals has been successfully", appeals)
+
+}
+
+//func to get the appeals of the siswa of the token, the orangtua gets the appeals of their children or of one
+//child (?student_id=)
+func (h *HandleRequest) MyAppeals_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the students of the user
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	student_ids, ok := h.appealStudents(ctx, w, r)
+	if !ok {
+		return 
+	}
+
+	//execute the query
+	appeals := []types.GradeAppeal{}
+	for _, student_id := range student_ids {
+		student_appeals, err := h.db.GetAppeals(ctx, nil, &student_id)
+		if err != nil {
+			//logger if the response is failed
+			logger.Log.Error("Failed to get the appeals", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the appeals!", err.Error())
+			return 
+		}
+		appeals = append(appeals, student_appeals...)
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the appeals has been successfully", appeals)
+
+}
+
+//helper to get the students of the appeals of the user, the siswa itself or the children of the orangtua
+func (h *HandleRequest) appealStudents(ctx context.Context, w http.ResponseWriter, r *http.Request) ([]uuid.UUID, bool) {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return nil, false
+	}
+	if role != types.RoleOrangtua {
+		student_id, ok := h.studentOfUser(ctx, w, r)
+		return []uuid.UUID{student_id}, ok
+	}
+	if value := r.URL.Query().Get("student_id"); value != "" {
+		student_id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+			return nil, false
+		}
+		student_id, ok := h.studentOfAppeal(ctx, w, r, &student_id)
+		return []uuid.UUID{student_id}, ok
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return nil, false
+	}
+	guardian, err := h.guardians.GetGuardianByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the guardian!", err.Error())
+		return nil, false
+	}
+	if guardian == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The user is not registered as a guardian!", false)
+		return nil, false
+	}
+	student_ids := make([]uuid.UUID, 0, len(guardian.Children))
+	for _, child := range guardian.Children {
+		student_ids = append(student_ids, child.StudentId)
+	}
+	return student_ids, true
+}
+
+//func to answer the pending appeal, the accepted appeal changes the score with the response as the reason
+func (h *HandleRequest) RespondAppeal_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.RespondGradeAppeal
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//get the appeal
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	appeal, err := h.db.GetAppealById(ctx, id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the appeal!", err.Error())
+		return 
+	}
+	if appeal == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The appeal is not exist!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can answer the appeal
+	assessment, ok := h.assessmentById(ctx, w, appeal.AssessmentId)
+	if !ok {
+		return 
+	}
+	key := types.GradebookKey{SubjectId: assessment.SubjectId, ClassId: assessment.ClassId, TermId: assessment.TermId}
+	if !h.canGrade(ctx, w, r, key) || !h.termNotClosed(ctx, w, key.TermId) {
+		return 
+	}
+
+	//the accepted appeal changes the score
+	appeal.Status = payload.Status
+	appeal.Response = payload.Response
+	var user_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		user_by = &user_id
+	}
+	appeal.RespondedBy = user_by
+	var change *types.GradeChange
+	if payload.Status == types.AppealAccepted {
+		if *payload.NewScore > assessment.MaxScore {
+			utils.ResponseError(w, http.StatusBadRequest, fmt.Sprintf("The score cannot be more than the max score %.2f!", assessment.MaxScore), false)
+			return 
+		}
+		appeal.NewScore = payload.NewScore
+		change = &types.GradeChange{
+			AssessmentId: assessment.Id,
+			StudentId: appeal.StudentId,
+			NewScore: *payload.NewScore,
+			Source: types.ChangeByAppeal,
+			SourceId: appeal.Id,
+			Reason: payload.Response,
+			ChangedBy: user_by,
+		}
+	}
+
+	//execute the query
+	if err := h.db.RespondAppeal(ctx, appeal, change); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to answer the appeal", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to answer the appeal!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Answer the appeal has been successfully", appeal)
+
+}
+
+//func to get the recorded grade changes of the student, the siswa can only get their own
+func (h *HandleRequest) GradeChanges_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if !h.canReadStudent(ctx, w, r, student_id) {
+		return 
+	}
+
+	//execute the query
+	changes, err := h.db.GetGradeChanges(ctx, student_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the grade changes", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the grade changes!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the grade changes has been successfully", changes)
+
+}
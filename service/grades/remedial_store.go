@@ -0,0 +1,387 @@
+package grades
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//the remedial with the title of the assessment and the name of the student
+const remedialSelect = `
+	SELECT r.id, r.assessment_id, a.title AS assessment_title, r.student_id, s.name AS student_name, r.title,
+	r.instructions, r.due_on, r.cap_score, r.original_score, r.remedial_score, r.final_score, r.status,
+	r.assigned_by, r.graded_by, r.graded_at, r.created_at, r.updated_at
+	FROM remedials r
+	JOIN assessments a ON a.id = r.assessment_id
+	JOIN students s ON s.id = r.student_id
+`
+
+//the appeal with the title of the assessment and the name of the student
+const appealSelect = `
+	SELECT g.id, g.assessment_id, a.title AS assessment_title, g.student_id, s.name AS student_name, g.submitted_by,
+	g.reason, g.status, g.current_score, g.new_score, g.response, g.responded_by, g.responded_at, g.created_at, g.updated_at
+	FROM grade_appeals g
+	JOIN assessments a ON a.id = g.assessment_id
+	JOIN students s ON s.id = g.student_id
+`
+
+//helper to change the score of the student and record the change with the reason, the saved term score
+//of the published gradebook is computed again in the transaction from the scores after the change
+func applyGradeChange(ctx context.Context, tx *sqlx.Tx, change *types.GradeChange) error {
+
+	//the old score of the student
+	var old sql.NullFloat64
+	if err := tx.GetContext(ctx, &old, `
+		SELECT score FROM assessment_scores WHERE assessment_id = $1 AND student_id = $2 FOR UPDATE;
+	`, change.AssessmentId, change.StudentId); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return errors.New("Failed to get the score of the student! " + err.Error())
+	}
+	if old.Valid {
+		change.OldScore = &old.Float64
+	}
+
+	//save the new score
+	change.Created_at = time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO assessment_scores (assessment_id, student_id, score, graded_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (assessment_id, student_id) DO UPDATE
+		SET score = EXCLUDED.score, graded_by = EXCLUDED.graded_by, updated_at = EXCLUDED.updated_at;
+	`, change.AssessmentId, change.StudentId, change.NewScore, change.ChangedBy, change.Created_at); err != nil {
+		return errors.New("Failed to save the score! " + err.Error())
+	}
+	if err := tx.GetContext(ctx, &change.Id, `
+		INSERT INTO grade_changes (assessment_id, student_id, old_score, new_score, source, source_id, reason, changed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id;
+	`, change.AssessmentId, change.StudentId, change.OldScore, change.NewScore, change.Source, change.SourceId,
+		change.Reason, change.ChangedBy, change.Created_at); err != nil {
+		return errors.New("Failed to record the grade change! " + err.Error())
+	}
+
+	return updateTermScore(ctx, tx, change.AssessmentId, change.StudentId)
+
+}
+
+//helper to compute the term score of the student in the gradebook of the assessment again, only the
+//published gradebook has a saved term score
+func updateTermScore(ctx context.Context, tx *sqlx.Tx, assessmentId uuid.UUID, studentId uuid.UUID) error {
+
+	var key types.GradebookKey
+	if err := tx.GetContext(ctx, &key, `
+		SELECT subject_id, class_id, term_id FROM assessments WHERE id = $1;
+	`, assessmentId); err != nil {
+		return errors.New("Failed to get the assessment! " + err.Error())
+	}
+	var published bool
+	if err := tx.GetContext(ctx, &published, `
+		SELECT EXISTS (SELECT 1 FROM gradebook_publications WHERE subject_id = $1 AND class_id = $2 AND term_id = $3);
+	`, key.SubjectId, key.ClassId, key.TermId); err != nil {
+		return errors.New("Failed to get the publication! " + err.Error())
+	}
+	if !published {
+		return nil
+	}
+
+	//the scores of the student with the new score
+	weights, err := selectWeights(ctx, tx, key)
+	if err != nil {
+		return err
+	}
+	assessments, err := selectAssessments(ctx, tx, key)
+	if err != nil {
+		return err
+	}
+	scores, err := selectScores(ctx, tx, key, &studentId)
+	if err != nil {
+		return err
+	}
+	byAssessment := make(map[uuid.UUID]float64, len(scores))
+	for _, entry := range scores {
+		byAssessment[entry.AssessmentId] = entry.Score
+	}
+	termScore := ComputeTermScore(EffectiveWeights(weights), assessments, byAssessment).Score
+	if termScore == nil {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE term_scores SET score = $1
+		WHERE student_id = $2 AND subject_id = $3 AND term_id = $4;
+	`, *termScore, studentId, key.SubjectId, key.TermId); err != nil {
+		return errors.New("Failed to update the term score! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the score of the student in the assessment, nil when the student has no score
+func (s *GradeStore) GetAssessmentScore(ctx context.Context, assessmentId uuid.UUID, studentId uuid.UUID) (*float64, error) {
+
+	//execute the query
+	var score float64
+	if err := s.db.GetContext(ctx, &score, `
+		SELECT score FROM assessment_scores WHERE assessment_id = $1 AND student_id = $2;
+	`, assessmentId, studentId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the score: %w", err)
+	}
+
+	return &score, nil
+
+}
+
+//func to assign the remedial, the student can have only one remedial of the assessment
+func (s *GradeStore) CreateRemedial(ctx context.Context, remedial *types.Remedial) error {
+
+	//base query
+	query := `
+		INSERT INTO remedials (assessment_id, student_id, title, instructions, due_on, cap_score, original_score,
+		status, assigned_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		ON CONFLICT (assessment_id, student_id) DO NOTHING
+		RETURNING id;
+	`
+
+	//execute the query
+	now := time.Now().UTC()
+	if err := s.db.GetContext(ctx, &remedial.Id, query, remedial.AssessmentId, remedial.StudentId, remedial.Title,
+		remedial.Instructions, remedial.DueOn, remedial.CapScore, remedial.OriginalScore, types.RemedialAssigned,
+		remedial.AssignedBy, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRemedialExists
+		}
+		return errors.New("Failed to create the remedial! " + err.Error())
+	}
+	remedial.Status = types.RemedialAssigned
+	remedial.Created_at = now
+	remedial.Updated_at = now
+
+	return nil
+
+}
+
+//func to get the remedial by the id, nil when the remedial is not exist
+func (s *GradeStore) GetRemedialById(ctx context.Context, id uuid.UUID) (*types.Remedial, error) {
+
+	//execute the query
+	var remedial types.Remedial
+	if err := s.db.GetContext(ctx, &remedial, remedialSelect+` WHERE r.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the remedial: %w", err)
+	}
+
+	return &remedial, nil
+
+}
+
+//func to get the remedials of the assessment or of the student
+func (s *GradeStore) GetRemedials(ctx context.Context, assessmentId *uuid.UUID, studentId *uuid.UUID) ([]types.Remedial, error) {
+
+	//base query
+	query := remedialSelect + `
+		WHERE ($1::uuid IS NULL OR r.assessment_id = $1) AND ($2::uuid IS NULL OR r.student_id = $2)
+		ORDER BY r.created_at DESC;
+	`
+
+	//execute the query
+	remedials := []types.Remedial{}
+	if err := s.db.SelectContext(ctx, &remedials, query, assessmentId, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the remedials: %w", err)
+	}
+
+	return remedials, nil
+
+}
+
+//func to delete the remedial that is not graded yet
+func (s *GradeStore) DeleteRemedial(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM remedials WHERE id = $1 AND status = $2;`, id, types.RemedialAssigned)
+	if err != nil {
+		return errors.New("Failed to delete the remedial! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return ErrAlreadyResolved
+	}
+
+	return nil
+
+}
+
+//func to grade the remedial, the final score replaces the score of the assessment and the change is recorded
+func (s *GradeStore) GradeRemedial(ctx context.Context, remedial *types.Remedial, change *types.GradeChange) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the remedial can be graded only once
+	now := time.Now().UTC()
+	rows, err := tx.ExecContext(ctx, `
+		UPDATE remedials SET remedial_score = $1, final_score = $2, status = $3, graded_by = $4, graded_at = $5, updated_at = $5
+		WHERE id = $6 AND status = $7;
+	`, remedial.RemedialScore, remedial.FinalScore, types.RemedialGraded, remedial.GradedBy, now, remedial.Id, types.RemedialAssigned)
+	if err != nil {
+		return errors.New("Failed to grade the remedial! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return ErrAlreadyResolved
+	}
+	if err := applyGradeChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+	remedial.Status = types.RemedialGraded
+	remedial.GradedAt = &now
+	remedial.Updated_at = now
+
+	return nil
+
+}
+
+//func to submit the appeal, the score can have only one pending appeal
+func (s *GradeStore) CreateAppeal(ctx context.Context, appeal *types.GradeAppeal) error {
+
+	//base query
+	query := `
+		INSERT INTO grade_appeals (assessment_id, student_id, submitted_by, reason, status, current_score, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (assessment_id, student_id) WHERE status = 'pending' DO NOTHING
+		RETURNING id;
+	`
+
+	//execute the query
+	now := time.Now().UTC()
+	if err := s.db.GetContext(ctx, &appeal.Id, query, appeal.AssessmentId, appeal.StudentId, appeal.SubmittedBy,
+		appeal.Reason, types.AppealPending, appeal.CurrentScore, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAppealPending
+		}
+		return errors.New("Failed to submit the appeal! " + err.Error())
+	}
+	appeal.Status = types.AppealPending
+	appeal.Created_at = now
+	appeal.Updated_at = now
+
+	return nil
+
+}
+
+//func to get the appeal by the id, nil when the appeal is not exist
+func (s *GradeStore) GetAppealById(ctx context.Context, id uuid.UUID) (*types.GradeAppeal, error) {
+
+	//execute the query
+	var appeal types.GradeAppeal
+	if err := s.db.GetContext(ctx, &appeal, appealSelect+` WHERE g.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the appeal: %w", err)
+	}
+
+	return &appeal, nil
+
+}
+
+//func to get the appeals of the assessment or of the student
+func (s *GradeStore) GetAppeals(ctx context.Context, assessmentId *uuid.UUID, studentId *uuid.UUID) ([]types.GradeAppeal, error) {
+
+	//base query
+	query := appealSelect + `
+		WHERE ($1::uuid IS NULL OR g.assessment_id = $1) AND ($2::uuid IS NULL OR g.student_id = $2)
+		ORDER BY g.created_at DESC;
+	`
+
+	//execute the query
+	appeals := []types.GradeAppeal{}
+	if err := s.db.SelectContext(ctx, &appeals, query, assessmentId, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the appeals: %w", err)
+	}
+
+	return appeals, nil
+
+}
+
+//func to answer the pending appeal, the score is changed and recorded only when the change is not nil
+func (s *GradeStore) RespondAppeal(ctx context.Context, appeal *types.GradeAppeal, change *types.GradeChange) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the appeal can be answered only once
+	now := time.Now().UTC()
+	rows, err := tx.ExecContext(ctx, `
+		UPDATE grade_appeals SET status = $1, new_score = $2, response = $3, responded_by = $4, responded_at = $5, updated_at = $5
+		WHERE id = $6 AND status = $7;
+	`, appeal.Status, appeal.NewScore, appeal.Response, appeal.RespondedBy, now, appeal.Id, types.AppealPending)
+	if err != nil {
+		return errors.New("Failed to answer the appeal! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return ErrAlreadyResolved
+	}
+	if change != nil {
+		if err := applyGradeChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+	appeal.RespondedAt = &now
+	appeal.Updated_at = now
+
+	return nil
+
+}
+
+//func to get the grade changes of the student, the newest first
+func (s *GradeStore) GetGradeChanges(ctx context.Context, studentId uuid.UUID) ([]types.GradeChange, error) {
+
+	//base query
+	query := `
+		SELECT gc.id, gc.assessment_id, a.title AS assessment_title, sb.name AS subject_name, gc.student_id,
+		gc.old_score, gc.new_score, gc.source, gc.source_id, gc.reason, gc.changed_by, gc.created_at
+		FROM grade_changes gc
+		JOIN assessments a ON a.id = gc.assessment_id
+		JOIN subjects sb ON sb.id = a.subject_id
+		WHERE gc.student_id = $1
+		ORDER BY gc.created_at DESC;
+	`
+
+	//execute the query
+	changes := []types.GradeChange{}
+	if err := s.db.SelectContext(ctx, &changes, query, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the grade changes: %w", err)
+	}
+
+	return changes, nil
+
+}
@@ -0,0 +1,107 @@
+package grades
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//script the gradebook of the midterm and the final with the same weight, the scores are the scores that the
+//database has after the change
+func scriptGradebook(fake *dbtest.DB, key types.GradebookKey, published bool, midterm, final uuid.UUID, scores map[uuid.UUID]float64) {
+	now := time.Now().UTC()
+	fake.On("SELECT subject_id, class_id, term_id FROM assessments", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"subject_id", "class_id", "term_id"}, []driver.Value{key.SubjectId.String(), key.ClassId.String(), key.TermId.String()})
+	})
+	fake.On("FROM gradebook_publications", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"exists"}, []driver.Value{published})
+	})
+	fake.On("FROM grade_weights", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"category", "weight"}, []driver.Value{"midterm", int64(50)}, []driver.Value{"final", int64(50)})
+	})
+	fake.On("ORDER BY assessed_on", func(args []any) dbtest.Result {
+		columns := []string{"id", "subject_id", "class_id", "term_id", "category", "title", "max_score", "assessed_on", "created_by", "created_at", "updated_at"}
+		row := func(id uuid.UUID, category string) []driver.Value {
+			return []driver.Value{id.String(), key.SubjectId.String(), key.ClassId.String(), key.TermId.String(), category, category, float64(100), nil, nil, now, now}
+		}
+		return dbtest.Rows(columns, row(midterm, "midterm"), row(final, "final"))
+	})
+	fake.On("FROM assessment_scores sc", func(args []any) dbtest.Result {
+		rows := [][]driver.Value{}
+		for id, score := range scores {
+			rows = append(rows, []driver.Value{id.String(), args[3].(*uuid.UUID).String(), score, nil, now})
+		}
+		return dbtest.Rows([]string{"assessment_id", "student_id", "score", "graded_by", "updated_at"}, rows...)
+	})
+	fake.On("UPDATE term_scores", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+}
+
+func scriptChange(fake *dbtest.DB, old float64) {
+	fake.On("SELECT score FROM assessment_scores WHERE", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"score"}, []driver.Value{old})
+	})
+	fake.On("INSERT INTO assessment_scores", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("INSERT INTO grade_changes", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"id"}, []driver.Value{uuid.NewString()})
+	})
+}
+
+func TestRespondAppealRecomputesTheTermScore(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewGradeStore(db)
+
+	key := types.GradebookKey{SubjectId: uuid.New(), ClassId: uuid.New(), TermId: uuid.New()}
+	midterm, final := uuid.New(), uuid.New()
+	student := uuid.New()
+	fake.On("UPDATE grade_appeals", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	scriptChange(fake, 50)
+	scriptGradebook(fake, key, true, midterm, final, map[uuid.UUID]float64{midterm: 80, final: 60})
+
+	newScore := float64(80)
+	appeal := &types.GradeAppeal{Id: uuid.New(), Status: types.AppealAccepted, NewScore: &newScore}
+	change := &types.GradeChange{AssessmentId: midterm, StudentId: student, NewScore: newScore, Source: types.ChangeByAppeal}
+	if err := store.RespondAppeal(context.Background(), appeal, change); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	//the term score is read after the new score and saved in the same transaction
+	saved := fake.Index("INSERT INTO assessment_scores", 0)
+	read := fake.Index("FROM assessment_scores sc", saved)
+	update := fake.Index("UPDATE term_scores", read)
+	commit := fake.Index(dbtest.Commit, update)
+	if saved < 0 || read < 0 || update < 0 || commit < 0 || fake.Count(dbtest.Begin) != 1 {
+		t.Fatalf("expected the term score in the transaction, got %v", fake.Statements())
+	}
+	if got := fake.Statements()[update].Args[0]; got != float64(70) {
+		t.Fatalf("expected the term score 70, got %v", got)
+	}
+	if change.OldScore == nil || *change.OldScore != 50 {
+		t.Fatalf("expected the old score 50, got %v", change.OldScore)
+	}
+}
+
+func TestGradeRemedialOfUnpublishedGradebook(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewGradeStore(db)
+
+	key := types.GradebookKey{SubjectId: uuid.New(), ClassId: uuid.New(), TermId: uuid.New()}
+	fake.On("UPDATE remedials", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	scriptChange(fake, 60)
+	scriptGradebook(fake, key, false, uuid.New(), uuid.New(), nil)
+
+	final := float64(75)
+	remedial := &types.Remedial{Id: uuid.New(), FinalScore: &final}
+	change := &types.GradeChange{AssessmentId: uuid.New(), StudentId: uuid.New(), NewScore: final, Source: types.ChangeByRemedial}
+	if err := store.GradeRemedial(context.Background(), remedial, change); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if fake.Count("UPDATE term_scores") != 0 || fake.Count("FROM assessment_scores sc") != 0 {
+		t.Fatalf("the unpublished gradebook has no term score to change")
+	}
+}
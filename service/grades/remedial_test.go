@@ -0,0 +1,41 @@
+package grades
+
+import "testing"
+
+func TestFailed(t *testing.T) {
+	if !Failed(74, 100, 75) {
+		t.Fatalf("74 of 100 should fail the kkm 75")
+	}
+	if Failed(75, 100, 75) {
+		t.Fatalf("75 of 100 should pass the kkm 75")
+	}
+	//15 of 20 is 75
+	if Failed(15, 20, 75) || !Failed(14.5, 20, 75) {
+		t.Fatalf("the score should be compared in the scale of the max score")
+	}
+}
+
+func TestDefaultRemedialCap(t *testing.T) {
+	if got := DefaultRemedialCap(100, 75); got != 75 {
+		t.Fatalf("expected 75, got %v", got)
+	}
+	if got := DefaultRemedialCap(40, 70); got != 28 {
+		t.Fatalf("expected 28, got %v", got)
+	}
+}
+
+func TestRemedialFinalScore(t *testing.T) {
+	cases := []struct {
+		original, remedial, cap, want float64
+	}{
+		{60, 95, 75, 75},
+		{60, 70, 75, 70},
+		{60, 50, 75, 60},
+		{72, 72, 75, 72},
+	}
+	for _, c := range cases {
+		if got := RemedialFinalScore(c.original, c.remedial, c.cap); got != c.want {
+			t.Fatalf("RemedialFinalScore(%v, %v, %v) = %v, want %v", c.original, c.remedial, c.cap, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package grades
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// Categories is the order of the categories of the assessments
+var Categories = []string{types.CategoryQuiz, types.CategoryAssignment, types.CategoryMidterm, types.CategoryFinal}
+
+// DefaultWeights is the weights of the gradebook without the own weights
+var DefaultWeights = map[string]int{
+	types.CategoryQuiz: 20,
+	types.CategoryAssignment: 20,
+	types.CategoryMidterm: 25,
+	types.CategoryFinal: 35,
+}
+
+// ErrGradebookLocked is returned when the published gradebook is changed
+var ErrGradebookLocked = errors.New("The gradebook is published and locked!")
+
+// EffectiveWeights returns the weights of every category, the saved weights replace the default weights
+func EffectiveWeights(saved []types.GradeWeight) []types.GradeWeight {
+
+	weights := make([]types.GradeWeight, 0, len(Categories))
+	if len(saved) > 0 {
+		byCategory := make(map[string]int, len(saved))
+		for _, weight := range saved {
+			byCategory[weight.Category] = weight.Weight
+		}
+		for _, category := range Categories {
+			weights = append(weights, types.GradeWeight{Category: category, Weight: byCategory[category]})
+		}
+		return weights
+	}
+	for _, category := range Categories {
+		weights = append(weights, types.GradeWeight{Category: category, Weight: DefaultWeights[category]})
+	}
+
+	return weights
+
+}
+
+// ValidateWeights checks that every category is written once and the weights make 100
+func ValidateWeights(entries []types.GradeWeightEntry) error {
+
+	seen := make(map[string]bool, len(entries))
+	total := 0
+	for _, entry := range entries {
+		if seen[entry.Category] {
+			return fmt.Errorf("the category %s is written more than once", entry.Category)
+		}
+		seen[entry.Category] = true
+		total += entry.Weight
+	}
+	if total != 100 {
+		return fmt.Errorf("the total of the weights must be 100, got %d", total)
+	}
+
+	return nil
+
+}
+
+// ComputeTermScore computes the term score of one student from the scores by the assessment id. Every score
+// is taken as the percent of the max score and a missing score counts as zero. The category without any
+// assessment is skipped and the weights of the other categories are scaled to 100, so the score is nil
+// when there is no weighted assessment at all.
+func ComputeTermScore(weights []types.GradeWeight, assessments []types.Assessment, scores map[uuid.UUID]float64) types.TermScore {
+
+	result := types.TermScore{Categories: []types.CategoryScore{}}
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, assessment := range assessments {
+		counts[assessment.Category]++
+		score, ok := scores[assessment.Id]
+		if !ok {
+			result.Missing++
+			continue
+		}
+		if assessment.MaxScore > 0 {
+			totals[assessment.Category] += math.Min(score/assessment.MaxScore, 1) * 100
+		}
+	}
+
+	weighted, weightTotal := 0.0, 0
+	for _, weight := range weights {
+		count := counts[weight.Category]
+		if count == 0 {
+			continue
+		}
+		average := totals[weight.Category] / float64(count)
+		result.Categories = append(result.Categories, types.CategoryScore{
+			Category: weight.Category,
+			Weight: weight.Weight,
+			Average: Round2(average),
+			Assessments: count,
+		})
+		weighted += average * float64(weight.Weight)
+		weightTotal += weight.Weight
+	}
+	if weightTotal > 0 {
+		score := Round2(weighted / float64(weightTotal))
+		result.Score = &score
+	}
+
+	return result
+
+}
+
+// Round2 rounds the score to two decimals
+func Round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,72 @@
+package grades
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestEffectiveWeights(t *testing.T) {
+	weights := EffectiveWeights(nil)
+	total := 0
+	for _, weight := range weights {
+		total += weight.Weight
+	}
+	if len(weights) != 4 || total != 100 {
+		t.Fatalf("the default weights should have 4 categories and make 100, got %v", weights)
+	}
+
+	weights = EffectiveWeights([]types.GradeWeight{{Category: "final", Weight: 60}, {Category: "midterm", Weight: 40}})
+	if weights[0].Weight != 0 || weights[2].Weight != 40 || weights[3].Weight != 60 {
+		t.Fatalf("unexpected saved weights %v", weights)
+	}
+}
+
+func TestValidateWeights(t *testing.T) {
+	if err := ValidateWeights([]types.GradeWeightEntry{{Category: "quiz", Weight: 50}, {Category: "final", Weight: 50}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := ValidateWeights([]types.GradeWeightEntry{{Category: "quiz", Weight: 50}, {Category: "final", Weight: 40}}); err == nil {
+		t.Fatalf("the weights that do not make 100 should fail")
+	}
+	if err := ValidateWeights([]types.GradeWeightEntry{{Category: "quiz", Weight: 50}, {Category: "quiz", Weight: 50}}); err == nil {
+		t.Fatalf("the repeated category should fail")
+	}
+}
+
+func TestComputeTermScore(t *testing.T) {
+	weights := []types.GradeWeight{{Category: "quiz", Weight: 20}, {Category: "assignment", Weight: 20}, {Category: "midterm", Weight: 25}, {Category: "final", Weight: 35}}
+	quiz1 := types.Assessment{Id: uuid.New(), Category: "quiz", MaxScore: 10}
+	quiz2 := types.Assessment{Id: uuid.New(), Category: "quiz", MaxScore: 20}
+	midterm := types.Assessment{Id: uuid.New(), Category: "midterm", MaxScore: 100}
+	final := types.Assessment{Id: uuid.New(), Category: "final", MaxScore: 100}
+	assessments := []types.Assessment{quiz1, quiz2, midterm, final}
+
+	//quiz (80 + 50) / 2 = 65, midterm 70, final 90, the assignment has no assessment
+	result := ComputeTermScore(weights, assessments, map[uuid.UUID]float64{
+		quiz1.Id: 8, quiz2.Id: 10, midterm.Id: 70, final.Id: 90,
+	})
+	want := Round2((65*20 + 70*25 + 90*35) / 80.0)
+	if result.Score == nil || *result.Score != want {
+		t.Fatalf("got %v, want %v", result.Score, want)
+	}
+	if len(result.Categories) != 3 || result.Missing != 0 {
+		t.Fatalf("unexpected categories %v or missing %d", result.Categories, result.Missing)
+	}
+
+	//the missing final counts as zero
+	result = ComputeTermScore(weights, assessments, map[uuid.UUID]float64{
+		quiz1.Id: 8, quiz2.Id: 10, midterm.Id: 70,
+	})
+	want = Round2((65*20 + 70*25) / 80.0)
+	if result.Score == nil || *result.Score != want || result.Missing != 1 {
+		t.Fatalf("got %v with %d missing, want %v", result.Score, result.Missing, want)
+	}
+
+	//no assessment at all
+	if result := ComputeTermScore(weights, nil, nil); result.Score != nil {
+		t.Fatalf("the score without assessment should be nil")
+	}
+}
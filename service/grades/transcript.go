@@ -0,0 +1,146 @@
+package grades
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// ValidateRules checks the rounding mode and the decimals of the transcript
+func ValidateRules(rules types.TranscriptRules) error {
+	switch rules.Rounding {
+	case types.RoundHalfUp, types.RoundHalfEven, types.RoundFloor, types.RoundCeil:
+	default:
+		return fmt.Errorf("unknown rounding %q, use half_up, half_even, floor or ceil", rules.Rounding)
+	}
+	if rules.Decimals < 0 || rules.Decimals > 2 {
+		return fmt.Errorf("the decimals must be between 0 and 2, got %d", rules.Decimals)
+	}
+	if rules.DefaultKkm < 0 || rules.DefaultKkm > 100 {
+		return fmt.Errorf("the default kkm must be between 0 and 100")
+	}
+	return nil
+}
+
+// RoundScore rounds the score to the decimals with the rounding mode. The scaled value is cleaned from
+// the floating point noise first, so 72.45 is never floored to 72.44.
+func RoundScore(value float64, decimals int, mode string) float64 {
+
+	scale := math.Pow(10, float64(decimals))
+	scaled := math.Round(value*scale*1e6) / 1e6
+	switch mode {
+	case types.RoundHalfEven:
+		scaled = math.RoundToEven(scaled)
+	case types.RoundFloor:
+		scaled = math.Floor(scaled)
+	case types.RoundCeil:
+		scaled = math.Ceil(scaled)
+	default:
+		scaled = math.Round(scaled)
+	}
+
+	return scaled / scale
+
+}
+
+// BuildTranscript groups the published term scores by the term in the order of the rows and by the subject.
+// Every term score is rounded first and the averages are computed from the rounded scores, so the averages
+// match the printed grades. A term score under the kkm of the subject needs the remedial work.
+func BuildTranscript(rows []types.TranscriptScore, rules types.TranscriptRules) types.Transcript {
+
+	transcript := types.Transcript{
+		Rules: rules,
+		Terms: []types.TranscriptTerm{},
+		Subjects: []types.TranscriptSubject{},
+	}
+
+	termIndex := make(map[uuid.UUID]int)
+	subjectIndex := make(map[uuid.UUID]int)
+	subjectTotals := make(map[uuid.UUID]float64)
+	total, count := 0.0, 0
+	for _, row := range rows {
+		kkm := rules.DefaultKkm
+		if row.Kkm != nil {
+			kkm = *row.Kkm
+		}
+		score := RoundScore(row.Score, rules.Decimals, rules.Rounding)
+		entry := types.TranscriptEntry{
+			SubjectId: row.SubjectId,
+			SubjectCode: row.SubjectCode,
+			SubjectName: row.SubjectName,
+			Score: score,
+			Kkm: kkm,
+			Passed: score >= kkm,
+			Remedial: score < kkm,
+		}
+
+		//the term of the score
+		i, ok := termIndex[row.TermId]
+		if !ok {
+			i = len(transcript.Terms)
+			termIndex[row.TermId] = i
+			transcript.Terms = append(transcript.Terms, types.TranscriptTerm{
+				TermId: row.TermId,
+				TermName: row.TermName,
+				Semester: row.Semester,
+				AcademicYear: row.AcademicYear,
+				ClassName: row.ClassName,
+				Entries: []types.TranscriptEntry{},
+			})
+		}
+		transcript.Terms[i].Entries = append(transcript.Terms[i].Entries, entry)
+
+		//the subject of the score
+		j, ok := subjectIndex[row.SubjectId]
+		if !ok {
+			j = len(transcript.Subjects)
+			subjectIndex[row.SubjectId] = j
+			transcript.Subjects = append(transcript.Subjects, types.TranscriptSubject{
+				SubjectId: row.SubjectId,
+				SubjectCode: row.SubjectCode,
+				SubjectName: row.SubjectName,
+				Kkm: kkm,
+				RemedialTerms: []uuid.UUID{},
+			})
+		}
+		subject := &transcript.Subjects[j]
+		subject.Terms++
+		subjectTotals[row.SubjectId] += score
+		if entry.Remedial {
+			subject.NeedsRemedial = true
+			subject.RemedialTerms = append(subject.RemedialTerms, row.TermId)
+			transcript.RemedialCount++
+		}
+
+		total += score
+		count++
+	}
+
+	//the averages
+	for i := range transcript.Terms {
+		term := &transcript.Terms[i]
+		sum := 0.0
+		for _, entry := range term.Entries {
+			sum += entry.Score
+		}
+		term.Average = RoundScore(sum/float64(len(term.Entries)), rules.Decimals, rules.Rounding)
+	}
+	for i := range transcript.Subjects {
+		subject := &transcript.Subjects[i]
+		subject.Average = RoundScore(subjectTotals[subject.SubjectId]/float64(subject.Terms), rules.Decimals, rules.Rounding)
+	}
+	sort.SliceStable(transcript.Subjects, func(a, b int) bool {
+		return transcript.Subjects[a].SubjectName < transcript.Subjects[b].SubjectName
+	})
+	if count > 0 {
+		average := RoundScore(total/float64(count), rules.Decimals, rules.Rounding)
+		transcript.CumulativeAverage = &average
+	}
+
+	return transcript
+
+}
@@ -0,0 +1,98 @@
+package grades
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//func to get the transcript of the student over every term, the rules can be changed with the query
+//params (?rounding=half_up|half_even|floor|ceil&decimals=0..2&kkm=), the siswa can only get their own
+func (h *HandleRequest) Transcript_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//the rules of the transcript
+	rules := h.rules
+	query := r.URL.Query()
+	if value := query.Get("rounding"); value != "" {
+		rules.Rounding = value
+	}
+	if value := query.Get("decimals"); value != "" {
+		if rules.Decimals, err = strconv.Atoi(value); err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid decimals of the transcript!", err.Error())
+			return 
+		}
+	}
+	if value := query.Get("kkm"); value != "" {
+		if rules.DefaultKkm, err = strconv.ParseFloat(value, 64); err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid kkm of the transcript!", err.Error())
+			return 
+		}
+	}
+	if err := ValidateRules(rules); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid rules of the transcript!", err.Error())
+		return 
+	}
+
+	//the siswa can only get their own transcript
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if !h.canReadStudent(ctx, w, r, student_id) {
+		return 
+	}
+
+	//execute the query
+	student, err := h.db.GetTranscriptStudent(ctx, student_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return 
+	}
+	if student == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The student is not exist!", false)
+		return 
+	}
+	scores, err := h.db.GetTranscriptScores(ctx, student_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the transcript", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the transcript!", err.Error())
+		return 
+	}
+
+	//return a final result
+	transcript := BuildTranscript(scores, rules)
+	transcript.Student = student
+	utils.ResponseSuccess(w, http.StatusOK, "Get the transcript has been successfully", transcript)
+
+}
@@ -0,0 +1,94 @@
+package grades
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestRoundScore(t *testing.T) {
+	cases := []struct {
+		value    float64
+		decimals int
+		mode     string
+		want     float64
+	}{
+		{72.5, 0, types.RoundHalfUp, 73},
+		{72.5, 0, types.RoundHalfEven, 72},
+		{73.5, 0, types.RoundHalfEven, 74},
+		{72.9, 0, types.RoundFloor, 72},
+		{72.1, 0, types.RoundCeil, 73},
+		{72.45, 2, types.RoundFloor, 72.45},
+		{72.455, 2, types.RoundHalfUp, 72.46},
+		{72.45, 1, types.RoundHalfEven, 72.4},
+	}
+	for _, c := range cases {
+		if got := RoundScore(c.value, c.decimals, c.mode); got != c.want {
+			t.Fatalf("RoundScore(%v, %d, %s) = %v, want %v", c.value, c.decimals, c.mode, got, c.want)
+		}
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	if err := ValidateRules(types.TranscriptRules{Rounding: types.RoundHalfUp, Decimals: 0, DefaultKkm: 75}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := ValidateRules(types.TranscriptRules{Rounding: "banker", DefaultKkm: 75}); err == nil {
+		t.Fatalf("the unknown rounding should fail")
+	}
+	if err := ValidateRules(types.TranscriptRules{Rounding: types.RoundFloor, Decimals: 3, DefaultKkm: 75}); err == nil {
+		t.Fatalf("the decimals over 2 should fail")
+	}
+	if err := ValidateRules(types.TranscriptRules{Rounding: types.RoundFloor, DefaultKkm: 120}); err == nil {
+		t.Fatalf("the kkm over 100 should fail")
+	}
+}
+
+func TestBuildTranscript(t *testing.T) {
+	term1, term2 := uuid.New(), uuid.New()
+	math, physics := uuid.New(), uuid.New()
+	kkm := 80.0
+	rows := []types.TranscriptScore{
+		{TermId: term1, TermName: "Ganjil", SubjectId: physics, SubjectName: "Fisika", Score: 70.4, Kkm: &kkm},
+		{TermId: term1, TermName: "Ganjil", SubjectId: math, SubjectName: "Matematika", Score: 74.6},
+		{TermId: term2, TermName: "Genap", SubjectId: physics, SubjectName: "Fisika", Score: 90.5, Kkm: &kkm},
+		{TermId: term2, TermName: "Genap", SubjectId: math, SubjectName: "Matematika", Score: 81},
+	}
+	transcript := BuildTranscript(rows, types.TranscriptRules{Rounding: types.RoundHalfUp, Decimals: 0, DefaultKkm: 75})
+
+	if len(transcript.Terms) != 2 || transcript.Terms[0].TermId != term1 {
+		t.Fatalf("the terms should follow the rows, got %v", transcript.Terms)
+	}
+	//70 and 75 in the first term
+	if transcript.Terms[0].Average != 73 {
+		t.Fatalf("the average of the first term should be 73, got %v", transcript.Terms[0].Average)
+	}
+	if len(transcript.Subjects) != 2 || transcript.Subjects[0].SubjectName != "Fisika" {
+		t.Fatalf("the subjects should be sorted by name, got %v", transcript.Subjects)
+	}
+	physicsSubject := transcript.Subjects[0]
+	if !physicsSubject.NeedsRemedial || len(physicsSubject.RemedialTerms) != 1 || physicsSubject.RemedialTerms[0] != term1 {
+		t.Fatalf("fisika should need the remedial of the first term, got %v", physicsSubject)
+	}
+	if physicsSubject.Kkm != 80 || physicsSubject.Average != 81 {
+		t.Fatalf("unexpected kkm or average of fisika %v", physicsSubject)
+	}
+	//75 is on the default kkm so it is passed
+	if transcript.Subjects[1].NeedsRemedial {
+		t.Fatalf("matematika should not need the remedial, got %v", transcript.Subjects[1])
+	}
+	if transcript.RemedialCount != 1 {
+		t.Fatalf("the remedial count should be 1, got %d", transcript.RemedialCount)
+	}
+	//70 + 75 + 91 + 81 = 317 / 4 = 79.25
+	if transcript.CumulativeAverage == nil || *transcript.CumulativeAverage != 79 {
+		t.Fatalf("the cumulative average should be 79, got %v", transcript.CumulativeAverage)
+	}
+
+	empty := BuildTranscript(nil, types.TranscriptRules{Rounding: types.RoundHalfUp})
+	if empty.CumulativeAverage != nil || len(empty.Terms) != 0 {
+		t.Fatalf("the empty transcript should not have an average, got %v", empty)
+	}
+}
@@ -0,0 +1,561 @@
+package guardians
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the guardian store for a database logic
+type HandleRequest struct {
+	db types.GuardianStore
+}
+
+//func that declare the handler for the guardians and the links to the students
+func NewHandlerGuardian(db types.GuardianStore) *HandleRequest {
+	return &HandleRequest{db: db}
+}
+
+//helper to allow only the admin, the admin manages the guardians
+func adminOnly(w http.ResponseWriter, r *http.Request) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//helper to allow only the guru and the admin
+func staffOnly(w http.ResponseWriter, r *http.Request) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//func to create the guardian, the user id links the login of the role orangtua
+func (h *HandleRequest) CreateGuardian_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can create the guardian
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.CreateGuardian
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	//execute the query
+	now := time.Now().UTC()
+	guardian := types.Guardian{
+		Id: uuid.New(),
+		UserId: payload.UserId,
+		FullName: payload.FullName,
+		Phone: payload.Phone,
+		Email: payload.Email,
+		Address: payload.Address,
+		Occupation: payload.Occupation,
+		Children: []types.GuardianChild{},
+		Created_at: now,
+		Updated_at: now,
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CreateGuardian(ctx, &guardian); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the guardian",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the guardian!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the guardian has been successfully", guardian)
+
+}
+
+//func to get the guardians (?search=)
+func (h *HandleRequest) GetGuardians_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can see the guardians
+	if !staffOnly(w, r) {
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	guardians, err := h.db.GetGuardians(ctx, r.URL.Query().Get("search"))
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the guardians",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the guardians!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the guardians has been successfully", guardians)
+
+}
+
+//func to get the guardian by the id with the children
+func (h *HandleRequest) GetGuardian_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can see the guardian
+	if !staffOnly(w, r) {
+		return
+	}
+
+	//declare the id of the parameters
+	guardian_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	guardian, err := h.db.GetGuardianById(ctx, guardian_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the guardian",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the guardian!", err.Error())
+		return
+	}
+	if guardian == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The guardian is not exist!", false)
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the guardian has been successfully", guardian)
+
+}
+
+//func to update the guardian
+func (h *HandleRequest) UpdateGuardian_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can update the guardian
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//declare the id of the parameters
+	guardian_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.UpdateGuardian
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	guardian, err := h.db.UpdateGuardian(ctx, guardian_id, payload)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to update the guardian",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the guardian!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Update the guardian has been successfully", guardian)
+
+}
+
+//func to delete the guardian with the links to the students, the user of the guardian is kept
+func (h *HandleRequest) DeleteGuardian_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can delete the guardian
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//declare the id of the parameters
+	guardian_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.DeleteGuardian(ctx, guardian_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the guardian",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the guardian!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the guardian has been successfully", guardian_id)
+
+}
+
+//func to link the student to the guardian with the relationship, the link is updated when it is already exist
+func (h *HandleRequest) LinkStudent_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can link the student
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//declare the id of the parameters
+	guardian_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//decode and validate the payload
+	var payload types.LinkGuardianStudent
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	//execute the query
+	link := types.GuardianLink{
+		GuardianId: guardian_id,
+		StudentId: payload.StudentId,
+		Relationship: payload.Relationship,
+		IsPrimary: payload.IsPrimary,
+		Created_at: time.Now().UTC(),
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.LinkStudent(ctx, &link); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to link the student to the guardian",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to link the student!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Link the student has been successfully", link)
+
+}
+
+//func to unlink the student from the guardian
+func (h *HandleRequest) UnlinkStudent_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the admin can unlink the student
+	if !adminOnly(w, r) {
+		return
+	}
+
+	//declare the id of the parameters
+	guardian_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+	student_id, err := uuid.Parse(mux.Vars(r)["student_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.UnlinkStudent(ctx, guardian_id, student_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to unlink the student from the guardian",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to unlink the student!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Unlink the student has been successfully", student_id)
+
+}
+
+//func to get the guardians of the student for the guru and the admin
+func (h *HandleRequest) StudentGuardians_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the guru and the admin can see the guardians of the student
+	if !staffOnly(w, r) {
+		return
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	guardians, err := h.db.GetStudentGuardians(ctx, student_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the guardians of the student",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the guardians!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the guardians of the student has been successfully", guardians)
+
+}
+
+//func to get the guardian of the user of the token with the children, only for the role orangtua
+func (h *HandleRequest) MyGuardian_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//only the parents have the guardian
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return
+	}
+	if role != types.RoleOrangtua {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	guardian, err := h.db.GetGuardianByUser(ctx, user_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the guardian of the user",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the guardian!", err.Error())
+		return
+	}
+	if guardian == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The user is not registered as a guardian!", false)
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the guardian has been successfully", guardian)
+
+}
@@ -0,0 +1,358 @@
+package guardians
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store guardian
+type GuardianStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewGuardianStore(db *sqlx.DB) *GuardianStore {
+	return &GuardianStore{db: db}
+}
+
+//the column of the guardian that we select in every query
+const guardianColumns = `id, user_id, full_name, phone, email, address, occupation, created_at, updated_at`
+
+//the student of the guardian with the class of the student
+const childSelect = `
+	SELECT gs.guardian_id, gs.student_id, s.name AS student_name, s.class_id, c.name AS class_name,
+	gs.relationship, gs.is_primary
+	FROM guardian_students gs
+	JOIN students s ON s.id = gs.student_id
+	LEFT JOIN classes c ON c.id = s.class_id
+`
+
+//helper to check that the user of the guardian has the role orangtua
+func checkParentUser(ctx context.Context, db sqlx.QueryerContext, userId *uuid.UUID) error {
+
+	if userId == nil {
+		return nil
+	}
+	var role string
+	if err := sqlx.GetContext(ctx, db, &role, `SELECT role FROM users WHERE id = $1;`, *userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("The user is not exist!")
+		}
+		return errors.New("Failed to get the user of the guardian! " + err.Error())
+	}
+	if role != types.RoleOrangtua {
+		return errors.New("The user of the guardian must have the role orangtua!")
+	}
+
+	return nil
+
+}
+
+//helper to map the error of the unique user of the guardian
+func guardianError(err error, message string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return errors.New("The user is already linked to another guardian!")
+	}
+	return errors.New(message + " " + err.Error())
+}
+
+//func to create the guardian, the user is optional and must have the role orangtua
+func (s *GuardianStore) CreateGuardian(ctx context.Context, guardian *types.Guardian) error {
+
+	if err := checkParentUser(ctx, s.db, guardian.UserId); err != nil {
+		return err
+	}
+
+	//execute the query
+	query := `INSERT INTO guardians (` + guardianColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+	if _, err := s.db.ExecContext(ctx, query,
+		guardian.Id,
+		guardian.UserId,
+		guardian.FullName,
+		guardian.Phone,
+		guardian.Email,
+		guardian.Address,
+		guardian.Occupation,
+		guardian.Created_at,
+		guardian.Updated_at,
+	); err != nil {
+		return guardianError(err, "Failed to create the guardian!")
+	}
+
+	return nil
+
+}
+
+//func to get the guardian by the id with the children
+func (s *GuardianStore) GetGuardianById(ctx context.Context, id uuid.UUID) (*types.Guardian, error) {
+
+	//execute the query
+	var guardian types.Guardian
+	if err := s.db.GetContext(ctx, &guardian, `SELECT `+guardianColumns+` FROM guardians WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the guardian: %w", err)
+	}
+	children, err := s.GetChildren(ctx, guardian.Id)
+	if err != nil {
+		return nil, err
+	}
+	guardian.Children = children
+
+	return &guardian, nil
+
+}
+
+//func to get the guardian of the user with the role orangtua
+func (s *GuardianStore) GetGuardianByUser(ctx context.Context, userId uuid.UUID) (*types.Guardian, error) {
+
+	//execute the query
+	var guardian types.Guardian
+	if err := s.db.GetContext(ctx, &guardian, `SELECT `+guardianColumns+` FROM guardians WHERE user_id = $1;`, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the guardian of the user: %w", err)
+	}
+	children, err := s.GetChildren(ctx, guardian.Id)
+	if err != nil {
+		return nil, err
+	}
+	guardian.Children = children
+
+	return &guardian, nil
+
+}
+
+//func to get the guardians, the search matches the name, the phone or the email
+func (s *GuardianStore) GetGuardians(ctx context.Context, search string) ([]types.Guardian, error) {
+
+	//execute the query
+	guardians := []types.Guardian{}
+	query := `
+		SELECT ` + guardianColumns + ` FROM guardians
+		WHERE $1 = '' OR full_name ILIKE '%' || $1 || '%' OR phone ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%'
+		ORDER BY full_name, id;
+	`
+	if err := s.db.SelectContext(ctx, &guardians, query, strings.TrimSpace(search)); err != nil {
+		return nil, fmt.Errorf("failed to get the guardians: %w", err)
+	}
+
+	return guardians, nil
+
+}
+
+//func to update the guardian, only the field that is not nil is updated
+func (s *GuardianStore) UpdateGuardian(ctx context.Context, id uuid.UUID, payload types.UpdateGuardian) (*types.Guardian, error) {
+
+	//setup the args and args id
+	var settings []string
+	argsId := 1
+	var args []interface{}
+
+	if payload.UserId != nil {
+		if err := checkParentUser(ctx, s.db, payload.UserId); err != nil {
+			return nil, err
+		}
+		settings = append(settings, fmt.Sprintf("user_id=$%d", argsId))
+		args = append(args, *payload.UserId)
+		argsId++
+	}
+	if payload.FullName != nil {
+		settings = append(settings, fmt.Sprintf("full_name=$%d", argsId))
+		args = append(args, *payload.FullName)
+		argsId++
+	}
+	if payload.Phone != nil {
+		settings = append(settings, fmt.Sprintf("phone=$%d", argsId))
+		args = append(args, *payload.Phone)
+		argsId++
+	}
+	if payload.Email != nil {
+		settings = append(settings, fmt.Sprintf("email=$%d", argsId))
+		args = append(args, *payload.Email)
+		argsId++
+	}
+	if payload.Address != nil {
+		settings = append(settings, fmt.Sprintf("address=$%d", argsId))
+		args = append(args, *payload.Address)
+		argsId++
+	}
+	if payload.Occupation != nil {
+		settings = append(settings, fmt.Sprintf("occupation=$%d", argsId))
+		args = append(args, *payload.Occupation)
+		argsId++
+	}
+	if len(settings) == 0 {
+		return nil, errors.New("Nothing to update!")
+	}
+	settings = append(settings, fmt.Sprintf("updated_at=$%d", argsId))
+	args = append(args, time.Now().UTC())
+	argsId++
+	args = append(args, id)
+
+	//execute the query
+	query := fmt.Sprintf(`UPDATE guardians SET %s WHERE id=$%d RETURNING `+guardianColumns+`;`, strings.Join(settings, ", "), argsId)
+	var guardian types.Guardian
+	if err := s.db.GetContext(ctx, &guardian, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("The guardian is not exist!")
+		}
+		return nil, guardianError(err, "Failed to update the guardian!")
+	}
+	children, err := s.GetChildren(ctx, guardian.Id)
+	if err != nil {
+		return nil, err
+	}
+	guardian.Children = children
+
+	return &guardian, nil
+
+}
+
+//func to delete the guardian, the links to the students are deleted too
+func (s *GuardianStore) DeleteGuardian(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `DELETE FROM guardians WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the guardian! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to get the rows affected! " + err.Error())
+	}
+	if rows == 0 {
+		return errors.New("The guardian is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to link the student to the guardian or to change the relationship of the link, the student has
+//only one primary guardian so the other guardians of the student are not primary anymore
+func (s *GuardianStore) LinkStudent(ctx context.Context, link *types.GuardianLink) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	if link.IsPrimary {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE guardian_students SET is_primary = FALSE WHERE student_id = $1 AND guardian_id <> $2;
+		`, link.StudentId, link.GuardianId); err != nil {
+			return errors.New("Failed to update the primary guardian! " + err.Error())
+		}
+	}
+
+	//execute the query
+	if err := tx.GetContext(ctx, &link.Created_at, `
+		INSERT INTO guardian_students (guardian_id, student_id, relationship, is_primary, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (guardian_id, student_id) DO UPDATE SET relationship = EXCLUDED.relationship, is_primary = EXCLUDED.is_primary
+		RETURNING created_at;
+	`, link.GuardianId, link.StudentId, link.Relationship, link.IsPrimary, link.Created_at); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return errors.New("The guardian or the student is not exist!")
+		}
+		return errors.New("Failed to link the student! " + err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!")
+	}
+
+	return nil
+
+}
+
+//func to unlink the student from the guardian
+func (s *GuardianStore) UnlinkStudent(ctx context.Context, guardianId uuid.UUID, studentId uuid.UUID) error {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM guardian_students WHERE guardian_id = $1 AND student_id = $2;
+	`, guardianId, studentId)
+	if err != nil {
+		return errors.New("Failed to unlink the student! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to get the rows affected! " + err.Error())
+	}
+	if rows == 0 {
+		return errors.New("The student is not linked to the guardian!")
+	}
+
+	return nil
+
+}
+
+//func to get the students of the guardian
+func (s *GuardianStore) GetChildren(ctx context.Context, guardianId uuid.UUID) ([]types.GuardianChild, error) {
+
+	//execute the query
+	children := []types.GuardianChild{}
+	if err := s.db.SelectContext(ctx, &children, childSelect+` WHERE gs.guardian_id = $1 ORDER BY s.name, s.id;`, guardianId); err != nil {
+		return nil, fmt.Errorf("failed to get the children of the guardian: %w", err)
+	}
+
+	return children, nil
+
+}
+
+//func to get the guardians of the student, the primary guardian is the first
+func (s *GuardianStore) GetStudentGuardians(ctx context.Context, studentId uuid.UUID) ([]types.StudentGuardian, error) {
+
+	//execute the query
+	guardians := []types.StudentGuardian{}
+	if err := s.db.SelectContext(ctx, &guardians, `
+		SELECT gs.guardian_id, g.full_name, g.phone, g.email, gs.relationship, gs.is_primary,
+		g.user_id IS NOT NULL AS has_login
+		FROM guardian_students gs
+		JOIN guardians g ON g.id = gs.guardian_id
+		WHERE gs.student_id = $1
+		ORDER BY gs.is_primary DESC, g.full_name, g.id;
+	`, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the guardians of the student: %w", err)
+	}
+
+	return guardians, nil
+
+}
+
+//func to check that the user is the guardian of the student, the parents only see their own children
+func (s *GuardianStore) IsGuardianOf(ctx context.Context, userId uuid.UUID, studentId uuid.UUID) (bool, error) {
+
+	//execute the query
+	var linked bool
+	if err := s.db.GetContext(ctx, &linked, `
+		SELECT EXISTS (
+			SELECT 1 FROM guardian_students gs
+			JOIN guardians g ON g.id = gs.guardian_id
+			WHERE g.user_id = $1 AND gs.student_id = $2
+		);
+	`, userId, studentId); err != nil {
+		return false, fmt.Errorf("failed to check the guardian of the student: %w", err)
+	}
+
+	return linked, nil
+
+}
@@ -0,0 +1,155 @@
+package guardians
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//the store of the handler tests, it records the user and the student that the handler asked for
+type guardianStore struct {
+	types.GuardianStore
+	byUser map[uuid.UUID]*types.Guardian
+	askedUser uuid.UUID
+	askedStudent uuid.UUID
+	linked bool
+}
+
+func (s *guardianStore) GetGuardianByUser(ctx context.Context, userId uuid.UUID) (*types.Guardian, error) {
+	s.askedUser = userId
+	return s.byUser[userId], nil
+}
+
+func (s *guardianStore) GetStudentGuardians(ctx context.Context, studentId uuid.UUID) ([]types.StudentGuardian, error) {
+	s.askedStudent = studentId
+	return []types.StudentGuardian{}, nil
+}
+
+func (s *guardianStore) LinkStudent(ctx context.Context, link *types.GuardianLink) error {
+	s.linked = true
+	return nil
+}
+
+func guardianRequest(method string, body string, role string, userId uuid.UUID, vars map[string]string) *http.Request {
+	r := httptest.NewRequest(method, "/guardians", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(r.Context(), "request_id", "test")
+	ctx = context.WithValue(ctx, "role_user", role)
+	ctx = context.WithValue(ctx, "user_id", userId)
+	return mux.SetURLVars(r.WithContext(ctx), vars)
+}
+
+func TestMyGuardianIsTheGuardianOfTheToken(t *testing.T) {
+	logger.Log = zap.NewNop()
+	parent := uuid.New()
+	store := &guardianStore{byUser: map[uuid.UUID]*types.Guardian{parent: {Id: uuid.New(), UserId: &parent, FullName: "Siti"}}}
+	handler := NewHandlerGuardian(store)
+
+	for _, role := range []string{"siswa", "guru", "admin"} {
+		w := httptest.NewRecorder()
+		handler.MyGuardian_Bp(w, guardianRequest(http.MethodGet, "", role, parent, nil))
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("role %s: expected 403, got %d", role, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.MyGuardian_Bp(w, guardianRequest(http.MethodGet, "", types.RoleOrangtua, parent, nil))
+	if w.Code != http.StatusOK || store.askedUser != parent || !strings.Contains(w.Body.String(), "Siti") {
+		t.Fatalf("expected the guardian of the token, got %d: %s", w.Code, w.Body.String())
+	}
+
+	//the orangtua without the guardian record
+	w = httptest.NewRecorder()
+	handler.MyGuardian_Bp(w, guardianRequest(http.MethodGet, "", types.RoleOrangtua, uuid.New(), nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for the user without the guardian, got %d", w.Code)
+	}
+}
+
+func TestStudentGuardiansIsForTheStaff(t *testing.T) {
+	logger.Log = zap.NewNop()
+	student := uuid.New()
+	vars := map[string]string{"id": student.String()}
+
+	for _, role := range []string{"siswa", types.RoleOrangtua} {
+		store := &guardianStore{}
+		w := httptest.NewRecorder()
+		NewHandlerGuardian(store).StudentGuardians_Bp(w, guardianRequest(http.MethodGet, "", role, uuid.New(), vars))
+		if w.Code != http.StatusForbidden || store.askedStudent != uuid.Nil {
+			t.Fatalf("role %s: expected 403 without the query, got %d", role, w.Code)
+		}
+	}
+
+	store := &guardianStore{}
+	w := httptest.NewRecorder()
+	NewHandlerGuardian(store).StudentGuardians_Bp(w, guardianRequest(http.MethodGet, "", "guru", uuid.New(), vars))
+	if w.Code != http.StatusOK || store.askedStudent != student {
+		t.Fatalf("expected the guardians of the student, got %d", w.Code)
+	}
+}
+
+func TestLinkStudentIsAdminOnly(t *testing.T) {
+	logger.Log = zap.NewNop()
+	body := `{"student_id":"` + uuid.NewString() + `","relationship":"ibu"}`
+	vars := map[string]string{"id": uuid.NewString()}
+
+	for _, role := range []string{"guru", "siswa", types.RoleOrangtua} {
+		store := &guardianStore{}
+		w := httptest.NewRecorder()
+		NewHandlerGuardian(store).LinkStudent_Bp(w, guardianRequest(http.MethodPost, body, role, uuid.New(), vars))
+		if w.Code != http.StatusForbidden || store.linked {
+			t.Fatalf("role %s: expected 403 without the link, got %d", role, w.Code)
+		}
+	}
+}
+
+func TestIsGuardianOfScopesTheUser(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewGuardianStore(db)
+
+	parent, child, other := uuid.New(), uuid.New(), uuid.New()
+	fake.On("FROM guardian_students gs", func(args []any) dbtest.Result {
+		linked := args[0] == parent && args[1] == child
+		return dbtest.Rows([]string{"exists"}, []driver.Value{linked})
+	})
+
+	if linked, err := store.IsGuardianOf(context.Background(), parent, child); err != nil || !linked {
+		t.Fatalf("expected the linked child, got %v %v", linked, err)
+	}
+	if linked, err := store.IsGuardianOf(context.Background(), parent, other); err != nil || linked {
+		t.Fatalf("the student of the other family is not linked, got %v %v", linked, err)
+	}
+	if fake.Index("WHERE g.user_id = $1 AND gs.student_id = $2", 0) < 0 {
+		t.Fatalf("expected the link of the user and the student")
+	}
+}
+
+func TestCreateGuardianRequiresTheParentUser(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewGuardianStore(db)
+
+	fake.On("SELECT role FROM users", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"role"}, []driver.Value{"siswa"})
+	})
+
+	user := uuid.New()
+	err := store.CreateGuardian(context.Background(), &types.Guardian{Id: uuid.New(), UserId: &user, FullName: "Siti"})
+	if err == nil || !strings.Contains(err.Error(), "orangtua") {
+		t.Fatalf("expected the user of the guardian to be orangtua, got %v", err)
+	}
+	if fake.Count("INSERT INTO guardians") != 0 {
+		t.Fatalf("the guardian should not be created")
+	}
+}
@@ -0,0 +1,918 @@
+package homework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/service/grades"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/storage"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the homework store for a database logic
+type HandleRequest struct {
+	db types.HomeworkStore
+	grades types.GradeStore
+	subjects types.SubjectStore
+	academics types.AcademicStore
+	notifier types.Notifier
+	files storage.Storage
+	urlTTL time.Duration
+}
+
+//func that declare the handler for homework with the storage of the submitted files
+func NewHandlerHomework(db types.HomeworkStore, grades types.GradeStore, subjects types.SubjectStore, academics types.AcademicStore, notifier types.Notifier, files storage.Storage, cfg config.ConfigParams) *HandleRequest {
+	return &HandleRequest{
+		db: db,
+		grades: grades,
+		subjects: subjects,
+		academics: academics,
+		notifier: notifier,
+		files: files,
+		urlTTL: cfg.StorageURLTTL,
+	}
+}
+
+//helper for the term query params, the default is the open term
+func (h *HandleRequest) termParam(ctx context.Context, r *http.Request) (uuid.UUID, error) {
+	if value := r.URL.Query().Get("term_id"); value != "" {
+		return uuid.Parse(value)
+	}
+	term, err := h.academics.GetActiveTerm(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if term == nil {
+		return uuid.Nil, fmt.Errorf("there is no open term")
+	}
+	return term.Id, nil
+}
+
+//helper to check that the term is not closed
+func (h *HandleRequest) termNotClosed(ctx context.Context, w http.ResponseWriter, termId uuid.UUID) bool {
+	term, err := h.academics.GetTermById(ctx, termId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return false
+	}
+	if term == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The term is not exist!", false)
+		return false
+	}
+	if term.Status == "closed" {
+		utils.ResponseError(w, http.StatusBadRequest, "The homework of a closed term cannot be changed!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check if the user can manage the homework of the subject in the class, the admin can manage
+//every homework and the guru only the homework of the subject that they teach in the class
+func (h *HandleRequest) canManage(ctx context.Context, w http.ResponseWriter, r *http.Request, classId uuid.UUID, termId uuid.UUID, subjectId uuid.UUID) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role == "admin" {
+		return true
+	}
+	if role != "guru" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	allowed, err := h.subjects.CanTeach(ctx, user_id, classId, &termId, &subjectId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the teaching assignment!", err.Error())
+		return false
+	}
+	if !allowed {
+		utils.ResponseError(w, http.StatusForbidden, "The guru is not assigned to the subject of this class!", false)
+		return false
+	}
+	return true
+}
+
+//helper to get the student of the siswa of the token, nil for the guru and the admin
+func (h *HandleRequest) studentOf(ctx context.Context, w http.ResponseWriter, r *http.Request) (*uuid.UUID, bool) {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return nil, false
+	}
+	if role == "guru" || role == "admin" {
+		return nil, true
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return nil, false
+	}
+	student_id, err := h.grades.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return nil, false
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return nil, false
+	}
+	return student_id, true
+}
+
+//helper to check that the student is a member of the class of the homework
+func (h *HandleRequest) memberOf(ctx context.Context, w http.ResponseWriter, studentId uuid.UUID, classId uuid.UUID, termId uuid.UUID) bool {
+	member, err := h.db.IsStudentOfClass(ctx, studentId, classId, termId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the class of the student!", err.Error())
+		return false
+	}
+	if !member {
+		utils.ResponseError(w, http.StatusForbidden, "The student is not a member of this class!", false)
+		return false
+	}
+	return true
+}
+
+//helper to get the homework by the id of the parameters
+func (h *HandleRequest) homeworkParam(ctx context.Context, w http.ResponseWriter, r *http.Request) (*types.Homework, bool) {
+	homework_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, false
+	}
+	homework, err := h.db.GetHomeworkById(ctx, homework_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the homework!", err.Error())
+		return nil, false
+	}
+	if homework == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The homework is not exist!", false)
+		return nil, false
+	}
+	return homework, true
+}
+
+//helper to respond the error of the store, the change of a graded submission or of a published gradebook is a conflict
+func storeError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, ErrAlreadyGraded) || errors.Is(err, grades.ErrGradebookLocked) {
+		utils.ResponseError(w, http.StatusConflict, message, err.Error())
+		return
+	}
+	utils.ResponseError(w, http.StatusBadRequest, message, err.Error())
+}
+
+//func to create the homework of the subject in the class (?term_id=), the homework is saved as an
+//assignment assessment of the gradebook so the score of the submission flows into the gradebook
+func (h *HandleRequest) CreateHomework_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	subject_id, err := uuid.Parse(mux.Vars(r)["subject_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateHomework
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the admin and the assigned guru can create the homework
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	if !h.canManage(ctx, w, r, class_id, term_id, subject_id) || !h.termNotClosed(ctx, w, term_id) {
+		return 
+	}
+
+	//the assessment of the homework in the gradebook
+	now := time.Now().UTC()
+	due_at := payload.DueAt.UTC()
+	max_score := payload.MaxScore
+	if max_score == 0 {
+		max_score = 100
+	}
+	allow_late := true
+	if payload.AllowLate != nil {
+		allow_late = *payload.AllowLate
+	}
+	var created_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		created_by = &user_id
+	}
+	assessment := types.Assessment{
+		Id: uuid.New(),
+		SubjectId: subject_id,
+		ClassId: class_id,
+		TermId: term_id,
+		Category: types.CategoryAssignment,
+		Title: payload.Title,
+		MaxScore: max_score,
+		AssessedOn: &due_at,
+		CreatedBy: created_by,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if err := h.grades.CreateAssessment(ctx, &assessment); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the assessment of the homework", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to create the homework!", err)
+		return 
+	}
+
+	//execute the query
+	homework := types.Homework{
+		Id: uuid.New(),
+		AssessmentId: assessment.Id,
+		SubjectId: subject_id,
+		ClassId: class_id,
+		TermId: term_id,
+		Title: payload.Title,
+		Description: payload.Description,
+		DueAt: due_at,
+		AllowLate: allow_late,
+		MaxScore: max_score,
+		CreatedBy: created_by,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if err := h.db.CreateHomework(ctx, &homework); err != nil {
+		//the assessment without the homework is removed again
+		if err := h.grades.DeleteAssessment(ctx, assessment.Id); err != nil {
+			logger.Log.Error("Failed to remove the assessment of the homework", 
+				zap.String("request_id", requestID),
+				zap.String("assessment_id", assessment.Id.String()),
+				zap.Error(err),
+		)
+		}
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the homework", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the homework!", err.Error())
+		return 
+	}
+
+	//tell the students of the class and their guardians, the homework is already saved so the failure is only logged
+	subject_name := ""
+	if subject, err := h.subjects.GetSubjectById(ctx, subject_id); err == nil && subject != nil {
+		subject_name = subject.Name
+	}
+	if err := h.notifier.Notify(ctx, types.NotificationEvent{
+		Type: types.NotifyHomeworkAssigned,
+		Audience: types.NotificationAudience{ClassId: &class_id, TermId: &term_id, Guardians: true},
+		Payload: map[string]any{
+			"homework_id": homework.Id.String(),
+			"title": homework.Title,
+			"subject_id": subject_id.String(),
+			"subject_name": subject_name,
+			"due_at": homework.DueAt,
+		},
+		DedupeKey: "homework_assigned:" + homework.Id.String(),
+		At: now,
+	}); err != nil {
+		logger.Log.Warn("Failed to notify the new homework",
+			zap.String("request_id", requestID),
+			zap.Error(err),
+		)
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the homework has been successfully", homework)
+
+}
+
+//func to get the homework of the class in the term (?term_id=&subject_id=), the siswa of the class get
+//their own submission with the homework
+func (h *HandleRequest) GetClassHomework_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	var subject_id *uuid.UUID
+	if value := r.URL.Query().Get("subject_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+			return 
+		}
+		subject_id = &id
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//the siswa must be a member of the class
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id != nil && !h.memberOf(ctx, w, *student_id, class_id, term_id) {
+		return 
+	}
+
+	//execute the query
+	homework, err := h.db.GetHomework(ctx, class_id, term_id, subject_id, student_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the homework", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the homework!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the homework has been successfully", homework)
+
+}
+
+//func to get the homework of the class of the siswa of the token in the term (?term_id=) with their submissions
+func (h *HandleRequest) MyHomework_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the student of the user
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The user is not registered as a student!", false)
+		return 
+	}
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	class, err := h.grades.GetStudentClass(ctx, *student_id, term_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class of the student!", err.Error())
+		return 
+	}
+	if class == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The student has no class in this term!", false)
+		return 
+	}
+
+	//execute the query
+	homework, err := h.db.GetHomework(ctx, class.ClassId, term_id, nil, student_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the homework", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the homework!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the homework has been successfully", homework)
+
+}
+
+//func to get the homework by the id, the siswa of the class get their own submission with the homework
+func (h *HandleRequest) GetHomework_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the homework, the siswa must be a member of the class
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	homework, ok := h.homeworkParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id != nil {
+		if !h.memberOf(ctx, w, *student_id, homework.ClassId, homework.TermId) {
+			return 
+		}
+		submission, err := h.db.GetSubmission(ctx, homework.Id, *student_id)
+		if err != nil {
+			//logger if the response is failed
+			logger.Log.Error("Failed to get the submission", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the submission!", err.Error())
+			return 
+		}
+		homework.Submission = submission
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the homework has been successfully", homework)
+
+}
+
+//func to delete the homework with the assessment and the scores, the homework of a published gradebook cannot be deleted
+func (h *HandleRequest) DeleteHomework_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can delete the homework
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	homework, ok := h.homeworkParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if !h.canManage(ctx, w, r, homework.ClassId, homework.TermId, homework.SubjectId) || !h.termNotClosed(ctx, w, homework.TermId) {
+		return 
+	}
+
+	//execute the query, the homework is deleted with the assessment
+	if err := h.grades.DeleteAssessment(ctx, homework.AssessmentId); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the homework", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to delete the homework!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the homework has been successfully", homework.Id)
+
+}
+
+//func to submit the homework by the siswa of the token, the form has the text (body) and the files
+//(files), the submission after the due date is flagged as late
+func (h *HandleRequest) Submit_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the homework, only the siswa of the class can submit it
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	homework, ok := h.homeworkParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusForbidden, "Only the siswa can submit the homework!", false)
+		return 
+	}
+	if !h.memberOf(ctx, w, *student_id, homework.ClassId, homework.TermId) || !h.termNotClosed(ctx, w, homework.TermId) {
+		return 
+	}
+	submitted_at := time.Now().UTC()
+	late, err := CheckSubmission(submitted_at, homework.DueAt, homework.AllowLate)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, err.Error(), false)
+		return 
+	}
+
+	//declare the form validaton for the size of the files
+	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize)
+	if err := r.ParseMultipartForm(MaxUploadSize); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to parse the multipart form data for a request!", err.Error())
+		return 
+	}
+	body := r.FormValue("body")
+	headers := r.MultipartForm.File["files"]
+	if body == "" && len(headers) == 0 {
+		utils.ResponseError(w, http.StatusBadRequest, "The submission must have the text or the files!", false)
+		return 
+	}
+	if len(headers) > MaxFiles {
+		utils.ResponseError(w, http.StatusBadRequest, fmt.Sprintf("The submission can have %d files at most!", MaxFiles), false)
+		return 
+	}
+
+	//save every file in the storage
+	files := make([]types.HomeworkFile, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to open the file!", err.Error())
+			return 
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil || len(data) == 0 {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to read the file!", false)
+			return 
+		}
+		content_type := http.DetectContentType(data)
+		ext, ok := FileExt(content_type, header.Filename)
+		if !ok {
+			//logger the data response if the type of file is failed
+			logger.Log.Error("Failed because the file of the submission is invalid!", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.String("filename", header.Filename),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed content file type, use pdf, image, text, zip or office documents!", header.Filename)
+			return 
+		}
+		key := storage.ContentKey("homework", data, ext)
+		if err := storage.PutBytes(ctx, h.files, key, data, content_type); err != nil {
+			//logger the data response if the storage is failed
+			logger.Log.Error("Failed to save the file of the submission", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.String("key", key),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusInternalServerError, "Failed to save the file!", err.Error())
+			return 
+		}
+		files = append(files, types.HomeworkFile{
+			FileKey: key,
+			FileName: CleanFileName(header.Filename),
+			ContentType: content_type,
+			Size: int64(len(data)),
+		})
+	}
+
+	//execute the query
+	submission := types.HomeworkSubmission{
+		HomeworkId: homework.Id,
+		StudentId: *student_id,
+		Body: body,
+		SubmittedAt: submitted_at,
+		IsLate: late,
+	}
+	if err := h.db.SaveSubmission(ctx, &submission, files); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the submission", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to save the submission!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Submit the homework has been successfully", submission)
+
+}
+
+//func to get every submission of the homework
+func (h *HandleRequest) GetSubmissions_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can see the submissions
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	homework, ok := h.homeworkParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if !h.canManage(ctx, w, r, homework.ClassId, homework.TermId, homework.SubjectId) {
+		return 
+	}
+
+	//execute the query
+	submissions, err := h.db.GetSubmissions(ctx, homework.Id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the submissions", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the submissions!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the submissions has been successfully", submissions)
+
+}
+
+//func to grade the submission with the feedback, the score is saved in the gradebook as the score of the
+//assessment of the homework
+func (h *HandleRequest) GradeSubmission_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	submission_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.GradeSubmission
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//get the submission and the homework
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	submission, err := h.db.GetSubmissionById(ctx, submission_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the submission!", err.Error())
+		return 
+	}
+	if submission == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The submission is not exist!", false)
+		return 
+	}
+	homework, err := h.db.GetHomeworkById(ctx, submission.HomeworkId)
+	if err != nil || homework == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the homework!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can grade the submission
+	if !h.canManage(ctx, w, r, homework.ClassId, homework.TermId, homework.SubjectId) || !h.termNotClosed(ctx, w, homework.TermId) {
+		return 
+	}
+	assessment, err := h.grades.GetAssessmentById(ctx, homework.AssessmentId)
+	if err != nil || assessment == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the assessment of the homework!", false)
+		return 
+	}
+
+	//the score flows into the gradebook first, so the published gradebook stops the grading
+	var graded_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		graded_by = &user_id
+	}
+	if err := h.grades.SaveScores(ctx, assessment, []types.ScoreEntry{{StudentId: submission.StudentId, Score: *payload.Score}}, graded_by); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the score of the submission", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to save the score!", err)
+		return 
+	}
+
+	//execute the query
+	if err := h.db.GradeSubmission(ctx, submission.Id, *payload.Score, payload.Feedback, graded_by); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to grade the submission", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to grade the submission!", err.Error())
+		return 
+	}
+	now := time.Now().UTC()
+	submission.Score = payload.Score
+	submission.Feedback = payload.Feedback
+	submission.GradedBy = graded_by
+	submission.GradedAt = &now
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Grade the submission has been successfully", submission)
+
+}
+
+//func to download the file of the submission with a signed url, the siswa can only download their own files
+func (h *HandleRequest) DownloadFile_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	file_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//get the file with the submission and the homework
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	file, err := h.db.GetFileById(ctx, file_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the file!", err.Error())
+		return 
+	}
+	if file == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The file is not exist!", false)
+		return 
+	}
+	submission, err := h.db.GetSubmissionById(ctx, file.SubmissionId)
+	if err != nil || submission == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the submission!", false)
+		return 
+	}
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id != nil && *student_id != submission.StudentId {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+	if student_id == nil {
+		homework, err := h.db.GetHomeworkById(ctx, submission.HomeworkId)
+		if err != nil || homework == nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the homework!", false)
+			return 
+		}
+		if !h.canManage(ctx, w, r, homework.ClassId, homework.TermId, homework.SubjectId) {
+			return 
+		}
+	}
+
+	//make the signed url and redirect the client into it
+	signed_url, err := h.files.SignedURL(ctx, file.FileKey, h.urlTTL)
+	if err != nil {
+		//logger the data response if the signed url is failed
+		logger.Log.Error("Failed to sign the url of the file", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to make the url of the file!", err.Error())
+		return 
+	}
+	http.Redirect(w, r, signed_url, http.StatusFound)
+
+}
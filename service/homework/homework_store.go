@@ -0,0 +1,339 @@
+package homework
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store homework
+type HomeworkStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewHomeworkStore(db *sqlx.DB) *HomeworkStore {
+	return &HomeworkStore{db: db}
+}
+
+//the homework with the max score of the assessment, the name of the subject and the count of the submissions
+const homeworkSelect = `
+	SELECT hw.id, hw.assessment_id, hw.subject_id, sb.name AS subject_name, hw.class_id, hw.term_id, hw.title,
+	hw.description, hw.due_at, hw.allow_late, a.max_score, hw.created_by, hw.created_at, hw.updated_at,
+	(SELECT COUNT(*) FROM homework_submissions hs WHERE hs.homework_id = hw.id) AS submissions
+	FROM homework hw
+	JOIN assessments a ON a.id = hw.assessment_id
+	JOIN subjects sb ON sb.id = hw.subject_id
+`
+
+//the submission with the name of the student
+const submissionSelect = `
+	SELECT hs.id, hs.homework_id, hs.student_id, s.name AS student_name, hs.body, hs.submitted_at, hs.is_late,
+	hs.score, hs.feedback, hs.graded_by, hs.graded_at, hs.created_at, hs.updated_at
+	FROM homework_submissions hs
+	JOIN students s ON s.id = hs.student_id
+`
+
+//the column of the file that we select in every query
+const fileColumns = `id, submission_id, file_key, file_name, content_type, size, created_at`
+
+//helper to attach the files into the submissions
+func (s *HomeworkStore) attachFiles(ctx context.Context, submissions []types.HomeworkSubmission) error {
+
+	if len(submissions) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(submissions))
+	index := make(map[uuid.UUID]int, len(submissions))
+	for i := range submissions {
+		submissions[i].Files = []types.HomeworkFile{}
+		ids = append(ids, submissions[i].Id.String())
+		index[submissions[i].Id] = i
+	}
+
+	//execute the query
+	files := []types.HomeworkFile{}
+	if err := s.db.SelectContext(ctx, &files, `
+		SELECT `+fileColumns+` FROM homework_files WHERE submission_id = ANY($1::uuid[]) ORDER BY created_at, file_name;
+	`, pq.StringArray(ids)); err != nil {
+		return fmt.Errorf("failed to get the files of the submissions: %w", err)
+	}
+	for _, file := range files {
+		i := index[file.SubmissionId]
+		submissions[i].Files = append(submissions[i].Files, file)
+	}
+
+	return nil
+
+}
+
+//func to create the homework of the assessment
+func (s *HomeworkStore) CreateHomework(ctx context.Context, homework *types.Homework) error {
+
+	//base query
+	query := `
+		INSERT INTO homework
+		(id, assessment_id, subject_id, class_id, term_id, title, description, due_at, allow_late, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		homework.Id,
+		homework.AssessmentId,
+		homework.SubjectId,
+		homework.ClassId,
+		homework.TermId,
+		homework.Title,
+		homework.Description,
+		homework.DueAt,
+		homework.AllowLate,
+		homework.CreatedBy,
+		homework.Created_at,
+		homework.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new homework! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the homework by id, nil when the homework is not exist
+func (s *HomeworkStore) GetHomeworkById(ctx context.Context, id uuid.UUID) (*types.Homework, error) {
+
+	//execute the query
+	var homework types.Homework
+	if err := s.db.GetContext(ctx, &homework, homeworkSelect+` WHERE hw.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the homework: %w", err)
+	}
+
+	return &homework, nil
+
+}
+
+//func to get the homework of the class in the term ordered by the due date, the submission of the student
+//is attached when the student id is not nil
+func (s *HomeworkStore) GetHomework(ctx context.Context, classId uuid.UUID, termId uuid.UUID, subjectId *uuid.UUID, studentId *uuid.UUID) ([]types.Homework, error) {
+
+	//base query
+	query := homeworkSelect + `
+		WHERE hw.class_id = $1 AND hw.term_id = $2 AND ($3::uuid IS NULL OR hw.subject_id = $3)
+		ORDER BY hw.due_at, hw.title;
+	`
+
+	//execute the query
+	homework := []types.Homework{}
+	if err := s.db.SelectContext(ctx, &homework, query, classId, termId, subjectId); err != nil {
+		return nil, fmt.Errorf("failed to get the homework: %w", err)
+	}
+	if studentId == nil || len(homework) == 0 {
+		return homework, nil
+	}
+
+	//the submissions of the student
+	ids := make([]string, 0, len(homework))
+	for _, item := range homework {
+		ids = append(ids, item.Id.String())
+	}
+	submissions := []types.HomeworkSubmission{}
+	if err := s.db.SelectContext(ctx, &submissions, submissionSelect+`
+		WHERE hs.student_id = $1 AND hs.homework_id = ANY($2::uuid[]);
+	`, *studentId, pq.StringArray(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get the submissions of the student: %w", err)
+	}
+	if err := s.attachFiles(ctx, submissions); err != nil {
+		return nil, err
+	}
+	byHomework := make(map[uuid.UUID]*types.HomeworkSubmission, len(submissions))
+	for i := range submissions {
+		byHomework[submissions[i].HomeworkId] = &submissions[i]
+	}
+	for i := range homework {
+		homework[i].Submission = byHomework[homework[i].Id]
+	}
+
+	return homework, nil
+
+}
+
+//func to check if the student is a member of the class in the term, the membership of the term or the
+//active student of the class
+func (s *HomeworkStore) IsStudentOfClass(ctx context.Context, studentId uuid.UUID, classId uuid.UUID, termId uuid.UUID) (bool, error) {
+
+	//execute the query
+	var member bool
+	if err := s.db.GetContext(ctx, &member, `
+		SELECT EXISTS (
+			SELECT 1 FROM class_enrollments WHERE student_id = $1 AND class_id = $2 AND term_id = $3
+		) OR EXISTS (
+			SELECT 1 FROM students WHERE id = $1 AND class_id = $2 AND status = 'active'
+		);
+	`, studentId, classId, termId); err != nil {
+		return false, fmt.Errorf("failed to check the class of the student: %w", err)
+	}
+
+	return member, nil
+
+}
+
+//func to save the submission of the student with the files, the submission that is sent again replaces
+//the text and the files until it is graded
+func (s *HomeworkStore) SaveSubmission(ctx context.Context, submission *types.HomeworkSubmission, files []types.HomeworkFile) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//save the submission, the graded submission is not changed
+	now := time.Now().UTC()
+	if err := tx.GetContext(ctx, submission, `
+		INSERT INTO homework_submissions (homework_id, student_id, body, submitted_at, is_late, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (homework_id, student_id) DO UPDATE
+		SET body = EXCLUDED.body, submitted_at = EXCLUDED.submitted_at, is_late = EXCLUDED.is_late, updated_at = EXCLUDED.updated_at
+		WHERE homework_submissions.score IS NULL
+		RETURNING id, homework_id, student_id, body, submitted_at, is_late, score, feedback, graded_by, graded_at, created_at, updated_at;
+	`, submission.HomeworkId, submission.StudentId, submission.Body, submission.SubmittedAt, submission.IsLate, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAlreadyGraded
+		}
+		return errors.New("Failed to save the submission! " + err.Error())
+	}
+
+	//replace the files
+	if _, err := tx.ExecContext(ctx, `DELETE FROM homework_files WHERE submission_id = $1;`, submission.Id); err != nil {
+		return errors.New("Failed to delete the old files! " + err.Error())
+	}
+	submission.Files = []types.HomeworkFile{}
+	for _, file := range files {
+		file.SubmissionId = submission.Id
+		file.Created_at = now
+		if err := tx.GetContext(ctx, &file.Id, `
+			INSERT INTO homework_files (submission_id, file_key, file_name, content_type, size, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id;
+		`, file.SubmissionId, file.FileKey, file.FileName, file.ContentType, file.Size, file.Created_at); err != nil {
+			return errors.New("Failed to save the file of the submission! " + err.Error())
+		}
+		submission.Files = append(submission.Files, file)
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the submission by id with the files, nil when the submission is not exist
+func (s *HomeworkStore) GetSubmissionById(ctx context.Context, id uuid.UUID) (*types.HomeworkSubmission, error) {
+
+	//execute the query
+	submissions := []types.HomeworkSubmission{}
+	if err := s.db.SelectContext(ctx, &submissions, submissionSelect+` WHERE hs.id = $1;`, id); err != nil {
+		return nil, fmt.Errorf("failed to get the submission: %w", err)
+	}
+	if len(submissions) == 0 {
+		return nil, nil
+	}
+	if err := s.attachFiles(ctx, submissions); err != nil {
+		return nil, err
+	}
+
+	return &submissions[0], nil
+
+}
+
+//func to get the submission of the student for the homework, nil when the student has not submitted yet
+func (s *HomeworkStore) GetSubmission(ctx context.Context, homeworkId uuid.UUID, studentId uuid.UUID) (*types.HomeworkSubmission, error) {
+
+	//execute the query
+	submissions := []types.HomeworkSubmission{}
+	if err := s.db.SelectContext(ctx, &submissions, submissionSelect+`
+		WHERE hs.homework_id = $1 AND hs.student_id = $2;
+	`, homeworkId, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the submission: %w", err)
+	}
+	if len(submissions) == 0 {
+		return nil, nil
+	}
+	if err := s.attachFiles(ctx, submissions); err != nil {
+		return nil, err
+	}
+
+	return &submissions[0], nil
+
+}
+
+//func to get every submission of the homework with the files
+func (s *HomeworkStore) GetSubmissions(ctx context.Context, homeworkId uuid.UUID) ([]types.HomeworkSubmission, error) {
+
+	//execute the query
+	submissions := []types.HomeworkSubmission{}
+	if err := s.db.SelectContext(ctx, &submissions, submissionSelect+`
+		WHERE hs.homework_id = $1 ORDER BY s.name;
+	`, homeworkId); err != nil {
+		return nil, fmt.Errorf("failed to get the submissions: %w", err)
+	}
+	if err := s.attachFiles(ctx, submissions); err != nil {
+		return nil, err
+	}
+
+	return submissions, nil
+
+}
+
+//func to save the score and the feedback of the submission
+func (s *HomeworkStore) GradeSubmission(ctx context.Context, id uuid.UUID, score float64, feedback string, gradedBy *uuid.UUID) error {
+
+	//execute the query
+	now := time.Now().UTC()
+	rows, err := s.db.ExecContext(ctx, `
+		UPDATE homework_submissions SET score = $1, feedback = $2, graded_by = $3, graded_at = $4, updated_at = $4
+		WHERE id = $5;
+	`, score, feedback, gradedBy, now, id)
+	if err != nil {
+		return errors.New("Failed to grade the submission! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The submission is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to get the file of the submission by id, nil when the file is not exist
+func (s *HomeworkStore) GetFileById(ctx context.Context, id uuid.UUID) (*types.HomeworkFile, error) {
+
+	//execute the query
+	var file types.HomeworkFile
+	if err := s.db.GetContext(ctx, &file, `SELECT `+fileColumns+` FROM homework_files WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the file: %w", err)
+	}
+
+	return &file, nil
+
+}
@@ -0,0 +1,70 @@
+package homework
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// the limits of one submission
+const (
+	MaxFiles 		= 5
+	MaxUploadSize 	= 10 << 20
+)
+
+// ErrSubmissionClosed is returned when the homework is past the due date and the late submission is not allowed
+var ErrSubmissionClosed = errors.New("The homework is past the due date and the late submission is not allowed!")
+
+// ErrAlreadyGraded is returned when the graded submission is sent again
+var ErrAlreadyGraded = errors.New("The submission is already graded!")
+
+// the detected type of the file with the extension of the stored file
+var fileTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/png": ".png",
+	"image/jpeg": ".jpg",
+	"text/plain; charset=utf-8": ".txt",
+	"application/zip": ".zip",
+}
+
+// the office documents are detected as a zip file, so the extension of the name is kept
+var zipExts = map[string]bool{".zip": true, ".docx": true, ".xlsx": true, ".pptx": true}
+
+// CheckSubmission reports if the submission at the time is late, the late submission is rejected when the
+// homework does not allow it
+func CheckSubmission(at time.Time, dueAt time.Time, allowLate bool) (bool, error) {
+	if !at.After(dueAt) {
+		return false, nil
+	}
+	if !allowLate {
+		return true, ErrSubmissionClosed
+	}
+	return true, nil
+}
+
+// FileExt returns the extension of the stored file from the detected content type, false when the type is not allowed
+func FileExt(contentType string, name string) (string, bool) {
+	ext, ok := fileTypes[contentType]
+	if !ok {
+		return "", false
+	}
+	if ext == ".zip" {
+		if own := strings.ToLower(filepath.Ext(name)); zipExts[own] {
+			return own, true
+		}
+	}
+	return ext, true
+}
+
+// CleanFileName keeps only the base name of the uploaded file for the download
+func CleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
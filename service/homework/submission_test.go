@@ -0,0 +1,54 @@
+package homework
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckSubmission(t *testing.T) {
+	due := time.Date(2026, 3, 10, 23, 59, 0, 0, time.UTC)
+
+	late, err := CheckSubmission(due, due, false)
+	if late || err != nil {
+		t.Fatalf("the submission on the due date should not be late, got %v %v", late, err)
+	}
+	late, err = CheckSubmission(due.Add(time.Minute), due, true)
+	if !late || err != nil {
+		t.Fatalf("the submission after the due date should be late, got %v %v", late, err)
+	}
+	if _, err = CheckSubmission(due.Add(time.Minute), due, false); !errors.Is(err, ErrSubmissionClosed) {
+		t.Fatalf("the late submission should be rejected, got %v", err)
+	}
+}
+
+func TestFileExt(t *testing.T) {
+	cases := []struct {
+		contentType, name, want string
+		ok                      bool
+	}{
+		{"application/pdf", "tugas.PDF", ".pdf", true},
+		{"application/zip", "Tugas Bab 2.DOCX", ".docx", true},
+		{"application/zip", "tugas.jar", ".zip", true},
+		{"text/plain; charset=utf-8", "jawaban.txt", ".txt", true},
+		{"application/x-msdownload", "virus.exe", "", false},
+	}
+	for _, c := range cases {
+		ext, ok := FileExt(c.contentType, c.name)
+		if ext != c.want || ok != c.ok {
+			t.Fatalf("FileExt(%q, %q) = %q %v, want %q %v", c.contentType, c.name, ext, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestCleanFileName(t *testing.T) {
+	if got := CleanFileName(`C:\Users\siswa\tugas.pdf`); got != "tugas.pdf" {
+		t.Fatalf("expected tugas.pdf, got %q", got)
+	}
+	if got := CleanFileName("../../etc/passwd"); got != "passwd" {
+		t.Fatalf("expected passwd, got %q", got)
+	}
+	if got := CleanFileName(""); got != "file" {
+		t.Fatalf("expected file, got %q", got)
+	}
+}
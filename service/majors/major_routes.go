@@ -0,0 +1,555 @@
+package majors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the major store for a database logic
+type HandleRequest struct {
+	db types.MajorStore
+}
+
+//func that declare the handler for major
+func NewHandlerMajor(db types.MajorStore) *HandleRequest {
+	return &HandleRequest{db: db}
+}
+
+//helper to make the response of the major
+func majorResponse(major types.Major) types.MajorResponse {
+	return types.MajorResponse{
+		Id: major.Id,
+		Code: major.Code,
+		Name: major.Name,
+		Description: major.Description,
+		IsActive: major.IsActive,
+		Created_at: major.Created_at.Format("2006-01-02"),
+		Updated_at: major.Updated_at.Format("2006-01-02"),
+	}
+}
+
+//helper for the academic year query params, the default is the current year
+func academicYearParam(r *http.Request) string {
+	if year := r.URL.Query().Get("academic_year"); year != "" {
+		return year
+	}
+	return utils.AcademicYearOf(time.Now().UTC())
+}
+
+//func to create a new major
+func (h *HandleRequest) CreateMajor_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the majors
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateMajor
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//the code is saved in upper case
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	code := strings.ToUpper(payload.Code)
+	exist, err := h.db.GetMajorByCode(ctx, code)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the major!", err.Error())
+		return 
+	}
+	if exist != nil {
+		utils.ResponseError(w, http.StatusConflict, "The code of the major has been already exist!", false)
+		return 
+	}
+
+	//make the struct of the major and execute the query
+	major := &types.Major{
+		Id: uuid.New(),
+		Code: code,
+		Name: payload.Name,
+		Description: payload.Description,
+		IsActive: true,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	if err := h.db.CreateMajor(ctx, major); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new major", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the major!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new major has been successfully", majorResponse(*major))
+
+}
+
+//func to get all the majors (?active=true)
+func (h *HandleRequest) GetAllMajors_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	majors, err := h.db.GetAllMajors(ctx, r.URL.Query().Get("active") == "true")
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get all the majors", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the majors!", err.Error())
+		return 
+	}
+
+	//make the response
+	response := make([]types.MajorResponse, 0, len(majors))
+	for _, major := range majors {
+		response = append(response, majorResponse(major))
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get all majors has been successfully", response)
+
+}
+
+//func to get the major with the capacity of the year (?academic_year=2025/2026)
+func (h *HandleRequest) GetMajor_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	major_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	major, err := h.db.GetMajorById(ctx, major_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the major!", err.Error())
+		return 
+	}
+	if major == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The major is not exist!", false)
+		return 
+	}
+	capacity, err := h.db.GetCapacity(ctx, major_id, academicYearParam(r))
+	if err != nil {
+		//logger if the capacity is failed
+		logger.Log.Error("Failed to get the capacity", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the capacity!", err.Error())
+		return 
+	}
+
+	//return a final result
+	response := map[string]interface{}{
+		"major": majorResponse(*major),
+		"capacity": capacity,
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "Get the major has been successfully", response)
+
+}
+
+//func to update the major (name, description, active flag)
+func (h *HandleRequest) UpdateMajor_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the majors
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	major_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.UpdateMajor
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.UpdateMajor(ctx, major_id, payload); err != nil {
+		//logger if the update is failed
+		logger.Log.Error("Failed to update the major", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the major!", err.Error())
+		return 
+	}
+	major, err := h.db.GetMajorById(ctx, major_id)
+	if err != nil || major == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the major!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Update the major has been successfully", majorResponse(*major))
+
+}
+
+//func to set the seat capacity of the major for one academic year
+func (h *HandleRequest) SetCapacity_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the majors
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	major_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SetCapacity
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", err.Error())
+		return 
+	}
+
+	//validate the major
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	major, err := h.db.GetMajorById(ctx, major_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the major!", err.Error())
+		return 
+	}
+	if major == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The major is not exist!", false)
+		return 
+	}
+
+	//execute the query, the waitlist is promoted into the new seats
+	promoted, err := h.db.SetCapacity(ctx, major_id, payload.AcademicYear, *payload.Seats)
+	if err != nil {
+		//logger if the capacity is failed
+		logger.Log.Error("Failed to set the capacity", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to set the capacity!", err.Error())
+		return 
+	}
+	capacity, err := h.db.GetCapacity(ctx, major_id, payload.AcademicYear)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the capacity!", err.Error())
+		return 
+	}
+
+	//return a final result
+	response := map[string]interface{}{
+		"capacity": capacity,
+		"promoted": promoted,
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "Set the capacity has been successfully", response)
+
+}
+
+//func to see the waitlist of the major (?academic_year=2025/2026)
+func (h *HandleRequest) Waitlist_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the waitlist has the data of the other students
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	major_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	entries, err := h.db.GetWaitlist(ctx, major_id, r.URL.Query().Get("academic_year"))
+	if err != nil {
+		//logger if the waitlist is failed
+		logger.Log.Error("Failed to get the waitlist", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the waitlist!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the waitlist has been successfully", entries)
+
+}
+
+//func to promote the waitlist by hand, for example after a student leave the major
+func (h *HandleRequest) PromoteWaitlist_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the majors
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	major_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	promoted, err := h.db.PromoteWaitlist(ctx, major_id, academicYearParam(r))
+	if err != nil {
+		//logger if the promotion is failed
+		logger.Log.Error("Failed to promote the waitlist", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to promote the waitlist!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Promote the waitlist has been successfully", promoted)
+
+}
+
+//func to cancel a waiting registration, siswa can only cancel their own registration
+func (h *HandleRequest) CancelWaitlist_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the role and the id of the user
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+	var requested_by *uuid.UUID
+	if role != "guru" && role != "admin" {
+		requested_by = &user_id
+	}
+
+	//declare the id of the parameters
+	entry_id, err := uuid.Parse(mux.Vars(r)["entry_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CancelWaitlist(ctx, entry_id, requested_by); err != nil {
+		//logger if the cancel is failed
+		logger.Log.Error("Failed to cancel the waitlist", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		if errors.Is(err, ErrWaitlistPromoted) {
+			utils.ResponseError(w, http.StatusConflict, "Failed to cancel the waitlist!", err.Error())
+			return 
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to cancel the waitlist!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Cancel the waitlist has been successfully", entry_id)
+
+}
@@ -0,0 +1,573 @@
+package majors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store major
+type MajorStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewMajorStore(db *sqlx.DB) *MajorStore {
+	return &MajorStore{db: db}
+}
+
+//the column of the major that we select in every query
+const majorColumns = `id, code, name, description, is_active, created_at, updated_at`
+
+//the column of the waitlist, the position is the place in the queue of the waiting entries
+const waitlistColumns = `
+	w.id, w.major_id, w.academic_year, w.requested_by, w.name, w.class_id, w.address,
+	w.student_profile, w.status, w.student_id, w.created_at, w.updated_at,
+	CASE WHEN w.status = 'waiting' THEN (
+		SELECT COUNT(*) FROM major_waitlist q
+		WHERE q.major_id = w.major_id AND q.academic_year = w.academic_year
+		AND q.status = 'waiting' AND q.created_at <= w.created_at
+	) ELSE 0 END AS position
+`
+
+//func to create a new major
+func (s *MajorStore) CreateMajor(ctx context.Context, major *types.Major) error {
+
+	//base query
+	query := `
+		INSERT INTO majors (id, code, name, description, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		major.Id,
+		major.Code,
+		major.Name,
+		major.Description,
+		major.IsActive,
+		major.Created_at,
+		major.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new major! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the major by id
+func (s *MajorStore) GetMajorById(ctx context.Context, id uuid.UUID) (*types.Major, error) {
+
+	//execute the query
+	var major types.Major
+	if err := s.db.GetContext(ctx, &major, `SELECT `+majorColumns+` FROM majors WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get major by id: %w", err)
+	}
+
+	return &major, nil
+
+}
+
+//func to get the major by the code, the code is not case sensitive
+func (s *MajorStore) GetMajorByCode(ctx context.Context, code string) (*types.Major, error) {
+
+	//execute the query
+	var major types.Major
+	if err := s.db.GetContext(ctx, &major, `SELECT `+majorColumns+` FROM majors WHERE code = $1;`, strings.ToUpper(strings.TrimSpace(code))); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get major by code: %w", err)
+	}
+
+	return &major, nil
+
+}
+
+//func to get all of the majors
+func (s *MajorStore) GetAllMajors(ctx context.Context, onlyActive bool) ([]types.Major, error) {
+
+	//base query
+	query := `SELECT ` + majorColumns + ` FROM majors WHERE ($1 = FALSE OR is_active) ORDER BY code;`
+
+	//execute the query
+	majors := []types.Major{}
+	if err := s.db.SelectContext(ctx, &majors, query, onlyActive); err != nil {
+		return nil, fmt.Errorf("failed to get the majors: %w", err)
+	}
+
+	return majors, nil
+
+}
+
+//func to update the major
+func (s *MajorStore) UpdateMajor(ctx context.Context, id uuid.UUID, payload types.UpdateMajor) error {
+
+	//setup the args and args id
+	var settings []string
+	argsId := 1
+	var args []interface{}
+
+	//if the name is changed
+	if payload.Name != nil {
+		settings = append(settings, fmt.Sprintf("name=$%d", argsId))
+		args = append(args, *payload.Name)
+		argsId++
+	}
+
+	//if the description is changed
+	if payload.Description != nil {
+		settings = append(settings, fmt.Sprintf("description=$%d", argsId))
+		args = append(args, *payload.Description)
+		argsId++
+	}
+
+	//if the major is activated or deactivated
+	if payload.IsActive != nil {
+		settings = append(settings, fmt.Sprintf("is_active=$%d", argsId))
+		args = append(args, *payload.IsActive)
+		argsId++
+	}
+
+	//validate if the no one field changes
+	if len(args) == 0 {
+		return errors.New("No one data changes")
+	}
+
+	//update the updated at
+	settings = append(settings, fmt.Sprintf("updated_at=$%d", argsId))
+	args = append(args, time.Now().UTC())
+	argsId++
+
+	//define a fullquery and execute it
+	fullquery := fmt.Sprintf("UPDATE majors SET %s WHERE id = $%d", strings.Join(settings, ", "), argsId)
+	args = append(args, id)
+	rows, err := s.db.ExecContext(ctx, fullquery, args...)
+	if err != nil {
+		return errors.New("Failed to update the major! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The major is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to set the seats of the major in one year, the waitlist is promoted if there are new free seats
+func (s *MajorStore) SetCapacity(ctx context.Context, majorId uuid.UUID, academicYear string, seats int) ([]types.WaitlistEntry, error) {
+
+	//setup the transaction, the lock of the capacity row keeps the seats in order
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//upsert the capacity
+	query := `
+		INSERT INTO major_capacities (major_id, academic_year, seats, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (major_id, academic_year) DO UPDATE SET seats = EXCLUDED.seats, updated_at = EXCLUDED.updated_at;
+	`
+	if _, err := tx.ExecContext(ctx, query, majorId, academicYear, seats, time.Now().UTC()); err != nil {
+		return nil, errors.New("Failed to set the capacity! " + err.Error())
+	}
+
+	//promote the waitlist into the free seats
+	promoted, err := promoteWaitlist(ctx, tx, majorId, academicYear)
+	if err != nil {
+		return nil, err
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return promoted, nil
+
+}
+
+//func to get the seats, the enrolled students and the waiting list count of the major
+func (s *MajorStore) GetCapacity(ctx context.Context, majorId uuid.UUID, academicYear string) (*types.MajorCapacity, error) {
+
+	//base query
+	query := `
+		SELECT
+			$1::UUID AS major_id,
+			$2::VARCHAR AS academic_year,
+			(SELECT seats FROM major_capacities WHERE major_id = $1 AND academic_year = $2) AS seats,
+			(SELECT COUNT(*) FROM students WHERE major_id = $1 AND academic_year = $2 AND status = 'active') AS enrolled,
+			(SELECT COUNT(*) FROM major_waitlist WHERE major_id = $1 AND academic_year = $2 AND status = 'waiting') AS waiting;
+	`
+
+	//execute the query
+	var capacity types.MajorCapacity
+	if err := s.db.GetContext(ctx, &capacity, query, majorId, academicYear); err != nil {
+		return nil, fmt.Errorf("failed to get the capacity: %w", err)
+	}
+
+	return &capacity, nil
+
+}
+
+//func to create the student if there is a free seat in the major, or to put the registration into the waitlist
+func (s *MajorStore) EnrollStudent(ctx context.Context, student *types.Student, requestedBy uuid.UUID) (*types.WaitlistEntry, error) {
+
+	if student.MajorId == nil || student.AcademicYear == nil || student.ClassId == nil {
+		return nil, errors.New("The major, class and academic year of the student is required!")
+	}
+
+	//setup the transaction, the lock of the capacity row keeps the seats in order
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//check the free seat, the row of the capacity is locked until the commit
+	free, err := freeSeats(ctx, tx, *student.MajorId, *student.AcademicYear)
+	if err != nil {
+		return nil, err
+	}
+
+	//the major is full, the registration goes into the waitlist
+	if free == 0 {
+		entry := &types.WaitlistEntry{
+			Id: uuid.New(),
+			MajorId: *student.MajorId,
+			AcademicYear: *student.AcademicYear,
+			RequestedBy: requestedBy,
+			Name: student.Name,
+			ClassId: *student.ClassId,
+			Address: student.Address,
+			StudentProfile: student.StudentProfile,
+			Status: "waiting",
+			Created_at: time.Now().UTC(),
+			Updated_at: time.Now().UTC(),
+		}
+		query := `
+			INSERT INTO major_waitlist 
+			(id, major_id, academic_year, requested_by, name, class_id, address, student_profile, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+		`
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			entry.Id,
+			entry.MajorId,
+			entry.AcademicYear,
+			entry.RequestedBy,
+			entry.Name,
+			entry.ClassId,
+			entry.Address,
+			entry.StudentProfile,
+			entry.Status,
+			entry.Created_at,
+			entry.Updated_at,
+		); err != nil {
+			return nil, errors.New("Failed to put the student into the waitlist! " + err.Error())
+		}
+		if err := tx.GetContext(ctx, entry, `SELECT `+waitlistColumns+` FROM major_waitlist w WHERE w.id = $1;`, entry.Id); err != nil {
+			return nil, errors.New("Failed to get the waitlist! " + err.Error())
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+		}
+		return entry, nil
+	}
+
+	//there is a free seat, create the student
+	if err := insertStudent(ctx, tx, student); err != nil {
+		return nil, err
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil, nil
+
+}
+
+//func to get the waitlist of the major in one year
+func (s *MajorStore) GetWaitlist(ctx context.Context, majorId uuid.UUID, academicYear string) ([]types.WaitlistEntry, error) {
+
+	//base query
+	query := `
+		SELECT ` + waitlistColumns + ` FROM major_waitlist w
+		WHERE w.major_id = $1 AND ($2 = '' OR w.academic_year = $2)
+		ORDER BY w.status DESC, w.created_at;
+	`
+
+	//execute the query
+	entries := []types.WaitlistEntry{}
+	if err := s.db.SelectContext(ctx, &entries, query, majorId, academicYear); err != nil {
+		return nil, fmt.Errorf("failed to get the waitlist: %w", err)
+	}
+
+	return entries, nil
+
+}
+
+// ErrWaitlistPromoted is returned when the registration is already promoted, the student has the seat and
+// only leaves the major with the student record
+var ErrWaitlistPromoted = errors.New("The registration has been already promoted into a student!")
+
+//func to cancel a waiting registration, when requestedBy is not nil only the owner can cancel it
+func (s *MajorStore) CancelWaitlist(ctx context.Context, id uuid.UUID, requestedBy *uuid.UUID) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.GetContext(ctx, &status, `
+		SELECT status FROM major_waitlist
+		WHERE id = $1 AND status IN ('waiting', 'promoted') AND ($2::UUID IS NULL OR requested_by = $2)
+		FOR UPDATE;
+	`, id, requestedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("The waiting registration is not exist!")
+	}
+	if err != nil {
+		return errors.New("Failed to get the waitlist! " + err.Error())
+	}
+	if status == "promoted" {
+		return ErrWaitlistPromoted
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE major_waitlist SET status = 'cancelled', updated_at = $1 WHERE id = $2;
+	`, time.Now().UTC(), id); err != nil {
+		return errors.New("Failed to cancel the waitlist! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to move the waiting registrations into the free seats of the major
+func (s *MajorStore) PromoteWaitlist(ctx context.Context, majorId uuid.UUID, academicYear string) ([]types.WaitlistEntry, error) {
+
+	//setup the transaction, the lock of the capacity row keeps the seats in order
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	promoted, err := promoteWaitlist(ctx, tx, majorId, academicYear)
+	if err != nil {
+		return nil, err
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return promoted, nil
+
+}
+
+//ReleaseSeats promotes the waiting registrations of the major in the year inside the transaction of the caller,
+//it is called wherever the students leave the seats of the major
+func ReleaseSeats(ctx context.Context, tx *sqlx.Tx, majorId uuid.UUID, academicYear string) ([]types.WaitlistEntry, error) {
+	return promoteWaitlist(ctx, tx, majorId, academicYear)
+}
+
+//CheckSeats fails when the active students of the major in the year are more than the seats, it is called
+//inside the transaction of the caller wherever the students come back into the seats without the enrollment
+func CheckSeats(ctx context.Context, tx *sqlx.Tx, majorId uuid.UUID, academicYear string) error {
+
+	var over bool
+	if err := tx.GetContext(ctx, &over, `
+		SELECT COALESCE((SELECT seats FROM major_capacities WHERE major_id = $1 AND academic_year = $2 FOR UPDATE) <
+		(SELECT COUNT(*) FROM students WHERE major_id = $1 AND academic_year = $2 AND status = 'active'), FALSE);
+	`, majorId, academicYear); err != nil {
+		return errors.New("Failed to check the seats of the major! " + err.Error())
+	}
+	if over {
+		return fmt.Errorf("The seats of the major in %s are already taken!", academicYear)
+	}
+
+	return nil
+
+}
+
+//helper to count the free seats of the active students, -1 means the major has no limit in that year.
+//The capacity row is locked before the count, so the concurrent enrollments of the major wait for each other
+//and every count in read committed sees the students of the transaction before
+func freeSeats(ctx context.Context, tx *sqlx.Tx, majorId uuid.UUID, academicYear string) (int, error) {
+
+	var seats int
+	err := tx.GetContext(ctx, &seats, `
+		SELECT seats FROM major_capacities WHERE major_id = $1 AND academic_year = $2 FOR UPDATE;
+	`, majorId, academicYear)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, errors.New("Failed to get the capacity! " + err.Error())
+	}
+
+	var enrolled int
+	if err := tx.GetContext(ctx, &enrolled, `
+		SELECT COUNT(*) FROM students WHERE major_id = $1 AND academic_year = $2 AND status = 'active';
+	`, majorId, academicYear); err != nil {
+		return 0, errors.New("Failed to count the students! " + err.Error())
+	}
+
+	return max(seats-enrolled, 0), nil
+
+}
+
+//helper that promote the oldest waiting registrations while there is a free seat
+func promoteWaitlist(ctx context.Context, tx *sqlx.Tx, majorId uuid.UUID, academicYear string) ([]types.WaitlistEntry, error) {
+
+	free, err := freeSeats(ctx, tx, majorId, academicYear)
+	if err != nil {
+		return nil, err
+	}
+
+	//no limit means every waiting registration can be promoted
+	limit := free
+	if free < 0 {
+		limit = 1 << 30
+	}
+
+	var waiting []types.WaitlistEntry
+	if err := tx.SelectContext(ctx, &waiting, `
+		SELECT w.id, w.major_id, w.academic_year, w.requested_by, w.name, w.class_id, w.address,
+		w.student_profile, w.status, w.student_id, w.created_at, w.updated_at, 0 AS position
+		FROM major_waitlist w
+		WHERE w.major_id = $1 AND w.academic_year = $2 AND w.status = 'waiting'
+		ORDER BY w.created_at LIMIT $3 FOR UPDATE;
+	`, majorId, academicYear, limit); err != nil {
+		return nil, errors.New("Failed to get the waitlist! " + err.Error())
+	}
+
+	promoted := []types.WaitlistEntry{}
+	for _, entry := range waiting {
+
+		//the student takes the major code and the class name at the time of the promotion
+		var names struct {
+			Class string `db:"class"`
+			Major string `db:"major"`
+		}
+		if err := tx.GetContext(ctx, &names, `
+			SELECT c.name AS class, m.code AS major FROM classes c, majors m WHERE c.id = $1 AND m.id = $2;
+		`, entry.ClassId, entry.MajorId); err != nil {
+			return nil, errors.New("Failed to get the class of the waitlist! " + err.Error())
+		}
+
+		//the requester becomes the account of the student, when the account has no student yet
+		var taken bool
+		if err := tx.GetContext(ctx, &taken, `SELECT EXISTS (SELECT 1 FROM students WHERE user_id = $1);`, entry.RequestedBy); err != nil {
+			return nil, errors.New("Failed to check the account of the waitlist! " + err.Error())
+		}
+
+		student_id := uuid.New()
+		student := &types.Student{
+			Id: student_id,
+			Name: entry.Name,
+			Class: names.Class,
+			ClassId: &entry.ClassId,
+			Address: entry.Address,
+			Major: names.Major,
+			MajorId: &entry.MajorId,
+			AcademicYear: &entry.AcademicYear,
+			StudentProfile: entry.StudentProfile,
+			Created_at: time.Now().UTC(),
+			Updated_at: time.Now().UTC(),
+		}
+		if !taken {
+			student.UserId = &entry.RequestedBy
+		}
+		if err := insertStudent(ctx, tx, student); err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE major_waitlist SET status = 'promoted', student_id = $1, updated_at = $2 WHERE id = $3;
+		`, student_id, time.Now().UTC(), entry.Id); err != nil {
+			return nil, errors.New("Failed to update the waitlist! " + err.Error())
+		}
+
+		entry.Status = "promoted"
+		entry.StudentId = &student_id
+		promoted = append(promoted, entry)
+	}
+
+	return promoted, nil
+
+}
+
+//helper to insert the student inside the transaction of the enrollment
+func insertStudent(ctx context.Context, tx *sqlx.Tx, student *types.Student) error {
+
+	query := `
+		INSERT INTO students 
+		(id, name, class, class_id, address, major, major_id, academic_year, user_id, student_profile, created_at, updated_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+	`
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		student.Id,
+		student.Name,
+		student.Class,
+		student.ClassId,
+		student.Address,
+		student.Major,
+		student.MajorId,
+		student.AcademicYear,
+		student.UserId,
+		student.StudentProfile,
+		student.Created_at,
+		student.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new student! " + err.Error())
+	}
+
+	//when the term of the class year is already open, the student is a member of the class for that term
+	if student.ClassId != nil {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO class_enrollments (id, student_id, class_id, term_id, created_at, updated_at)
+			SELECT gen_random_uuid(), $1, $2, t.id, $3, $3
+			FROM terms t JOIN academic_years y ON y.id = t.academic_year_id
+			WHERE t.status = 'open' AND y.name = $4
+			ON CONFLICT (student_id, term_id) DO NOTHING;
+		`, student.Id, student.ClassId, time.Now().UTC(), student.AcademicYear); err != nil {
+			return errors.New("Failed to save the class membership! " + err.Error())
+		}
+	}
+
+	return nil
+
+}
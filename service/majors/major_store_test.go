@@ -0,0 +1,120 @@
+package majors
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+)
+
+var waitlistRow = []string{
+	"id", "major_id", "academic_year", "requested_by", "name", "class_id", "address",
+	"student_profile", "status", "student_id", "created_at", "updated_at", "position",
+}
+
+func waitlistEntry(id, majorId uuid.UUID, status string, studentId any, created time.Time) []driver.Value {
+	return []driver.Value{
+		id.String(), majorId.String(), "2025/2026", uuid.NewString(), "Budi", uuid.NewString(), "Jl. Merdeka",
+		"", status, studentId, created, created, int64(0),
+	}
+}
+
+//script the queries of the promotion of the waitlist, the seats are taken by the enrolled students
+func scriptPromotion(fake *dbtest.DB, seats int64, enrolled int64, waiting ...[]driver.Value) {
+	fake.On("SELECT seats FROM major_capacities", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"seats"}, []driver.Value{seats})
+	})
+	fake.On("SELECT COUNT(*) FROM students WHERE major_id", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"count"}, []driver.Value{enrolled})
+	})
+	fake.On("AND w.status = 'waiting'", func(args []any) dbtest.Result {
+		limit := int(args[2].(int))
+		return dbtest.Rows(waitlistRow, waiting[:min(limit, len(waiting))]...)
+	})
+	fake.On("SELECT c.name AS class, m.code AS major", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"class", "major"}, []driver.Value{"X IPA 1", "IPA"})
+	})
+	fake.On("SELECT EXISTS (SELECT 1 FROM students WHERE user_id", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"exists"}, []driver.Value{false})
+	})
+	fake.On("INSERT INTO students", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("INSERT INTO class_enrollments", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("UPDATE major_waitlist SET status = 'promoted'", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+}
+
+func TestCancelPromotedEntryIsRefused(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewMajorStore(db)
+
+	fake.On("SELECT status FROM major_waitlist", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"status"}, []driver.Value{"promoted"})
+	})
+
+	err := store.CancelWaitlist(context.Background(), uuid.New(), nil)
+	if !errors.Is(err, ErrWaitlistPromoted) {
+		t.Fatalf("expected the promoted registration to be refused, got %v", err)
+	}
+
+	//the student keeps the seat, the major and the class
+	statements := fake.Statements()
+	if statements[len(statements)-1].Query != dbtest.Rollback {
+		t.Fatalf("expected the transaction to be rolled back, got %v", statements[len(statements)-1])
+	}
+	if fake.Count("UPDATE major_waitlist") != 0 || fake.Count("UPDATE students") != 0 {
+		t.Fatalf("expected nothing to be changed")
+	}
+}
+
+func TestCancelWaitingEntryKeepsTheSeats(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewMajorStore(db)
+
+	entry := uuid.New()
+	fake.On("SELECT status FROM major_waitlist", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"status"}, []driver.Value{"waiting"})
+	})
+	fake.On("UPDATE major_waitlist SET status = 'cancelled'", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+
+	if err := store.CancelWaitlist(context.Background(), entry, nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if fake.Count("INSERT INTO students") != 0 {
+		t.Fatalf("the waiting registration does not free a seat")
+	}
+}
+
+func TestPromoteWaitlistOrder(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewMajorStore(db)
+
+	major := uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now().UTC()
+	scriptPromotion(fake, 30, 28,
+		waitlistEntry(first, major, "waiting", nil, now.Add(-3*time.Hour)),
+		waitlistEntry(second, major, "waiting", nil, now.Add(-2*time.Hour)),
+		waitlistEntry(third, major, "waiting", nil, now.Add(-time.Hour)),
+	)
+
+	promoted, err := store.PromoteWaitlist(context.Background(), major, "2025/2026")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	//the two free seats go to the oldest registrations
+	if len(promoted) != 2 || promoted[0].Id != first || promoted[1].Id != second {
+		t.Fatalf("expected the two oldest registrations, got %+v", promoted)
+	}
+	queue := fake.Statements()[fake.Index("AND w.status = 'waiting'", 0)]
+	if queue.Args[2] != 2 {
+		t.Fatalf("expected the limit of the free seats, got %v", queue.Args[2])
+	}
+	if fake.Index("ORDER BY w.created_at", 0) < 0 {
+		t.Fatalf("expected the waitlist in the order of the registration")
+	}
+}
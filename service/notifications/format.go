@@ -0,0 +1,72 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// the layout of the time in the message
+const messageTime = "02 Jan 2006 15:04"
+
+// Render formats the title and the body of the event, the services only send the data of the event so
+// every message is written here
+func Render(event types.NotificationEvent) (string, string, error) {
+
+	switch event.Type {
+	case types.NotifyGradesPublished:
+		subject := text(event.Payload, "subject_name", "a subject")
+		return "Grades published",
+			fmt.Sprintf("The grades of %s are published.", subject),
+			nil
+	case types.NotifyHomeworkAssigned:
+		title := text(event.Payload, "title", "New homework")
+		subject := text(event.Payload, "subject_name", "a subject")
+		body := fmt.Sprintf("New homework %q for %s.", title, subject)
+		if dueAt, ok := moment(event.Payload, "due_at"); ok {
+			body = fmt.Sprintf("New homework %q for %s is due %s.", title, subject, dueAt.Format(messageTime))
+		}
+		return "New homework", body, nil
+	case types.NotifyHomeworkDue:
+		title := text(event.Payload, "title", "The homework")
+		student := text(event.Payload, "student_name", "the student")
+		body := fmt.Sprintf("%q is not submitted yet by %s.", title, student)
+		if dueAt, ok := moment(event.Payload, "due_at"); ok {
+			body = fmt.Sprintf("%q is due %s and is not submitted yet by %s.", title, dueAt.Format(messageTime), student)
+		}
+		return "Homework due soon", body, nil
+	case types.NotifyAbsenceRecorded:
+		student := text(event.Payload, "student_name", "The student")
+		status := text(event.Payload, "status", "absent")
+		body := fmt.Sprintf("%s is recorded %s.", student, status)
+		if date, ok := moment(event.Payload, "date"); ok {
+			body = fmt.Sprintf("%s is recorded %s on %s.", student, status, date.Format("02 Jan 2006"))
+		}
+		return "Absence recorded", body, nil
+	}
+
+	return "", "", errors.New("The type of the notification is not valid!")
+
+}
+
+// helper to get the text of the payload
+func text(payload map[string]any, key string, fallback string) string {
+	if value, ok := payload[key].(string); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// helper to get the time of the payload, the time is kept as it is or as the text of rfc3339
+func moment(payload map[string]any, key string) (time.Time, bool) {
+	switch value := payload[key].(type) {
+	case time.Time:
+		return value, !value.IsZero()
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		return parsed, err == nil
+	}
+	return time.Time{}, false
+}
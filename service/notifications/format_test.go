@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestRender(t *testing.T) {
+	dueAt := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	cases := []struct {
+		event types.NotificationEvent
+		title string
+		body  string
+	}{
+		{
+			types.NotificationEvent{Type: types.NotifyGradesPublished, Payload: map[string]any{"subject_name": "Matematika"}},
+			"Grades published", "Matematika",
+		},
+		{
+			types.NotificationEvent{Type: types.NotifyHomeworkAssigned, Payload: map[string]any{"title": "Bab 3", "due_at": dueAt}},
+			"New homework", "02 Mar 2026 07:00",
+		},
+		{
+			types.NotificationEvent{Type: types.NotifyHomeworkDue, Payload: map[string]any{"title": "Bab 3", "student_name": "Budi", "due_at": dueAt.Format(time.RFC3339)}},
+			"Homework due soon", "Budi",
+		},
+		{
+			types.NotificationEvent{Type: types.NotifyAbsenceRecorded, Payload: map[string]any{"student_name": "Budi", "status": "alpa"}},
+			"Absence recorded", "Budi is recorded alpa",
+		},
+	}
+	for i, c := range cases {
+		title, body, err := Render(c.event)
+		if err != nil {
+			t.Fatalf("case %d: unexpected error %v", i, err)
+		}
+		if title != c.title || !strings.Contains(body, c.body) {
+			t.Fatalf("case %d: got %q %q", i, title, body)
+		}
+	}
+	if _, _, err := Render(types.NotificationEvent{Type: "unknown"}); err == nil {
+		t.Fatalf("the unknown type should fail")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	user := uuid.New()
+	other := uuid.New()
+	event := types.NotificationEvent{
+		Type: types.NotifyAbsenceRecorded,
+		Payload: map[string]any{"student_name": "Budi", "status": "sakit"},
+		DedupeKey: "absence:1:sakit",
+	}
+
+	notifications, err := Build(event, []uuid.UUID{user, other, user})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifications))
+	}
+	for _, notification := range notifications {
+		if notification.DedupeKey == nil || *notification.DedupeKey != "absence:1:sakit" {
+			t.Fatalf("expected the dedupe key of the event")
+		}
+		if notification.Created_at.IsZero() {
+			t.Fatalf("expected the created time")
+		}
+		payload := map[string]any{}
+		if err := json.Unmarshal(notification.Payload, &payload); err != nil || payload["status"] != "sakit" {
+			t.Fatalf("expected the payload of the event, got %s", notification.Payload)
+		}
+	}
+
+	empty, err := Build(types.NotificationEvent{Type: types.NotifyGradesPublished}, nil)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected no notification, got %d %v", len(empty), err)
+	}
+}
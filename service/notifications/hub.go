@@ -0,0 +1,127 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// the window of the reminder before the homework is due
+const DueWindow = 24 * time.Hour
+
+// Hub is the notifier of the services, it formats the event and saves one notification for every user
+// of the audience
+type Hub struct {
+	store types.NotificationStore
+}
+
+func NewHub(store types.NotificationStore) *Hub {
+	return &Hub{store: store}
+}
+
+// Notify saves the event for the users of its audience, the audience without any user is not an error
+func (h *Hub) Notify(ctx context.Context, event types.NotificationEvent) error {
+
+	recipients, err := h.store.GetRecipients(ctx, event.Audience)
+	if err != nil {
+		return err
+	}
+	notifications, err := Build(event, recipients)
+	if err != nil {
+		return err
+	}
+	if _, err := h.store.CreateNotifications(ctx, notifications); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+// SyncDueReminders saves the reminder of the homework that is due in the window and is not submitted yet
+// by the student of the user, the reminder is saved once for every homework and student
+func (h *Hub) SyncDueReminders(ctx context.Context, userId uuid.UUID, now time.Time) error {
+
+	homework, err := h.store.GetDueHomework(ctx, userId, now, now.Add(DueWindow))
+	if err != nil {
+		return err
+	}
+	notifications := []types.Notification{}
+	for _, item := range homework {
+		built, err := Build(types.NotificationEvent{
+			Type: types.NotifyHomeworkDue,
+			Payload: map[string]any{
+				"homework_id": item.HomeworkId.String(),
+				"student_id": item.StudentId.String(),
+				"student_name": item.StudentName,
+				"title": item.Title,
+				"subject_name": item.SubjectName,
+				"due_at": item.DueAt,
+			},
+			DedupeKey: "homework_due:" + item.HomeworkId.String() + ":" + item.StudentId.String(),
+			At: now,
+		}, []uuid.UUID{userId})
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, built...)
+	}
+	if _, err := h.store.CreateNotifications(ctx, notifications); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+// Build makes the notification of the event for every recipient, the same user is only notified once
+func Build(event types.NotificationEvent, recipients []uuid.UUID) ([]types.Notification, error) {
+
+	title, body, err := Render(event)
+	if err != nil {
+		return nil, err
+	}
+	payload := event.Payload
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.New("Failed to encode the payload of the notification! " + err.Error())
+	}
+	at := event.At
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	var dedupeKey *string
+	if event.DedupeKey != "" {
+		dedupeKey = &event.DedupeKey
+	}
+
+	notifications := make([]types.Notification, 0, len(recipients))
+	seen := make(map[uuid.UUID]bool, len(recipients))
+	for _, userId := range recipients {
+		if seen[userId] {
+			continue
+		}
+		seen[userId] = true
+		notifications = append(notifications, types.Notification{
+			Id: uuid.New(),
+			UserId: userId,
+			Type: event.Type,
+			Title: title,
+			Body: body,
+			Payload: raw,
+			DedupeKey: dedupeKey,
+			Created_at: at,
+		})
+	}
+
+	return notifications, nil
+
+}
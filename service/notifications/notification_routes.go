@@ -0,0 +1,265 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+// the page size of the notifications
+const (
+	DefaultLimit 	= 20
+	MaxLimit 		= 100
+)
+
+//type handlerequest that declare the notification store for a database logic
+type HandleRequest struct {
+	db types.NotificationStore
+	hub *Hub
+}
+
+//func that declare the handler for the notifications of the user
+func NewHandlerNotification(db types.NotificationStore, hub *Hub) *HandleRequest {
+	return &HandleRequest{
+		db: db,
+		hub: hub,
+	}
+}
+
+//helper to check the type of the query params, the empty type is every type
+func validType(notificationType string) bool {
+	switch notificationType {
+	case "", types.NotifyGradesPublished, types.NotifyHomeworkAssigned, types.NotifyHomeworkDue, types.NotifyAbsenceRecorded:
+		return true
+	}
+	return false
+}
+
+//helper to get the user of the token
+func userOf(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return uuid.Nil, false
+	}
+	return user_id, true
+}
+
+//func to get the notifications of the user, the newest first. The before is the created time of the last
+//notification of the previous page
+func (h *HandleRequest) GetNotifications_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	user_id, ok := userOf(w, r)
+	if !ok {
+		return
+	}
+
+	//declare the query params
+	filter := types.NotificationFilter{UserId: user_id, Limit: DefaultLimit}
+	filter.UnreadOnly = r.URL.Query().Get("unread") == "true"
+	filter.Type = r.URL.Query().Get("type")
+	if !validType(filter.Type) {
+		utils.ResponseError(w, http.StatusBadRequest, "The type of the notification is not valid!", false)
+		return
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		limit_convert, err := strconv.Atoi(limit)
+		if err != nil || limit_convert < 1 || limit_convert > MaxLimit {
+			utils.ResponseError(w, http.StatusBadRequest, "The limit must be between 1 and 100!", false)
+			return
+		}
+		filter.Limit = limit_convert
+	}
+	if before := r.URL.Query().Get("before"); before != "" {
+		before_time, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the time!", err.Error())
+			return
+		}
+		filter.Before = &before_time
+	}
+
+	//save the reminder of the homework that is due soon before the list
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.hub.SyncDueReminders(ctx, user_id, time.Now().UTC()); err != nil {
+		logger.Log.Warn("Failed to save the reminders of the due homework",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+		)
+	}
+
+	//execute the query
+	notifications, err := h.db.GetNotifications(ctx, filter)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the notifications",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the notifications!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the notifications has been successfully", notifications)
+
+}
+
+//func to get the count of the unread notifications of the user, the total and every type
+func (h *HandleRequest) UnreadCount_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	user_id, ok := userOf(w, r)
+	if !ok {
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	counts, err := h.db.CountUnread(ctx, user_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to count the unread notifications",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to count the unread notifications!", err.Error())
+		return
+	}
+	counter := types.UnreadCounter{ByType: counts}
+	for _, count := range counts {
+		counter.Total += count.Unread
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the unread notifications has been successfully", counter)
+
+}
+
+//func to mark the notification of the user as read
+func (h *HandleRequest) MarkRead_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	//declare the id params
+	notification_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return
+	}
+	user_id, ok := userOf(w, r)
+	if !ok {
+		return
+	}
+
+	//execute the query, the notification of the other user is not found
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.MarkRead(ctx, user_id, notification_id, time.Now().UTC()); err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to mark the notification as read",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusNotFound, "Failed to mark the notification as read!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Mark the notification as read has been successfully", true)
+
+}
+
+//func to mark every unread notification of the user as read, the type query params only marks that type
+func (h *HandleRequest) MarkAllRead_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!",
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return
+	}
+
+	user_id, ok := userOf(w, r)
+	if !ok {
+		return
+	}
+	notification_type := r.URL.Query().Get("type")
+	if !validType(notification_type) {
+		utils.ResponseError(w, http.StatusBadRequest, "The type of the notification is not valid!", false)
+		return
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	marked, err := h.db.MarkAllRead(ctx, user_id, notification_type, time.Now().UTC())
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to mark the notifications as read",
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to mark the notifications as read!", err.Error())
+		return
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Mark the notifications as read has been successfully", map[string]int64{"marked": marked})
+
+}
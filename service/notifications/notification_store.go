@@ -0,0 +1,247 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store notification
+type NotificationStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewNotificationStore(db *sqlx.DB) *NotificationStore {
+	return &NotificationStore{db: db}
+}
+
+//the column of the notification that we select in every query
+const notificationColumns = `id, user_id, type, title, body, payload, dedupe_key, read_at, created_at`
+
+//func to create the notifications, the notification that the user already has with the same dedupe key is skipped
+func (s *NotificationStore) CreateNotifications(ctx context.Context, notifications []types.Notification) (int, error) {
+
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	//settings the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	created := 0
+	for _, notification := range notifications {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO notifications (id, user_id, type, title, body, payload, dedupe_key, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7, $8)
+			ON CONFLICT (user_id, dedupe_key) DO NOTHING;
+		`,
+			notification.Id,
+			notification.UserId,
+			notification.Type,
+			notification.Title,
+			notification.Body,
+			string(notification.Payload),
+			notification.DedupeKey,
+			notification.Created_at,
+		)
+		if err != nil {
+			return 0, errors.New("Failed to create the notification! " + err.Error())
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, errors.New("Failed to create the notification! " + err.Error())
+		}
+		created += int(rows)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.New("Failed to commit the query of transaction!")
+	}
+
+	return created, nil
+
+}
+
+//func to get the notifications of the user, the newest first
+func (s *NotificationStore) GetNotifications(ctx context.Context, filter types.NotificationFilter) ([]types.Notification, error) {
+
+	conditions := []string{"user_id = $1"}
+	args := []interface{}{filter.UserId}
+	argsId := 2
+
+	if filter.UnreadOnly {
+		conditions = append(conditions, "read_at IS NULL")
+	}
+	if filter.Type != "" {
+		conditions = append(conditions, "type = $"+strconv.Itoa(argsId))
+		args = append(args, filter.Type)
+		argsId++
+	}
+	if filter.Before != nil {
+		conditions = append(conditions, "created_at < $"+strconv.Itoa(argsId))
+		args = append(args, *filter.Before)
+		argsId++
+	}
+	args = append(args, filter.Limit)
+
+	//execute the query
+	notifications := []types.Notification{}
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY created_at DESC, id LIMIT $` + strconv.Itoa(argsId) + `;`
+	if err := s.db.SelectContext(ctx, &notifications, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get the notifications: %w", err)
+	}
+
+	return notifications, nil
+
+}
+
+//func to count the unread notifications of the user by the type
+func (s *NotificationStore) CountUnread(ctx context.Context, userId uuid.UUID) ([]types.NotificationCount, error) {
+
+	//execute the query
+	counts := []types.NotificationCount{}
+	if err := s.db.SelectContext(ctx, &counts, `
+		SELECT type, COUNT(*) AS unread FROM notifications
+		WHERE user_id = $1 AND read_at IS NULL
+		GROUP BY type ORDER BY type;
+	`, userId); err != nil {
+		return nil, fmt.Errorf("failed to count the unread notifications: %w", err)
+	}
+
+	return counts, nil
+
+}
+
+//func to mark the notification of the user as read, the notification that is already read keeps its time
+func (s *NotificationStore) MarkRead(ctx context.Context, userId uuid.UUID, id uuid.UUID, at time.Time) error {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE id = $1 AND user_id = $2;
+	`, id, userId, at)
+	if err != nil {
+		return errors.New("Failed to mark the notification as read! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.New("Failed to mark the notification as read! " + err.Error())
+	}
+	if rows == 0 {
+		return errors.New("The notification is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to mark every unread notification of the user as read, the empty type marks every type
+func (s *NotificationStore) MarkAllRead(ctx context.Context, userId uuid.UUID, notificationType string, at time.Time) (int64, error) {
+
+	//execute the query
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE notifications SET read_at = $2
+		WHERE user_id = $1 AND read_at IS NULL AND ($3 = '' OR type = $3);
+	`, userId, at, notificationType)
+	if err != nil {
+		return 0, errors.New("Failed to mark the notifications as read! " + err.Error())
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.New("Failed to mark the notifications as read! " + err.Error())
+	}
+
+	return rows, nil
+
+}
+
+//func to get the users of the audience, the students of the class in the term and the given students with
+//their guardians when it is asked, and the given users
+func (s *NotificationStore) GetRecipients(ctx context.Context, audience types.NotificationAudience) ([]uuid.UUID, error) {
+
+	userIds := make([]string, 0, len(audience.UserIds))
+	for _, id := range audience.UserIds {
+		userIds = append(userIds, id.String())
+	}
+	studentIds := make([]string, 0, len(audience.StudentIds))
+	for _, id := range audience.StudentIds {
+		studentIds = append(studentIds, id.String())
+	}
+
+	//execute the query
+	recipients := []uuid.UUID{}
+	if err := s.db.SelectContext(ctx, &recipients, `
+		WITH audience_students AS (
+			SELECT e.student_id AS id FROM class_enrollments e
+			WHERE e.class_id = $1 AND e.term_id = $2
+			UNION
+			SELECT s.id FROM students s
+			WHERE $1::uuid IS NOT NULL AND s.class_id = $1 AND s.status = 'active'
+			UNION
+			SELECT s.id FROM students s WHERE s.id = ANY($3::uuid[])
+		)
+		SELECT s.user_id FROM students s
+		JOIN audience_students a ON a.id = s.id
+		WHERE s.user_id IS NOT NULL
+		UNION
+		SELECT g.user_id FROM guardian_students gs
+		JOIN guardians g ON g.id = gs.guardian_id
+		JOIN audience_students a ON a.id = gs.student_id
+		WHERE $4
+		UNION
+		SELECT u.id FROM users u WHERE u.id = ANY($5::uuid[]);
+	`, audience.ClassId, audience.TermId, pq.StringArray(studentIds), audience.Guardians, pq.StringArray(userIds)); err != nil {
+		return nil, fmt.Errorf("failed to get the recipients of the notification: %w", err)
+	}
+
+	return recipients, nil
+
+}
+
+//func to get the homework that is due in the window and is not submitted yet by the student of the user,
+//the student itself for the siswa or the children for the orangtua
+func (s *NotificationStore) GetDueHomework(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]types.DueHomework, error) {
+
+	//execute the query
+	homework := []types.DueHomework{}
+	if err := s.db.SelectContext(ctx, &homework, `
+		WITH user_students AS (
+			SELECT s.id FROM students s WHERE s.user_id = $1
+			UNION
+			SELECT gs.student_id FROM guardian_students gs
+			JOIN guardians g ON g.id = gs.guardian_id
+			WHERE g.user_id = $1
+		)
+		SELECT hw.id AS homework_id, s.id AS student_id, s.name AS student_name, hw.title,
+		sb.name AS subject_name, hw.due_at
+		FROM homework hw
+		JOIN subjects sb ON sb.id = hw.subject_id
+		JOIN students s ON (
+			EXISTS (SELECT 1 FROM class_enrollments e WHERE e.student_id = s.id AND e.class_id = hw.class_id AND e.term_id = hw.term_id)
+			OR (s.class_id = hw.class_id AND s.status = 'active')
+		)
+		JOIN user_students us ON us.id = s.id
+		WHERE hw.due_at > $2 AND hw.due_at <= $3
+		AND NOT EXISTS (SELECT 1 FROM homework_submissions hs WHERE hs.homework_id = hw.id AND hs.student_id = s.id)
+		ORDER BY hw.due_at, s.name;
+	`, userId, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get the due homework: %w", err)
+	}
+
+	return homework, nil
+
+}
@@ -0,0 +1,97 @@
+package promotions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//the actions of the promotion item
+const (
+	ActionPromote = "promote"
+	ActionRetain = "retain"
+	ActionGraduate = "graduate"
+)
+
+//helper for the key of the class inside one academic year
+func classKey(gradeLevel int, major, section string) string {
+	return fmt.Sprintf("%d|%s|%s", gradeLevel, strings.ToUpper(major), strings.ToUpper(section))
+}
+
+// PlanPromotion decides the next class of every candidate: the retained students stay in the
+// same grade, the final grade graduates and the others go to grade+1 with the same major and
+// section. The classes of the next year that are not exist yet are returned to be created.
+func PlanPromotion(candidates []types.PromotionCandidate, existing []types.Class, toYear string, finalGrade int) ([]types.PromotionItem, []types.Class) {
+
+	classes := make(map[string]*types.Class, len(existing))
+	for i := range existing {
+		class := &existing[i]
+		classes[classKey(class.GradeLevel, class.Major, class.Section)] = class
+	}
+
+	items := make([]types.PromotionItem, 0, len(candidates))
+	var created []types.Class
+	planned := map[uuid.UUID]bool{}
+	for _, candidate := range candidates {
+		item := types.PromotionItem{
+			Id: uuid.New(),
+			StudentId: candidate.StudentId,
+			Name: candidate.Name,
+			FromClassId: candidate.ClassId,
+			FromClass: candidate.Class,
+		}
+
+		grade := candidate.GradeLevel + 1
+		switch {
+		case candidate.Retained:
+			item.Action = ActionRetain
+			grade = candidate.GradeLevel
+		case candidate.GradeLevel >= finalGrade:
+			item.Action = ActionGraduate
+			items = append(items, item)
+			continue
+		default:
+			item.Action = ActionPromote
+		}
+
+		//find the class of the next year, or plan a new one
+		key := classKey(grade, candidate.Major, candidate.Section)
+		class, ok := classes[key]
+		if !ok {
+			class = &types.Class{
+				Id: uuid.New(),
+				Name: utils.ClassName{GradeLevel: grade, Major: candidate.Major, Section: candidate.Section}.String(),
+				GradeLevel: grade,
+				Major: candidate.Major,
+				Section: candidate.Section,
+				AcademicYear: toYear,
+				Created_at: time.Now().UTC(),
+				Updated_at: time.Now().UTC(),
+			}
+			classes[key] = class
+			planned[class.Id] = true
+			created = append(created, *class)
+		}
+		item.ToClassId = &class.Id
+		item.ToClass = class.Name
+		item.CreateClass = planned[class.Id]
+		items = append(items, item)
+	}
+
+	return items, created
+
+}
+
+// NextAcademicYear returns the name of the year after the given one (2025/2026 -> 2026/2027)
+func NextAcademicYear(name string) (string, error) {
+	var first, second int
+	if _, err := fmt.Sscanf(name, "%d/%d", &first, &second); err != nil || second != first + 1 {
+		return "", fmt.Errorf("invalid academic year %q", name)
+	}
+	return fmt.Sprintf("%d/%d", first + 1, second + 1), nil
+}
@@ -0,0 +1,51 @@
+package promotions
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestPlanPromotion(t *testing.T) {
+	existing := []types.Class{
+		{Id: uuid.New(), Name: "XI IPA 1", GradeLevel: 11, Major: "IPA", Section: "1", AcademicYear: "2026/2027"},
+	}
+	candidates := []types.PromotionCandidate{
+		{StudentId: uuid.New(), Name: "Andi", GradeLevel: 10, Major: "IPA", Section: "1"},
+		{StudentId: uuid.New(), Name: "Budi", GradeLevel: 10, Major: "IPA", Section: "1", Retained: true},
+		{StudentId: uuid.New(), Name: "Citra", GradeLevel: 10, Major: "IPA", Section: "1", Retained: true},
+		{StudentId: uuid.New(), Name: "Dewi", GradeLevel: 12, Major: "IPS", Section: "2"},
+	}
+
+	items, created := PlanPromotion(candidates, existing, "2026/2027", 12)
+	if len(items) != len(candidates) {
+		t.Fatalf("got %d items", len(items))
+	}
+
+	if items[0].Action != ActionPromote || *items[0].ToClassId != existing[0].Id || items[0].CreateClass {
+		t.Fatalf("promote into the existing class, got %+v", items[0])
+	}
+	if items[1].Action != ActionRetain || items[1].ToClass != "X IPA 1" || !items[1].CreateClass {
+		t.Fatalf("retain into a new class, got %+v", items[1])
+	}
+	if *items[2].ToClassId != *items[1].ToClassId {
+		t.Fatalf("the new class must be planned once")
+	}
+	if items[3].Action != ActionGraduate || items[3].ToClassId != nil {
+		t.Fatalf("graduate the final grade, got %+v", items[3])
+	}
+	if len(created) != 1 || created[0].AcademicYear != "2026/2027" || created[0].GradeLevel != 10 {
+		t.Fatalf("got created classes %+v", created)
+	}
+}
+
+func TestNextAcademicYear(t *testing.T) {
+	if got, err := NextAcademicYear("2025/2026"); err != nil || got != "2026/2027" {
+		t.Fatalf("got %s (%v)", got, err)
+	}
+	if _, err := NextAcademicYear("2025/2027"); err == nil {
+		t.Fatalf("should be invalid")
+	}
+}
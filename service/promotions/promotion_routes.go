@@ -0,0 +1,520 @@
+package promotions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//the last grade of the school, the students of this grade graduate
+const defaultFinalGrade = 12
+
+//type handlerequest that declare the promotion store for a database logic
+type HandleRequest struct {
+	db types.PromotionStore
+}
+
+//func that declare the handler for promotion
+func NewHandlerPromotion(db types.PromotionStore) *HandleRequest {
+	return &HandleRequest{db: db}
+}
+
+//helper to make the response of the planned or committed promotion
+func promotionResponse(run *types.PromotionRun, created []types.Class) map[string]interface{} {
+	classes := make([]types.ClassResponse, 0, len(created))
+	for _, class := range created {
+		classes = append(classes, types.ClassResponse{
+			Id: class.Id,
+			Name: class.Name,
+			GradeLevel: class.GradeLevel,
+			Major: class.Major,
+			Section: class.Section,
+			AcademicYear: class.AcademicYear,
+			Created_at: class.Created_at.Format("2006-01-02"),
+			Updated_at: class.Updated_at.Format("2006-01-02"),
+		})
+	}
+	return map[string]interface{}{
+		"run": run,
+		"new_classes": classes,
+	}
+}
+
+//helper to read the payload of the promotion into a run, shared by the preview and the commit
+func (h *HandleRequest) decodeRun(w http.ResponseWriter, r *http.Request) (*types.PromotionRun, bool) {
+
+	//only guru and admin can promote the students
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return nil, false
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return nil, false
+	}
+
+	//decode and validate the payload
+	var payload types.PromotionRequest
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return nil, false
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return nil, false
+	}
+
+	//the default is the next year and the last grade of the school
+	next_year, err := NextAcademicYear(payload.FromYear)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid academic year!", err.Error())
+		return nil, false
+	}
+	if payload.ToYear == "" {
+		payload.ToYear = next_year
+	}
+	if payload.ToYear == payload.FromYear {
+		utils.ResponseError(w, http.StatusBadRequest, "The next academic year must not be the same year!", false)
+		return nil, false
+	}
+	if payload.FinalGrade == 0 {
+		payload.FinalGrade = defaultFinalGrade
+	}
+
+	run := &types.PromotionRun{
+		Id: uuid.New(),
+		FromYear: payload.FromYear,
+		ToYear: payload.ToYear,
+		FinalGrade: payload.FinalGrade,
+		Status: "committed",
+		Created_at: time.Now().UTC(),
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		run.CreatedBy = &user_id
+	}
+
+	return run, true
+
+}
+
+//func to preview the promotion of the academic year, nothing is changed
+func (h *HandleRequest) Preview_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	run, ok := h.decodeRun(w, r)
+	if !ok {
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	created, err := h.db.PreviewPromotion(ctx, run)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to preview the promotion", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to preview the promotion!", err.Error())
+		return 
+	}
+
+	//the preview has no id and no status
+	run.Id = uuid.Nil
+	run.Status = "preview"
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Preview the promotion has been successfully", promotionResponse(run, created))
+
+}
+
+//func to commit the promotion of the academic year
+func (h *HandleRequest) Commit_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	run, ok := h.decodeRun(w, r)
+	if !ok {
+		return 
+	}
+
+	//execute the query, every student is moved in one transaction
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 60)
+	defer cancle()
+	created, err := h.db.CommitPromotion(ctx, run)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to commit the promotion", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to commit the promotion!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Commit the promotion has been successfully", promotionResponse(run, created))
+
+}
+
+//func to get the promotion runs (?from_year=2025/2026)
+func (h *HandleRequest) GetRuns_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the promotions
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	runs, err := h.db.GetPromotionRuns(ctx, r.URL.Query().Get("from_year"))
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the promotion runs", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the promotions!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the promotions has been successfully", runs)
+
+}
+
+//func to get one promotion run with the students
+func (h *HandleRequest) GetRun_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the promotions
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	run_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	run, err := h.db.GetPromotionRun(ctx, run_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the promotion!", err.Error())
+		return 
+	}
+	if run == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The promotion is not exist!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the promotion has been successfully", run)
+
+}
+
+//func to roll back the promotion
+func (h *HandleRequest) Rollback_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can roll back the promotion
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	run_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 60)
+	defer cancle()
+	if err := h.db.RollbackPromotion(ctx, run_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to roll back the promotion", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to roll back the promotion!", err.Error())
+		return 
+	}
+	run, err := h.db.GetPromotionRun(ctx, run_id)
+	if err != nil || run == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the promotion!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Roll back the promotion has been successfully", run)
+
+}
+
+//func to retain the student, the student is not promoted at the end of the year
+func (h *HandleRequest) CreateRetention_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can retain the student
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateRetention
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//make the struct of the retention and execute the query
+	retention := &types.Retention{
+		StudentId: payload.StudentId,
+		AcademicYear: payload.AcademicYear,
+		Reason: payload.Reason,
+		Created_at: time.Now().UTC(),
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		retention.CreatedBy = &user_id
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CreateRetention(ctx, retention); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to retain the student", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to retain the student!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Retain the student has been successfully", retention)
+
+}
+
+//func to get the retained students (?academic_year=2025/2026)
+func (h *HandleRequest) GetRetentions_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the retained students
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	retentions, err := h.db.GetRetentions(ctx, r.URL.Query().Get("academic_year"))
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the retentions", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the retained students!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the retained students has been successfully", retentions)
+
+}
+
+//func to remove the retention of the student (?academic_year=2025/2026)
+func (h *HandleRequest) DeleteRetention_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can remove the retention
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["student_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	academic_year := r.URL.Query().Get("academic_year")
+	if academic_year == "" {
+		utils.ResponseError(w, http.StatusBadRequest, "The academic year is required!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.DeleteRetention(ctx, student_id, academic_year); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the retention!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the retention has been successfully", student_id)
+
+}
@@ -0,0 +1,429 @@
+package promotions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/service/majors"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store promotion
+type PromotionStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewPromotionStore(db *sqlx.DB) *PromotionStore {
+	return &PromotionStore{db: db}
+}
+
+//the column that we select in every query
+const runColumns = `
+	id, from_year, to_year, term_id, final_grade, status, promoted, retained, graduated,
+	created_by, created_at, rolled_back_at
+`
+
+//helper to plan the promotion inside the transaction, lock is used when the plan is committed
+func planPromotion(ctx context.Context, tx *sqlx.Tx, run *types.PromotionRun, lock bool) ([]types.Class, error) {
+
+	//both academic years must be registered
+	var years int
+	if err := tx.GetContext(ctx, &years, `SELECT COUNT(*) FROM academic_years WHERE name IN ($1, $2);`, run.FromYear, run.ToYear); err != nil {
+		return nil, errors.New("Failed to get the academic years! " + err.Error())
+	}
+	if years != 2 {
+		return nil, fmt.Errorf("The academic year %s or %s is not exist!", run.FromYear, run.ToYear)
+	}
+
+	//the active students of the year with the grade of the class
+	query := `
+		SELECT s.id AS student_id, s.name, c.id AS class_id, c.name AS class, c.grade_level, c.major, c.section,
+		EXISTS (
+			SELECT 1 FROM student_retentions r WHERE r.student_id = s.id AND r.academic_year = c.academic_year
+		) AS retained
+		FROM students s JOIN classes c ON c.id = s.class_id
+		WHERE c.academic_year = $1 AND s.status = 'active'
+		ORDER BY c.grade_level, c.name, s.name
+	`
+	if lock {
+		query += ` FOR UPDATE OF s`
+	}
+	var candidates []types.PromotionCandidate
+	if err := tx.SelectContext(ctx, &candidates, query, run.FromYear); err != nil {
+		return nil, errors.New("Failed to get the students! " + err.Error())
+	}
+
+	//the classes that already exist in the next year
+	var existing []types.Class
+	if err := tx.SelectContext(ctx, &existing, `
+		SELECT id, name, grade_level, major, section, academic_year, homeroom_teacher_id, created_at, updated_at
+		FROM classes WHERE academic_year = $1;
+	`, run.ToYear); err != nil {
+		return nil, errors.New("Failed to get the classes! " + err.Error())
+	}
+
+	items, created := PlanPromotion(candidates, existing, run.ToYear, run.FinalGrade)
+	run.Items = items
+	run.Promoted, run.Retained, run.Graduated = 0, 0, 0
+	for _, item := range items {
+		switch item.Action {
+		case ActionPromote:
+			run.Promoted++
+		case ActionRetain:
+			run.Retained++
+		case ActionGraduate:
+			run.Graduated++
+		}
+	}
+
+	return created, nil
+
+}
+
+//helper to get the current term: the open term, or the last closed term between two terms
+func currentTerm(ctx context.Context, tx *sqlx.Tx) (uuid.UUID, error) {
+
+	var id uuid.UUID
+	err := tx.GetContext(ctx, &id, `
+		SELECT id FROM terms WHERE status <> 'planned'
+		ORDER BY status = 'open' DESC, closed_at DESC NULLS LAST LIMIT 1;
+	`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, errors.New("There is no open or closed term yet!")
+	}
+	if err != nil {
+		return uuid.Nil, errors.New("Failed to get the current term! " + err.Error())
+	}
+
+	return id, nil
+
+}
+
+//func to preview the promotion, nothing is saved
+func (s *PromotionStore) PreviewPromotion(ctx context.Context, run *types.PromotionRun) ([]types.Class, error) {
+
+	//setup the read only transaction so the plan is made from one snapshot
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	return planPromotion(ctx, tx, run, false)
+
+}
+
+//func to commit the promotion in one transaction, it return the new classes of the next year
+func (s *PromotionStore) CommitPromotion(ctx context.Context, run *types.PromotionRun) ([]types.Class, error) {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the year can only be promoted once
+	var committed bool
+	if err := tx.GetContext(ctx, &committed, `
+		SELECT EXISTS (SELECT 1 FROM promotion_runs WHERE from_year = $1 AND status = 'committed');
+	`, run.FromYear); err != nil {
+		return nil, errors.New("Failed to check the promotion! " + err.Error())
+	}
+	if committed {
+		return nil, fmt.Errorf("The academic year %s has been already promoted!", run.FromYear)
+	}
+
+	//the run can be rolled back only in the current term
+	term_id, err := currentTerm(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	run.TermId = term_id
+
+	//plan again with the students locked
+	created, err := planPromotion(ctx, tx, run, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(run.Items) == 0 {
+		return nil, errors.New("There is no active student in this academic year!")
+	}
+
+	//create the classes of the next year
+	for _, class := range created {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO classes (id, name, grade_level, major, section, academic_year, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+		`, class.Id, class.Name, class.GradeLevel, class.Major, class.Section, class.AcademicYear, class.Created_at, class.Updated_at); err != nil {
+			return nil, errors.New("Failed to create the class " + class.Name + "! " + err.Error())
+		}
+	}
+
+	//save the run
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO promotion_runs
+		(id, from_year, to_year, term_id, final_grade, status, promoted, retained, graduated, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+	`, run.Id, run.FromYear, run.ToYear, run.TermId, run.FinalGrade, run.Status,
+		run.Promoted, run.Retained, run.Graduated, run.CreatedBy, run.Created_at); err != nil {
+		return nil, errors.New("Failed to save the promotion! " + err.Error())
+	}
+
+	//the majors of the students, the seats of the year are free after the move
+	student_ids := make([]string, 0, len(run.Items))
+	for _, item := range run.Items {
+		student_ids = append(student_ids, item.StudentId.String())
+	}
+	var major_ids []uuid.UUID
+	if err := tx.SelectContext(ctx, &major_ids, `
+		SELECT DISTINCT major_id FROM students WHERE id = ANY($1::uuid[]) AND major_id IS NOT NULL ORDER BY major_id;
+	`, pq.StringArray(student_ids)); err != nil {
+		return nil, errors.New("Failed to get the majors of the students! " + err.Error())
+	}
+
+	//move every student and keep the previous class in the item
+	for i := range run.Items {
+		item := &run.Items[i]
+		item.RunId = run.Id
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO promotion_items
+			(id, run_id, student_id, action, from_class_id, from_class, to_class_id, to_class, create_class)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+		`, item.Id, item.RunId, item.StudentId, item.Action, item.FromClassId, item.FromClass,
+			item.ToClassId, item.ToClass, item.CreateClass); err != nil {
+			return nil, errors.New("Failed to save the promotion item! " + err.Error())
+		}
+
+		if item.Action == ActionGraduate {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE students SET status = 'alumni', graduated_at = $1, updated_at = $1 WHERE id = $2;
+			`, run.Created_at, item.StudentId)
+		} else {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE students SET class_id = $1, class = $2, academic_year = $3, updated_at = $4 WHERE id = $5;
+			`, item.ToClassId, item.ToClass, run.ToYear, run.Created_at, item.StudentId)
+		}
+		if err != nil {
+			return nil, errors.New("Failed to move the student " + item.Name + "! " + err.Error())
+		}
+	}
+
+	//the seats of the year that is ending are not used anymore, only the next year can take the waiting registrations
+	for _, major_id := range major_ids {
+		if _, err := majors.ReleaseSeats(ctx, tx, major_id, run.ToYear); err != nil {
+			return nil, err
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return created, nil
+
+}
+
+//func to get the promotion runs, filtered by the academic year
+func (s *PromotionStore) GetPromotionRuns(ctx context.Context, fromYear string) ([]types.PromotionRun, error) {
+
+	//base query
+	query := `
+		SELECT ` + runColumns + ` FROM promotion_runs
+		WHERE ($1 = '' OR from_year = $1) ORDER BY created_at DESC;
+	`
+
+	//execute the query
+	runs := []types.PromotionRun{}
+	if err := s.db.SelectContext(ctx, &runs, query, fromYear); err != nil {
+		return nil, fmt.Errorf("failed to get the promotion runs: %w", err)
+	}
+
+	return runs, nil
+
+}
+
+//func to get the promotion run with the items
+func (s *PromotionStore) GetPromotionRun(ctx context.Context, id uuid.UUID) (*types.PromotionRun, error) {
+
+	//get the run
+	var run types.PromotionRun
+	if err := s.db.GetContext(ctx, &run, `SELECT `+runColumns+` FROM promotion_runs WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the promotion run: %w", err)
+	}
+
+	//get the items with the name of the student
+	if err := s.db.SelectContext(ctx, &run.Items, `
+		SELECT i.id, i.run_id, i.student_id, s.name, i.action, i.from_class_id, i.from_class,
+		i.to_class_id, i.to_class, i.create_class
+		FROM promotion_items i JOIN students s ON s.id = i.student_id
+		WHERE i.run_id = $1 ORDER BY i.from_class, s.name;
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get the promotion items: %w", err)
+	}
+
+	return &run, nil
+
+}
+
+//func to roll back the promotion, only in the same term where it has been committed
+func (s *PromotionStore) RollbackPromotion(ctx context.Context, id uuid.UUID) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//lock the run
+	var run types.PromotionRun
+	if err := tx.GetContext(ctx, &run, `SELECT `+runColumns+` FROM promotion_runs WHERE id = $1 FOR UPDATE;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("The promotion is not exist!")
+		}
+		return errors.New("Failed to get the promotion! " + err.Error())
+	}
+	if run.Status != "committed" {
+		return errors.New("The promotion has been already rolled back!")
+	}
+	term_id, err := currentTerm(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if term_id != run.TermId {
+		return errors.New("The promotion can only be rolled back in the same term!")
+	}
+
+	//the majors of the students of the run
+	var major_ids []uuid.UUID
+	if err := tx.SelectContext(ctx, &major_ids, `
+		SELECT DISTINCT s.major_id FROM students s JOIN promotion_items i ON i.student_id = s.id
+		WHERE i.run_id = $1 AND s.major_id IS NOT NULL ORDER BY s.major_id;
+	`, id); err != nil {
+		return errors.New("Failed to get the majors of the students! " + err.Error())
+	}
+
+	//put every student back into the previous class
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE students s
+		SET class_id = i.from_class_id, class = i.from_class, academic_year = $2,
+		status = 'active', graduated_at = NULL, updated_at = $3
+		FROM promotion_items i
+		WHERE i.run_id = $1 AND s.id = i.student_id;
+	`, id, run.FromYear, now); err != nil {
+		return errors.New("Failed to restore the students! " + err.Error())
+	}
+
+	//the students take their seats of the previous year again, the seats may be taken since the promotion,
+	//and they leave the seats of the next year to the waiting registrations
+	for _, major_id := range major_ids {
+		if err := majors.CheckSeats(ctx, tx, major_id, run.FromYear); err != nil {
+			return errors.New(err.Error() + " The promotion can not be rolled back!")
+		}
+		if _, err := majors.ReleaseSeats(ctx, tx, major_id, run.ToYear); err != nil {
+			return err
+		}
+	}
+
+	//remove the classes made by the run when nobody uses them
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM classes c
+		WHERE c.id IN (SELECT to_class_id FROM promotion_items WHERE run_id = $1 AND create_class)
+		AND NOT EXISTS (SELECT 1 FROM students s WHERE s.class_id = c.id);
+	`, id); err != nil {
+		return errors.New("Failed to remove the new classes! " + err.Error())
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE promotion_runs SET status = 'rolled_back', rolled_back_at = $1 WHERE id = $2;
+	`, now, id); err != nil {
+		return errors.New("Failed to roll back the promotion! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to mark the student as retained in the academic year
+func (s *PromotionStore) CreateRetention(ctx context.Context, retention *types.Retention) error {
+
+	//base query
+	query := `
+		INSERT INTO student_retentions (student_id, academic_year, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (student_id, academic_year) DO UPDATE SET reason = EXCLUDED.reason, created_by = EXCLUDED.created_by;
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		retention.StudentId,
+		retention.AcademicYear,
+		retention.Reason,
+		retention.CreatedBy,
+		retention.Created_at,
+	); err != nil {
+		return errors.New("Failed to retain the student! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the retained students of the academic year
+func (s *PromotionStore) GetRetentions(ctx context.Context, academicYear string) ([]types.Retention, error) {
+
+	//base query
+	query := `
+		SELECT r.student_id, s.name, r.academic_year, r.reason, r.created_by, r.created_at
+		FROM student_retentions r JOIN students s ON s.id = r.student_id
+		WHERE ($1 = '' OR r.academic_year = $1) ORDER BY r.academic_year DESC, s.name;
+	`
+
+	//execute the query
+	retentions := []types.Retention{}
+	if err := s.db.SelectContext(ctx, &retentions, query, academicYear); err != nil {
+		return nil, fmt.Errorf("failed to get the retentions: %w", err)
+	}
+
+	return retentions, nil
+
+}
+
+//func to remove the retention of the student
+func (s *PromotionStore) DeleteRetention(ctx context.Context, studentId uuid.UUID, academicYear string) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `
+		DELETE FROM student_retentions WHERE student_id = $1 AND academic_year = $2;
+	`, studentId, academicYear)
+	if err != nil {
+		return errors.New("Failed to delete the retention! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The retention is not exist!")
+	}
+
+	return nil
+
+}
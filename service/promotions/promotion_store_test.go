@@ -0,0 +1,263 @@
+package promotions
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+const (
+	fromYear = "2025/2026"
+	toYear = "2026/2027"
+)
+
+type seat struct {
+	year string
+	status string
+	classId string
+	grade int64
+}
+
+type registration struct {
+	id uuid.UUID
+	year string
+	status string
+}
+
+//school is the state of one major behind the scripted database, the seats are counted from the students
+type school struct {
+	major uuid.UUID
+	seats map[string]int64
+	students map[string]*seat
+	waitlist []*registration
+	items map[string]string
+}
+
+func (s *school) enrolled(year string) int64 {
+	var count int64
+	for _, student := range s.students {
+		if student.year == year && student.status == "active" {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *school) waiting(year string) []*registration {
+	var waiting []*registration
+	for _, entry := range s.waitlist {
+		if entry.year == year && entry.status == "waiting" {
+			waiting = append(waiting, entry)
+		}
+	}
+	return waiting
+}
+
+//script the queries of the commit and the roll back on the state of the school
+func scriptSchool(fake *dbtest.DB, s *school, run *types.PromotionRun) {
+	fake.On("SELECT EXISTS (SELECT 1 FROM promotion_runs", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"exists"}, []driver.Value{false})
+	})
+	fake.On("SELECT id FROM terms", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"id"}, []driver.Value{run.TermId.String()})
+	})
+	fake.On("FROM academic_years WHERE name IN", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"count"}, []driver.Value{int64(2)})
+	})
+	fake.On("FROM students s JOIN classes c ON c.id = s.class_id", func(args []any) dbtest.Result {
+		var rows [][]driver.Value
+		for id, student := range s.students {
+			if student.year == args[0] && student.status == "active" {
+				class := fmt.Sprintf("%d IPA 1", student.grade)
+				rows = append(rows, []driver.Value{id, "Siswa", student.classId, class, student.grade, "IPA", "1", false})
+			}
+		}
+		return dbtest.Rows([]string{"student_id", "name", "class_id", "class", "grade_level", "major", "section", "retained"}, rows...)
+	})
+	fake.On("FROM classes WHERE academic_year = $1", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"id", "name", "grade_level", "major", "section", "academic_year", "homeroom_teacher_id", "created_at", "updated_at"})
+	})
+	fake.On("INSERT INTO classes", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("INSERT INTO promotion_runs", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("SELECT DISTINCT", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"major_id"}, []driver.Value{s.major.String()})
+	})
+	fake.On("INSERT INTO promotion_items", func(args []any) dbtest.Result {
+		s.items[fmt.Sprint(args[2])] = fmt.Sprint(args[4])
+		return dbtest.Affected(1)
+	})
+	fake.On("UPDATE students SET status = 'alumni'", func(args []any) dbtest.Result {
+		s.students[fmt.Sprint(args[1])].status = "alumni"
+		return dbtest.Affected(1)
+	})
+	fake.On("UPDATE students SET class_id", func(args []any) dbtest.Result {
+		student := s.students[fmt.Sprint(args[4])]
+		student.year, student.classId = args[2].(string), args[0].(*uuid.UUID).String()
+		return dbtest.Affected(1)
+	})
+
+	//the seats of the major
+	fake.On("COALESCE((SELECT seats", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"over"}, []driver.Value{s.enrolled(args[1].(string)) > s.seats[args[1].(string)]})
+	})
+	fake.On("SELECT seats FROM major_capacities", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"seats"}, []driver.Value{s.seats[args[1].(string)]})
+	})
+	fake.On("SELECT COUNT(*) FROM students WHERE major_id", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"count"}, []driver.Value{s.enrolled(args[1].(string))})
+	})
+	fake.On("AND w.status = 'waiting'", func(args []any) dbtest.Result {
+		var rows [][]driver.Value
+		for _, entry := range s.waiting(args[1].(string)) {
+			if len(rows) == args[2].(int) {
+				break
+			}
+			rows = append(rows, []driver.Value{
+				entry.id.String(), s.major.String(), entry.year, uuid.NewString(), "Baru", uuid.NewString(), "Jl. Merdeka",
+				"", entry.status, nil, time.Now().UTC(), time.Now().UTC(), int64(0),
+			})
+		}
+		return dbtest.Rows([]string{
+			"id", "major_id", "academic_year", "requested_by", "name", "class_id", "address",
+			"student_profile", "status", "student_id", "created_at", "updated_at", "position",
+		}, rows...)
+	})
+	fake.On("SELECT c.name AS class, m.code AS major", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"class", "major"}, []driver.Value{"10 IPA 1", "IPA"})
+	})
+	fake.On("SELECT EXISTS (SELECT 1 FROM students WHERE user_id", func(args []any) dbtest.Result {
+		return dbtest.Rows([]string{"exists"}, []driver.Value{false})
+	})
+	fake.On("INSERT INTO students", func(args []any) dbtest.Result {
+		s.students[fmt.Sprint(args[0])] = &seat{year: *args[7].(*string), status: "active", classId: args[3].(*uuid.UUID).String(), grade: 10}
+		return dbtest.Affected(1)
+	})
+	fake.On("INSERT INTO class_enrollments", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+	fake.On("UPDATE major_waitlist SET status = 'promoted'", func(args []any) dbtest.Result {
+		for _, entry := range s.waitlist {
+			if entry.id == args[2] {
+				entry.status = "promoted"
+			}
+		}
+		return dbtest.Affected(1)
+	})
+
+	//the roll back of the run
+	fake.On("FROM promotion_runs WHERE id = $1 FOR UPDATE", func(args []any) dbtest.Result {
+		return dbtest.Rows(strings.Split(strings.Join(strings.Fields(runColumns), ""), ","), []driver.Value{
+			run.Id.String(), run.FromYear, run.ToYear, run.TermId.String(), int64(run.FinalGrade), "committed",
+			int64(run.Promoted), int64(run.Retained), int64(run.Graduated), nil, run.Created_at, nil,
+		})
+	})
+	fake.On("UPDATE students s", func(args []any) dbtest.Result {
+		for id, class := range s.items {
+			student := s.students[id]
+			student.year, student.status, student.classId = args[1].(string), "active", class
+		}
+		return dbtest.Affected(int64(len(s.items)))
+	})
+	fake.On("DELETE FROM classes c", func(args []any) dbtest.Result { return dbtest.Affected(0) })
+	fake.On("UPDATE promotion_runs SET status = 'rolled_back'", func(args []any) dbtest.Result { return dbtest.Affected(1) })
+}
+
+//the major has two seats in both years: the grade 10 student moves on, the grade 12 student graduates
+//and one registration waits for a seat in every year
+func newSchool() *school {
+	s := &school{
+		major: uuid.New(),
+		seats: map[string]int64{fromYear: 2, toYear: 2},
+		students: map[string]*seat{
+			uuid.NewString(): {year: fromYear, status: "active", classId: uuid.NewString(), grade: 10},
+			uuid.NewString(): {year: fromYear, status: "active", classId: uuid.NewString(), grade: 12},
+			uuid.NewString(): {year: toYear, status: "active", classId: uuid.NewString(), grade: 10},
+		},
+		waitlist: []*registration{
+			{id: uuid.New(), year: fromYear, status: "waiting"},
+			{id: uuid.New(), year: toYear, status: "waiting"},
+		},
+		items: map[string]string{},
+	}
+	return s
+}
+
+func TestCommitAndRollbackPromotionSeats(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewPromotionStore(db)
+
+	s := newSchool()
+	run := &types.PromotionRun{
+		Id: uuid.New(), FromYear: fromYear, ToYear: toYear, TermId: uuid.New(), FinalGrade: 12,
+		Status: "committed", Created_at: time.Now().UTC(),
+	}
+	scriptSchool(fake, s, run)
+
+	if _, err := store.CommitPromotion(context.Background(), run); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	//the year that is ending takes nobody from the waitlist, the next year is full with the promoted student
+	if got := s.enrolled(fromYear); got != 0 {
+		t.Fatalf("expected no student in %s after the commit, got %d", fromYear, got)
+	}
+	if got := s.enrolled(toYear); got != 2 {
+		t.Fatalf("expected 2 students in %s after the commit, got %d", toYear, got)
+	}
+	if len(s.waiting(fromYear)) != 1 || len(s.waiting(toYear)) != 1 {
+		t.Fatalf("expected the waitlist to keep waiting, got %d and %d", len(s.waiting(fromYear)), len(s.waiting(toYear)))
+	}
+
+	if err := store.RollbackPromotion(context.Background(), run.Id); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	//the students are back in their seats and the seat of the next year goes to the waiting registration
+	if got := s.enrolled(fromYear); got != 2 {
+		t.Fatalf("expected 2 students in %s after the roll back, got %d", fromYear, got)
+	}
+	if got := s.enrolled(toYear); got != 2 {
+		t.Fatalf("expected 2 students in %s after the roll back, got %d", toYear, got)
+	}
+	if len(s.waiting(fromYear)) != 1 || len(s.waiting(toYear)) != 0 {
+		t.Fatalf("expected only the registration of %s to be promoted, got %d and %d", toYear, len(s.waiting(fromYear)), len(s.waiting(toYear)))
+	}
+	statements := fake.Statements()
+	if statements[len(statements)-1].Query != dbtest.Commit {
+		t.Fatalf("expected the roll back to be committed, got %v", statements[len(statements)-1])
+	}
+}
+
+func TestRollbackPromotionChecksTheSeats(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewPromotionStore(db)
+
+	s := newSchool()
+	run := &types.PromotionRun{
+		Id: uuid.New(), FromYear: fromYear, ToYear: toYear, TermId: uuid.New(), FinalGrade: 12,
+		Status: "committed", Created_at: time.Now().UTC(),
+	}
+	scriptSchool(fake, s, run)
+
+	if _, err := store.CommitPromotion(context.Background(), run); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	//a late enrollment takes a seat of the year that has been promoted
+	s.students[uuid.NewString()] = &seat{year: fromYear, status: "active", classId: uuid.NewString(), grade: 11}
+
+	err := store.RollbackPromotion(context.Background(), run.Id)
+	if err == nil || !strings.Contains(err.Error(), "can not be rolled back") {
+		t.Fatalf("expected the roll back to be refused, got %v", err)
+	}
+	statements := fake.Statements()
+	if statements[len(statements)-1].Query != dbtest.Rollback || fake.Count("UPDATE promotion_runs SET status = 'rolled_back'") != 0 {
+		t.Fatalf("expected the roll back to be aborted, got %v", statements[len(statements)-1])
+	}
+}
@@ -0,0 +1,224 @@
+package quizzes
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// DeadlineGrace is the time after the deadline that the answers are still accepted, for the slow network
+const DeadlineGrace = 15 * time.Second
+
+// the errors of the attempt
+var (
+	ErrAttemptClosed = errors.New("The time of the attempt is over!")
+	ErrNoAttemptsLeft = errors.New("The student has no attempts left for this quiz!")
+	ErrQuestionInUse = errors.New("The question is used by a quiz!")
+)
+
+// ValidateQuestion checks the options of the question type: the multiple choice has exactly one correct
+// option, the multi select at least one, the short answer has the accepted answers and the essay has none
+func ValidateQuestion(question types.CreateQuestion) error {
+	correct := 0
+	for _, option := range question.Options {
+		if option.Correct {
+			correct++
+		}
+	}
+	switch question.Type {
+	case types.QuestionMultipleChoice:
+		if len(question.Options) < 2 || correct != 1 {
+			return fmt.Errorf("the multiple choice question needs at least 2 options with exactly 1 correct option")
+		}
+	case types.QuestionMultiSelect:
+		if len(question.Options) < 2 || correct < 1 {
+			return fmt.Errorf("the multi select question needs at least 2 options with at least 1 correct option")
+		}
+	case types.QuestionShortAnswer:
+		if len(question.Options) < 1 {
+			return fmt.Errorf("the short answer question needs at least 1 accepted answer in the options")
+		}
+	case types.QuestionEssay:
+		if len(question.Options) > 0 {
+			return fmt.Errorf("the essay question cannot have the options")
+		}
+	default:
+		return fmt.Errorf("unknown question type %q", question.Type)
+	}
+	return nil
+}
+
+// Deadline is the end of the time limit of the attempt, never after the quiz closes
+func Deadline(startedAt time.Time, limitMinutes int, closesAt time.Time) time.Time {
+	deadline := startedAt.Add(time.Duration(limitMinutes) * time.Minute)
+	if closesAt.Before(deadline) {
+		return closesAt
+	}
+	return deadline
+}
+
+// Expired reports if the attempt is past the deadline with the grace time
+func Expired(now time.Time, deadline time.Time) bool {
+	return now.After(deadline.Add(DeadlineGrace))
+}
+
+// Shuffle returns a copy of the ids in the order of the seed, the same seed always gives the same order
+func Shuffle(ids []uuid.UUID, seed int64) []uuid.UUID {
+	shuffled := append([]uuid.UUID(nil), ids...)
+	random := rand.New(rand.NewSource(seed))
+	random.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+// OptionSeed is the seed of the order of the options of one question in the attempt
+func OptionSeed(seed int64, questionId uuid.UUID) int64 {
+	hash := fnv.New64a()
+	hash.Write(questionId[:])
+	return seed ^ int64(hash.Sum64())
+}
+
+// ArrangeAttempt returns the questions in the order of the attempt with the saved answers, the correct
+// answers and the accepted answers of the short answer questions are never included
+func ArrangeAttempt(questions []types.QuizQuestion, answers []types.AttemptAnswer, seed int64, shuffleQuestions bool, shuffleOptions bool) []types.AttemptQuestion {
+
+	sorted := append([]types.QuizQuestion(nil), questions...)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Position < sorted[b].Position })
+	byId := make(map[uuid.UUID]types.QuizQuestion, len(sorted))
+	ids := make([]uuid.UUID, 0, len(sorted))
+	for _, question := range sorted {
+		byId[question.Id] = question
+		ids = append(ids, question.Id)
+	}
+	if shuffleQuestions {
+		ids = Shuffle(ids, seed)
+	}
+	byAnswer := make(map[uuid.UUID]*types.AttemptAnswer, len(answers))
+	for i := range answers {
+		byAnswer[answers[i].QuestionId] = &answers[i]
+	}
+
+	arranged := make([]types.AttemptQuestion, 0, len(ids))
+	for _, id := range ids {
+		question := byId[id]
+		item := types.AttemptQuestion{
+			QuestionId: question.Id,
+			Type: question.Type,
+			Prompt: question.Prompt,
+			Points: question.Points,
+			Options: []types.AttemptOption{},
+			Answer: byAnswer[question.Id],
+		}
+		if question.Type == types.QuestionMultipleChoice || question.Type == types.QuestionMultiSelect {
+			options := make(map[uuid.UUID]string, len(question.Options))
+			optionIds := make([]uuid.UUID, 0, len(question.Options))
+			for _, option := range question.Options {
+				options[option.Id] = option.Text
+				optionIds = append(optionIds, option.Id)
+			}
+			if shuffleOptions {
+				optionIds = Shuffle(optionIds, OptionSeed(seed, question.Id))
+			}
+			for _, optionId := range optionIds {
+				item.Options = append(item.Options, types.AttemptOption{Id: optionId, Text: options[optionId]})
+			}
+		}
+		arranged = append(arranged, item)
+	}
+
+	return arranged
+
+}
+
+// NormalizeAnswer makes the short answer comparable: the case, the spaces around and between the words are ignored
+func NormalizeAnswer(answer string) string {
+	return strings.Join(strings.Fields(strings.ToLower(answer)), " ")
+}
+
+// AutoScore grades the objective question, the multi select is all or nothing. The answered essay has no
+// auto score because it waits for the manual grading, the empty essay is zero.
+func AutoScore(question types.QuizQuestion, answer *types.AttemptAnswer) *float64 {
+
+	score := 0.0
+	if answer == nil {
+		return &score
+	}
+	if question.Type == types.QuestionEssay {
+		if strings.TrimSpace(answer.TextAnswer) == "" {
+			return &score
+		}
+		return nil
+	}
+
+	switch question.Type {
+	case types.QuestionMultipleChoice, types.QuestionMultiSelect:
+		selected := make(map[uuid.UUID]bool, len(answer.SelectedOptions))
+		for _, id := range answer.SelectedOptions {
+			selected[id] = true
+		}
+		correct := true
+		for _, option := range question.Options {
+			if option.IsCorrect != selected[option.Id] {
+				correct = false
+			}
+			delete(selected, option.Id)
+		}
+		if correct && len(selected) == 0 {
+			score = question.Points
+		}
+	case types.QuestionShortAnswer:
+		given := NormalizeAnswer(answer.TextAnswer)
+		for _, option := range question.Options {
+			if given != "" && given == NormalizeAnswer(option.Text) {
+				score = question.Points
+				break
+			}
+		}
+	}
+
+	return &score
+
+}
+
+// TotalScore sums the scores of the answers, the manual score replaces the auto score. The attempt is
+// still pending when an essay has no manual score yet.
+func TotalScore(questions []types.QuizQuestion, answers []types.AttemptAnswer) (float64, bool) {
+
+	byAnswer := make(map[uuid.UUID]types.AttemptAnswer, len(answers))
+	for _, answer := range answers {
+		byAnswer[answer.QuestionId] = answer
+	}
+	total, pending := 0.0, false
+	for _, question := range questions {
+		answer, ok := byAnswer[question.Id]
+		switch {
+		case ok && answer.ManualScore != nil:
+			total += *answer.ManualScore
+		case ok && answer.AutoScore != nil:
+			total += *answer.AutoScore
+		case question.Type == types.QuestionEssay:
+			pending = true
+		}
+	}
+
+	return total, pending
+
+}
+
+// MaxScore sums the points of the questions
+func MaxScore(questions []types.QuizQuestion) float64 {
+	total := 0.0
+	for _, question := range questions {
+		total += question.Points
+	}
+	return total
+}
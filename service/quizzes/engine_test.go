@@ -0,0 +1,165 @@
+package quizzes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func choiceQuestion(kind string, points float64, correct ...bool) types.QuizQuestion {
+	question := types.QuizQuestion{Question: types.Question{Id: uuid.New(), Type: kind, Points: points}}
+	for i, ok := range correct {
+		question.Options = append(question.Options, types.QuestionOption{Id: uuid.New(), IsCorrect: ok, Position: i})
+	}
+	return question
+}
+
+func TestValidateQuestion(t *testing.T) {
+	options := func(correct ...bool) []types.CreateQuestionOption {
+		list := []types.CreateQuestionOption{}
+		for _, ok := range correct {
+			list = append(list, types.CreateQuestionOption{Text: "x", Correct: ok})
+		}
+		return list
+	}
+	cases := []struct {
+		question types.CreateQuestion
+		ok       bool
+	}{
+		{types.CreateQuestion{Type: types.QuestionMultipleChoice, Options: options(true, false)}, true},
+		{types.CreateQuestion{Type: types.QuestionMultipleChoice, Options: options(true, true)}, false},
+		{types.CreateQuestion{Type: types.QuestionMultiSelect, Options: options(true, true, false)}, true},
+		{types.CreateQuestion{Type: types.QuestionMultiSelect, Options: options(false, false)}, false},
+		{types.CreateQuestion{Type: types.QuestionShortAnswer, Options: options(false)}, true},
+		{types.CreateQuestion{Type: types.QuestionShortAnswer}, false},
+		{types.CreateQuestion{Type: types.QuestionEssay}, true},
+		{types.CreateQuestion{Type: types.QuestionEssay, Options: options(true)}, false},
+	}
+	for i, c := range cases {
+		if err := ValidateQuestion(c.question); (err == nil) != c.ok {
+			t.Fatalf("case %d: unexpected result %v", i, err)
+		}
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	start := time.Date(2026, 5, 4, 8, 0, 0, 0, time.UTC)
+	if got := Deadline(start, 45, start.Add(2*time.Hour)); !got.Equal(start.Add(45 * time.Minute)) {
+		t.Fatalf("the deadline should be the time limit, got %v", got)
+	}
+	if got := Deadline(start, 45, start.Add(30*time.Minute)); !got.Equal(start.Add(30 * time.Minute)) {
+		t.Fatalf("the deadline should not be after the quiz closes, got %v", got)
+	}
+	if Expired(start.Add(10*time.Second), start) || !Expired(start.Add(time.Minute), start) {
+		t.Fatalf("unexpected expiry with the grace time")
+	}
+}
+
+func TestArrangeAttempt(t *testing.T) {
+	questions := []types.QuizQuestion{}
+	for i := 0; i < 8; i++ {
+		question := choiceQuestion(types.QuestionMultipleChoice, 1, true, false, false, false)
+		question.Position = i
+		questions = append(questions, question)
+	}
+	short := types.QuizQuestion{Question: types.Question{Id: uuid.New(), Type: types.QuestionShortAnswer, Points: 1,
+		Options: []types.QuestionOption{{Id: uuid.New(), Text: "Jakarta", IsCorrect: true}}}, Position: 8}
+	questions = append(questions, short)
+
+	first := ArrangeAttempt(questions, nil, 42, true, true)
+	again := ArrangeAttempt(questions, nil, 42, true, true)
+	other := ArrangeAttempt(questions, nil, 7, true, true)
+	if len(first) != len(questions) {
+		t.Fatalf("expected %d questions, got %d", len(questions), len(first))
+	}
+	sameOrder := func(a, b []types.AttemptQuestion) bool {
+		for i := range a {
+			if a[i].QuestionId != b[i].QuestionId {
+				return false
+			}
+		}
+		return true
+	}
+	if !sameOrder(first, again) {
+		t.Fatalf("the same seed should give the same order")
+	}
+	if sameOrder(first, other) {
+		t.Fatalf("another seed should give another order")
+	}
+	for _, item := range first {
+		if item.QuestionId == short.Id && len(item.Options) != 0 {
+			t.Fatalf("the accepted answers of the short answer should be hidden")
+		}
+	}
+
+	plain := ArrangeAttempt(questions, nil, 42, false, false)
+	for i := range plain[:8] {
+		if plain[i].QuestionId != questions[i].Id || plain[i].Options[0].Id != questions[i].Options[0].Id {
+			t.Fatalf("without the shuffle the order should be the position")
+		}
+	}
+}
+
+func TestAutoScore(t *testing.T) {
+	single := choiceQuestion(types.QuestionMultipleChoice, 2, false, true, false)
+	multi := choiceQuestion(types.QuestionMultiSelect, 3, true, true, false)
+	short := types.QuizQuestion{Question: types.Question{Id: uuid.New(), Type: types.QuestionShortAnswer, Points: 1,
+		Options: []types.QuestionOption{{Id: uuid.New(), Text: "Ki Hajar  Dewantara", IsCorrect: true}}}}
+	essay := types.QuizQuestion{Question: types.Question{Id: uuid.New(), Type: types.QuestionEssay, Points: 10}}
+
+	score := func(question types.QuizQuestion, answer *types.AttemptAnswer) float64 {
+		got := AutoScore(question, answer)
+		if got == nil {
+			return -1
+		}
+		return *got
+	}
+	if score(single, &types.AttemptAnswer{SelectedOptions: []uuid.UUID{single.Options[1].Id}}) != 2 {
+		t.Fatalf("the correct single choice should get the points")
+	}
+	if score(single, &types.AttemptAnswer{SelectedOptions: []uuid.UUID{single.Options[0].Id}}) != 0 {
+		t.Fatalf("the wrong single choice should get zero")
+	}
+	if score(multi, &types.AttemptAnswer{SelectedOptions: []uuid.UUID{multi.Options[0].Id, multi.Options[1].Id}}) != 3 {
+		t.Fatalf("the exact multi select should get the points")
+	}
+	if score(multi, &types.AttemptAnswer{SelectedOptions: []uuid.UUID{multi.Options[0].Id}}) != 0 {
+		t.Fatalf("the partial multi select should get zero")
+	}
+	if score(multi, &types.AttemptAnswer{SelectedOptions: []uuid.UUID{multi.Options[0].Id, multi.Options[1].Id, uuid.New()}}) != 0 {
+		t.Fatalf("the unknown option should get zero")
+	}
+	if score(short, &types.AttemptAnswer{TextAnswer: "  ki hajar dewantara "}) != 1 {
+		t.Fatalf("the short answer should ignore the case and the spaces")
+	}
+	if score(single, nil) != 0 || score(essay, nil) != 0 {
+		t.Fatalf("the question without the answer should get zero")
+	}
+	if score(essay, &types.AttemptAnswer{TextAnswer: "jawaban"}) != -1 {
+		t.Fatalf("the answered essay should wait for the manual grading")
+	}
+}
+
+func TestTotalScore(t *testing.T) {
+	single := choiceQuestion(types.QuestionMultipleChoice, 2, true, false)
+	essay := types.QuizQuestion{Question: types.Question{Id: uuid.New(), Type: types.QuestionEssay, Points: 10}}
+	questions := []types.QuizQuestion{single, essay}
+	two, eight := 2.0, 8.0
+
+	answers := []types.AttemptAnswer{{QuestionId: single.Id, AutoScore: &two}, {QuestionId: essay.Id, TextAnswer: "x"}}
+	total, pending := TotalScore(questions, answers)
+	if total != 2 || !pending {
+		t.Fatalf("the essay without the manual score should be pending, got %v %v", total, pending)
+	}
+	answers[1].ManualScore = &eight
+	total, pending = TotalScore(questions, answers)
+	if total != 10 || pending {
+		t.Fatalf("expected 10 and not pending, got %v %v", total, pending)
+	}
+	if MaxScore(questions) != 12 {
+		t.Fatalf("expected the max score 12")
+	}
+}
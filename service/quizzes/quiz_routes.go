@@ -0,0 +1,1380 @@
+package quizzes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the quiz store for a database logic
+type HandleRequest struct {
+	db types.QuizStore
+	grades types.GradeStore
+	subjects types.SubjectStore
+	academics types.AcademicStore
+}
+
+//func that declare the handler for the question banks and the quizzes
+func NewHandlerQuiz(db types.QuizStore, grades types.GradeStore, subjects types.SubjectStore, academics types.AcademicStore) *HandleRequest {
+	return &HandleRequest{
+		db: db,
+		grades: grades,
+		subjects: subjects,
+		academics: academics,
+	}
+}
+
+//helper for the term query params, the default is the open term
+func (h *HandleRequest) termParam(ctx context.Context, r *http.Request) (uuid.UUID, error) {
+	if value := r.URL.Query().Get("term_id"); value != "" {
+		return uuid.Parse(value)
+	}
+	term, err := h.academics.GetActiveTerm(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if term == nil {
+		return uuid.Nil, fmt.Errorf("there is no open term")
+	}
+	return term.Id, nil
+}
+
+//helper to check that the term is not closed
+func (h *HandleRequest) termNotClosed(ctx context.Context, w http.ResponseWriter, termId uuid.UUID) bool {
+	term, err := h.academics.GetTermById(ctx, termId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return false
+	}
+	if term == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The term is not exist!", false)
+		return false
+	}
+	if term.Status == "closed" {
+		utils.ResponseError(w, http.StatusBadRequest, "The quiz of a closed term cannot be changed!", false)
+		return false
+	}
+	return true
+}
+
+//helper to allow only the guru and the admin, the question banks have the correct answers
+func staffOnly(w http.ResponseWriter, r *http.Request) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check if the user can change the question bank, the admin can change every bank and the guru
+//only the bank that they created
+func canEditBank(w http.ResponseWriter, r *http.Request, bank *types.QuestionBank) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role == "admin" {
+		return true
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if role != "guru" || err != nil || bank.CreatedBy == nil || *bank.CreatedBy != user_id {
+		utils.ResponseError(w, http.StatusForbidden, "Only the admin and the guru that created the bank can change it!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check if the user can manage the quiz of the subject in the class, the admin can manage
+//every quiz and the guru only the quiz of the subject that they teach in the class
+func (h *HandleRequest) canManage(ctx context.Context, w http.ResponseWriter, r *http.Request, classId uuid.UUID, termId uuid.UUID, subjectId uuid.UUID) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role == "admin" {
+		return true
+	}
+	if role != "guru" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	allowed, err := h.subjects.CanTeach(ctx, user_id, classId, &termId, &subjectId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the teaching assignment!", err.Error())
+		return false
+	}
+	if !allowed {
+		utils.ResponseError(w, http.StatusForbidden, "The guru is not assigned to the subject of this class!", false)
+		return false
+	}
+	return true
+}
+
+//helper to get the student of the siswa of the token, nil for the guru and the admin
+func (h *HandleRequest) studentOf(ctx context.Context, w http.ResponseWriter, r *http.Request) (*uuid.UUID, bool) {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return nil, false
+	}
+	if role == "guru" || role == "admin" {
+		return nil, true
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return nil, false
+	}
+	student_id, err := h.grades.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return nil, false
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return nil, false
+	}
+	return student_id, true
+}
+
+//helper to check that the student is a member of the class of the quiz
+func (h *HandleRequest) memberOf(ctx context.Context, w http.ResponseWriter, studentId uuid.UUID, classId uuid.UUID, termId uuid.UUID) bool {
+	member, err := h.db.IsStudentOfClass(ctx, studentId, classId, termId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the class of the student!", err.Error())
+		return false
+	}
+	if !member {
+		utils.ResponseError(w, http.StatusForbidden, "The student is not a member of this class!", false)
+		return false
+	}
+	return true
+}
+
+//helper to get the question bank by the id of the parameters
+func (h *HandleRequest) bankParam(ctx context.Context, w http.ResponseWriter, r *http.Request) (*types.QuestionBank, bool) {
+	bank_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, false
+	}
+	bank, err := h.db.GetBankById(ctx, bank_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the question bank!", err.Error())
+		return nil, false
+	}
+	if bank == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The question bank is not exist!", false)
+		return nil, false
+	}
+	return bank, true
+}
+
+//helper to get the quiz by the id of the parameters
+func (h *HandleRequest) quizParam(ctx context.Context, w http.ResponseWriter, r *http.Request) (*types.Quiz, bool) {
+	quiz_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, false
+	}
+	quiz, err := h.db.GetQuizById(ctx, quiz_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the quiz!", err.Error())
+		return nil, false
+	}
+	if quiz == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The quiz is not exist!", false)
+		return nil, false
+	}
+	return quiz, true
+}
+
+//helper to get the attempt by the id of the parameters with the quiz, the siswa can only get their own
+//attempt and the guru only the attempt of the quiz that they manage
+func (h *HandleRequest) attemptParam(ctx context.Context, w http.ResponseWriter, r *http.Request) (*types.QuizAttempt, *types.Quiz, *uuid.UUID, bool) {
+	attempt_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return nil, nil, nil, false
+	}
+	attempt, err := h.db.GetAttemptById(ctx, attempt_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attempt!", err.Error())
+		return nil, nil, nil, false
+	}
+	if attempt == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The attempt is not exist!", false)
+		return nil, nil, nil, false
+	}
+	quiz, err := h.db.GetQuizById(ctx, attempt.QuizId)
+	if err != nil || quiz == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the quiz!", false)
+		return nil, nil, nil, false
+	}
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	if student_id != nil && *student_id != attempt.StudentId {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return nil, nil, nil, false
+	}
+	if student_id == nil && !h.canManage(ctx, w, r, quiz.ClassId, quiz.TermId, quiz.SubjectId) {
+		return nil, nil, nil, false
+	}
+	return attempt, quiz, student_id, true
+}
+
+//helper to respond the error of the store, the closed attempt, the used question and the attempt over
+//the limit are a conflict
+func storeError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, ErrAttemptClosed) || errors.Is(err, ErrNoAttemptsLeft) || errors.Is(err, ErrQuestionInUse) {
+		utils.ResponseError(w, http.StatusConflict, message, err.Error())
+		return
+	}
+	utils.ResponseError(w, http.StatusBadRequest, message, err.Error())
+}
+
+//helper to finish the attempt with the auto scores, the attempt is graded unless an essay waits for the
+//manual grading
+func (h *HandleRequest) finalize(ctx context.Context, attempt *types.QuizAttempt, now time.Time) error {
+
+	questions, err := h.db.GetQuizQuestions(ctx, attempt.QuizId)
+	if err != nil {
+		return err
+	}
+	answers, err := h.db.GetAnswers(ctx, attempt.Id)
+	if err != nil {
+		return err
+	}
+	byAnswer := make(map[uuid.UUID]*types.AttemptAnswer, len(answers))
+	for i := range answers {
+		byAnswer[answers[i].QuestionId] = &answers[i]
+	}
+
+	//the auto score of every question
+	scores := make(map[uuid.UUID]*float64, len(questions))
+	scored := make([]types.AttemptAnswer, 0, len(questions))
+	for _, question := range questions {
+		auto := AutoScore(question, byAnswer[question.Id])
+		scores[question.Id] = auto
+		answer := types.AttemptAnswer{AttemptId: attempt.Id, QuestionId: question.Id, AutoScore: auto}
+		if saved, ok := byAnswer[question.Id]; ok {
+			answer = *saved
+			answer.AutoScore = auto
+		}
+		scored = append(scored, answer)
+	}
+	total, pending := TotalScore(questions, scored)
+	status := types.AttemptGraded
+	if pending {
+		status = types.AttemptSubmitted
+	}
+
+	//execute the query
+	if err := h.db.FinishAttempt(ctx, attempt.Id, scores, total, status, now); err != nil {
+		return err
+	}
+	attempt.Status = status
+	attempt.Score = &total
+	attempt.SubmittedAt = &now
+
+	return nil
+
+}
+
+//helper to finish the attempts of the quiz that are over the deadline, the attempt that is finished at
+//the same time by the submit of the student is skipped
+func (h *HandleRequest) finalizeExpired(ctx context.Context, quizId uuid.UUID, studentId *uuid.UUID) error {
+	attempts, err := h.db.GetAttempts(ctx, quizId, studentId)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for i := range attempts {
+		if attempts[i].Status != types.AttemptInProgress || !Expired(now, attempts[i].DeadlineAt) {
+			continue
+		}
+		if err := h.finalize(ctx, &attempts[i], now); err != nil && !errors.Is(err, ErrAttemptClosed) {
+			return err
+		}
+	}
+	return nil
+}
+
+//helper to attach the questions in the order of the attempt with the saved answers
+func (h *HandleRequest) arrange(ctx context.Context, attempt *types.QuizAttempt, quiz *types.Quiz) error {
+	questions, err := h.db.GetQuizQuestions(ctx, quiz.Id)
+	if err != nil {
+		return err
+	}
+	answers, err := h.db.GetAnswers(ctx, attempt.Id)
+	if err != nil {
+		return err
+	}
+	attempt.Questions = ArrangeAttempt(questions, answers, attempt.Seed, quiz.ShuffleQuestions, quiz.ShuffleOptions)
+	return nil
+}
+
+//func to create a new question bank of the subject
+func (h *HandleRequest) CreateBank_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the guru and the admin can create the question bank
+	if !staffOnly(w, r) {
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateQuestionBank
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//check the subject of the bank
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	subject, err := h.subjects.GetSubjectById(ctx, payload.SubjectId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the subject!", err.Error())
+		return 
+	}
+	if subject == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The subject is not exist!", false)
+		return 
+	}
+
+	//execute the query
+	now := time.Now().UTC()
+	var created_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		created_by = &user_id
+	}
+	bank := types.QuestionBank{
+		Id: uuid.New(),
+		SubjectId: payload.SubjectId,
+		Name: payload.Name,
+		Description: payload.Description,
+		CreatedBy: created_by,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if err := h.db.CreateBank(ctx, &bank); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the question bank", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the question bank!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the question bank has been successfully", bank)
+
+}
+
+//func to get the question banks (?subject_id=)
+func (h *HandleRequest) GetBanks_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the guru and the admin can see the question banks
+	if !staffOnly(w, r) {
+		return 
+	}
+	var subject_id *uuid.UUID
+	if value := r.URL.Query().Get("subject_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+			return 
+		}
+		subject_id = &id
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	banks, err := h.db.GetBanks(ctx, subject_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the question banks", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the question banks!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the question banks has been successfully", banks)
+
+}
+
+//func to create the question in the bank, the options must match the type of the question
+func (h *HandleRequest) CreateQuestion_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateQuestion
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	if err := ValidateQuestion(payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", err.Error())
+		return 
+	}
+
+	//only the admin and the guru of the bank can add the question
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	bank, ok := h.bankParam(ctx, w, r)
+	if !ok || !canEditBank(w, r, bank) {
+		return 
+	}
+
+	//execute the query
+	now := time.Now().UTC()
+	points := payload.Points
+	if points == 0 {
+		points = 1
+	}
+	question := types.Question{
+		Id: uuid.New(),
+		BankId: bank.Id,
+		Type: payload.Type,
+		Prompt: payload.Prompt,
+		Points: points,
+		Options: []types.QuestionOption{},
+		Created_at: now,
+		Updated_at: now,
+	}
+	for i, option := range payload.Options {
+		question.Options = append(question.Options, types.QuestionOption{Text: option.Text, IsCorrect: option.Correct, Position: i})
+	}
+	if err := h.db.CreateQuestion(ctx, &question); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the question", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the question!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the question has been successfully", question)
+
+}
+
+//func to get the questions of the bank with the correct answers
+func (h *HandleRequest) GetQuestions_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the guru and the admin can see the questions
+	if !staffOnly(w, r) {
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	bank, ok := h.bankParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+
+	//execute the query
+	questions, err := h.db.GetQuestions(ctx, bank.Id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the questions", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the questions!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the questions has been successfully", questions)
+
+}
+
+//func to delete the question from the bank, the question of a quiz cannot be deleted
+func (h *HandleRequest) DeleteQuestion_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	question_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//get the question and the bank
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	question, err := h.db.GetQuestionById(ctx, question_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the question!", err.Error())
+		return 
+	}
+	if question == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The question is not exist!", false)
+		return 
+	}
+	bank, err := h.db.GetBankById(ctx, question.BankId)
+	if err != nil || bank == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the question bank!", false)
+		return 
+	}
+	if !canEditBank(w, r, bank) {
+		return 
+	}
+
+	//execute the query
+	if err := h.db.DeleteQuestion(ctx, question.Id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the question", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to delete the question!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the question has been successfully", question.Id)
+
+}
+
+//func to create the quiz of the subject in the class (?term_id=) from the questions of the banks of the subject
+func (h *HandleRequest) CreateQuiz_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	subject_id, err := uuid.Parse(mux.Vars(r)["subject_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateQuiz
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the admin and the assigned guru can create the quiz
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	if !h.canManage(ctx, w, r, class_id, term_id, subject_id) || !h.termNotClosed(ctx, w, term_id) {
+		return 
+	}
+
+	//execute the query
+	now := time.Now().UTC()
+	var created_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		created_by = &user_id
+	}
+	quiz := types.Quiz{
+		Id: uuid.New(),
+		SubjectId: subject_id,
+		ClassId: class_id,
+		TermId: term_id,
+		Title: payload.Title,
+		TimeLimitMinutes: payload.TimeLimitMinutes,
+		OpensAt: payload.OpensAt.UTC(),
+		ClosesAt: payload.ClosesAt.UTC(),
+		ShuffleQuestions: true,
+		ShuffleOptions: true,
+		MaxAttempts: 1,
+		CreatedBy: created_by,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if payload.ShuffleQuestions != nil {
+		quiz.ShuffleQuestions = *payload.ShuffleQuestions
+	}
+	if payload.ShuffleOptions != nil {
+		quiz.ShuffleOptions = *payload.ShuffleOptions
+	}
+	if payload.MaxAttempts > 0 {
+		quiz.MaxAttempts = payload.MaxAttempts
+	}
+	if err := h.db.CreateQuiz(ctx, &quiz, payload.QuestionIds); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to create the quiz", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the quiz!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Create the quiz has been successfully", quiz)
+
+}
+
+//func to get the quizzes of the class in the term (?term_id=)
+func (h *HandleRequest) GetClassQuizzes_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//the siswa must be a member of the class
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id != nil && !h.memberOf(ctx, w, *student_id, class_id, term_id) {
+		return 
+	}
+
+	//execute the query
+	quizzes, err := h.db.GetQuizzes(ctx, class_id, term_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the quizzes", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the quizzes!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the quizzes has been successfully", quizzes)
+
+}
+
+//func to get the quiz by the id, the staff get the questions with the correct answers and the siswa of
+//the class get their own attempts
+func (h *HandleRequest) GetQuiz_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the quiz
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	quiz, ok := h.quizParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	detail := types.QuizDetail{Quiz: *quiz}
+
+	//the attempts of the siswa, the attempt over the deadline is finished first
+	if student_id != nil {
+		if !h.memberOf(ctx, w, *student_id, quiz.ClassId, quiz.TermId) {
+			return 
+		}
+		if err := h.finalizeExpired(ctx, quiz.Id, student_id); err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to finish the attempts!", err.Error())
+			return 
+		}
+		attempts, err := h.db.GetAttempts(ctx, quiz.Id, student_id)
+		if err != nil {
+			//logger if the response is failed
+			logger.Log.Error("Failed to get the attempts", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attempts!", err.Error())
+			return 
+		}
+		detail.Attempts = attempts
+		utils.ResponseSuccess(w, http.StatusOK, "Get the quiz has been successfully", detail)
+		return 
+	}
+
+	//the questions for the admin and the assigned guru
+	if !h.canManage(ctx, w, r, quiz.ClassId, quiz.TermId, quiz.SubjectId) {
+		return 
+	}
+	questions, err := h.db.GetQuizQuestions(ctx, quiz.Id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the questions of the quiz", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the questions of the quiz!", err.Error())
+		return 
+	}
+	detail.Items = questions
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the quiz has been successfully", detail)
+
+}
+
+//func to start the attempt of the quiz by the siswa of the token, the attempt in progress is resumed and
+//the deadline is the time limit from the start but never after the quiz closes
+func (h *HandleRequest) StartAttempt_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the quiz, only the siswa of the class can start it
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	quiz, ok := h.quizParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	student_id, ok := h.studentOf(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusForbidden, "Only the siswa can start the quiz!", false)
+		return 
+	}
+	if !h.memberOf(ctx, w, *student_id, quiz.ClassId, quiz.TermId) {
+		return 
+	}
+
+	//resume the attempt in progress, the attempt over the deadline is finished first
+	now := time.Now().UTC()
+	open, err := h.db.GetOpenAttempt(ctx, quiz.Id, *student_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attempt in progress!", err.Error())
+		return 
+	}
+	if open != nil && !Expired(now, open.DeadlineAt) {
+		if err := h.arrange(ctx, open, quiz); err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the questions of the attempt!", err.Error())
+			return 
+		}
+		utils.ResponseSuccess(w, http.StatusOK, "Resume the attempt has been successfully", open)
+		return 
+	}
+	if open != nil {
+		if err := h.finalize(ctx, open, now); err != nil && !errors.Is(err, ErrAttemptClosed) {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to finish the attempt!", err.Error())
+			return 
+		}
+	}
+	if now.Before(quiz.OpensAt) || !now.Before(quiz.ClosesAt) {
+		utils.ResponseError(w, http.StatusBadRequest, "The quiz is not open!", false)
+		return 
+	}
+
+	//execute the query
+	attempt := types.QuizAttempt{
+		Id: uuid.New(),
+		QuizId: quiz.Id,
+		StudentId: *student_id,
+		Seed: rand.Int63(),
+		StartedAt: now,
+		DeadlineAt: Deadline(now, quiz.TimeLimitMinutes, quiz.ClosesAt),
+		Status: types.AttemptInProgress,
+		MaxScore: quiz.MaxScore,
+		Created_at: now,
+		Updated_at: now,
+	}
+	if err := h.db.StartAttempt(ctx, &attempt, quiz.MaxAttempts); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to start the attempt", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to start the attempt!", err)
+		return 
+	}
+	if err := h.arrange(ctx, &attempt, quiz); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the questions of the attempt!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusCreated, "Start the attempt has been successfully", attempt)
+
+}
+
+//func to get every attempt of the quiz, the attempts over the deadline are finished first
+func (h *HandleRequest) GetAttempts_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can see the attempts
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	quiz, ok := h.quizParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if !h.canManage(ctx, w, r, quiz.ClassId, quiz.TermId, quiz.SubjectId) {
+		return 
+	}
+	if err := h.finalizeExpired(ctx, quiz.Id, nil); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to finish the attempts!", err.Error())
+		return 
+	}
+
+	//execute the query
+	attempts, err := h.db.GetAttempts(ctx, quiz.Id, nil)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the attempts", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the attempts!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the attempts has been successfully", attempts)
+
+}
+
+//func to get the essay answers of the quiz that wait for the manual grading
+func (h *HandleRequest) GradingQueue_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin and the assigned guru can grade the quiz
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	quiz, ok := h.quizParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if !h.canManage(ctx, w, r, quiz.ClassId, quiz.TermId, quiz.SubjectId) {
+		return 
+	}
+	if err := h.finalizeExpired(ctx, quiz.Id, nil); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to finish the attempts!", err.Error())
+		return 
+	}
+
+	//execute the query
+	queue, err := h.db.GetGradingQueue(ctx, quiz.Id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the grading queue", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the grading queue!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the grading queue has been successfully", queue)
+
+}
+
+//func to get the attempt with the questions in the order of the attempt and the saved answers, the
+//correct answers are never shown
+func (h *HandleRequest) GetAttempt_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//get the attempt, the attempt over the deadline is finished first
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	attempt, quiz, _, ok := h.attemptParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	now := time.Now().UTC()
+	if attempt.Status == types.AttemptInProgress && Expired(now, attempt.DeadlineAt) {
+		if err := h.finalize(ctx, attempt, now); err != nil && !errors.Is(err, ErrAttemptClosed) {
+			//logger if some error is detected
+			logger.Log.Error("Failed to finish the attempt", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to finish the attempt!", err.Error())
+			return 
+		}
+	}
+	if err := h.arrange(ctx, attempt, quiz); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the questions of the attempt!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the attempt has been successfully", attempt)
+
+}
+
+//func to save the answers of the attempt by the siswa of the token, the answers after the deadline are refused
+func (h *HandleRequest) SaveAnswers_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SaveAnswers
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the siswa of the attempt can answer it
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	attempt, _, student_id, ok := h.attemptParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusForbidden, "Only the siswa of the attempt can answer it!", false)
+		return 
+	}
+
+	//execute the query
+	if err := h.db.SaveAnswers(ctx, attempt.Id, payload.Answers, time.Now().UTC()); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the answers", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to save the answers!", err)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Save the answers has been successfully", attempt.Id)
+
+}
+
+//func to submit the attempt by the siswa of the token, the objective questions are graded at once
+func (h *HandleRequest) SubmitAttempt_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the siswa of the attempt can submit it
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	attempt, quiz, student_id, ok := h.attemptParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id == nil {
+		utils.ResponseError(w, http.StatusForbidden, "Only the siswa of the attempt can submit it!", false)
+		return 
+	}
+	if attempt.Status != types.AttemptInProgress {
+		utils.ResponseError(w, http.StatusConflict, "The attempt is submitted already!", false)
+		return 
+	}
+
+	//execute the query
+	if err := h.finalize(ctx, attempt, time.Now().UTC()); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to submit the attempt", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		storeError(w, "Failed to submit the attempt!", err)
+		return 
+	}
+	if err := h.arrange(ctx, attempt, quiz); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the questions of the attempt!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Submit the attempt has been successfully", attempt)
+
+}
+
+//func to grade the answer of the submitted attempt by hand, the manual score replaces the auto score and
+//the attempt is graded when no essay is left
+func (h *HandleRequest) GradeAnswer_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	question_id, err := uuid.Parse(mux.Vars(r)["question_id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.GradeAnswer
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the admin and the assigned guru can grade the submitted attempt
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	attempt, quiz, student_id, ok := h.attemptParam(ctx, w, r)
+	if !ok {
+		return 
+	}
+	if student_id != nil {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+	if attempt.Status == types.AttemptInProgress {
+		utils.ResponseError(w, http.StatusBadRequest, "The attempt is not submitted yet!", false)
+		return 
+	}
+	questions, err := h.db.GetQuizQuestions(ctx, quiz.Id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the questions of the quiz!", err.Error())
+		return 
+	}
+	var question *types.QuizQuestion
+	for i := range questions {
+		if questions[i].Id == question_id {
+			question = &questions[i]
+		}
+	}
+	if question == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The question is not in the quiz!", false)
+		return 
+	}
+	if *payload.Score > question.Points {
+		utils.ResponseError(w, http.StatusBadRequest, fmt.Sprintf("The score cannot be more than the points of the question (%g)!", question.Points), false)
+		return 
+	}
+
+	//execute the query
+	var graded_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		graded_by = &user_id
+	}
+	if err := h.db.GradeAnswer(ctx, attempt.Id, question_id, *payload.Score, payload.Feedback, graded_by); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to grade the answer", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to grade the answer!", err.Error())
+		return 
+	}
+
+	//the new score of the attempt
+	answers, err := h.db.GetAnswers(ctx, attempt.Id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the answers!", err.Error())
+		return 
+	}
+	total, pending := TotalScore(questions, answers)
+	status := types.AttemptGraded
+	if pending {
+		status = types.AttemptSubmitted
+	}
+	if err := h.db.UpdateAttemptScore(ctx, attempt.Id, total, status); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to update the score of the attempt", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the score of the attempt!", err.Error())
+		return 
+	}
+	attempt.Score = &total
+	attempt.Status = status
+	attempt.Questions = ArrangeAttempt(questions, answers, attempt.Seed, quiz.ShuffleQuestions, quiz.ShuffleOptions)
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Grade the answer has been successfully", attempt)
+
+}
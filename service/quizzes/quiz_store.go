@@ -0,0 +1,729 @@
+package quizzes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store quiz
+type QuizStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewQuizStore(db *sqlx.DB) *QuizStore {
+	return &QuizStore{db: db}
+}
+
+//the bank with the count of the questions
+const bankSelect = `
+	SELECT b.id, b.subject_id, b.name, b.description, b.created_by, b.created_at, b.updated_at,
+	(SELECT COUNT(*) FROM questions q WHERE q.bank_id = b.id) AS questions
+	FROM question_banks b
+`
+
+//the quiz with the count of the questions and the max score
+const quizSelect = `
+	SELECT qz.id, qz.subject_id, qz.class_id, qz.term_id, qz.title, qz.time_limit_minutes, qz.opens_at, qz.closes_at,
+	qz.shuffle_questions, qz.shuffle_options, qz.max_attempts, qz.created_by, qz.created_at, qz.updated_at,
+	(SELECT COUNT(*) FROM quiz_questions qq WHERE qq.quiz_id = qz.id) AS questions,
+	(SELECT COALESCE(SUM(q.points), 0) FROM quiz_questions qq JOIN questions q ON q.id = qq.question_id WHERE qq.quiz_id = qz.id) AS max_score
+	FROM quizzes qz
+`
+
+//the attempt with the name of the student
+const attemptSelect = `
+	SELECT qa.id, qa.quiz_id, qa.student_id, s.name AS student_name, qa.attempt_no, qa.seed, qa.started_at, qa.deadline_at,
+	qa.submitted_at, qa.status, qa.score, qa.max_score, qa.created_at, qa.updated_at
+	FROM quiz_attempts qa
+	JOIN students s ON s.id = qa.student_id
+`
+
+//the row of the answer, the selected options are the array of the uuid
+type answerRow struct {
+	AttemptId 		uuid.UUID 		`db:"attempt_id"`
+	QuestionId 		uuid.UUID 		`db:"question_id"`
+	SelectedOptions pq.StringArray 	`db:"selected_options"`
+	TextAnswer 		string 			`db:"text_answer"`
+	AutoScore 		*float64 		`db:"auto_score"`
+	ManualScore 	*float64 		`db:"manual_score"`
+	Feedback 		string 			`db:"feedback"`
+	GradedBy 		*uuid.UUID 		`db:"graded_by"`
+	GradedAt 		*time.Time 		`db:"graded_at"`
+}
+
+//helper to convert the list of the uuid into the array of the query
+func idArray(ids []uuid.UUID) pq.StringArray {
+	array := make(pq.StringArray, 0, len(ids))
+	for _, id := range ids {
+		array = append(array, id.String())
+	}
+	return array
+}
+
+//helper to attach the options into the questions in the order of the position
+func (s *QuizStore) attachOptions(ctx context.Context, questions []*types.Question) error {
+
+	if len(questions) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(questions))
+	index := make(map[uuid.UUID]*types.Question, len(questions))
+	for _, question := range questions {
+		question.Options = []types.QuestionOption{}
+		ids = append(ids, question.Id)
+		index[question.Id] = question
+	}
+
+	//execute the query
+	options := []types.QuestionOption{}
+	if err := s.db.SelectContext(ctx, &options, `
+		SELECT id, question_id, text, is_correct, position FROM question_options
+		WHERE question_id = ANY($1::uuid[]) ORDER BY position;
+	`, idArray(ids)); err != nil {
+		return fmt.Errorf("failed to get the options of the questions: %w", err)
+	}
+	for _, option := range options {
+		question := index[option.QuestionId]
+		question.Options = append(question.Options, option)
+	}
+
+	return nil
+
+}
+
+//func to create a new question bank
+func (s *QuizStore) CreateBank(ctx context.Context, bank *types.QuestionBank) error {
+
+	//base query
+	query := `
+		INSERT INTO question_banks (id, subject_id, name, description, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		bank.Id,
+		bank.SubjectId,
+		bank.Name,
+		bank.Description,
+		bank.CreatedBy,
+		bank.Created_at,
+		bank.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new question bank! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the question bank by id, nil when the bank is not exist
+func (s *QuizStore) GetBankById(ctx context.Context, id uuid.UUID) (*types.QuestionBank, error) {
+
+	//execute the query
+	var bank types.QuestionBank
+	if err := s.db.GetContext(ctx, &bank, bankSelect+` WHERE b.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the question bank: %w", err)
+	}
+
+	return &bank, nil
+
+}
+
+//func to get the question banks, every bank when the subject id is nil
+func (s *QuizStore) GetBanks(ctx context.Context, subjectId *uuid.UUID) ([]types.QuestionBank, error) {
+
+	//execute the query
+	banks := []types.QuestionBank{}
+	if err := s.db.SelectContext(ctx, &banks, bankSelect+`
+		WHERE ($1::uuid IS NULL OR b.subject_id = $1) ORDER BY b.name;
+	`, subjectId); err != nil {
+		return nil, fmt.Errorf("failed to get the question banks: %w", err)
+	}
+
+	return banks, nil
+
+}
+
+//func to create the question with the options
+func (s *QuizStore) CreateQuestion(ctx context.Context, question *types.Question) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//save the question
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO questions (id, bank_id, type, prompt, points, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`, question.Id, question.BankId, question.Type, question.Prompt, question.Points, question.Created_at, question.Updated_at); err != nil {
+		return errors.New("Failed to create a new question! " + err.Error())
+	}
+
+	//save the options
+	for i := range question.Options {
+		option := &question.Options[i]
+		option.QuestionId = question.Id
+		if err := tx.GetContext(ctx, &option.Id, `
+			INSERT INTO question_options (question_id, text, is_correct, position)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id;
+		`, option.QuestionId, option.Text, option.IsCorrect, option.Position); err != nil {
+			return errors.New("Failed to create the option of the question! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the questions of the bank with the options
+func (s *QuizStore) GetQuestions(ctx context.Context, bankId uuid.UUID) ([]types.Question, error) {
+
+	//execute the query
+	questions := []types.Question{}
+	if err := s.db.SelectContext(ctx, &questions, `
+		SELECT id, bank_id, type, prompt, points, created_at, updated_at FROM questions
+		WHERE bank_id = $1 ORDER BY created_at;
+	`, bankId); err != nil {
+		return nil, fmt.Errorf("failed to get the questions: %w", err)
+	}
+	list := make([]*types.Question, 0, len(questions))
+	for i := range questions {
+		list = append(list, &questions[i])
+	}
+	if err := s.attachOptions(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+
+}
+
+//func to get the question by id with the options, nil when the question is not exist
+func (s *QuizStore) GetQuestionById(ctx context.Context, id uuid.UUID) (*types.Question, error) {
+
+	//execute the query
+	var question types.Question
+	if err := s.db.GetContext(ctx, &question, `
+		SELECT id, bank_id, type, prompt, points, created_at, updated_at FROM questions WHERE id = $1;
+	`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the question: %w", err)
+	}
+	if err := s.attachOptions(ctx, []*types.Question{&question}); err != nil {
+		return nil, err
+	}
+
+	return &question, nil
+
+}
+
+//func to delete the question from the bank, the question of a quiz is kept for the attempts
+func (s *QuizStore) DeleteQuestion(ctx context.Context, id uuid.UUID) error {
+
+	//check the quizzes of the question
+	var used bool
+	if err := s.db.GetContext(ctx, &used, `
+		SELECT EXISTS (SELECT 1 FROM quiz_questions WHERE question_id = $1);
+	`, id); err != nil {
+		return errors.New("Failed to check the quizzes of the question! " + err.Error())
+	}
+	if used {
+		return ErrQuestionInUse
+	}
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM questions WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the question! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The question is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to create the quiz with the questions in the order of the ids, every question must be in a bank of
+//the subject of the quiz
+func (s *QuizStore) CreateQuiz(ctx context.Context, quiz *types.Quiz, questionIds []uuid.UUID) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//save the quiz
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO quizzes
+		(id, subject_id, class_id, term_id, title, time_limit_minutes, opens_at, closes_at, shuffle_questions,
+		shuffle_options, max_attempts, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+	`,
+		quiz.Id,
+		quiz.SubjectId,
+		quiz.ClassId,
+		quiz.TermId,
+		quiz.Title,
+		quiz.TimeLimitMinutes,
+		quiz.OpensAt,
+		quiz.ClosesAt,
+		quiz.ShuffleQuestions,
+		quiz.ShuffleOptions,
+		quiz.MaxAttempts,
+		quiz.CreatedBy,
+		quiz.Created_at,
+		quiz.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new quiz! " + err.Error())
+	}
+
+	//save the questions of the quiz
+	for i, questionId := range questionIds {
+		rows, err := tx.ExecContext(ctx, `
+			INSERT INTO quiz_questions (quiz_id, question_id, position)
+			SELECT $1, q.id, $3 FROM questions q
+			JOIN question_banks b ON b.id = q.bank_id
+			WHERE q.id = $2 AND b.subject_id = $4;
+		`, quiz.Id, questionId, i, quiz.SubjectId)
+		if err != nil {
+			return errors.New("Failed to save the questions of the quiz! " + err.Error())
+		}
+		if result, err := rows.RowsAffected(); err != nil || result == 0 {
+			return errors.New("The question " + questionId.String() + " is not in a question bank of the subject!")
+		}
+	}
+
+	//the count and the max score of the quiz
+	if err := tx.QueryRowxContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(q.points), 0) FROM quiz_questions qq
+		JOIN questions q ON q.id = qq.question_id WHERE qq.quiz_id = $1;
+	`, quiz.Id).Scan(&quiz.Questions, &quiz.MaxScore); err != nil {
+		return errors.New("Failed to count the questions of the quiz! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the quiz by id, nil when the quiz is not exist
+func (s *QuizStore) GetQuizById(ctx context.Context, id uuid.UUID) (*types.Quiz, error) {
+
+	//execute the query
+	var quiz types.Quiz
+	if err := s.db.GetContext(ctx, &quiz, quizSelect+` WHERE qz.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the quiz: %w", err)
+	}
+
+	return &quiz, nil
+
+}
+
+//func to get the quizzes of the class in the term ordered by the open time
+func (s *QuizStore) GetQuizzes(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]types.Quiz, error) {
+
+	//execute the query
+	quizzes := []types.Quiz{}
+	if err := s.db.SelectContext(ctx, &quizzes, quizSelect+`
+		WHERE qz.class_id = $1 AND qz.term_id = $2 ORDER BY qz.opens_at, qz.title;
+	`, classId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the quizzes: %w", err)
+	}
+
+	return quizzes, nil
+
+}
+
+//func to get the questions of the quiz with the options in the order of the position
+func (s *QuizStore) GetQuizQuestions(ctx context.Context, quizId uuid.UUID) ([]types.QuizQuestion, error) {
+
+	//execute the query
+	questions := []types.QuizQuestion{}
+	if err := s.db.SelectContext(ctx, &questions, `
+		SELECT q.id, q.bank_id, q.type, q.prompt, q.points, q.created_at, q.updated_at, qq.position
+		FROM quiz_questions qq
+		JOIN questions q ON q.id = qq.question_id
+		WHERE qq.quiz_id = $1 ORDER BY qq.position;
+	`, quizId); err != nil {
+		return nil, fmt.Errorf("failed to get the questions of the quiz: %w", err)
+	}
+	list := make([]*types.Question, 0, len(questions))
+	for i := range questions {
+		list = append(list, &questions[i].Question)
+	}
+	if err := s.attachOptions(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+
+}
+
+//func to check if the student is a member of the class in the term, the membership of the term or the
+//active student of the class
+func (s *QuizStore) IsStudentOfClass(ctx context.Context, studentId uuid.UUID, classId uuid.UUID, termId uuid.UUID) (bool, error) {
+
+	//execute the query
+	var member bool
+	if err := s.db.GetContext(ctx, &member, `
+		SELECT EXISTS (
+			SELECT 1 FROM class_enrollments WHERE student_id = $1 AND class_id = $2 AND term_id = $3
+		) OR EXISTS (
+			SELECT 1 FROM students WHERE id = $1 AND class_id = $2 AND status = 'active'
+		);
+	`, studentId, classId, termId); err != nil {
+		return false, fmt.Errorf("failed to check the class of the student: %w", err)
+	}
+
+	return member, nil
+
+}
+
+//func to start a new attempt of the student, the attempt number is the next number and the student
+//cannot start more attempts than the max attempts of the quiz
+func (s *QuizStore) StartAttempt(ctx context.Context, attempt *types.QuizAttempt, maxAttempts int) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//count the attempts of the student
+	var count int
+	if err := tx.GetContext(ctx, &count, `
+		SELECT COUNT(*) FROM quiz_attempts WHERE quiz_id = $1 AND student_id = $2;
+	`, attempt.QuizId, attempt.StudentId); err != nil {
+		return errors.New("Failed to count the attempts of the student! " + err.Error())
+	}
+	if count >= maxAttempts {
+		return ErrNoAttemptsLeft
+	}
+	attempt.AttemptNo = count + 1
+
+	//execute the query, the unique attempt number protects the attempts that start at the same time
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO quiz_attempts
+		(id, quiz_id, student_id, attempt_no, seed, started_at, deadline_at, status, max_score, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+	`,
+		attempt.Id,
+		attempt.QuizId,
+		attempt.StudentId,
+		attempt.AttemptNo,
+		attempt.Seed,
+		attempt.StartedAt,
+		attempt.DeadlineAt,
+		attempt.Status,
+		attempt.MaxScore,
+		attempt.Created_at,
+		attempt.Updated_at,
+	); err != nil {
+		return errors.New("Failed to start the attempt! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the attempt by id, nil when the attempt is not exist
+func (s *QuizStore) GetAttemptById(ctx context.Context, id uuid.UUID) (*types.QuizAttempt, error) {
+
+	//execute the query
+	var attempt types.QuizAttempt
+	if err := s.db.GetContext(ctx, &attempt, attemptSelect+` WHERE qa.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the attempt: %w", err)
+	}
+
+	return &attempt, nil
+
+}
+
+//func to get the attempt in progress of the student, nil when the student has no attempt in progress
+func (s *QuizStore) GetOpenAttempt(ctx context.Context, quizId uuid.UUID, studentId uuid.UUID) (*types.QuizAttempt, error) {
+
+	//execute the query
+	var attempt types.QuizAttempt
+	if err := s.db.GetContext(ctx, &attempt, attemptSelect+`
+		WHERE qa.quiz_id = $1 AND qa.student_id = $2 AND qa.status = 'in_progress';
+	`, quizId, studentId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the attempt in progress: %w", err)
+	}
+
+	return &attempt, nil
+
+}
+
+//func to get the attempts of the quiz, only the attempts of the student when the student id is not nil
+func (s *QuizStore) GetAttempts(ctx context.Context, quizId uuid.UUID, studentId *uuid.UUID) ([]types.QuizAttempt, error) {
+
+	//execute the query
+	attempts := []types.QuizAttempt{}
+	if err := s.db.SelectContext(ctx, &attempts, attemptSelect+`
+		WHERE qa.quiz_id = $1 AND ($2::uuid IS NULL OR qa.student_id = $2)
+		ORDER BY s.name, qa.attempt_no;
+	`, quizId, studentId); err != nil {
+		return nil, fmt.Errorf("failed to get the attempts: %w", err)
+	}
+
+	return attempts, nil
+
+}
+
+//func to get the answers of the attempt
+func (s *QuizStore) GetAnswers(ctx context.Context, attemptId uuid.UUID) ([]types.AttemptAnswer, error) {
+
+	//execute the query
+	rows := []answerRow{}
+	if err := s.db.SelectContext(ctx, &rows, `
+		SELECT attempt_id, question_id, selected_options, text_answer, auto_score, manual_score, feedback, graded_by, graded_at
+		FROM attempt_answers WHERE attempt_id = $1;
+	`, attemptId); err != nil {
+		return nil, fmt.Errorf("failed to get the answers: %w", err)
+	}
+	answers := make([]types.AttemptAnswer, 0, len(rows))
+	for _, row := range rows {
+		selected := make([]uuid.UUID, 0, len(row.SelectedOptions))
+		for _, value := range row.SelectedOptions {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the selected option: %w", err)
+			}
+			selected = append(selected, id)
+		}
+		answers = append(answers, types.AttemptAnswer{
+			AttemptId: row.AttemptId,
+			QuestionId: row.QuestionId,
+			SelectedOptions: selected,
+			TextAnswer: row.TextAnswer,
+			AutoScore: row.AutoScore,
+			ManualScore: row.ManualScore,
+			Feedback: row.Feedback,
+			GradedBy: row.GradedBy,
+			GradedAt: row.GradedAt,
+		})
+	}
+
+	return answers, nil
+
+}
+
+//func to save the answers of the attempt, the answers are only accepted while the attempt is in progress
+//and before the deadline with the grace time
+func (s *QuizStore) SaveAnswers(ctx context.Context, attemptId uuid.UUID, answers []types.AnswerEntry, now time.Time) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//lock the attempt so the submit waits for the answers
+	var attempt struct {
+		QuizId 		uuid.UUID 	`db:"quiz_id"`
+		Status 		string 		`db:"status"`
+		DeadlineAt 	time.Time 	`db:"deadline_at"`
+	}
+	if err := tx.GetContext(ctx, &attempt, `
+		SELECT quiz_id, status, deadline_at FROM quiz_attempts WHERE id = $1 FOR UPDATE;
+	`, attemptId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("The attempt is not exist!")
+		}
+		return errors.New("Failed to get the attempt! " + err.Error())
+	}
+	if attempt.Status != types.AttemptInProgress || Expired(now, attempt.DeadlineAt) {
+		return ErrAttemptClosed
+	}
+
+	//the questions of the quiz
+	questionIds := []uuid.UUID{}
+	if err := tx.SelectContext(ctx, &questionIds, `
+		SELECT question_id FROM quiz_questions WHERE quiz_id = $1;
+	`, attempt.QuizId); err != nil {
+		return errors.New("Failed to get the questions of the quiz! " + err.Error())
+	}
+	inQuiz := make(map[uuid.UUID]bool, len(questionIds))
+	for _, id := range questionIds {
+		inQuiz[id] = true
+	}
+
+	//execute the query
+	for _, answer := range answers {
+		if !inQuiz[answer.QuestionId] {
+			return errors.New("The question " + answer.QuestionId.String() + " is not in the quiz!")
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO attempt_answers (attempt_id, question_id, selected_options, text_answer, updated_at)
+			VALUES ($1, $2, $3::uuid[], $4, $5)
+			ON CONFLICT (attempt_id, question_id) DO UPDATE
+			SET selected_options = EXCLUDED.selected_options, text_answer = EXCLUDED.text_answer, updated_at = EXCLUDED.updated_at;
+		`, attemptId, answer.QuestionId, idArray(answer.SelectedOptions), answer.Text, now); err != nil {
+			return errors.New("Failed to save the answer! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to finish the attempt in progress with the auto scores of every question, the question without
+//the answer gets the answer row with the score so the attempt has a row for every question
+func (s *QuizStore) FinishAttempt(ctx context.Context, attemptId uuid.UUID, scores map[uuid.UUID]*float64, score float64, status string, now time.Time) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//finish the attempt, the attempt that is finished already is not changed
+	rows, err := tx.ExecContext(ctx, `
+		UPDATE quiz_attempts SET status = $1, score = $2, submitted_at = $3, updated_at = $3
+		WHERE id = $4 AND status = 'in_progress';
+	`, status, score, now, attemptId)
+	if err != nil {
+		return errors.New("Failed to finish the attempt! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return ErrAttemptClosed
+	}
+
+	//save the auto scores
+	for questionId, auto := range scores {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO attempt_answers (attempt_id, question_id, auto_score, updated_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (attempt_id, question_id) DO UPDATE
+			SET auto_score = EXCLUDED.auto_score, updated_at = EXCLUDED.updated_at;
+		`, attemptId, questionId, auto, now); err != nil {
+			return errors.New("Failed to save the score of the answer! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the essay answers of the submitted attempts of the quiz that wait for the manual grading
+func (s *QuizStore) GetGradingQueue(ctx context.Context, quizId uuid.UUID) ([]types.GradingQueueItem, error) {
+
+	//execute the query
+	queue := []types.GradingQueueItem{}
+	if err := s.db.SelectContext(ctx, &queue, `
+		SELECT aa.attempt_id, qa.student_id, s.name AS student_name, aa.question_id, q.prompt, q.points,
+		aa.text_answer, qa.submitted_at
+		FROM attempt_answers aa
+		JOIN quiz_attempts qa ON qa.id = aa.attempt_id
+		JOIN questions q ON q.id = aa.question_id
+		JOIN students s ON s.id = qa.student_id
+		WHERE qa.quiz_id = $1 AND qa.status = 'submitted' AND q.type = 'essay'
+		AND aa.auto_score IS NULL AND aa.manual_score IS NULL
+		ORDER BY qa.submitted_at, s.name;
+	`, quizId); err != nil {
+		return nil, fmt.Errorf("failed to get the grading queue: %w", err)
+	}
+
+	return queue, nil
+
+}
+
+//func to save the manual score and the feedback of the answer
+func (s *QuizStore) GradeAnswer(ctx context.Context, attemptId uuid.UUID, questionId uuid.UUID, score float64, feedback string, gradedBy *uuid.UUID) error {
+
+	//execute the query
+	now := time.Now().UTC()
+	rows, err := s.db.ExecContext(ctx, `
+		UPDATE attempt_answers SET manual_score = $1, feedback = $2, graded_by = $3, graded_at = $4, updated_at = $4
+		WHERE attempt_id = $5 AND question_id = $6;
+	`, score, feedback, gradedBy, now, attemptId, questionId)
+	if err != nil {
+		return errors.New("Failed to grade the answer! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The answer is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to update the score and the status of the finished attempt
+func (s *QuizStore) UpdateAttemptScore(ctx context.Context, attemptId uuid.UUID, score float64, status string) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `
+		UPDATE quiz_attempts SET score = $1, status = $2, updated_at = $3
+		WHERE id = $4 AND status <> 'in_progress';
+	`, score, status, time.Now().UTC(), attemptId)
+	if err != nil {
+		return errors.New("Failed to update the score of the attempt! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The finished attempt is not exist!")
+	}
+
+	return nil
+
+}
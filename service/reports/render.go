@@ -0,0 +1,199 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// RenderMeta is the identity of the generated document that is printed in the footer
+type RenderMeta struct {
+	DocumentId 		uuid.UUID
+	Version 		int
+	IssuedAt 		time.Time
+}
+
+// the names of the months for the date of the signature
+var monthNames = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// RenderReportCard writes the report card of the student as an A4 PDF with the letterhead of the template.
+// The logo is optional (png or jpeg) and the creation date of the document is the issue date, so the same
+// input always makes the same file.
+func RenderReportCard(data types.ReportCardData, template types.ReportTemplate, logo []byte, meta RenderMeta) ([]byte, error) {
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(meta.IssuedAt)
+	pdf.SetModificationDate(meta.IssuedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle(template.Title+" - "+data.StudentName, true)
+	pdf.SetMargins(20, 15, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	//the footer with the identity of the document in every page
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		footer := fmt.Sprintf("Dokumen %s versi %d", meta.DocumentId, meta.Version)
+		if template.FooterText != "" {
+			footer = template.FooterText + " - " + footer
+		}
+		pdf.CellFormat(0, 5, tr(footer), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	content := width - left - right
+
+	//the letterhead
+	top := pdf.GetY()
+	if imageType := logoType(logo); imageType != "" {
+		pdf.RegisterImageOptionsReader("logo", fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(logo))
+		pdf.ImageOptions("logo", left, top, 22, 0, false, fpdf.ImageOptions{ImageType: imageType}, 0, "")
+	}
+	if template.SchoolName != "" {
+		pdf.SetFont("Helvetica", "B", 15)
+		pdf.CellFormat(content, 8, tr(strings.ToUpper(template.SchoolName)), "", 1, "C", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, line := range []string{template.Address, template.Contact} {
+			if line != "" {
+				pdf.CellFormat(content, 5, tr(line), "", 1, "C", false, 0, "")
+			}
+		}
+		if pdf.GetY() < top+24 && logoType(logo) != "" {
+			pdf.SetY(top + 24)
+		}
+		pdf.SetLineWidth(0.6)
+		pdf.Line(left, pdf.GetY()+2, left+content, pdf.GetY()+2)
+		pdf.SetLineWidth(0.2)
+		pdf.Ln(6)
+	}
+
+	//the title and the identity of the student
+	title := template.Title
+	if title == "" {
+		title = "LAPORAN HASIL BELAJAR"
+	}
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(content, 8, tr(title), "", 1, "C", false, 0, "")
+	pdf.Ln(3)
+	pdf.SetFont("Helvetica", "", 10)
+	identity := [][2]string{
+		{"Nama Siswa", data.StudentName},
+		{"Kelas", data.ClassName},
+		{"Tahun Pelajaran", data.AcademicYear},
+		{"Semester", fmt.Sprintf("%d (%s)", data.Semester, data.TermName)},
+	}
+	for _, row := range identity {
+		pdf.CellFormat(40, 6, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(content-40, 6, tr(": "+row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	//the scores of the subjects
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(12, 7, "No", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(content-42, 7, "Mata Pelajaran", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(30, 7, "Nilai", "1", 1, "C", true, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	if len(data.Grades) == 0 {
+		pdf.CellFormat(content, 7, tr("Belum ada nilai yang dipublikasikan"), "1", 1, "C", false, 0, "")
+	}
+	for i, grade := range data.Grades {
+		pdf.CellFormat(12, 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(content-42, 7, tr(grade.SubjectName), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 7, fmt.Sprintf("%.2f", grade.Score), "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(5)
+
+	//the attendance of the term
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(content, 7, "Ketidakhadiran", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	absences := [][2]string{
+		{"Sakit", fmt.Sprintf("%d hari", data.Attendance.Sakit)},
+		{"Izin", fmt.Sprintf("%d hari", data.Attendance.Izin)},
+		{"Tanpa Keterangan", fmt.Sprintf("%d hari", data.Attendance.Alpa)},
+		{"Terlambat", fmt.Sprintf("%d kali", data.Attendance.Late)},
+	}
+	for _, row := range absences {
+		pdf.CellFormat(60, 6, row[0], "1", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 6, row[1], "1", 1, "C", false, 0, "")
+	}
+	pdf.Ln(5)
+
+	//the note of the homeroom teacher
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(content, 7, "Catatan Wali Kelas", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	note := data.Note
+	if note == "" {
+		note = "-"
+	}
+	pdf.MultiCell(content, 6, tr(note), "1", "L", false)
+	pdf.Ln(8)
+
+	//the signatures
+	place := formatDate(meta.IssuedAt)
+	if template.City != "" {
+		place = template.City + ", " + place
+	}
+	half := content / 2
+	pdf.CellFormat(half, 6, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(half, 6, tr(place), "", 1, "C", false, 0, "")
+	pdf.CellFormat(half, 6, "Kepala Sekolah", "", 0, "C", false, 0, "")
+	pdf.CellFormat(half, 6, "Wali Kelas", "", 1, "C", false, 0, "")
+	pdf.Ln(18)
+	pdf.SetFont("Helvetica", "BU", 10)
+	pdf.CellFormat(half, 6, tr(orDash(template.PrincipalName)), "", 0, "C", false, 0, "")
+	pdf.CellFormat(half, 6, tr(orDash(data.HomeroomTeacher)), "", 1, "C", false, 0, "")
+	if template.PrincipalNip != "" {
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(half, 5, tr("NIP. "+template.PrincipalNip), "", 1, "C", false, 0, "")
+	}
+
+	//write the document
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("failed to render the report card: %w", err)
+	}
+
+	return out.Bytes(), nil
+
+}
+
+//helper to get the image type of the logo for the pdf, empty when the logo is not a png or a jpeg
+func logoType(logo []byte) string {
+	if len(logo) == 0 {
+		return ""
+	}
+	switch http.DetectContentType(logo) {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg":
+		return "JPG"
+	}
+	return ""
+}
+
+//helper to write the date in the indonesian format (2 Januari 2006)
+func formatDate(date time.Time) string {
+	return fmt.Sprintf("%d %s %d", date.Day(), monthNames[date.Month()-1], date.Year())
+}
+
+//helper to print a dash for the empty name
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
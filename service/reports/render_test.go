@@ -0,0 +1,93 @@
+package reports
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func testReportData() types.ReportCardData {
+	return types.ReportCardData{
+		StudentId: uuid.New(),
+		StudentName: "Siti Aisyah",
+		ClassName: "X IPA 1",
+		HomeroomTeacher: "Budi Santoso",
+		TermName: "Semester Ganjil",
+		Semester: 1,
+		AcademicYear: "2025/2026",
+		Note: "Pertahankan prestasi, tingkatkan kehadiran tepat waktu.",
+		Grades: []types.ReportGrade{
+			{SubjectCode: "MTK", SubjectName: "Matematika", Score: 87.5},
+			{SubjectCode: "BIN", SubjectName: "Bahasa Indonesia", Score: 90},
+		},
+		Attendance: types.AttendanceSummary{Sakit: 2, Izin: 1, Late: 3},
+	}
+}
+
+func TestRenderReportCard(t *testing.T) {
+	template := types.ReportTemplate{
+		Title: "LAPORAN HASIL BELAJAR",
+		SchoolName: "SMA Negeri 1 Contoh",
+		Address: "Jl. Pendidikan No. 1",
+		City: "Bandung",
+		PrincipalName: "Dra. Sri Rahayu",
+		PrincipalNip: "196501011990032001",
+		FooterText: "Rapor resmi",
+	}
+	meta := RenderMeta{DocumentId: uuid.New(), Version: 2, IssuedAt: time.Date(2025, time.December, 19, 9, 0, 0, 0, time.UTC)}
+
+	first, err := RenderReportCard(testReportData(), template, nil, meta)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !bytes.HasPrefix(first, []byte("%PDF-")) {
+		t.Fatalf("the output is not a pdf")
+	}
+
+	//the same input makes the same document
+	second, err := RenderReportCard(testReportData(), template, nil, meta)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("the rendering should be deterministic")
+	}
+}
+
+func TestRenderReportCardWithLogo(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		img.Set(x, x, color.Black)
+	}
+	var logo bytes.Buffer
+	if err := png.Encode(&logo, img); err != nil {
+		t.Fatalf("failed to encode the logo %v", err)
+	}
+
+	template := types.ReportTemplate{SchoolName: "SMA Negeri 1 Contoh"}
+	out, err := RenderReportCard(types.ReportCardData{StudentName: "Andi"}, template, logo.Bytes(), RenderMeta{Version: 1, IssuedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatalf("the output is not a pdf")
+	}
+
+	//the unknown logo is skipped
+	if _, err := RenderReportCard(types.ReportCardData{}, template, []byte("not an image"), RenderMeta{Version: 1, IssuedAt: time.Now()}); err != nil {
+		t.Fatalf("the invalid logo should be skipped, got %v", err)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	if got := formatDate(time.Date(2025, time.August, 17, 0, 0, 0, 0, time.UTC)); got != "17 Agustus 2025" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,944 @@
+package reports
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/storage"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the report store for a database logic
+type HandleRequest struct {
+	db types.ReportStore
+	grades types.GradeStore
+	academics types.AcademicStore
+	files storage.Storage
+	urlTTL time.Duration
+	location *time.Location
+}
+
+//func that declare the handler for report cards with the storage of the documents
+func NewHandlerReport(db types.ReportStore, grades types.GradeStore, academics types.AcademicStore, files storage.Storage, cfg config.ConfigParams) *HandleRequest {
+	location, err := time.LoadLocation(cfg.SchoolTimezone)
+	if err != nil {
+		location = time.UTC
+	}
+	return &HandleRequest{
+		db: db,
+		grades: grades,
+		academics: academics,
+		files: files,
+		urlTTL: cfg.StorageURLTTL,
+		location: location,
+	}
+}
+
+//the type of the logo of the letterhead
+var logoTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+//the errors of the generation of the report card
+var (
+	errStudentNotFound = errors.New("The student or the term is not exist!")
+	errReasonRequired = errors.New("The reason is required to reissue the report card!")
+)
+
+//helper to clean the name of the student for the name of the file
+var unsafeFileName = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+//helper for the term query params, the default is the open term
+func (h *HandleRequest) termParam(ctx context.Context, r *http.Request) (uuid.UUID, error) {
+	if value := r.URL.Query().Get("term_id"); value != "" {
+		return uuid.Parse(value)
+	}
+	term, err := h.academics.GetActiveTerm(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if term == nil {
+		return uuid.Nil, fmt.Errorf("there is no open term")
+	}
+	return term.Id, nil
+}
+
+//helper to check if the user can write the report card of the student, the admin can write every report
+//card and the guru only the report cards of the students of their homeroom class
+func (h *HandleRequest) canReport(ctx context.Context, w http.ResponseWriter, r *http.Request, studentId uuid.UUID, termId uuid.UUID) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role == "admin" {
+		return true
+	}
+	if role != "guru" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	allowed, err := h.db.IsHomeroomOf(ctx, user_id, studentId, termId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to check the homeroom teacher!", err.Error())
+		return false
+	}
+	if !allowed {
+		utils.ResponseError(w, http.StatusForbidden, "Only the homeroom teacher can write the report card of the student!", false)
+		return false
+	}
+	return true
+}
+
+//helper to check if the user can read the report cards of the student, the siswa can only read their own
+func (h *HandleRequest) canRead(ctx context.Context, w http.ResponseWriter, r *http.Request, studentId uuid.UUID) bool {
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return false
+	}
+	if role == "guru" || role == "admin" {
+		return true
+	}
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return false
+	}
+	own, err := h.grades.GetStudentIdByUser(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student!", err.Error())
+		return false
+	}
+	if own == nil || *own != studentId {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return false
+	}
+	return true
+}
+
+//helper to get the template of the report card with the logo, the default template is used when the id
+//is nil and an empty letterhead when there is no template at all
+func (h *HandleRequest) loadTemplate(ctx context.Context, templateId *uuid.UUID) (*types.ReportTemplate, []byte, error) {
+
+	var template *types.ReportTemplate
+	var err error
+	if templateId != nil {
+		if template, err = h.db.GetTemplateById(ctx, *templateId); err != nil {
+			return nil, nil, err
+		}
+		if template == nil {
+			return nil, nil, errors.New("The template is not exist!")
+		}
+	} else {
+		if template, err = h.db.GetDefaultTemplate(ctx); err != nil {
+			return nil, nil, err
+		}
+		if template == nil {
+			return &types.ReportTemplate{Title: "LAPORAN HASIL BELAJAR"}, nil, nil
+		}
+	}
+
+	//read the logo from the storage
+	if template.LogoKey == nil || *template.LogoKey == "" {
+		return template, nil, nil
+	}
+	body, _, err := h.files.Get(ctx, *template.LogoKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open the logo of the template: %w", err)
+	}
+	defer body.Close()
+	logo, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the logo of the template: %w", err)
+	}
+
+	return template, logo, nil
+
+}
+
+//generated is one generated report card with the document and the name of the student
+type generated struct {
+	card *types.ReportCard
+	document []byte
+	studentName string
+}
+
+//helper to generate the next version of the report card, the pdf is saved in the storage before the
+//version is saved, so every saved version always has its file
+func (h *HandleRequest) generate(ctx context.Context, studentId uuid.UUID, termId uuid.UUID, template *types.ReportTemplate, logo []byte, reason string, generatedBy *uuid.UUID) (*generated, error) {
+
+	data, err := h.db.GetReportData(ctx, studentId, termId)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errStudentNotFound
+	}
+	version, err := h.db.NextReportVersion(ctx, studentId, termId)
+	if err != nil {
+		return nil, err
+	}
+	if version > 1 && reason == "" {
+		return nil, errReasonRequired
+	}
+
+	//render the document
+	card := &types.ReportCard{
+		Id: uuid.New(),
+		StudentId: studentId,
+		TermId: termId,
+		Version: version,
+		Reason: reason,
+		GeneratedBy: generatedBy,
+		Created_at: time.Now().UTC(),
+	}
+	if template.Id != uuid.Nil {
+		card.TemplateId = &template.Id
+	}
+	document, err := RenderReportCard(*data, *template, logo, RenderMeta{
+		DocumentId: card.Id,
+		Version: version,
+		IssuedAt: card.Created_at.In(h.location),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(document)
+	card.Checksum = hex.EncodeToString(sum[:])
+	card.Size = int64(len(document))
+	card.FileKey = storage.ContentKey("report-cards", document, ".pdf")
+
+	//save the file and the version
+	if err := storage.PutBytes(ctx, h.files, card.FileKey, document, "application/pdf"); err != nil {
+		return nil, fmt.Errorf("failed to save the report card: %w", err)
+	}
+	if err := h.db.SaveReportCard(ctx, card); err != nil {
+		return nil, err
+	}
+
+	return &generated{card: card, document: document, studentName: data.StudentName}, nil
+
+}
+
+//func to create a new template of the report card
+func (h *HandleRequest) CreateTemplate_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin can manage the templates
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateReportTemplate
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+	if payload.Title == "" {
+		payload.Title = "LAPORAN HASIL BELAJAR"
+	}
+
+	//make the struct of the template and execute the query
+	template := &types.ReportTemplate{
+		Id: uuid.New(),
+		Name: payload.Name,
+		Title: payload.Title,
+		SchoolName: payload.SchoolName,
+		Address: payload.Address,
+		Contact: payload.Contact,
+		City: payload.City,
+		PrincipalName: payload.PrincipalName,
+		PrincipalNip: payload.PrincipalNip,
+		FooterText: payload.FooterText,
+		IsDefault: payload.IsDefault,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CreateTemplate(ctx, template); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new template", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the template!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new template has been successfully", template)
+
+}
+
+//func to get all of the templates of the report card
+func (h *HandleRequest) GetTemplates_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the templates
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	templates, err := h.db.GetTemplates(ctx)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the templates", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the templates!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the templates has been successfully", templates)
+
+}
+
+//func to update the template of the report card
+func (h *HandleRequest) UpdateTemplate_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin can manage the templates
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	template_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.UpdateReportTemplate
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.UpdateTemplate(ctx, template_id, payload); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to update the template", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the template!", err.Error())
+		return 
+	}
+
+	//return a final result
+	template, err := h.db.GetTemplateById(ctx, template_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the template!", err.Error())
+		return 
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "Update the template has been successfully", template)
+
+}
+
+//func to upload the logo of the letterhead (multipart field "logo", png or jpeg up to 1 MB)
+func (h *HandleRequest) UploadTemplateLogo_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin can manage the templates
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	template_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//declare the form validaton for the size of the logo
+	r.Body = http.MaxBytesReader(w, r.Body, 1 << 20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to parse the multipart form data for a request!", err.Error())
+		return 
+	}
+	file, _, err := r.FormFile("logo")
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to detect the logo!", err.Error())
+		return 
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil || len(data) == 0 {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to read the logo file!", false)
+		return 
+	}
+	ext, ok := logoTypes[http.DetectContentType(data)]
+	if !ok {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed content file type, use png or jpeg!", false)
+		return 
+	}
+
+	//save the logo in the storage and the key in the template
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	key := storage.ContentKey("letterheads", data, ext)
+	if err := storage.PutBytes(ctx, h.files, key, data, http.DetectContentType(data)); err != nil {
+		//logger the data response if the storage is failed
+		logger.Log.Error("Failed to save the logo", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("key", key),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to save the logo!", err.Error())
+		return 
+	}
+	if err := h.db.SetTemplateLogo(ctx, template_id, key); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to set the logo of the template!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Upload the logo has been successfully", key)
+
+}
+
+//func to save the note of the homeroom teacher for the report card of the student (?term_id=)
+func (h *HandleRequest) SaveNote_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SaveReportNote
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the admin and the homeroom teacher can write the note
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	if !h.canReport(ctx, w, r, student_id, term_id) {
+		return 
+	}
+
+	//make the struct of the note and execute the query
+	note := &types.ReportNote{
+		StudentId: student_id,
+		TermId: term_id,
+		Note: payload.Note,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		note.WrittenBy = &user_id
+	}
+	if err := h.db.SaveNote(ctx, note); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to save the note", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to save the note!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Save the note has been successfully", note)
+
+}
+
+//func to generate a new version of the report card of the student (?term_id=), a reissue needs the reason
+func (h *HandleRequest) GenerateReportCard_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.GenerateReportCard
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//only the admin and the homeroom teacher can generate the report card
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 30)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	if !h.canReport(ctx, w, r, student_id, term_id) {
+		return 
+	}
+	template, logo, err := h.loadTemplate(ctx, payload.TemplateId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the template!", err.Error())
+		return 
+	}
+
+	//generate and save the report card
+	var generated_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		generated_by = &user_id
+	}
+	result, err := h.generate(ctx, student_id, term_id, template, logo, payload.Reason, generated_by)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to generate the report card", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		if errors.Is(err, errStudentNotFound) {
+			utils.ResponseError(w, http.StatusNotFound, "Failed to generate the report card!", err.Error())
+			return 
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to generate the report card!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Generate the report card has been successfully", result.card)
+
+}
+
+//func to generate the report cards of every student of the class (?term_id=) as one zip file
+func (h *HandleRequest) GenerateClassReportCards_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.GenerateReportCard
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//get the students of the class
+	ctx, cancle := context.WithTimeout(r.Context(), time.Minute * 2)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+	students, err := h.db.GetClassStudentIds(ctx, class_id, term_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the students of the class!", err.Error())
+		return 
+	}
+	if len(students) == 0 {
+		utils.ResponseError(w, http.StatusNotFound, "The class has no student in the term!", false)
+		return 
+	}
+
+	//check every student before the first document, so the batch is not stopped half way
+	for _, student_id := range students {
+		if !h.canReport(ctx, w, r, student_id, term_id) {
+			return 
+		}
+		version, err := h.db.NextReportVersion(ctx, student_id, term_id)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the version of the report card!", err.Error())
+			return 
+		}
+		if version > 1 && payload.Reason == "" {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to generate the report cards!", errReasonRequired.Error())
+			return 
+		}
+	}
+	template, logo, err := h.loadTemplate(ctx, payload.TemplateId)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the template!", err.Error())
+		return 
+	}
+
+	//generate every report card, the zip is only written when every document is saved
+	var generated_by *uuid.UUID
+	if user_id, err := middleware.GetIdMiddleware(w, r); err == nil && user_id != uuid.Nil {
+		generated_by = &user_id
+	}
+	results := make([]*generated, 0, len(students))
+	for _, student_id := range students {
+		result, err := h.generate(ctx, student_id, term_id, template, logo, payload.Reason, generated_by)
+		if err != nil {
+			//logger if some error is detected
+			logger.Log.Error("Failed to generate the report card of the class", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.String("student_id", student_id.String()),
+				zap.Error(err),
+		)
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to generate the report cards!", err.Error())
+			return 
+		}
+		results = append(results, result)
+	}
+
+	//write the zip
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="rapor-%s.zip"`, class_id))
+	w.WriteHeader(http.StatusOK)
+	archive := zip.NewWriter(w)
+	for i, result := range results {
+		name := fmt.Sprintf("%02d-%s-v%d.pdf", i+1, unsafeFileName.ReplaceAllString(result.studentName, "_"), result.card.Version)
+		entry, err := archive.Create(name)
+		if err == nil {
+			_, err = entry.Write(result.document)
+		}
+		if err != nil {
+			logger.Log.Error("Failed to write the zip of the report cards", 
+				zap.String("request_id", requestID),
+				zap.Error(err),
+		)
+			return 
+		}
+	}
+	if err := archive.Close(); err != nil {
+		logger.Log.Error("Failed to close the zip of the report cards", 
+			zap.String("request_id", requestID),
+			zap.Error(err),
+	)
+	}
+
+}
+
+//func to get the versions of the report cards of the student (?term_id= for one term)
+func (h *HandleRequest) GetReportCards_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	student_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	var term_id *uuid.UUID
+	if value := r.URL.Query().Get("term_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+			return 
+		}
+		term_id = &id
+	}
+
+	//the siswa can only see their own report cards
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if !h.canRead(ctx, w, r, student_id) {
+		return 
+	}
+
+	//execute the query
+	cards, err := h.db.GetReportCards(ctx, student_id, term_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the report cards", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the report cards!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the report cards has been successfully", cards)
+
+}
+
+//func to download one version of the report card, the client is redirected to the signed url of the file
+func (h *HandleRequest) DownloadReportCard_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	card_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//get the report card, the siswa can only download their own report card
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	card, err := h.db.GetReportCardById(ctx, card_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the report card!", err.Error())
+		return 
+	}
+	if card == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The report card is not exist!", false)
+		return 
+	}
+	if !h.canRead(ctx, w, r, card.StudentId) {
+		return 
+	}
+
+	//make the signed url and redirect the client into it
+	signed_url, err := h.files.SignedURL(ctx, card.FileKey, h.urlTTL)
+	if err != nil {
+		//logger the data response if the signed url is failed
+		logger.Log.Error("Failed to sign the url of the report card", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to make the url of the report card!", err.Error())
+		return 
+	}
+	http.Redirect(w, r, signed_url, http.StatusFound)
+
+}
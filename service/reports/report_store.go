@@ -0,0 +1,451 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/service/attendance"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store report
+type ReportStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewReportStore(db *sqlx.DB) *ReportStore {
+	return &ReportStore{db: db}
+}
+
+//the column of the template that we select in every query
+const templateColumns = `
+	id, name, title, school_name, address, contact, city, principal_name, principal_nip,
+	footer_text, logo_key, is_default, created_at, updated_at
+`
+
+//the column of the report card that we select in every query
+const reportCardColumns = `id, student_id, term_id, version, template_id, file_key, checksum, size, reason, generated_by, created_at`
+
+//func to create a new template, the new default template replaces the old default one
+func (s *ReportStore) CreateTemplate(ctx context.Context, template *types.ReportTemplate) error {
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	if template.IsDefault {
+		if _, err := tx.ExecContext(ctx, `UPDATE report_templates SET is_default = FALSE WHERE is_default;`); err != nil {
+			return errors.New("Failed to reset the default template! " + err.Error())
+		}
+	}
+
+	//base query
+	query := `
+		INSERT INTO report_templates 
+		(id, name, title, school_name, address, contact, city, principal_name, principal_nip, footer_text, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+	`
+
+	//execute the query
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		template.Id,
+		template.Name,
+		template.Title,
+		template.SchoolName,
+		template.Address,
+		template.Contact,
+		template.City,
+		template.PrincipalName,
+		template.PrincipalNip,
+		template.FooterText,
+		template.IsDefault,
+		template.Created_at,
+		template.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new template! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the template by id
+func (s *ReportStore) GetTemplateById(ctx context.Context, id uuid.UUID) (*types.ReportTemplate, error) {
+
+	//execute the query
+	var template types.ReportTemplate
+	if err := s.db.GetContext(ctx, &template, `SELECT `+templateColumns+` FROM report_templates WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the template: %w", err)
+	}
+
+	return &template, nil
+
+}
+
+//func to get the default template, nil when there is no default template
+func (s *ReportStore) GetDefaultTemplate(ctx context.Context) (*types.ReportTemplate, error) {
+
+	//execute the query
+	var template types.ReportTemplate
+	if err := s.db.GetContext(ctx, &template, `SELECT `+templateColumns+` FROM report_templates WHERE is_default;`); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the default template: %w", err)
+	}
+
+	return &template, nil
+
+}
+
+//func to get all of the templates
+func (s *ReportStore) GetTemplates(ctx context.Context) ([]types.ReportTemplate, error) {
+
+	//execute the query
+	templates := []types.ReportTemplate{}
+	if err := s.db.SelectContext(ctx, &templates, `SELECT `+templateColumns+` FROM report_templates ORDER BY is_default DESC, name;`); err != nil {
+		return nil, fmt.Errorf("failed to get the templates: %w", err)
+	}
+
+	return templates, nil
+
+}
+
+//func to update the template, only the fields that are not nil
+func (s *ReportStore) UpdateTemplate(ctx context.Context, id uuid.UUID, payload types.UpdateReportTemplate) error {
+
+	//setup the args and args id
+	var settings []string
+	argsId := 1
+	var args []interface{}
+
+	//every text field of the letterhead that is changed
+	fields := []struct {
+		column string
+		value  *string
+	}{
+		{"title", payload.Title},
+		{"school_name", payload.SchoolName},
+		{"address", payload.Address},
+		{"contact", payload.Contact},
+		{"city", payload.City},
+		{"principal_name", payload.PrincipalName},
+		{"principal_nip", payload.PrincipalNip},
+		{"footer_text", payload.FooterText},
+	}
+	for _, field := range fields {
+		if field.value != nil {
+			settings = append(settings, fmt.Sprintf("%s=$%d", field.column, argsId))
+			args = append(args, *field.value)
+			argsId++
+		}
+	}
+
+	//if the template is set as the default or not
+	if payload.IsDefault != nil {
+		settings = append(settings, fmt.Sprintf("is_default=$%d", argsId))
+		args = append(args, *payload.IsDefault)
+		argsId++
+	}
+
+	//validate if the no one field changes
+	if len(args) == 0 {
+		return errors.New("No one data changes")
+	}
+
+	//update the updated at
+	settings = append(settings, fmt.Sprintf("updated_at=$%d", argsId))
+	args = append(args, time.Now().UTC())
+	argsId++
+
+	//setup the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	if payload.IsDefault != nil && *payload.IsDefault {
+		if _, err := tx.ExecContext(ctx, `UPDATE report_templates SET is_default = FALSE WHERE is_default AND id <> $1;`, id); err != nil {
+			return errors.New("Failed to reset the default template! " + err.Error())
+		}
+	}
+
+	//define a fullquery and execute it
+	fullquery := fmt.Sprintf("UPDATE report_templates SET %s WHERE id = $%d", strings.Join(settings, ", "), argsId)
+	args = append(args, id)
+	rows, err := tx.ExecContext(ctx, fullquery, args...)
+	if err != nil {
+		return errors.New("Failed to update the template! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The template is not exist!")
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to set the storage key of the logo of the template
+func (s *ReportStore) SetTemplateLogo(ctx context.Context, id uuid.UUID, logoKey string) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `
+		UPDATE report_templates SET logo_key = $1, updated_at = $2 WHERE id = $3;
+	`, logoKey, time.Now().UTC(), id)
+	if err != nil {
+		return errors.New("Failed to set the logo of the template! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The template is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to save the note of the homeroom teacher, the old note is replaced
+func (s *ReportStore) SaveNote(ctx context.Context, note *types.ReportNote) error {
+
+	//base query
+	query := `
+		INSERT INTO report_notes (student_id, term_id, note, written_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (student_id, term_id) DO UPDATE
+		SET note = EXCLUDED.note, written_by = EXCLUDED.written_by, updated_at = EXCLUDED.updated_at;
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		note.StudentId,
+		note.TermId,
+		note.Note,
+		note.WrittenBy,
+		note.Created_at,
+		note.Updated_at,
+	); err != nil {
+		return errors.New("Failed to save the note! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to check if the guru is the homeroom teacher of the class of the student in the term
+func (s *ReportStore) IsHomeroomOf(ctx context.Context, teacherId uuid.UUID, studentId uuid.UUID, termId uuid.UUID) (bool, error) {
+
+	//base query
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM classes c
+			WHERE c.homeroom_teacher_id = $1 AND c.id = COALESCE(
+				(SELECT e.class_id FROM class_enrollments e WHERE e.student_id = $2 AND e.term_id = $3),
+				(SELECT class_id FROM students WHERE id = $2)
+			)
+		);
+	`
+
+	//execute the query
+	var allowed bool
+	if err := s.db.GetContext(ctx, &allowed, query, teacherId, studentId, termId); err != nil {
+		return false, fmt.Errorf("failed to check the homeroom teacher: %w", err)
+	}
+
+	return allowed, nil
+
+}
+
+//func to get the data of the report card, the published term scores, the attendance of the term and
+//the note of the homeroom teacher, nil when the student or the term is not exist
+func (s *ReportStore) GetReportData(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) (*types.ReportCardData, error) {
+
+	//the student with the class of the term
+	query := `
+		SELECT s.id AS student_id, s.name AS student_name, COALESCE(c.name, '') AS class_name,
+		COALESCE(u.username, '') AS homeroom_teacher, t.id AS term_id, t.name AS term_name, t.semester,
+		y.name AS academic_year, COALESCE(n.note, '') AS note
+		FROM students s
+		JOIN terms t ON t.id = $2
+		JOIN academic_years y ON y.id = t.academic_year_id
+		LEFT JOIN classes c ON c.id = COALESCE(
+			(SELECT e.class_id FROM class_enrollments e WHERE e.student_id = s.id AND e.term_id = t.id),
+			s.class_id
+		)
+		LEFT JOIN users u ON u.id = c.homeroom_teacher_id
+		LEFT JOIN report_notes n ON n.student_id = s.id AND n.term_id = t.id
+		WHERE s.id = $1;
+	`
+	var data types.ReportCardData
+	if err := s.db.GetContext(ctx, &data, query, studentId, termId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the student of the report card: %w", err)
+	}
+
+	//the published term scores
+	data.Grades = []types.ReportGrade{}
+	if err := s.db.SelectContext(ctx, &data.Grades, `
+		SELECT sb.code AS subject_code, sb.name AS subject_name, ts.score
+		FROM term_scores ts
+		JOIN subjects sb ON sb.id = ts.subject_id
+		WHERE ts.student_id = $1 AND ts.term_id = $2
+		ORDER BY sb.name;
+	`, studentId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the term scores: %w", err)
+	}
+
+	//the attendance of the term
+	counts := []types.AttendanceStatusCount{}
+	if err := s.db.SelectContext(ctx, &counts, `
+		SELECT r.student_id, s.name AS student_name, r.status, COUNT(*) AS count
+		FROM attendance_records r
+		JOIN students s ON s.id = r.student_id
+		WHERE r.student_id = $1 AND r.term_id = $2
+		GROUP BY r.student_id, s.name, r.status;
+	`, studentId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the attendance: %w", err)
+	}
+	if students, _ := attendance.Summarize(counts); len(students) > 0 {
+		data.Attendance = students[0]
+	}
+
+	return &data, nil
+
+}
+
+//func to get the students of the class in the term, the members of the term or the active students of the class
+func (s *ReportStore) GetClassStudentIds(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]uuid.UUID, error) {
+
+	//base query
+	query := `
+		SELECT s.id FROM students s
+		WHERE EXISTS (
+			SELECT 1 FROM class_enrollments e WHERE e.student_id = s.id AND e.class_id = $1 AND e.term_id = $2
+		) OR (s.status = 'active' AND s.class_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM class_enrollments e WHERE e.student_id = s.id AND e.term_id = $2
+		))
+		ORDER BY s.name;
+	`
+
+	//execute the query
+	ids := []uuid.UUID{}
+	if err := s.db.SelectContext(ctx, &ids, query, classId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the students of the class: %w", err)
+	}
+
+	return ids, nil
+
+}
+
+//func to get the next version of the report card of the student in the term
+func (s *ReportStore) NextReportVersion(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) (int, error) {
+
+	//execute the query
+	var version int
+	if err := s.db.GetContext(ctx, &version, `
+		SELECT COALESCE(MAX(version), 0) + 1 FROM report_cards WHERE student_id = $1 AND term_id = $2;
+	`, studentId, termId); err != nil {
+		return 0, fmt.Errorf("failed to get the version of the report card: %w", err)
+	}
+
+	return version, nil
+
+}
+
+//func to save the generated report card, the version is unique so two reissues at once cannot share it
+func (s *ReportStore) SaveReportCard(ctx context.Context, card *types.ReportCard) error {
+
+	//base query
+	query := `
+		INSERT INTO report_cards 
+		(id, student_id, term_id, version, template_id, file_key, checksum, size, reason, generated_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		card.Id,
+		card.StudentId,
+		card.TermId,
+		card.Version,
+		card.TemplateId,
+		card.FileKey,
+		card.Checksum,
+		card.Size,
+		card.Reason,
+		card.GeneratedBy,
+		card.Created_at,
+	); err != nil {
+		return errors.New("Failed to save the report card! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the versions of the report cards of the student, the newest first, every term when the term id is nil
+func (s *ReportStore) GetReportCards(ctx context.Context, studentId uuid.UUID, termId *uuid.UUID) ([]types.ReportCard, error) {
+
+	//base query
+	query := `
+		SELECT ` + reportCardColumns + ` FROM report_cards
+		WHERE student_id = $1 AND ($2::UUID IS NULL OR term_id = $2)
+		ORDER BY created_at DESC, version DESC;
+	`
+
+	//execute the query
+	cards := []types.ReportCard{}
+	if err := s.db.SelectContext(ctx, &cards, query, studentId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the report cards: %w", err)
+	}
+
+	return cards, nil
+
+}
+
+//func to get the report card by id
+func (s *ReportStore) GetReportCardById(ctx context.Context, id uuid.UUID) (*types.ReportCard, error) {
+
+	//execute the query
+	var card types.ReportCard
+	if err := s.db.GetContext(ctx, &card, `SELECT `+reportCardColumns+` FROM report_cards WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the report card: %w", err)
+	}
+
+	return &card, nil
+
+}
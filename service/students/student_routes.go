@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -20,11 +21,14 @@ import (
 //type handlerequest that declare the student store for a database logic
 type HandleRequest struct{
 	db types.StudentStore
+	classes types.ClassStore
+	majors types.MajorStore
+	academics types.AcademicStore
 }
 
 //func that declare the handler for student
-func NewHandlerStudent(db types.StudentStore) *HandleRequest {
-	return &HandleRequest{db: db}
+func NewHandlerStudent(db types.StudentStore, classes types.ClassStore, majors types.MajorStore, academics types.AcademicStore) *HandleRequest {
+	return &HandleRequest{db: db, classes: classes, majors: majors, academics: academics}
 }
 
 //func to create a new student
@@ -103,6 +107,86 @@ func (h *HandleRequest) RegisterAsStudent_Bp(w http.ResponseWriter, r *http.Requ
 		return 
 	}
 
+	//the class must be registered in the classes, the free-text class is only read to find it
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	var class *types.Class
+	if payload.ClassId != nil {
+		class, err = h.classes.GetClassById(ctx, *payload.ClassId)
+	} else {
+		class_name, err_parse := utils.ParseClassName(payload.Class)
+		if err_parse != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid class!", err_parse.Error())
+			return 
+		}
+		//the class is searched in the active academic year, or the calendar year when no year is active
+		academic_year := utils.AcademicYearOf(time.Now().UTC())
+		active, err_year := h.academics.GetActiveAcademicYear(ctx)
+		if err_year != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the academic year!", err_year.Error())
+			return 
+		}
+		if active != nil {
+			academic_year = active.Name
+		}
+		class, err = h.classes.GetClassByName(
+			ctx,
+			class_name.GradeLevel,
+			class_name.Major,
+			class_name.Section,
+			academic_year,
+		)
+	}
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to get the class", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the class!", err.Error())
+		return 
+	}
+	if class == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "The class is not registered yet!", false)
+		return 
+	}
+
+	//the major must be an active major of the catalogue, and the same major as the class
+	major, err := h.majors.GetMajorByCode(ctx, payload.Major)
+	if err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to get the major", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the major!", err.Error())
+		return 
+	}
+	if major == nil || !major.IsActive {
+		utils.ResponseError(w, http.StatusBadRequest, "The major is not exist or not active!", false)
+		return 
+	}
+	if class.Major != "" && !strings.EqualFold(class.Major, major.Code) {
+		utils.ResponseError(w, http.StatusBadRequest, "The major is not the same as the major of the class!", false)
+		return 
+	}
+
+	//get the user id, the user is the account of the student (or the requester when the major is full)
+	user_id, err := middleware.GetIdMiddleware(w, r)
+	if err != nil || user_id == uuid.Nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+		return 
+	}
+	registered, err := h.db.GetStudentByUserId(ctx, user_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the student of the user!", err.Error())
+		return 
+	}
+	if registered != nil {
+		utils.ResponseError(w, http.StatusConflict, "The user has been already registered as a student!", false)
+		return 
+	}
+
 	//define the time updated and created response
 	time_updated_format := time.Now().UTC().Format("2006-01-02")
 	time_created_format := time.Now().UTC().Format("2006-01-02")
@@ -111,20 +195,21 @@ func (h *HandleRequest) RegisterAsStudent_Bp(w http.ResponseWriter, r *http.Requ
 	students_payload := &types.Student{
 		Id: uuid.New(),
 		Name: payload.Name,
-		Class: payload.Class,
+		Class: class.Name,
+		ClassId: &class.Id,
 		Address: payload.Address,
-		Major: payload.Major,
+		Major: major.Code,
+		MajorId: &major.Id,
+		AcademicYear: &class.AcademicYear,
+		UserId: &user_id,
 		StudentProfile: payload.StudentProfile,
-		Created_at: payload.Created_at,
-		Updated_at: payload.Updated_at,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
 	}
 
-	//declare the context to user
-	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
-	defer cancle()
-
-	//execute the query of the create user
-	if err := h.db.CreateNewStudent(ctx, students_payload); err != nil {
+	//execute the query of the create user, the registration goes into the waitlist if the major is full
+	waitlist, err := h.majors.EnrollStudent(ctx, students_payload, user_id)
+	if err != nil {
 		//logger if some error is detected when we want to create it
 		logger.Log.Error("Failed to create a new student", 
 			zap.String("request_id", requestID),
@@ -133,12 +218,17 @@ func (h *HandleRequest) RegisterAsStudent_Bp(w http.ResponseWriter, r *http.Requ
 		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the students data!", err.Error())
 		return 
 	}
+	if waitlist != nil {
+		utils.ResponseError(w, http.StatusConflict, "The major is full, the registration has been put into the waitlist!", waitlist)
+		return 
+	}
 
 	//make the response of the students data
 	students_response := types.StudentResponse{
 		Id: students_payload.Id,
 		Name: students_payload.Name,
 		Class: students_payload.Class,
+		ClassId: students_payload.ClassId,
 		Address: students_payload.Address,
 		Major: students_payload.Major,
 		StudentProfile: students_payload.StudentProfile,
@@ -175,7 +265,7 @@ func (h *HandleRequest) GetAll_Bp(w http.ResponseWriter, r *http.Request) {
 	)
 		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the role middleware!", err.Error())
 	}
-	if role_user == "siswa" {
+	if role_user != "guru" && role_user != "admin" {
 		utils.ResponseError(w, http.StatusBadRequest, "Failed to access this method!", false)
 		return 
 	}
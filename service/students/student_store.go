@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
 	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
@@ -89,9 +90,9 @@ func (s *StudentStore) CreateNewStudent(ctx context.Context, student *types.Stud
 	//make the base query for create a new student
 	query := `
 		INSERT INTO students 
-		(id, name, class, address, major, student_profile, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING *;
+		(id, name, class, class_id, address, major, major_id, academic_year, user_id, student_profile, created_at, updated_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, name, class, class_id, address, major, major_id, academic_year, user_id, student_profile, created_at, updated_at;
 	`
 
 	//make the method query
@@ -101,8 +102,12 @@ func (s *StudentStore) CreateNewStudent(ctx context.Context, student *types.Stud
 		student.Id,
 		student.Name,
 		student.Class,
+		student.ClassId,
 		student.Address,
 		student.Major,
+		student.MajorId,
+		student.AcademicYear,
+		student.UserId,
 		student.StudentProfile,
 		student.Created_at,
 		student.Updated_at,
@@ -110,8 +115,12 @@ func (s *StudentStore) CreateNewStudent(ctx context.Context, student *types.Stud
 		&student.Id,
 		&student.Name,
 		&student.Class,
+		&student.ClassId,
 		&student.Address,
 		&student.Major,
+		&student.MajorId,
+		&student.AcademicYear,
+		&student.UserId,
 		&student.StudentProfile,
 		&student.Created_at,
 		&student.Updated_at,
@@ -151,6 +160,28 @@ func (s *StudentStore) GetStudentByName(name string) (*types.Student, error) {
 
 }
 
+//func to get the student of the user account
+func (s *StudentStore) GetStudentByUserId(ctx context.Context, userId uuid.UUID) (*types.Student, error) {
+
+	//make base query
+	query := `
+		SELECT id, name, class, class_id, address, major, major_id, academic_year, user_id, student_profile, created_at, updated_at
+		FROM students WHERE user_id = $1;
+	`
+
+	//make the method of query
+	var student types.Student
+	if err := s.db.GetContext(ctx, &student, query, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the student of the user: %w", err)
+	}
+
+	return &student, nil
+
+}
+
 //get all student from db
 func (s *StudentStore) GetAllStudents(
 	ctx context.Context,
@@ -0,0 +1,644 @@
+package subjects
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the subject store for a database logic
+type HandleRequest struct {
+	db types.SubjectStore
+	academics types.AcademicStore
+}
+
+//func that declare the handler for subject
+func NewHandlerSubject(db types.SubjectStore, academics types.AcademicStore) *HandleRequest {
+	return &HandleRequest{db: db, academics: academics}
+}
+
+//helper to read an optional uuid of the query params
+func uuidParam(r *http.Request, name string) (*uuid.UUID, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return &id, nil
+}
+
+//func to create a new subject
+func (h *HandleRequest) CreateSubject_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the subjects
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateSubject
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//the code is saved in upper case
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	code := strings.ToUpper(payload.Code)
+	exist, err := h.db.GetSubjectByCode(ctx, code)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the subject!", err.Error())
+		return 
+	}
+	if exist != nil {
+		utils.ResponseError(w, http.StatusConflict, "The code of the subject has been already exist!", false)
+		return 
+	}
+
+	//make the struct of the subject and execute the query
+	subject := &types.Subject{
+		Id: uuid.New(),
+		Code: code,
+		Name: payload.Name,
+		Description: payload.Description,
+		Kkm: payload.Kkm,
+		IsActive: true,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	if err := h.db.CreateSubject(ctx, subject); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new subject", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the subject!", err.Error())
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new subject has been successfully", subject)
+
+}
+
+//func to get all the subjects (?active=true)
+func (h *HandleRequest) GetAllSubjects_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	subjects, err := h.db.GetAllSubjects(ctx, r.URL.Query().Get("active") == "true")
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get all the subjects", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the subjects!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get all subjects has been successfully", subjects)
+
+}
+
+//func to get the subject with the weekly hours of every grade
+func (h *HandleRequest) GetSubject_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	subject_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	subject, err := h.db.GetSubjectById(ctx, subject_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the subject!", err.Error())
+		return 
+	}
+	if subject == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The subject is not exist!", false)
+		return 
+	}
+	hours, err := h.db.GetSubjectHours(ctx, subject_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the hours of the subject!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the subject has been successfully", map[string]interface{}{
+		"subject": subject,
+		"hours": hours,
+	})
+
+}
+
+//func to update the subject (name, description, active flag)
+func (h *HandleRequest) UpdateSubject_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the subjects
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	subject_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.UpdateSubject
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.UpdateSubject(ctx, subject_id, payload); err != nil {
+		//logger if the update is failed
+		logger.Log.Error("Failed to update the subject", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to update the subject!", err.Error())
+		return 
+	}
+	subject, err := h.db.GetSubjectById(ctx, subject_id)
+	if err != nil || subject == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the subject!", false)
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Update the subject has been successfully", subject)
+
+}
+
+//func to replace the weekly hours of the subject for every grade and major
+func (h *HandleRequest) SetSubjectHours_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the subjects
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	subject_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.SetSubjectHours
+	if err := utils.DecodeData(r, &payload); err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//the same grade and major cannot be written twice
+	seen := make(map[string]bool, len(payload.Hours))
+	for _, entry := range payload.Hours {
+		key := fmt.Sprintf("%d|%s", entry.GradeLevel, strings.ToLower(strings.TrimSpace(entry.Major)))
+		if seen[key] {
+			utils.ResponseError(w, http.StatusBadRequest, "The grade and the major is written twice!", key)
+			return 
+		}
+		seen[key] = true
+	}
+
+	//validate the subject
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	subject, err := h.db.GetSubjectById(ctx, subject_id)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the subject!", err.Error())
+		return 
+	}
+	if subject == nil {
+		utils.ResponseError(w, http.StatusNotFound, "The subject is not exist!", false)
+		return 
+	}
+
+	//execute the query
+	hours, err := h.db.SetSubjectHours(ctx, subject_id, payload.Hours)
+	if err != nil {
+		//logger if the hours are failed
+		logger.Log.Error("Failed to set the hours of the subject", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to set the hours of the subject!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Set the hours of the subject has been successfully", hours)
+
+}
+
+//func to get the subjects of a grade with the weekly hours (?grade_level=10&major=IPA)
+func (h *HandleRequest) Curriculum_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//the grade level is required
+	grade_level, err := strconv.Atoi(r.URL.Query().Get("grade_level"))
+	if err != nil || grade_level < 1 || grade_level > 12 {
+		utils.ResponseError(w, http.StatusBadRequest, "The grade level must be between 1 and 12!", false)
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	hours, err := h.db.GetCurriculum(ctx, grade_level, r.URL.Query().Get("major"))
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the curriculum", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the curriculum!", err.Error())
+		return 
+	}
+
+	//count the total hours of the week
+	total := 0
+	for _, hour := range hours {
+		total += hour.HoursPerWeek
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the curriculum has been successfully", map[string]interface{}{
+		"grade_level": grade_level,
+		"subjects": hours,
+		"total_hours": total,
+	})
+
+}
+
+//func to assign a guru to teach the subject in the class, the default term is the open term
+func (h *HandleRequest) CreateAssignment_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin can assign the teachers, a guru cannot give the access to themselves
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateAssignment
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//the default term is the open term
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if payload.TermId == nil {
+		term, err := h.academics.GetActiveTerm(ctx)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the open term!", err.Error())
+			return 
+		}
+		if term == nil {
+			utils.ResponseError(w, http.StatusBadRequest, "There is no open term, the term id is required!", false)
+			return 
+		}
+		payload.TermId = &term.Id
+	}
+
+	//make the struct of the assignment and execute the query
+	assignment := &types.TeachingAssignment{
+		Id: uuid.New(),
+		TeacherId: payload.TeacherId,
+		SubjectId: payload.SubjectId,
+		ClassId: payload.ClassId,
+		TermId: *payload.TermId,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	if err := h.db.CreateAssignment(ctx, assignment); err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new assignment", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the assignment!", err.Error())
+		return 
+	}
+	created, err := h.db.GetAssignmentById(ctx, assignment.Id)
+	if err != nil || created == nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the assignment!", false)
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new assignment has been successfully", created)
+
+}
+
+//func to get the teaching assignments (?teacher_id&subject_id&class_id&term_id), ?mine=true
+//shows the assignments of the guru that login
+func (h *HandleRequest) GetAssignments_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can see the assignments
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//make the filter
+	var filter types.AssignmentFilter
+	for name, target := range map[string]**uuid.UUID{
+		"teacher_id": &filter.TeacherId,
+		"subject_id": &filter.SubjectId,
+		"class_id": &filter.ClassId,
+		"term_id": &filter.TermId,
+	} {
+		value, err := uuidParam(r, name)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Invalid query params!", err.Error())
+			return 
+		}
+		*target = value
+	}
+	if r.URL.Query().Get("mine") == "true" {
+		user_id, err := middleware.GetIdMiddleware(w, r)
+		if err != nil || user_id == uuid.Nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the user id!", false)
+			return 
+		}
+		filter.TeacherId = &user_id
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	assignments, err := h.db.GetAssignments(ctx, filter)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the assignments", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the assignments!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the assignments has been successfully", assignments)
+
+}
+
+//func to delete the teaching assignment
+func (h *HandleRequest) DeleteAssignment_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only the admin can remove the teachers
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	assignment_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.DeleteAssignment(ctx, assignment_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the assignment", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the assignment!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the assignment has been successfully", assignment_id)
+
+}
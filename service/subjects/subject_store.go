@@ -0,0 +1,402 @@
+package subjects
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store subject
+type SubjectStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewSubjectStore(db *sqlx.DB) *SubjectStore {
+	return &SubjectStore{db: db}
+}
+
+//the column of the subject that we select in every query
+const subjectColumns = `id, code, name, description, kkm, is_active, created_at, updated_at`
+
+//the column of the hours with the subject
+const hoursColumns = `h.subject_id, sb.code AS subject_code, sb.name AS subject_name, h.grade_level, h.major, h.hours_per_week`
+
+//the column of the assignment with the name of the teacher, the subject and the class
+const assignmentColumns = `
+	ta.id, ta.teacher_id, u.username AS teacher_name, ta.subject_id, sb.code AS subject_code,
+	sb.name AS subject_name, ta.class_id, c.name AS class_name, ta.term_id, ta.created_at, ta.updated_at
+`
+
+//the join of the assignment
+const assignmentJoins = `
+	FROM teaching_assignments ta
+	JOIN users u ON u.id = ta.teacher_id
+	JOIN subjects sb ON sb.id = ta.subject_id
+	JOIN classes c ON c.id = ta.class_id
+`
+
+//func to create a new subject
+func (s *SubjectStore) CreateSubject(ctx context.Context, subject *types.Subject) error {
+
+	//base query
+	query := `
+		INSERT INTO subjects (id, code, name, description, kkm, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		subject.Id,
+		subject.Code,
+		subject.Name,
+		subject.Description,
+		subject.Kkm,
+		subject.IsActive,
+		subject.Created_at,
+		subject.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new subject! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the subject by id
+func (s *SubjectStore) GetSubjectById(ctx context.Context, id uuid.UUID) (*types.Subject, error) {
+
+	//execute the query
+	var subject types.Subject
+	if err := s.db.GetContext(ctx, &subject, `SELECT `+subjectColumns+` FROM subjects WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get subject by id: %w", err)
+	}
+
+	return &subject, nil
+
+}
+
+//func to get the subject by the code, the code is not case sensitive
+func (s *SubjectStore) GetSubjectByCode(ctx context.Context, code string) (*types.Subject, error) {
+
+	//execute the query
+	var subject types.Subject
+	if err := s.db.GetContext(ctx, &subject, `SELECT `+subjectColumns+` FROM subjects WHERE code = $1;`, strings.ToUpper(strings.TrimSpace(code))); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get subject by code: %w", err)
+	}
+
+	return &subject, nil
+
+}
+
+//func to get all of the subjects
+func (s *SubjectStore) GetAllSubjects(ctx context.Context, onlyActive bool) ([]types.Subject, error) {
+
+	//base query
+	query := `SELECT ` + subjectColumns + ` FROM subjects WHERE ($1 = FALSE OR is_active) ORDER BY code;`
+
+	//execute the query
+	subjects := []types.Subject{}
+	if err := s.db.SelectContext(ctx, &subjects, query, onlyActive); err != nil {
+		return nil, fmt.Errorf("failed to get the subjects: %w", err)
+	}
+
+	return subjects, nil
+
+}
+
+//func to update the subject
+func (s *SubjectStore) UpdateSubject(ctx context.Context, id uuid.UUID, payload types.UpdateSubject) error {
+
+	//setup the args and args id
+	var settings []string
+	argsId := 1
+	var args []interface{}
+
+	//if the name is changed
+	if payload.Name != nil {
+		settings = append(settings, fmt.Sprintf("name=$%d", argsId))
+		args = append(args, *payload.Name)
+		argsId++
+	}
+
+	//if the description is changed
+	if payload.Description != nil {
+		settings = append(settings, fmt.Sprintf("description=$%d", argsId))
+		args = append(args, *payload.Description)
+		argsId++
+	}
+
+	//if the kkm is changed
+	if payload.Kkm != nil {
+		settings = append(settings, fmt.Sprintf("kkm=$%d", argsId))
+		args = append(args, *payload.Kkm)
+		argsId++
+	}
+
+	//if the subject is activated or deactivated
+	if payload.IsActive != nil {
+		settings = append(settings, fmt.Sprintf("is_active=$%d", argsId))
+		args = append(args, *payload.IsActive)
+		argsId++
+	}
+
+	//validate if the no one field changes
+	if len(args) == 0 {
+		return errors.New("No one data changes")
+	}
+
+	//update the updated at
+	settings = append(settings, fmt.Sprintf("updated_at=$%d", argsId))
+	args = append(args, time.Now().UTC())
+	argsId++
+
+	//define a fullquery and execute it
+	fullquery := fmt.Sprintf("UPDATE subjects SET %s WHERE id = $%d", strings.Join(settings, ", "), argsId)
+	args = append(args, id)
+	rows, err := s.db.ExecContext(ctx, fullquery, args...)
+	if err != nil {
+		return errors.New("Failed to update the subject! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The subject is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to replace the weekly hours of the subject, the old hours are deleted
+func (s *SubjectStore) SetSubjectHours(ctx context.Context, subjectId uuid.UUID, hours []types.SubjectHoursEntry) ([]types.SubjectHours, error) {
+
+	//make the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the old hours of the subject are replaced
+	if _, err := tx.ExecContext(ctx, `DELETE FROM subject_hours WHERE subject_id = $1;`, subjectId); err != nil {
+		return nil, errors.New("Failed to delete the old hours! " + err.Error())
+	}
+
+	//insert the new hours, the same grade and major cannot be written twice
+	query := `
+		INSERT INTO subject_hours (subject_id, grade_level, major, hours_per_week, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5);
+	`
+	now := time.Now().UTC()
+	for _, entry := range hours {
+		if _, err := tx.ExecContext(ctx, query, subjectId, entry.GradeLevel, strings.TrimSpace(entry.Major), entry.HoursPerWeek, now); err != nil {
+			return nil, errors.New("Failed to save the hours of the grade! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return s.GetSubjectHours(ctx, subjectId)
+
+}
+
+//func to get the weekly hours of the subject
+func (s *SubjectStore) GetSubjectHours(ctx context.Context, subjectId uuid.UUID) ([]types.SubjectHours, error) {
+
+	//base query
+	query := `
+		SELECT ` + hoursColumns + ` FROM subject_hours h
+		JOIN subjects sb ON sb.id = h.subject_id
+		WHERE h.subject_id = $1 ORDER BY h.grade_level, h.major;
+	`
+
+	//execute the query
+	hours := []types.SubjectHours{}
+	if err := s.db.SelectContext(ctx, &hours, query, subjectId); err != nil {
+		return nil, fmt.Errorf("failed to get the hours of the subject: %w", err)
+	}
+
+	return hours, nil
+
+}
+
+//func to get the active subjects of the grade and the major with the weekly hours,
+//the hours of the major win over the hours of every major
+func (s *SubjectStore) GetCurriculum(ctx context.Context, gradeLevel int, major string) ([]types.SubjectHours, error) {
+
+	//base query
+	query := `
+		SELECT * FROM (
+			SELECT DISTINCT ON (h.subject_id) ` + hoursColumns + ` FROM subject_hours h
+			JOIN subjects sb ON sb.id = h.subject_id
+			WHERE sb.is_active AND h.grade_level = $1 AND (h.major = '' OR LOWER(h.major) = LOWER($2))
+			ORDER BY h.subject_id, h.major DESC
+		) curriculum ORDER BY subject_code;
+	`
+
+	//execute the query
+	hours := []types.SubjectHours{}
+	if err := s.db.SelectContext(ctx, &hours, query, gradeLevel, strings.TrimSpace(major)); err != nil {
+		return nil, fmt.Errorf("failed to get the curriculum: %w", err)
+	}
+
+	return hours, nil
+
+}
+
+//func to create a new teaching assignment, the teacher must be a guru and the subject must be active
+func (s *SubjectStore) CreateAssignment(ctx context.Context, assignment *types.TeachingAssignment) error {
+
+	//make the transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//check the teacher and the subject
+	var teacher, active bool
+	if err := tx.GetContext(ctx, &teacher, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND role = 'guru');`, assignment.TeacherId); err != nil {
+		return errors.New("Failed to check the teacher! " + err.Error())
+	}
+	if !teacher {
+		return errors.New("The teacher must be a user with the role guru!")
+	}
+	if err := tx.GetContext(ctx, &active, `SELECT EXISTS (SELECT 1 FROM subjects WHERE id = $1 AND is_active);`, assignment.SubjectId); err != nil {
+		return errors.New("Failed to check the subject! " + err.Error())
+	}
+	if !active {
+		return errors.New("The subject is not exist or not active!")
+	}
+
+	//base query, one subject of the class in one term has one teacher
+	query := `
+		INSERT INTO teaching_assignments (id, teacher_id, subject_id, class_id, term_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (subject_id, class_id, term_id) DO NOTHING;
+	`
+	rows, err := tx.ExecContext(
+		ctx,
+		query,
+		assignment.Id,
+		assignment.TeacherId,
+		assignment.SubjectId,
+		assignment.ClassId,
+		assignment.TermId,
+		assignment.Created_at,
+		assignment.Updated_at,
+	)
+	if err != nil {
+		return errors.New("Failed to create a new assignment! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The subject of the class has been already assigned in this term!")
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the assignment by id
+func (s *SubjectStore) GetAssignmentById(ctx context.Context, id uuid.UUID) (*types.TeachingAssignment, error) {
+
+	//execute the query
+	var assignment types.TeachingAssignment
+	if err := s.db.GetContext(ctx, &assignment, `SELECT `+assignmentColumns+assignmentJoins+` WHERE ta.id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the assignment: %w", err)
+	}
+
+	return &assignment, nil
+
+}
+
+//func to get the assignments with the filter, every empty filter is ignored
+func (s *SubjectStore) GetAssignments(ctx context.Context, filter types.AssignmentFilter) ([]types.TeachingAssignment, error) {
+
+	//base query
+	query := `SELECT ` + assignmentColumns + assignmentJoins + `
+		WHERE ($1::UUID IS NULL OR ta.teacher_id = $1)
+		AND ($2::UUID IS NULL OR ta.subject_id = $2)
+		AND ($3::UUID IS NULL OR ta.class_id = $3)
+		AND ($4::UUID IS NULL OR ta.term_id = $4)
+		ORDER BY c.name, sb.code;
+	`
+
+	//execute the query
+	assignments := []types.TeachingAssignment{}
+	if err := s.db.SelectContext(ctx, &assignments, query, filter.TeacherId, filter.SubjectId, filter.ClassId, filter.TermId); err != nil {
+		return nil, fmt.Errorf("failed to get the assignments: %w", err)
+	}
+
+	return assignments, nil
+
+}
+
+//func to delete the assignment
+func (s *SubjectStore) DeleteAssignment(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM teaching_assignments WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the assignment! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The assignment is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to check if the guru teaches in the class, when the term is nil the open term is used
+//and when the subject is nil any subject of the class (or the homeroom of the class) is enough
+func (s *SubjectStore) CanTeach(ctx context.Context, teacherId uuid.UUID, classId uuid.UUID, termId *uuid.UUID, subjectId *uuid.UUID) (bool, error) {
+
+	//base query
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM teaching_assignments ta
+			JOIN terms t ON t.id = ta.term_id
+			WHERE ta.teacher_id = $1 AND ta.class_id = $2
+			AND (($3::UUID IS NULL AND t.status = 'open') OR ta.term_id = $3)
+			AND ($4::UUID IS NULL OR ta.subject_id = $4)
+		) OR ($4::UUID IS NULL AND EXISTS (
+			SELECT 1 FROM classes WHERE id = $2 AND homeroom_teacher_id = $1
+		));
+	`
+
+	//execute the query
+	var allowed bool
+	if err := s.db.GetContext(ctx, &allowed, query, teacherId, classId, termId, subjectId); err != nil {
+		return false, fmt.Errorf("failed to check the assignment: %w", err)
+	}
+
+	return allowed, nil
+
+}
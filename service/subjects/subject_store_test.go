@@ -0,0 +1,92 @@
+package subjects
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/db/dbtest"
+)
+
+//the teaching assignment of the model of the can teach query
+type assignment struct {
+	teacher, class, term, subject uuid.UUID
+	open bool
+}
+
+//script the can teach query with the assignments and the homerooms, the answer follows the rules of the
+//query: the nil term is the open term, the nil subject is any subject or the homeroom of the class
+func scriptCanTeach(fake *dbtest.DB, assignments []assignment, homerooms map[uuid.UUID]uuid.UUID) {
+	fake.On("FROM teaching_assignments ta", func(args []any) dbtest.Result {
+		teacher, class := args[0].(uuid.UUID), args[1].(uuid.UUID)
+		term, subject := args[2].(*uuid.UUID), args[3].(*uuid.UUID)
+		allowed := false
+		for _, a := range assignments {
+			if a.teacher != teacher || a.class != class {
+				continue
+			}
+			if (term == nil && !a.open) || (term != nil && *term != a.term) {
+				continue
+			}
+			if subject != nil && *subject != a.subject {
+				continue
+			}
+			allowed = true
+		}
+		if subject == nil && homerooms[class] == teacher {
+			allowed = true
+		}
+		return dbtest.Rows([]string{"allowed"}, []driver.Value{allowed})
+	})
+}
+
+func TestCanTeach(t *testing.T) {
+	fake, db := dbtest.New(t)
+	store := NewSubjectStore(db)
+
+	guru, homeroom := uuid.New(), uuid.New()
+	class, other := uuid.New(), uuid.New()
+	open, closed := uuid.New(), uuid.New()
+	math, physics := uuid.New(), uuid.New()
+	scriptCanTeach(fake, []assignment{
+		{teacher: guru, class: class, term: open, subject: math, open: true},
+		{teacher: guru, class: other, term: closed, subject: physics},
+	}, map[uuid.UUID]uuid.UUID{class: homeroom})
+
+	cases := []struct {
+		name string
+		teacher, class uuid.UUID
+		term, subject *uuid.UUID
+		want bool
+	}{
+		{"the subject of the open term", guru, class, nil, &math, true},
+		{"any subject of the class", guru, class, nil, nil, true},
+		{"the subject that is not assigned", guru, class, nil, &physics, false},
+		{"the assignment of the closed term is not the open term", guru, other, nil, nil, false},
+		{"the assignment of the given term", guru, other, &closed, &physics, true},
+		{"the homeroom without the subject", homeroom, class, nil, nil, true},
+		{"the homeroom is not the teacher of the subject", homeroom, class, nil, &math, false},
+		{"the other class", homeroom, other, nil, nil, false},
+	}
+	for _, c := range cases {
+		allowed, err := store.CanTeach(context.Background(), c.teacher, c.class, c.term, c.subject)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", c.name, err)
+		}
+		if allowed != c.want {
+			t.Fatalf("%s: got %v, want %v", c.name, allowed, c.want)
+		}
+	}
+
+	//the arguments are the teacher, the class, the term and the subject in the order of the query
+	statement := fake.Statements()[0]
+	if len(statement.Args) != 4 || statement.Args[0] != guru || statement.Args[1] != class {
+		t.Fatalf("unexpected arguments %v", statement.Args)
+	}
+	if fake.Index("($3::UUID IS NULL AND t.status = 'open') OR ta.term_id = $3", 0) < 0 ||
+		fake.Index("($4::UUID IS NULL AND EXISTS", 0) < 0 || fake.Index("homeroom_teacher_id = $1", 0) < 0 {
+		t.Fatalf("expected the open term and the homeroom rules in the query")
+	}
+}
@@ -0,0 +1,155 @@
+package timetable
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// the kind of the conflict of a proposed slot
+const (
+	ConflictClass 		= "class"
+	ConflictTeacher 	= "teacher"
+	ConflictRoom 		= "room"
+	ConflictQuota 		= "quota"
+)
+
+// the status of the weekly hours of the subject
+const (
+	QuotaUnder 			= "under"
+	QuotaMet 			= "met"
+	QuotaOver 			= "over"
+)
+
+// ClockMinutes reads the clock of the school ("07:30" or "07:30:00") as minutes after midnight.
+func ClockMinutes(value string) (int, error) {
+	clock, err := time.Parse("15:04:05", value)
+	if err != nil {
+		if clock, err = time.Parse("15:04", value); err != nil {
+			return 0, fmt.Errorf("invalid time %q", value)
+		}
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// Overlaps reports if two lessons of the same day share any minute, a lesson that starts
+// when the other ends does not overlap. An invalid clock is treated as an overlap.
+func Overlaps(aStart, aEnd, bStart, bEnd string) bool {
+	as, err1 := ClockMinutes(aStart)
+	ae, err2 := ClockMinutes(aEnd)
+	bs, err3 := ClockMinutes(bStart)
+	be, err4 := ClockMinutes(bEnd)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		return true
+	}
+	return as < be && bs < ae
+}
+
+// DetectConflicts compares the proposed slot with the existing slots of the term and reports every
+// double booking of the class, the teacher and the room. One existing slot can give more than one conflict.
+func DetectConflicts(candidate types.TimetableSlot, existing []types.TimetableSlot) []types.SlotConflict {
+
+	conflicts := []types.SlotConflict{}
+	for _, slot := range existing {
+		if slot.Id == candidate.Id || slot.TermId != candidate.TermId || slot.DayOfWeek != candidate.DayOfWeek {
+			continue
+		}
+		if !Overlaps(candidate.StartTime, candidate.EndTime, slot.StartTime, slot.EndTime) {
+			continue
+		}
+
+		when := fmt.Sprintf("day %d %s-%s", slot.DayOfWeek, shortClock(slot.StartTime), shortClock(slot.EndTime))
+		if slot.ClassId == candidate.ClassId {
+			conflicts = append(conflicts, conflictOf(slot, ConflictClass,
+				fmt.Sprintf("the class already has %s on %s", slot.Subject, when)))
+		}
+		if sameId(slot.TeacherId, candidate.TeacherId) {
+			conflicts = append(conflicts, conflictOf(slot, ConflictTeacher,
+				fmt.Sprintf("the teacher already teaches %s in another class on %s", slot.Subject, when)))
+		}
+		if sameId(slot.RoomId, candidate.RoomId) {
+			conflicts = append(conflicts, conflictOf(slot, ConflictRoom,
+				fmt.Sprintf("the room is already used by %s on %s", slot.Subject, when)))
+		}
+	}
+
+	return conflicts
+
+}
+
+// CheckQuota reports a conflict when one more lesson of the subject is over the weekly hours of the
+// curriculum, required is nil when the subject has no weekly hours for the grade of the class.
+func CheckQuota(candidate types.TimetableSlot, required *int, planned int) *types.SlotConflict {
+
+	if candidate.SubjectId == nil {
+		return nil
+	}
+	if required == nil {
+		return &types.SlotConflict{
+			Kind: ConflictQuota,
+			Message: fmt.Sprintf("%s has no weekly hours for the grade of the class", candidate.Subject),
+			ClassId: &candidate.ClassId,
+			Subject: candidate.Subject,
+		}
+	}
+	if planned+1 > *required {
+		return &types.SlotConflict{
+			Kind: ConflictQuota,
+			Message: fmt.Sprintf("%s already has %d of %d weekly hours", candidate.Subject, planned, *required),
+			ClassId: &candidate.ClassId,
+			Subject: candidate.Subject,
+		}
+	}
+	return nil
+
+}
+
+// QuotaStatus compares the planned lessons of the week with the weekly hours of the subject.
+func QuotaStatus(required, planned int) string {
+	switch {
+	case planned < required:
+		return QuotaUnder
+	case planned > required:
+		return QuotaOver
+	}
+	return QuotaMet
+}
+
+//helper to make the conflict of the existing slot
+func conflictOf(slot types.TimetableSlot, kind string, message string) types.SlotConflict {
+	id := slot.Id
+	conflict := types.SlotConflict{
+		Kind: kind,
+		Message: message,
+		SlotId: &id,
+		DayOfWeek: slot.DayOfWeek,
+		StartTime: slot.StartTime,
+		EndTime: slot.EndTime,
+		Subject: slot.Subject,
+	}
+	switch kind {
+	case ConflictClass:
+		conflict.ClassId = &slot.ClassId
+	case ConflictTeacher:
+		conflict.TeacherId = slot.TeacherId
+	case ConflictRoom:
+		conflict.RoomId = slot.RoomId
+	}
+	return conflict
+}
+
+//helper to compare two optional ids, an empty id never clashes
+func sameId(a, b *uuid.UUID) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+//helper to show the clock without the seconds
+func shortClock(value string) string {
+	if len(value) > 5 {
+		return value[:5]
+	}
+	return value
+}
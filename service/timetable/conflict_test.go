@@ -0,0 +1,90 @@
+package timetable
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func TestOverlaps(t *testing.T) {
+	cases := []struct {
+		aStart, aEnd, bStart, bEnd string
+		want                       bool
+	}{
+		{"07:00", "07:45", "07:45:00", "08:30:00", false},
+		{"07:00", "08:00", "07:45:00", "08:30:00", true},
+		{"09:00", "10:00", "07:00:00", "11:00:00", true},
+		{"10:00", "11:00", "07:00:00", "08:00:00", false},
+		{"bad", "11:00", "07:00:00", "08:00:00", true},
+	}
+	for _, c := range cases {
+		if got := Overlaps(c.aStart, c.aEnd, c.bStart, c.bEnd); got != c.want {
+			t.Fatalf("%s-%s vs %s-%s: got %v, want %v", c.aStart, c.aEnd, c.bStart, c.bEnd, got, c.want)
+		}
+	}
+}
+
+func TestDetectConflicts(t *testing.T) {
+	term := uuid.New()
+	class := uuid.New()
+	other := uuid.New()
+	teacher := uuid.New()
+	room := uuid.New()
+
+	existing := []types.TimetableSlot{
+		{Id: uuid.New(), ClassId: class, TermId: term, DayOfWeek: 1, StartTime: "07:00:00", EndTime: "07:45:00", Subject: "MTK"},
+		{Id: uuid.New(), ClassId: other, TermId: term, DayOfWeek: 1, StartTime: "07:30:00", EndTime: "08:15:00", Subject: "FIS", TeacherId: &teacher},
+		{Id: uuid.New(), ClassId: other, TermId: term, DayOfWeek: 1, StartTime: "07:00:00", EndTime: "07:45:00", Subject: "KIM", RoomId: &room},
+		{Id: uuid.New(), ClassId: class, TermId: term, DayOfWeek: 2, StartTime: "07:00:00", EndTime: "07:45:00", Subject: "BIO"},
+		{Id: uuid.New(), ClassId: class, TermId: uuid.New(), DayOfWeek: 1, StartTime: "07:00:00", EndTime: "07:45:00", Subject: "OLD"},
+	}
+	candidate := types.TimetableSlot{
+		Id: uuid.New(), ClassId: class, TermId: term, DayOfWeek: 1,
+		StartTime: "07:15", EndTime: "08:00", Subject: "ING", TeacherId: &teacher, RoomId: &room,
+	}
+
+	conflicts := DetectConflicts(candidate, existing)
+	if len(conflicts) != 3 {
+		t.Fatalf("got %d conflicts, want 3: %+v", len(conflicts), conflicts)
+	}
+	kinds := map[string]uuid.UUID{}
+	for _, conflict := range conflicts {
+		kinds[conflict.Kind] = *conflict.SlotId
+	}
+	if kinds[ConflictClass] != existing[0].Id || kinds[ConflictTeacher] != existing[1].Id || kinds[ConflictRoom] != existing[2].Id {
+		t.Fatalf("unexpected conflicts %+v", conflicts)
+	}
+
+	candidate.StartTime, candidate.EndTime = "08:15", "09:00"
+	if conflicts := DetectConflicts(candidate, existing); len(conflicts) != 0 {
+		t.Fatalf("the next period should be free: %+v", conflicts)
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	subject := uuid.New()
+	candidate := types.TimetableSlot{ClassId: uuid.New(), Subject: "MTK", SubjectId: &subject}
+	four := 4
+
+	if conflict := CheckQuota(candidate, &four, 3); conflict != nil {
+		t.Fatalf("the fourth hour should be accepted: %+v", conflict)
+	}
+	if conflict := CheckQuota(candidate, &four, 4); conflict == nil || conflict.Kind != ConflictQuota {
+		t.Fatalf("the fifth hour should be rejected")
+	}
+	if conflict := CheckQuota(candidate, nil, 0); conflict == nil {
+		t.Fatalf("a subject outside the curriculum should be rejected")
+	}
+	candidate.SubjectId = nil
+	if conflict := CheckQuota(candidate, nil, 10); conflict != nil {
+		t.Fatalf("a free text lesson has no quota")
+	}
+}
+
+func TestQuotaStatus(t *testing.T) {
+	if QuotaStatus(4, 3) != QuotaUnder || QuotaStatus(4, 4) != QuotaMet || QuotaStatus(4, 5) != QuotaOver {
+		t.Fatalf("unexpected quota status")
+	}
+}
@@ -0,0 +1,448 @@
+package timetable
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+// the kind of the unsatisfiable constraint of the solver
+const (
+	UnsatAssignment 	= "assignment"
+	UnsatClassHours 	= "class_hours"
+	UnsatTeacherHours 	= "teacher_hours"
+	UnsatLesson 		= "lesson"
+	UnsatRooms 			= "rooms"
+	UnsatNoSolution 	= "no_solution"
+	UnsatSearchLimit 	= "search_limit"
+	UnsatUnplaced 		= "unplaced"
+)
+
+// DefaultSolverSteps is the limit of the search when the input has no limit
+const DefaultSolverSteps = 200000
+
+// SolverLesson is the weekly hours of one subject of the class that must be placed in the periods
+type SolverLesson struct {
+	ClassId 		uuid.UUID
+	SubjectId 		uuid.UUID
+	TeacherId 		uuid.UUID
+	Subject 		string
+	Hours 			int
+}
+
+// SolverInput is everything the solver needs, the occupied slots (the pinned slots and the slots of the
+// other classes) keep their class, teacher and room busy and every room is good for every lesson
+type SolverInput struct {
+	TermId 			uuid.UUID
+	Days 			[]int
+	Periods 		[]types.Period
+	Rooms 			[]uuid.UUID
+	Lessons 		[]SolverLesson
+	Occupied 		[]types.TimetableSlot
+	Unavailable 	[]types.Unavailability
+	MaxSteps 		int
+}
+
+type cell struct {
+	day 		int
+	period 		types.Period
+}
+
+type dailyKey struct {
+	lesson 		int
+	day 		int
+}
+
+type solver struct {
+	input 			SolverInput
+	cells 			[]cell
+	classBusy 		map[uuid.UUID][]bool
+	teacherBusy 	map[uuid.UUID][]bool
+	rooms 			[][]uuid.UUID
+	roomUsed 		[]int
+	useRooms 		bool
+	remaining 		[]int
+	placed 			[][]int
+	daily 			map[dailyKey]int
+	total 			int
+	done 			int
+	steps 			int
+	maxSteps 		int
+	best 			int
+	bestRemaining 	[]int
+}
+
+// Solve places every weekly hour of the lessons into the periods of the days without any double booking
+// of the class, the teacher or the room. It returns the new slots, or the constraints that cannot be
+// satisfied when there is no solution. The search is a backtracking that always continues with the lesson
+// that has the fewest free periods left, and it spreads the hours of one subject over different days.
+func Solve(input SolverInput) ([]types.TimetableSlot, []types.UnsatConstraint) {
+
+	s := newSolver(input)
+	if unsat := s.precheck(); len(unsat) > 0 {
+		return nil, unsat
+	}
+	if !s.search() {
+		return nil, s.failure()
+	}
+	return s.slots(), nil
+
+}
+
+//helper to build the cells of the week and mark the occupied and the unavailable cells
+func newSolver(input SolverInput) *solver {
+
+	s := &solver{
+		input: input,
+		classBusy: map[uuid.UUID][]bool{},
+		teacherBusy: map[uuid.UUID][]bool{},
+		useRooms: len(input.Rooms) > 0,
+		remaining: make([]int, len(input.Lessons)),
+		placed: make([][]int, len(input.Lessons)),
+		daily: map[dailyKey]int{},
+		maxSteps: input.MaxSteps,
+	}
+	if s.maxSteps <= 0 {
+		s.maxSteps = DefaultSolverSteps
+	}
+	for _, day := range input.Days {
+		for _, period := range input.Periods {
+			s.cells = append(s.cells, cell{day: day, period: period})
+		}
+	}
+	s.rooms = make([][]uuid.UUID, len(s.cells))
+	s.roomUsed = make([]int, len(s.cells))
+	for c := range s.cells {
+		s.rooms[c] = append([]uuid.UUID{}, input.Rooms...)
+	}
+
+	//the occupied slots block every period that they overlap
+	for _, slot := range input.Occupied {
+		for c, item := range s.cells {
+			if item.day != slot.DayOfWeek || !Overlaps(item.period.StartTime, item.period.EndTime, slot.StartTime, slot.EndTime) {
+				continue
+			}
+			s.busy(s.classBusy, slot.ClassId)[c] = true
+			if slot.TeacherId != nil {
+				s.busy(s.teacherBusy, *slot.TeacherId)[c] = true
+			}
+			if slot.RoomId != nil {
+				s.removeRoom(c, *slot.RoomId)
+			}
+		}
+	}
+
+	//the unavailable times of the teachers and the rooms
+	for _, entry := range input.Unavailable {
+		for c, item := range s.cells {
+			if item.day != entry.DayOfWeek || (entry.PeriodId != nil && *entry.PeriodId != item.period.Id) {
+				continue
+			}
+			if entry.TeacherId != nil {
+				s.busy(s.teacherBusy, *entry.TeacherId)[c] = true
+			}
+			if entry.RoomId != nil {
+				s.removeRoom(c, *entry.RoomId)
+			}
+		}
+	}
+
+	for i, lesson := range input.Lessons {
+		s.busy(s.classBusy, lesson.ClassId)
+		s.busy(s.teacherBusy, lesson.TeacherId)
+		s.remaining[i] = lesson.Hours
+		s.total += lesson.Hours
+	}
+	s.bestRemaining = append([]int{}, s.remaining...)
+
+	return s
+
+}
+
+//helper to get the busy cells of the class or the teacher
+func (s *solver) busy(table map[uuid.UUID][]bool, id uuid.UUID) []bool {
+	if _, ok := table[id]; !ok {
+		table[id] = make([]bool, len(s.cells))
+	}
+	return table[id]
+}
+
+//helper to remove the room from the free rooms of the cell
+func (s *solver) removeRoom(c int, roomId uuid.UUID) {
+	rooms := s.rooms[c][:0]
+	for _, room := range s.rooms[c] {
+		if room != roomId {
+			rooms = append(rooms, room)
+		}
+	}
+	s.rooms[c] = rooms
+}
+
+//helper to count the free cells
+func countFree(busy []bool) int {
+	free := 0
+	for _, value := range busy {
+		if !value {
+			free++
+		}
+	}
+	return free
+}
+
+//helper to find the cases that can never be solved before the search, so the report is precise
+func (s *solver) precheck() []types.UnsatConstraint {
+
+	unsat := []types.UnsatConstraint{}
+
+	//the hours of every class and every teacher must fit into the free periods
+	classHours := map[uuid.UUID]int{}
+	teacherHours := map[uuid.UUID]int{}
+	var classOrder, teacherOrder []uuid.UUID
+	for _, lesson := range s.input.Lessons {
+		if _, ok := classHours[lesson.ClassId]; !ok {
+			classOrder = append(classOrder, lesson.ClassId)
+		}
+		if _, ok := teacherHours[lesson.TeacherId]; !ok {
+			teacherOrder = append(teacherOrder, lesson.TeacherId)
+		}
+		classHours[lesson.ClassId] += lesson.Hours
+		teacherHours[lesson.TeacherId] += lesson.Hours
+	}
+	for _, id := range classOrder {
+		if free := countFree(s.classBusy[id]); classHours[id] > free {
+			classId := id
+			unsat = append(unsat, types.UnsatConstraint{
+				Kind: UnsatClassHours,
+				Message: fmt.Sprintf("the class needs %d lessons in the week but only %d periods are free", classHours[id], free),
+				ClassId: &classId,
+			})
+		}
+	}
+	for _, id := range teacherOrder {
+		if free := countFree(s.teacherBusy[id]); teacherHours[id] > free {
+			teacherId := id
+			unsat = append(unsat, types.UnsatConstraint{
+				Kind: UnsatTeacherHours,
+				Message: fmt.Sprintf("the teacher needs %d lessons in the week but is available in only %d periods", teacherHours[id], free),
+				TeacherId: &teacherId,
+			})
+		}
+	}
+
+	//every lesson must have enough periods where the class, the teacher and a room are free together
+	for i, lesson := range s.input.Lessons {
+		free := 0
+		for c := range s.cells {
+			if s.open(i, c) {
+				free++
+			}
+		}
+		if lesson.Hours > free {
+			item := s.input.Lessons[i]
+			unsat = append(unsat, types.UnsatConstraint{
+				Kind: UnsatLesson,
+				Message: fmt.Sprintf("%s needs %d hours but the class and the teacher are free together in only %d periods", lesson.Subject, lesson.Hours, free),
+				ClassId: &item.ClassId,
+				TeacherId: &item.TeacherId,
+				SubjectId: &item.SubjectId,
+			})
+		}
+	}
+
+	//the rooms must be enough for every lesson of the week
+	if s.useRooms {
+		free := 0
+		for c := range s.cells {
+			free += len(s.rooms[c])
+		}
+		if s.total > free {
+			unsat = append(unsat, types.UnsatConstraint{
+				Kind: UnsatRooms,
+				Message: fmt.Sprintf("the week needs %d lessons but the rooms are free for only %d lessons", s.total, free),
+			})
+		}
+	}
+
+	return unsat
+
+}
+
+//helper to check if the lesson can be placed in the cell
+func (s *solver) open(lesson int, c int) bool {
+	item := s.input.Lessons[lesson]
+	if s.classBusy[item.ClassId][c] || s.teacherBusy[item.TeacherId][c] {
+		return false
+	}
+	return !s.useRooms || s.roomUsed[c] < len(s.rooms[c])
+}
+
+//helper to get the free cells of the lesson, the hours of one lesson are placed in the order of the
+//cells so the same set of periods is never tried twice in another order
+func (s *solver) feasible(lesson int) []int {
+	start := 0
+	if placed := s.placed[lesson]; len(placed) > 0 {
+		start = placed[len(placed)-1] + 1
+	}
+	cells := []int{}
+	for c := start; c < len(s.cells); c++ {
+		if s.open(lesson, c) {
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
+
+func (s *solver) place(lesson int, c int) {
+	item := s.input.Lessons[lesson]
+	s.classBusy[item.ClassId][c] = true
+	s.teacherBusy[item.TeacherId][c] = true
+	s.roomUsed[c]++
+	s.daily[dailyKey{lesson: lesson, day: s.cells[c].day}]++
+	s.placed[lesson] = append(s.placed[lesson], c)
+	s.remaining[lesson]--
+	s.done++
+}
+
+func (s *solver) unplace(lesson int, c int) {
+	item := s.input.Lessons[lesson]
+	s.classBusy[item.ClassId][c] = false
+	s.teacherBusy[item.TeacherId][c] = false
+	s.roomUsed[c]--
+	s.daily[dailyKey{lesson: lesson, day: s.cells[c].day}]--
+	s.placed[lesson] = s.placed[lesson][:len(s.placed[lesson])-1]
+	s.remaining[lesson]++
+	s.done--
+}
+
+//helper to remember the best partial timetable for the report
+func (s *solver) record() {
+	if s.done > s.best {
+		s.best = s.done
+		copy(s.bestRemaining, s.remaining)
+	}
+}
+
+func (s *solver) search() bool {
+
+	if s.done == s.total {
+		return true
+	}
+	if s.steps >= s.maxSteps {
+		return false
+	}
+	s.steps++
+
+	//continue with the lesson that has the smallest slack of free periods
+	pick := -1
+	var pickCells []int
+	minSlack := 0
+	for i := range s.input.Lessons {
+		if s.remaining[i] == 0 {
+			continue
+		}
+		cells := s.feasible(i)
+		slack := len(cells) - s.remaining[i]
+		if slack < 0 {
+			s.record()
+			return false
+		}
+		if pick == -1 || slack < minSlack {
+			pick, pickCells, minSlack = i, cells, slack
+		}
+	}
+
+	//the day with the fewest hours of the subject first
+	sort.SliceStable(pickCells, func(a, b int) bool {
+		return s.daily[dailyKey{lesson: pick, day: s.cells[pickCells[a]].day}] < s.daily[dailyKey{lesson: pick, day: s.cells[pickCells[b]].day}]
+	})
+	for _, c := range pickCells {
+		s.place(pick, c)
+		if s.search() {
+			return true
+		}
+		s.unplace(pick, c)
+		if s.steps >= s.maxSteps {
+			break
+		}
+	}
+
+	s.record()
+	return false
+
+}
+
+//helper to report the lessons that could not be placed in the best partial timetable
+func (s *solver) failure() []types.UnsatConstraint {
+
+	unsat := []types.UnsatConstraint{}
+	if s.steps >= s.maxSteps {
+		unsat = append(unsat, types.UnsatConstraint{
+			Kind: UnsatSearchLimit,
+			Message: fmt.Sprintf("no timetable was found in %d steps, pin some slots or relax the constraints", s.maxSteps),
+		})
+	} else {
+		unsat = append(unsat, types.UnsatConstraint{
+			Kind: UnsatNoSolution,
+			Message: "there is no timetable without conflicts for the teachers, the rooms and the unavailable times",
+		})
+	}
+	for i, lesson := range s.input.Lessons {
+		if s.bestRemaining[i] == 0 {
+			continue
+		}
+		item := s.input.Lessons[i]
+		unsat = append(unsat, types.UnsatConstraint{
+			Kind: UnsatUnplaced,
+			Message: fmt.Sprintf("%s could place only %d of %d hours", lesson.Subject, lesson.Hours-s.bestRemaining[i], lesson.Hours),
+			ClassId: &item.ClassId,
+			TeacherId: &item.TeacherId,
+			SubjectId: &item.SubjectId,
+		})
+	}
+	return unsat
+
+}
+
+//helper to make the slots of the solution, the free rooms of the cell are given in order
+func (s *solver) slots() []types.TimetableSlot {
+
+	used := make([]int, len(s.cells))
+	slots := []types.TimetableSlot{}
+	for i, lesson := range s.input.Lessons {
+		for _, c := range s.placed[i] {
+			item := s.input.Lessons[i]
+			period := s.cells[c].period
+			slot := types.TimetableSlot{
+				TermId: s.input.TermId,
+				ClassId: item.ClassId,
+				DayOfWeek: s.cells[c].day,
+				StartTime: period.StartTime,
+				EndTime: period.EndTime,
+				Subject: lesson.Subject,
+				SubjectId: &item.SubjectId,
+				TeacherId: &item.TeacherId,
+				PeriodId: &period.Id,
+			}
+			if s.useRooms {
+				room := s.rooms[c][used[c]]
+				slot.RoomId = &room
+				used[c]++
+			}
+			slots = append(slots, slot)
+		}
+	}
+	sort.SliceStable(slots, func(a, b int) bool {
+		if slots[a].ClassId != slots[b].ClassId {
+			return slots[a].ClassId.String() < slots[b].ClassId.String()
+		}
+		if slots[a].DayOfWeek != slots[b].DayOfWeek {
+			return slots[a].DayOfWeek < slots[b].DayOfWeek
+		}
+		return slots[a].StartTime < slots[b].StartTime
+	})
+	return slots
+
+}
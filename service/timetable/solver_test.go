@@ -0,0 +1,162 @@
+package timetable
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+func testPeriods() []types.Period {
+	return []types.Period{
+		{Id: uuid.New(), Number: 1, StartTime: "07:00:00", EndTime: "07:45:00"},
+		{Id: uuid.New(), Number: 2, StartTime: "07:45:00", EndTime: "08:30:00"},
+	}
+}
+
+//helper to check that the slots have no double booking
+func assertNoConflicts(t *testing.T, slots []types.TimetableSlot) {
+	t.Helper()
+	for i, slot := range slots {
+		slot.Id = uuid.New()
+		if conflicts := DetectConflicts(slot, slots[i+1:]); len(conflicts) > 0 {
+			t.Fatalf("the solution has conflicts: %+v", conflicts)
+		}
+	}
+}
+
+func TestSolveSharedTeacher(t *testing.T) {
+	teacher := uuid.New()
+	classA, classB := uuid.New(), uuid.New()
+	input := SolverInput{
+		Days: []int{1, 2},
+		Periods: testPeriods(),
+		Lessons: []SolverLesson{
+			{ClassId: classA, SubjectId: uuid.New(), TeacherId: teacher, Subject: "MTK", Hours: 2},
+			{ClassId: classB, SubjectId: uuid.New(), TeacherId: teacher, Subject: "MTK", Hours: 2},
+		},
+	}
+
+	slots, unsat := Solve(input)
+	if len(unsat) > 0 {
+		t.Fatalf("unexpected unsat: %+v", unsat)
+	}
+	if len(slots) != 4 {
+		t.Fatalf("got %d slots, want 4", len(slots))
+	}
+	assertNoConflicts(t, slots)
+
+	//the two hours of the subject are spread over the two days
+	days := map[uuid.UUID]map[int]bool{}
+	for _, slot := range slots {
+		if days[slot.ClassId] == nil {
+			days[slot.ClassId] = map[int]bool{}
+		}
+		days[slot.ClassId][slot.DayOfWeek] = true
+	}
+	if len(days[classA]) != 2 || len(days[classB]) != 2 {
+		t.Fatalf("the hours should be spread over the days: %+v", days)
+	}
+}
+
+func TestSolvePinnedAndUnavailable(t *testing.T) {
+	periods := testPeriods()
+	teacher := uuid.New()
+	class := uuid.New()
+	pinned := types.TimetableSlot{Id: uuid.New(), ClassId: class, DayOfWeek: 2, StartTime: "07:00:00", EndTime: "07:45:00", Subject: "AGM", IsPinned: true}
+	input := SolverInput{
+		Days: []int{1, 2},
+		Periods: periods,
+		Lessons: []SolverLesson{{ClassId: class, SubjectId: uuid.New(), TeacherId: teacher, Subject: "MTK", Hours: 1}},
+		Occupied: []types.TimetableSlot{pinned},
+		Unavailable: []types.Unavailability{{TeacherId: &teacher, DayOfWeek: 1}},
+	}
+
+	slots, unsat := Solve(input)
+	if len(unsat) > 0 {
+		t.Fatalf("unexpected unsat: %+v", unsat)
+	}
+	if len(slots) != 1 || slots[0].DayOfWeek != 2 || *slots[0].PeriodId != periods[1].Id {
+		t.Fatalf("the lesson should take the only free period: %+v", slots)
+	}
+}
+
+func TestSolveRooms(t *testing.T) {
+	roomA, roomB := uuid.New(), uuid.New()
+	lessons := []SolverLesson{
+		{ClassId: uuid.New(), SubjectId: uuid.New(), TeacherId: uuid.New(), Subject: "MTK", Hours: 2},
+		{ClassId: uuid.New(), SubjectId: uuid.New(), TeacherId: uuid.New(), Subject: "FIS", Hours: 2},
+	}
+
+	slots, unsat := Solve(SolverInput{Days: []int{1}, Periods: testPeriods(), Rooms: []uuid.UUID{roomA, roomB}, Lessons: lessons})
+	if len(unsat) > 0 {
+		t.Fatalf("unexpected unsat: %+v", unsat)
+	}
+	for _, slot := range slots {
+		if slot.RoomId == nil {
+			t.Fatalf("every slot should have a room")
+		}
+	}
+	assertNoConflicts(t, slots)
+
+	_, unsat = Solve(SolverInput{Days: []int{1}, Periods: testPeriods(), Rooms: []uuid.UUID{roomA}, Lessons: lessons})
+	if len(unsat) != 1 || unsat[0].Kind != UnsatRooms {
+		t.Fatalf("one room should not be enough: %+v", unsat)
+	}
+}
+
+func TestSolvePrecheck(t *testing.T) {
+	teacher := uuid.New()
+	class := uuid.New()
+
+	_, unsat := Solve(SolverInput{
+		Days: []int{1},
+		Periods: testPeriods(),
+		Lessons: []SolverLesson{{ClassId: class, SubjectId: uuid.New(), TeacherId: teacher, Subject: "MTK", Hours: 3}},
+	})
+	kinds := map[string]bool{}
+	for _, item := range unsat {
+		kinds[item.Kind] = true
+	}
+	if !kinds[UnsatClassHours] || !kinds[UnsatTeacherHours] || !kinds[UnsatLesson] {
+		t.Fatalf("unexpected unsat: %+v", unsat)
+	}
+}
+
+func TestSolveNoSolution(t *testing.T) {
+	periods := testPeriods()
+	shared, morningA, morningB := uuid.New(), uuid.New(), uuid.New()
+	classA, classB := uuid.New(), uuid.New()
+
+	//both classes need the shared teacher in the second period
+	input := SolverInput{
+		Days: []int{1},
+		Periods: periods,
+		Lessons: []SolverLesson{
+			{ClassId: classA, SubjectId: uuid.New(), TeacherId: morningA, Subject: "BIO", Hours: 1},
+			{ClassId: classA, SubjectId: uuid.New(), TeacherId: shared, Subject: "MTK", Hours: 1},
+			{ClassId: classB, SubjectId: uuid.New(), TeacherId: morningB, Subject: "KIM", Hours: 1},
+			{ClassId: classB, SubjectId: uuid.New(), TeacherId: shared, Subject: "MTK", Hours: 1},
+		},
+		Unavailable: []types.Unavailability{
+			{TeacherId: &morningA, DayOfWeek: 1, PeriodId: &periods[1].Id},
+			{TeacherId: &morningB, DayOfWeek: 1, PeriodId: &periods[1].Id},
+		},
+	}
+
+	slots, unsat := Solve(input)
+	if slots != nil || len(unsat) == 0 || unsat[0].Kind != UnsatNoSolution {
+		t.Fatalf("expected no solution: %+v", unsat)
+	}
+	unplaced := 0
+	for _, item := range unsat[1:] {
+		if item.Kind != UnsatUnplaced {
+			t.Fatalf("unexpected unsat: %+v", item)
+		}
+		unplaced++
+	}
+	if unplaced == 0 {
+		t.Fatalf("the unplaced lessons should be reported")
+	}
+}
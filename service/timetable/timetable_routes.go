@@ -0,0 +1,284 @@
+package timetable
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+//type handlerequest that declare the timetable store for a database logic
+type HandleRequest struct {
+	db types.TimetableStore
+	academics types.AcademicStore
+	subjects types.SubjectStore
+}
+
+//func that declare the handler for timetable
+func NewHandlerTimetable(db types.TimetableStore, academics types.AcademicStore, subjects types.SubjectStore) *HandleRequest {
+	return &HandleRequest{db: db, academics: academics, subjects: subjects}
+}
+
+//helper for the term query params, the default is the open term
+func (h *HandleRequest) termParam(ctx context.Context, r *http.Request) (uuid.UUID, error) {
+	if value := r.URL.Query().Get("term_id"); value != "" {
+		return uuid.Parse(value)
+	}
+	term, err := h.academics.GetActiveTerm(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if term == nil {
+		return uuid.Nil, fmt.Errorf("there is no open term")
+	}
+	return term.Id, nil
+}
+
+//func to add a weekly lesson into the timetable of the class
+func (h *HandleRequest) CreateSlot_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the timetable
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//decode and validate the payload
+	var payload types.CreateSlot
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//make the data response for logger if the decode is failed
+		logger.Log.Error("Failed to decode data payload", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data!", err.Error())
+		return 
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, errorValidate := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("error at field: %s, %s", errorValidate.Field(), errorValidate.Error()))
+		}
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return 
+	}
+
+	//the time of the lesson is taken from the period of the bell schedule
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if payload.PeriodId != nil {
+		period, err := h.db.GetPeriodById(ctx, *payload.PeriodId)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the period!", err.Error())
+			return 
+		}
+		if period == nil {
+			utils.ResponseError(w, http.StatusBadRequest, "The period is not exist!", false)
+			return 
+		}
+		payload.StartTime = shortClock(period.StartTime)
+		payload.EndTime = shortClock(period.EndTime)
+	}
+	if payload.EndTime <= payload.StartTime {
+		utils.ResponseError(w, http.StatusBadRequest, "The end time must be after the start time!", false)
+		return 
+	}
+
+	//the room must be active
+	if payload.RoomId != nil {
+		room, err := h.db.GetRoomById(ctx, *payload.RoomId)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the room!", err.Error())
+			return 
+		}
+		if room == nil || !room.IsActive {
+			utils.ResponseError(w, http.StatusBadRequest, "The room is not exist or not active!", false)
+			return 
+		}
+	}
+
+	//the name of the lesson is taken from the subject of the catalogue
+	if payload.SubjectId != nil {
+		subject, err := h.subjects.GetSubjectById(ctx, *payload.SubjectId)
+		if err != nil {
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to get the subject!", err.Error())
+			return 
+		}
+		if subject == nil || !subject.IsActive {
+			utils.ResponseError(w, http.StatusBadRequest, "The subject is not exist or not active!", false)
+			return 
+		}
+		payload.Subject = subject.Name
+	}
+
+	//make the struct of the slot and execute the query
+	slot := &types.TimetableSlot{
+		Id: uuid.New(),
+		ClassId: class_id,
+		TermId: payload.TermId,
+		DayOfWeek: payload.DayOfWeek,
+		StartTime: payload.StartTime,
+		EndTime: payload.EndTime,
+		Subject: payload.Subject,
+		SubjectId: payload.SubjectId,
+		TeacherId: payload.TeacherId,
+		PeriodId: payload.PeriodId,
+		RoomId: payload.RoomId,
+		IsPinned: payload.Pinned,
+		Created_at: time.Now().UTC(),
+		Updated_at: time.Now().UTC(),
+	}
+	conflicts, err := h.db.CreateSlot(ctx, slot)
+	if err != nil {
+		//logger if some error is detected when we want to create it
+		logger.Log.Error("Failed to create a new slot", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the slot!", err.Error())
+		return 
+	}
+	if len(conflicts) > 0 {
+		utils.ResponseError(w, http.StatusConflict, "The slot is rejected because of the conflicts!", conflicts)
+		return 
+	}
+
+	//return a final value
+	utils.ResponseSuccess(w, http.StatusCreated, "Create a new slot has been successfully", slot)
+
+}
+
+//func to get the weekly timetable of the class (?term_id=), the default is the open term
+func (h *HandleRequest) ClassTimetable_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	class_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	term_id, err := h.termParam(ctx, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the term!", err.Error())
+		return 
+	}
+
+	//execute the query
+	slots, err := h.db.GetClassTimetable(ctx, class_id, term_id)
+	if err != nil {
+		//logger if the response is failed
+		logger.Log.Error("Failed to get the timetable", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the timetable!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Get the timetable has been successfully", slots)
+
+}
+
+//func to delete a slot of the timetable
+func (h *HandleRequest) DeleteSlot_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get the request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//make the logger data response for info
+		logger.Log.Info("Failed to get the request id from this func!", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the request id!", false)
+		return 
+	}
+
+	//only guru and admin can manage the timetable
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "guru" && role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//declare the id of the parameters
+	slot_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
+		return 
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.DeleteSlot(ctx, slot_id); err != nil {
+		//logger if some error is detected
+		logger.Log.Error("Failed to delete the slot", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to delete the slot!", err.Error())
+		return 
+	}
+
+	//return a final result
+	utils.ResponseSuccess(w, http.StatusOK, "Delete the slot has been successfully", slot_id)
+
+}
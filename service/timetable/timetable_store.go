@@ -0,0 +1,682 @@
+package timetable
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+)
+
+//type for a store timetable
+type TimetableStore struct {
+	db *sqlx.DB
+}
+
+//func that we use when we want to use the store from this db
+func NewTimetableStore(db *sqlx.DB) *TimetableStore {
+	return &TimetableStore{db: db}
+}
+
+//the column that we select in every query
+const slotColumns = `id, class_id, term_id, day_of_week, start_time, end_time, subject, subject_id, teacher_id, period_id, room_id, is_pinned, created_at, updated_at`
+
+//func to create a new slot of the timetable, the slot is checked against the other slots of the
+//term and the weekly hours of the subject, the slot is not saved when there is any conflict
+func (s *TimetableStore) CreateSlot(ctx context.Context, slot *types.TimetableSlot) ([]types.SlotConflict, error) {
+
+	//make the transaction, serializable so two slots cannot take the same time together
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the default teacher is the guru assigned to the subject of the class
+	if slot.TeacherId == nil && slot.SubjectId != nil {
+		var teacher uuid.UUID
+		err := tx.GetContext(ctx, &teacher, `
+			SELECT teacher_id FROM teaching_assignments WHERE subject_id = $1 AND class_id = $2 AND term_id = $3;
+		`, slot.SubjectId, slot.ClassId, slot.TermId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("Failed to get the teacher of the subject! " + err.Error())
+		}
+		if err == nil {
+			slot.TeacherId = &teacher
+		}
+	}
+
+	//get the slots of the same day that share the class, the teacher or the room
+	existing := []types.TimetableSlot{}
+	if err := tx.SelectContext(ctx, &existing, `
+		SELECT `+slotColumns+` FROM timetable_slots
+		WHERE term_id = $1 AND day_of_week = $2
+		AND (class_id = $3 OR teacher_id = $4 OR room_id = $5);
+	`, slot.TermId, slot.DayOfWeek, slot.ClassId, slot.TeacherId, slot.RoomId); err != nil {
+		return nil, errors.New("Failed to get the slots of the day! " + err.Error())
+	}
+	conflicts := DetectConflicts(*slot, existing)
+
+	//the weekly hours of the subject in the curriculum of the class
+	if slot.SubjectId != nil {
+		var required *int
+		var hours int
+		err := tx.GetContext(ctx, &hours, `
+			SELECT h.hours_per_week FROM classes c
+			JOIN subject_hours h ON h.grade_level = c.grade_level AND (h.major = '' OR LOWER(h.major) = LOWER(c.major))
+			WHERE c.id = $1 AND h.subject_id = $2
+			ORDER BY h.major DESC LIMIT 1;
+		`, slot.ClassId, slot.SubjectId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("Failed to get the weekly hours of the subject! " + err.Error())
+		}
+		if err == nil {
+			required = &hours
+		}
+		var planned int
+		if err := tx.GetContext(ctx, &planned, `
+			SELECT COUNT(*) FROM timetable_slots WHERE class_id = $1 AND term_id = $2 AND subject_id = $3;
+		`, slot.ClassId, slot.TermId, slot.SubjectId); err != nil {
+			return nil, errors.New("Failed to count the lessons of the subject! " + err.Error())
+		}
+		if conflict := CheckQuota(*slot, required, planned); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	//base query
+	query := `
+		INSERT INTO timetable_slots 
+		(id, class_id, term_id, day_of_week, start_time, end_time, subject, subject_id, teacher_id, period_id, room_id, is_pinned, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+	`
+
+	//execute the query
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		slot.Id,
+		slot.ClassId,
+		slot.TermId,
+		slot.DayOfWeek,
+		slot.StartTime,
+		slot.EndTime,
+		slot.Subject,
+		slot.SubjectId,
+		slot.TeacherId,
+		slot.PeriodId,
+		slot.RoomId,
+		slot.IsPinned,
+		slot.Created_at,
+		slot.Updated_at,
+	); err != nil {
+		return nil, errors.New("Failed to create a new slot! " + err.Error())
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+
+	return nil, nil
+
+}
+
+//func to get the slot by id
+func (s *TimetableStore) GetSlotById(ctx context.Context, id uuid.UUID) (*types.TimetableSlot, error) {
+
+	//execute the query
+	var slot types.TimetableSlot
+	if err := s.db.GetContext(ctx, &slot, `SELECT `+slotColumns+` FROM timetable_slots WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the slot: %w", err)
+	}
+
+	return &slot, nil
+
+}
+
+//func to get the weekly timetable of the class in the term
+func (s *TimetableStore) GetClassTimetable(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]types.TimetableSlot, error) {
+
+	//base query
+	query := `
+		SELECT ` + slotColumns + ` FROM timetable_slots
+		WHERE class_id = $1 AND term_id = $2 ORDER BY day_of_week, start_time;
+	`
+
+	//execute the query
+	slots := []types.TimetableSlot{}
+	if err := s.db.SelectContext(ctx, &slots, query, classId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the timetable: %w", err)
+	}
+
+	return slots, nil
+
+}
+
+//func to delete the slot, the lesson attendance of the slot is deleted too
+func (s *TimetableStore) DeleteSlot(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM timetable_slots WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the slot! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The slot is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to get the planned lessons of every subject of the class against the weekly hours of the curriculum,
+//a planned subject that is not in the curriculum has zero required hours
+func (s *TimetableStore) GetClassQuota(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]types.SubjectQuota, error) {
+
+	//base query
+	query := `
+		WITH curriculum AS (
+			SELECT DISTINCT ON (h.subject_id) h.subject_id, h.hours_per_week
+			FROM classes c
+			JOIN subject_hours h ON h.grade_level = c.grade_level AND (h.major = '' OR LOWER(h.major) = LOWER(c.major))
+			WHERE c.id = $1
+			ORDER BY h.subject_id, h.major DESC
+		), planned AS (
+			SELECT subject_id, COUNT(*) AS planned FROM timetable_slots
+			WHERE class_id = $1 AND term_id = $2 AND subject_id IS NOT NULL
+			GROUP BY subject_id
+		)
+		SELECT sb.id AS subject_id, sb.code AS subject_code, sb.name AS subject_name,
+			COALESCE(cu.hours_per_week, 0) AS required, COALESCE(p.planned, 0) AS planned
+		FROM curriculum cu
+		FULL JOIN planned p ON p.subject_id = cu.subject_id
+		JOIN subjects sb ON sb.id = COALESCE(cu.subject_id, p.subject_id)
+		WHERE sb.is_active OR p.planned IS NOT NULL
+		ORDER BY sb.code;
+	`
+
+	//execute the query
+	quotas := []types.SubjectQuota{}
+	if err := s.db.SelectContext(ctx, &quotas, query, classId, termId); err != nil {
+		return nil, fmt.Errorf("failed to get the weekly hours of the class: %w", err)
+	}
+	for i := range quotas {
+		quotas[i].Status = QuotaStatus(quotas[i].Required, quotas[i].Planned)
+	}
+
+	return quotas, nil
+
+}
+
+//the column of the period and the room
+const periodColumns = `id, number, name, start_time, end_time, created_at, updated_at`
+const roomColumns = `id, code, name, capacity, is_active, created_at, updated_at`
+
+//func to create a new period of the bell schedule
+func (s *TimetableStore) CreatePeriod(ctx context.Context, period *types.Period) error {
+
+	//base query
+	query := `
+		INSERT INTO periods (id, number, name, start_time, end_time, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		period.Id,
+		period.Number,
+		period.Name,
+		period.StartTime,
+		period.EndTime,
+		period.Created_at,
+		period.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new period! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the period by id
+func (s *TimetableStore) GetPeriodById(ctx context.Context, id uuid.UUID) (*types.Period, error) {
+
+	//execute the query
+	var period types.Period
+	if err := s.db.GetContext(ctx, &period, `SELECT `+periodColumns+` FROM periods WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the period: %w", err)
+	}
+
+	return &period, nil
+
+}
+
+//func to get the bell schedule
+func (s *TimetableStore) GetPeriods(ctx context.Context) ([]types.Period, error) {
+
+	//execute the query
+	periods := []types.Period{}
+	if err := s.db.SelectContext(ctx, &periods, `SELECT `+periodColumns+` FROM periods ORDER BY number;`); err != nil {
+		return nil, fmt.Errorf("failed to get the periods: %w", err)
+	}
+
+	return periods, nil
+
+}
+
+//func to delete the period, the slots of the period keep their time
+func (s *TimetableStore) DeletePeriod(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM periods WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the period! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The period is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to create a new room
+func (s *TimetableStore) CreateRoom(ctx context.Context, room *types.Room) error {
+
+	//base query
+	query := `
+		INSERT INTO rooms (id, code, name, capacity, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		room.Id,
+		room.Code,
+		room.Name,
+		room.Capacity,
+		room.IsActive,
+		room.Created_at,
+		room.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create a new room! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the room by id
+func (s *TimetableStore) GetRoomById(ctx context.Context, id uuid.UUID) (*types.Room, error) {
+
+	//execute the query
+	var room types.Room
+	if err := s.db.GetContext(ctx, &room, `SELECT `+roomColumns+` FROM rooms WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the room: %w", err)
+	}
+
+	return &room, nil
+
+}
+
+//func to get all the rooms
+func (s *TimetableStore) GetRooms(ctx context.Context, onlyActive bool) ([]types.Room, error) {
+
+	//execute the query
+	rooms := []types.Room{}
+	if err := s.db.SelectContext(ctx, &rooms, `SELECT `+roomColumns+` FROM rooms WHERE ($1 = FALSE OR is_active) ORDER BY code;`, onlyActive); err != nil {
+		return nil, fmt.Errorf("failed to get the rooms: %w", err)
+	}
+
+	return rooms, nil
+
+}
+
+//func to update the room
+func (s *TimetableStore) UpdateRoom(ctx context.Context, id uuid.UUID, payload types.UpdateRoom) error {
+
+	//setup the args and args id
+	var settings []string
+	argsId := 1
+	var args []interface{}
+
+	//if the name is changed
+	if payload.Name != nil {
+		settings = append(settings, fmt.Sprintf("name=$%d", argsId))
+		args = append(args, *payload.Name)
+		argsId++
+	}
+
+	//if the capacity is changed
+	if payload.Capacity != nil {
+		settings = append(settings, fmt.Sprintf("capacity=$%d", argsId))
+		args = append(args, *payload.Capacity)
+		argsId++
+	}
+
+	//if the room is activated or deactivated
+	if payload.IsActive != nil {
+		settings = append(settings, fmt.Sprintf("is_active=$%d", argsId))
+		args = append(args, *payload.IsActive)
+		argsId++
+	}
+
+	//validate if the no one field changes
+	if len(args) == 0 {
+		return errors.New("No one data changes")
+	}
+
+	//update the updated at
+	settings = append(settings, fmt.Sprintf("updated_at=$%d", argsId))
+	args = append(args, time.Now().UTC())
+	argsId++
+
+	//define a fullquery and execute it
+	fullquery := fmt.Sprintf("UPDATE rooms SET %s WHERE id = $%d", strings.Join(settings, ", "), argsId)
+	args = append(args, id)
+	rows, err := s.db.ExecContext(ctx, fullquery, args...)
+	if err != nil {
+		return errors.New("Failed to update the room! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The room is not exist!")
+	}
+
+	return nil
+
+}
+
+//func to pin or unpin the slot, a pinned slot is kept when the timetable is generated again
+func (s *TimetableStore) SetSlotPinned(ctx context.Context, id uuid.UUID, pinned bool) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `UPDATE timetable_slots SET is_pinned = $1, updated_at = $2 WHERE id = $3;`, pinned, time.Now().UTC(), id)
+	if err != nil {
+		return errors.New("Failed to pin the slot! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The slot is not exist!")
+	}
+
+	return nil
+
+}
+
+//the column of the unavailable times
+const unavailabilityColumns = `id, teacher_id, room_id, day_of_week, period_id, reason, created_at, updated_at`
+
+//func to create a new unavailable time of the teacher or the room
+func (s *TimetableStore) CreateUnavailability(ctx context.Context, entry *types.Unavailability) error {
+
+	//base query
+	query := `
+		INSERT INTO timetable_unavailability (id, teacher_id, room_id, day_of_week, period_id, reason, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	//execute the query
+	if _, err := s.db.ExecContext(
+		ctx,
+		query,
+		entry.Id,
+		entry.TeacherId,
+		entry.RoomId,
+		entry.DayOfWeek,
+		entry.PeriodId,
+		entry.Reason,
+		entry.Created_at,
+		entry.Updated_at,
+	); err != nil {
+		return errors.New("Failed to create the unavailable time! " + err.Error())
+	}
+
+	return nil
+
+}
+
+//func to get the unavailable times, every empty filter is ignored
+func (s *TimetableStore) GetUnavailability(ctx context.Context, teacherId *uuid.UUID, roomId *uuid.UUID) ([]types.Unavailability, error) {
+
+	//base query
+	query := `
+		SELECT ` + unavailabilityColumns + ` FROM timetable_unavailability
+		WHERE ($1::UUID IS NULL OR teacher_id = $1) AND ($2::UUID IS NULL OR room_id = $2)
+		ORDER BY day_of_week, created_at;
+	`
+
+	//execute the query
+	entries := []types.Unavailability{}
+	if err := s.db.SelectContext(ctx, &entries, query, teacherId, roomId); err != nil {
+		return nil, fmt.Errorf("failed to get the unavailable times: %w", err)
+	}
+
+	return entries, nil
+
+}
+
+//func to delete the unavailable time
+func (s *TimetableStore) DeleteUnavailability(ctx context.Context, id uuid.UUID) error {
+
+	//execute the query
+	rows, err := s.db.ExecContext(ctx, `DELETE FROM timetable_unavailability WHERE id = $1;`, id)
+	if err != nil {
+		return errors.New("Failed to delete the unavailable time! " + err.Error())
+	}
+	if result, err := rows.RowsAffected(); err != nil || result == 0 {
+		return errors.New("The unavailable time is not exist!")
+	}
+
+	return nil
+
+}
+
+//the weekly hours of the subject of the class with the assigned teacher
+type demandRow struct {
+	ClassId 		uuid.UUID 		`db:"class_id"`
+	SubjectId 		uuid.UUID 		`db:"subject_id"`
+	Subject 		string 			`db:"subject"`
+	Hours 			int 			`db:"hours"`
+	Pinned 			int 			`db:"pinned"`
+	TeacherId 		*uuid.UUID 		`db:"teacher_id"`
+}
+
+//func to generate the timetable of the classes in the term with the solver, the pinned slots and the
+//slots of the other classes are kept, the other slots of the classes are replaced when it is not a dry run
+func (s *TimetableStore) GenerateTimetable(ctx context.Context, generation *types.TimetableGeneration) (*types.GenerationResult, error) {
+
+	//make the transaction, serializable so the timetable is not changed while it is generated
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return nil, errors.New("Failed to settings the db transactions")
+	}
+	defer tx.Rollback()
+
+	//the default classes are the classes of the year of the term
+	classIds := make([]string, 0, len(generation.ClassIds))
+	for _, id := range generation.ClassIds {
+		classIds = append(classIds, id.String())
+	}
+	if len(classIds) == 0 {
+		if err := tx.SelectContext(ctx, &classIds, `
+			SELECT c.id::TEXT FROM classes c
+			JOIN academic_years y ON y.name = c.academic_year
+			JOIN terms t ON t.academic_year_id = y.id
+			WHERE t.id = $1 ORDER BY c.name;
+		`, generation.TermId); err != nil {
+			return nil, errors.New("Failed to get the classes of the term! " + err.Error())
+		}
+	}
+	if len(classIds) == 0 {
+		return nil, errors.New("There is no class to generate the timetable!")
+	}
+
+	//the weekly hours of every subject of the classes minus the pinned hours
+	demand := []demandRow{}
+	if err := tx.SelectContext(ctx, &demand, `
+		WITH curriculum AS (
+			SELECT DISTINCT ON (c.id, h.subject_id) c.id AS class_id, h.subject_id, h.hours_per_week
+			FROM classes c
+			JOIN subject_hours h ON h.grade_level = c.grade_level AND (h.major = '' OR LOWER(h.major) = LOWER(c.major))
+			WHERE c.id = ANY($1::UUID[])
+			ORDER BY c.id, h.subject_id, h.major DESC
+		)
+		SELECT cu.class_id, cu.subject_id, sb.name AS subject, cu.hours_per_week AS hours, ta.teacher_id,
+			(SELECT COUNT(*) FROM timetable_slots ts
+			 WHERE ts.class_id = cu.class_id AND ts.subject_id = cu.subject_id AND ts.term_id = $2 AND ts.is_pinned) AS pinned
+		FROM curriculum cu
+		JOIN subjects sb ON sb.id = cu.subject_id AND sb.is_active
+		JOIN classes c ON c.id = cu.class_id
+		LEFT JOIN teaching_assignments ta ON ta.class_id = cu.class_id AND ta.subject_id = cu.subject_id AND ta.term_id = $2
+		ORDER BY c.name, sb.code;
+	`, pq.StringArray(classIds), generation.TermId); err != nil {
+		return nil, errors.New("Failed to get the weekly hours of the classes! " + err.Error())
+	}
+
+	//make the input of the solver
+	input := SolverInput{
+		TermId: generation.TermId,
+		Days: generation.Days,
+		MaxSteps: generation.MaxSteps,
+	}
+	result := &types.GenerationResult{TermId: generation.TermId, Slots: []types.TimetableSlot{}, Unsatisfied: []types.UnsatConstraint{}}
+	for _, row := range demand {
+		hours := row.Hours - row.Pinned
+		if hours <= 0 {
+			continue
+		}
+		if row.TeacherId == nil {
+			item := row
+			result.Unsatisfied = append(result.Unsatisfied, types.UnsatConstraint{
+				Kind: UnsatAssignment,
+				Message: fmt.Sprintf("%s has no teacher assigned in the term", row.Subject),
+				ClassId: &item.ClassId,
+				SubjectId: &item.SubjectId,
+			})
+			continue
+		}
+		input.Lessons = append(input.Lessons, SolverLesson{
+			ClassId: row.ClassId,
+			SubjectId: row.SubjectId,
+			TeacherId: *row.TeacherId,
+			Subject: row.Subject,
+			Hours: hours,
+		})
+	}
+	if err := tx.SelectContext(ctx, &input.Periods, `SELECT `+periodColumns+` FROM periods ORDER BY number;`); err != nil {
+		return nil, errors.New("Failed to get the periods! " + err.Error())
+	}
+	if len(input.Periods) == 0 {
+		return nil, errors.New("There is no period in the bell schedule!")
+	}
+	if err := tx.SelectContext(ctx, &input.Rooms, `SELECT id FROM rooms WHERE is_active ORDER BY code;`); err != nil {
+		return nil, errors.New("Failed to get the rooms! " + err.Error())
+	}
+	if err := tx.SelectContext(ctx, &input.Occupied, `
+		SELECT `+slotColumns+` FROM timetable_slots
+		WHERE term_id = $1 AND (is_pinned OR NOT (class_id = ANY($2::UUID[])));
+	`, generation.TermId, pq.StringArray(classIds)); err != nil {
+		return nil, errors.New("Failed to get the kept slots! " + err.Error())
+	}
+	if err := tx.SelectContext(ctx, &input.Unavailable, `SELECT `+unavailabilityColumns+` FROM timetable_unavailability;`); err != nil {
+		return nil, errors.New("Failed to get the unavailable times! " + err.Error())
+	}
+
+	//run the solver
+	slots, unsat := Solve(input)
+	result.Unsatisfied = append(result.Unsatisfied, unsat...)
+	if len(result.Unsatisfied) > 0 {
+		return result, nil
+	}
+	now := time.Now().UTC()
+	for i := range slots {
+		slots[i].Id = uuid.New()
+		slots[i].Created_at = now
+		slots[i].Updated_at = now
+	}
+	result.Slots = slots
+	if generation.DryRun {
+		return result, nil
+	}
+
+	//the slots with the lesson attendance cannot be replaced
+	var recorded int
+	if err := tx.GetContext(ctx, &recorded, `
+		SELECT COUNT(*) FROM lesson_attendance la
+		JOIN timetable_slots ts ON ts.id = la.slot_id
+		WHERE ts.term_id = $1 AND ts.class_id = ANY($2::UUID[]) AND NOT ts.is_pinned;
+	`, generation.TermId, pq.StringArray(classIds)); err != nil {
+		return nil, errors.New("Failed to check the lesson attendance! " + err.Error())
+	}
+	if recorded > 0 {
+		return nil, errors.New("The current timetable already has lesson attendance, pin the slots that must be kept!")
+	}
+
+	//replace the slots of the classes
+	rows, err := tx.ExecContext(ctx, `
+		DELETE FROM timetable_slots WHERE term_id = $1 AND class_id = ANY($2::UUID[]) AND NOT is_pinned;
+	`, generation.TermId, pq.StringArray(classIds))
+	if err != nil {
+		return nil, errors.New("Failed to delete the old slots! " + err.Error())
+	}
+	replaced, _ := rows.RowsAffected()
+	result.Replaced = int(replaced)
+	query := `
+		INSERT INTO timetable_slots 
+		(id, class_id, term_id, day_of_week, start_time, end_time, subject, subject_id, teacher_id, period_id, room_id, is_pinned, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, FALSE, $12, $13);
+	`
+	for _, slot := range slots {
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			slot.Id,
+			slot.ClassId,
+			slot.TermId,
+			slot.DayOfWeek,
+			slot.StartTime,
+			slot.EndTime,
+			slot.Subject,
+			slot.SubjectId,
+			slot.TeacherId,
+			slot.PeriodId,
+			slot.RoomId,
+			slot.Created_at,
+			slot.Updated_at,
+		); err != nil {
+			return nil, errors.New("Failed to save the generated slot! " + err.Error())
+		}
+	}
+
+	//commit the transaction
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("Failed to commit the query of transaction!" + err.Error())
+	}
+	result.Saved = true
+
+	return result, nil
+
+}
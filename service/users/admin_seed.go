@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+)
+
+// the min length of the password of the first admin
+const minAdminPassword = 8
+
+// SeedAdmin makes the first admin from the config when there is no admin yet, so the admin can create the
+// accounts of the guru and the orangtua. It returns true when the admin is made, nothing is done when an
+// admin already exists.
+func SeedAdmin(ctx context.Context, db types.UserStore, username string, email string, password string) (bool, error) {
+
+	total, err := db.CountUsersByRole(ctx, "admin")
+	if err != nil {
+		return false, err
+	}
+	if total > 0 {
+		return false, nil
+	}
+
+	//the first admin is required to use the other endpoints
+	if email == "" || password == "" {
+		return false, errors.New("There is no admin yet, set ADMIN_EMAIL and ADMIN_PASSWORD to create the first admin!")
+	}
+	if !utils.IsValidEmail(email) {
+		return false, errors.New("ADMIN_EMAIL is not a valid email!")
+	}
+	if len(password) < minAdminPassword {
+		return false, errors.New("ADMIN_PASSWORD must have at least 8 characters!")
+	}
+	if username == "" {
+		username = "admin"
+	}
+
+	hash_password, err := utils.HashPassword(password)
+	if err != nil {
+		return false, errors.New("Failed to hash the password of the admin! " + err.Error())
+	}
+	now := time.Now().UTC()
+	if err := db.CreateUser(ctx, &types.User{
+		Id: uuid.New(),
+		Username: username,
+		Email: email,
+		Password: hash_password,
+		Role: "admin",
+		Created_at: now,
+		Updated_at: now,
+	}); err != nil {
+		return false, errors.New("Failed to create the first admin! " + err.Error())
+	}
+
+	return true, nil
+
+}
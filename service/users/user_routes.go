@@ -5,8 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
+	"path"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -16,17 +15,29 @@ import (
 
 	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware"
 	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/storage"
 	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
 	"github.com/ArkaniLoveCoding/Shcool-manajement/utils"
+	"github.com/ArkaniLoveCoding/Shcool-manajement/utils/imaging"
 )
 
 // this is for router that token is not verified in their function!
 type HandleRequest struct {
-	db types.UserStore
+	db     types.UserStore
+	files  storage.Storage
+	urlTTL time.Duration
 }
 
-func NewHandlerUser(db types.UserStore) *HandleRequest {
-	return &HandleRequest{db: db}
+func NewHandlerUser(db types.UserStore, files storage.Storage, urlTTL time.Duration) *HandleRequest {
+	return &HandleRequest{db: db, files: files, urlTTL: urlTTL}
+}
+
+//the accepted content type of the profile image, every upload is decoded and re-encoded anyway
+var profileImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
 }
 
 // controler that take the services on it
@@ -85,6 +96,9 @@ func (h *HandleRequest) Register_Bp(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	//the self registration is only for the siswa, the other roles are made by the admin
+	payload.Role = "siswa"
+
 	// validate if the email and username has been already exist
 	users, err := h.db.GetUserByEmailAndUsername(payload.Email, payload.Username)
 	if err != nil {
@@ -161,6 +175,116 @@ func (h *HandleRequest) Register_Bp(w http.ResponseWriter, r *http.Request) {
 
 }
 
+//controller services for the admin to create the account of every role, the guru, the admin and the
+//orangtua cannot register themselves
+func (h *HandleRequest) CreateAccount_Bp(w http.ResponseWriter, r *http.Request) {
+
+	//get request id from this func
+	requestID := middleware.GetRequestID(r)
+	if requestID == "" {
+		//logger the data response if request id is zero value
+		logger.Log.Info("Failed to get the request id", 
+			zap.String("client_ip", r.RemoteAddr),
+			zap.String("path", r.URL.Path),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to detect the request id!", false)
+		return 
+	}
+
+	//only the admin can create the account
+	role, err := middleware.GetRoleMiddleware(w, r)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the middleware role", err.Error())
+		return 
+	}
+	if role != "admin" {
+		utils.ResponseError(w, http.StatusForbidden, "Failed to access this method!", false)
+		return 
+	}
+
+	//create the payload of the json
+	var payload types.CreateAccount
+	if err := utils.DecodeData(r, &payload); err != nil {
+		//logger the data response
+		logger.Log.Error("Failed to decode data", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+	)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to decode the data of the json!", err.Error())
+		return 
+	}
+	if !utils.IsValidEmail(payload.Email) {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid email!", false)
+		return
+	}
+	validate := validator.New()
+	if err := validate.Struct(&payload); err != nil {
+		var errors []string
+		for _, validator_payload := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("Error data %s, %s", validator_payload.Field(), validator_payload.Error()))
+		}
+		logger.Log.Warn("Validation failed",
+			zap.String("request_id", requestID),
+			zap.Strings("errors", errors),
+		)
+		utils.ResponseError(w, http.StatusBadRequest, "Validation error", errors)
+		return
+	}
+
+	// validate if the email and username has been already exist
+	users, err := h.db.GetUserByEmailAndUsername(payload.Email, payload.Username)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to get the email and username", err.Error())
+		return 
+	}
+	if users != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Username and email has been already exist!!", false)
+		return
+	}
+
+	//hash the password user for a better security 
+	hash_password, err := utils.HashPassword(payload.Password)
+	if err != nil {
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to hash the password of the data user!", err.Error())
+		return
+	}
+	now := time.Now().UTC()
+	final_payload := &types.User{
+		Id: uuid.New(),
+		Username: payload.Username,
+		Email: payload.Email,
+		Password: hash_password,
+		Role: payload.Role,
+		Created_at: now,
+		Updated_at: now,
+	}
+
+	//execute the query
+	ctx, cancle := context.WithTimeout(r.Context(), time.Second * 10)
+	defer cancle()
+	if err := h.db.CreateUser(ctx, final_payload); err != nil {
+		//logger the data response if create user is failed
+		logger.Log.Error("Failed to create the account", 
+			zap.String("request_id", requestID),
+			zap.String("client_ip", r.RemoteAddr),
+			zap.Error(err),
+	)	
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to create a new user", err.Error())
+		return
+	}
+
+	//return a response success without the password
+	utils.ResponseSuccess(w, http.StatusCreated, "Created a new account has been successfully!", types.UserResponse{
+		Id: final_payload.Id,
+		Username: final_payload.Username,
+		Email: final_payload.Email,
+		Role: final_payload.Role,
+		Created_at: now.Format("2006-01-02"),
+		Updated_at: now.Format("2006-01-02"),
+	})
+
+}
+
 //controller services for handling the router login
 func (h *HandleRequest) Login_Bp(w http.ResponseWriter, r *http.Request) {
 
@@ -326,6 +450,7 @@ func (h *HandleRequest) Profile_Bp(w http.ResponseWriter, r *http.Request) {
 		"username": users.Username,
 		"email": users.Email,
 		"role": users.Role,
+		"profile_image": h.profileImageURL(r.Context(), users.Profile_Image),
 	}
 
 	//return final result
@@ -394,10 +519,12 @@ func (h *HandleRequest) Update_Bp(w http.ResponseWriter, r *http.Request) {
 			return 
 		}
 	}
+	var old_profile_image string
 	if err == nil {
-		//validate the type of the file profile_image in db and read the file
-		buff := make([]byte, 512)
-		read_buff, err := file_image.Read(buff)
+		defer file_image.Close()
+
+		//read the whole file, the size is already limited by the max bytes reader
+		data, err := io.ReadAll(file_image)
 		if err != nil {
 			//logger the data response if the read file image is failed
 			logger.Log.Error("Failed to read image file", 
@@ -407,66 +534,54 @@ func (h *HandleRequest) Update_Bp(w http.ResponseWriter, r *http.Request) {
 			utils.ResponseError(w, http.StatusBadRequest, "Failed to read the image file!", err.Error())
 			return 
 		}
-		if read_buff == 0 {
+		if len(data) == 0 {
 			utils.ResponseError(w, http.StatusBadRequest, "Invalid lenght of the file data!", false)
 			return
 		}
-		type_content := http.DetectContentType(buff)
-		if type_content != "image/jpg" && type_content != "image/png" && type_content != "image/jpeg" {
+
+		//validate the type of the file profile_image
+		type_content := http.DetectContentType(data)
+		if !profileImageTypes[type_content] {
 			//logger the data response if the type of image is failed
 			logger.Log.Error("Failed because the image file is invalid!", 
 				zap.String("request_id", requestID),
 				zap.String("client_ip", r.RemoteAddr),
+				zap.String("filename", header.Filename),
 		)
 			utils.ResponseError(w, http.StatusBadRequest, "Failed content file type!", false)
 			return
 		}
-		file_image.Seek(0, 0)
-
-		//make the name of the file profile image and then join it into a some folder in this project
-		filename := uuid.New().String() + filepath.Ext(header.Filename)
-		upload_dir := "uploads_user"
-		if err := os.MkdirAll(upload_dir, os.ModePerm); err != nil {
-			//logger the data response if the make folder is failed
-			logger.Log.Error("Failed!", 
-				zap.String("request_id", requestID),
-				zap.String("client_ip", r.RemoteAddr),
-		)
-			utils.ResponseError(w, http.StatusBadRequest, "Failed to settings the mkdir all", err.Error())
-			return 
-		}
-		path_final := filepath.Join(upload_dir, filename)
-		folder, err := os.Create(path_final)
+
+		//decode the picture, fix the orientation, strip the exif and make the thumbnails
+		variants, err := imaging.Process(data)
 		if err != nil {
-			//logger the data response if create image file is failed
-			logger.Log.Error("Failed because the image file is invalid!", 
+			//logger the data response if the image cannot be processed
+			logger.Log.Error("Failed to process the profile image", 
 				zap.String("request_id", requestID),
 				zap.String("client_ip", r.RemoteAddr),
+				zap.Error(err),
 		)
-			utils.ResponseError(w, http.StatusBadRequest, "Failed to create the folder!", err.Error())
+			utils.ResponseError(w, http.StatusBadRequest, "Failed to process the profile image!", err.Error())
 			return 
 		}
-		if path_final == "" {
-			utils.ResponseError(w, http.StatusBadRequest, "Failed to create the folder of uploads!", false)
-			return 
-		}
-		defer folder.Close()
 
-		//copy the result into a io reader
-		dst, err := io.Copy(folder, file_image)
-		if err != nil {
-			//logger the data response if the copy io is failed
-			logger.Log.Error("Failed because the lenght of the file is zero!", 
-				zap.String("request_id", requestID),
-				zap.String("client_ip", r.RemoteAddr),
-		)
-			utils.ResponseError(w, http.StatusBadRequest, "Failed to copy the data from fileimage!", err.Error())
-			return 
-		} 
-		if dst == 0 {
-			utils.ResponseError(w, http.StatusBadRequest, "Failed to copy the data because the length of the data is 0", false)
-			return 
+		//the key is the hash of the content, so the same picture is only saved once in the storage
+		key := storage.ContentKey("profiles", data, "")
+		for _, variant := range variants {
+			variant_key := profileVariantKey(key, variant.Size, variant.Format)
+			if err := storage.PutBytes(r.Context(), h.files, variant_key, variant.Data, variant.Format.ContentType); err != nil {
+				//logger the data response if the storage is failed
+				logger.Log.Error("Failed to save the profile image", 
+					zap.String("request_id", requestID),
+					zap.String("client_ip", r.RemoteAddr),
+					zap.String("key", variant_key),
+					zap.Error(err),
+			)
+				utils.ResponseError(w, http.StatusInternalServerError, "Failed to save the profile image!", err.Error())
+				return 
+			}
 		}
+
 		users_profile_image, err := h.db.GetUserById(user_id)
 		if err != nil {
 			//logger the data response if users is failed
@@ -478,25 +593,11 @@ func (h *HandleRequest) Update_Bp(w http.ResponseWriter, r *http.Request) {
 			return 
 		}
 
-		//if the users have a profile image in their data profile, and then they want to update the profile image again
-		//it will be delete the old filename and old path name, that can be replace with new filename and pathname
-		//we use this because, if the profile image picture user that we save it into one folder and
-		//try to think that if a million users is update their proifile image together, its make server down and worst method
-		if users_profile_image.Profile_Image != "" {
-			path_old := users_profile_image.Profile_Image
-			if _, err := os.Stat(path_old); !os.IsNotExist(err) {
-				if err := os.Remove(path_old); err != nil {
-					//logger the data response if the file is not exist
-				logger.Log.Error("Failed ro remove data!", 
-					zap.String("request_id", requestID),
-					zap.String("client_ip", r.RemoteAddr),
-				)
-					utils.ResponseError(w, http.StatusBadRequest, "Failed to remove the new data of uploads!", err.Error())
-					return 
-				}
-			} 
+		//the old picture is removed after the update, only when no other user still points to the same content
+		if users_profile_image.Profile_Image != key {
+			old_profile_image = users_profile_image.Profile_Image
 		}
-		payload.Profile_Image = &path_final
+		payload.Profile_Image = &key
 	}
 
 	// validate if the username, email or password and profile image is nil, it will be return a nil not a string
@@ -525,6 +626,11 @@ func (h *HandleRequest) Update_Bp(w http.ResponseWriter, r *http.Request) {
 		return 
 	}
 
+	//remove the old profile image from the storage if nobody use it anymore
+	if old_profile_image != "" {
+		h.removeProfileImage(r.Context(), requestID, old_profile_image)
+	}
+
 	//get the user data in db
 	users, err := h.db.GetUserById(user_id)
 	if err != nil {
@@ -543,7 +649,7 @@ func (h *HandleRequest) Update_Bp(w http.ResponseWriter, r *http.Request) {
 		Username: users.Username,
 		Email: users.Email,
 		Password: users.Password,
-		Profile_Image: users.Profile_Image,
+		Profile_Image: h.profileImageURL(r.Context(), users.Profile_Image),
 		Role: users.Role,
 		Created_at: time.Now().UTC().Format("2006-01-02"),
 		Updated_at: time.Now().UTC().Format("2006-01-02"),
@@ -554,7 +660,7 @@ func (h *HandleRequest) Update_Bp(w http.ResponseWriter, r *http.Request) {
 
 }
 
-//func to get the profile image picture in postman / url
+//func to get the profile image picture in postman / url, it redirect into a signed url of the storage
 func (h *HandleRequest) Image_Bp(w http.ResponseWriter, r *http.Request) {
 
 	//get the request id from this func
@@ -569,32 +675,111 @@ func (h *HandleRequest) Image_Bp(w http.ResponseWriter, r *http.Request) {
 		return 
 	}
 
-	//to get the params of the file
-	filename_params := mux.Vars(r)
-	filename := filename_params["filename"]
-	if filename == "" {
-		//logger the data response if the params is null
+	//to get the params of the user
+	user_id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		//logger the data response if the params is invalid
 			logger.Log.Error("Failed because the params is invalid!", 
 				zap.String("request_id", requestID),
 				zap.String("client_ip", r.RemoteAddr),
 		)
-		utils.ResponseError(w, http.StatusBadRequest, "Failed to create the params of the image!", false)
+		utils.ResponseError(w, http.StatusBadRequest, "Failed to convert data string into a uuid type!", err.Error())
 		return 
 	}
 
-	//get the path name of the profile image
-	path_name := filepath.Join("uploads_user", filename)
-	if _, err := os.Stat(path_name); os.IsNotExist(err) {
-		//logger the data response the checking data file is invalid
-			logger.Log.Error("Failed because the file is not exist", 
+	//get the key of the profile image
+	users, err := h.db.GetUserById(user_id)
+	if err != nil {
+		//logger the data response if the user is not exist
+			logger.Log.Error("Failed because the id is invalid!", 
 				zap.String("request_id", requestID),
 				zap.String("client_ip", r.RemoteAddr),
 		)
-		utils.ResponseError(w, http.StatusBadRequest, "Failed to detect the file or path name", err.Error())
+		utils.ResponseError(w, http.StatusNotFound, "Failed to get data user from db!", err.Error())
+		return 
+	}
+	if users.Profile_Image == "" {
+		utils.ResponseError(w, http.StatusNotFound, "The user does not have a profile image!", false)
 		return 
 	}
 
-	//serve http for file
-	http.ServeFile(w, r, path_name)
+	//choose the size and the format of the picture (?size=small|medium|original&format=jpeg|png)
+	size, ok := imaging.LookupSize(r.URL.Query().Get("size"))
+	if !ok {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid size of the image, use small, medium or original!", false)
+		return 
+	}
+	format, ok := imaging.LookupFormat(r.URL.Query().Get("format"))
+	if !ok {
+		utils.ResponseError(w, http.StatusBadRequest, "Invalid format of the image, use jpeg or png!", false)
+		return 
+	}
+
+	//make the signed url and redirect the client into it
+	signed_url, err := h.files.SignedURL(r.Context(), profileVariantKey(users.Profile_Image, size, format), h.urlTTL)
+	if err != nil {
+		//logger the data response if the signed url is failed
+			logger.Log.Error("Failed to sign the url of the profile image", 
+				zap.String("request_id", requestID),
+				zap.String("client_ip", r.RemoteAddr),
+				zap.Error(err),
+		)
+		utils.ResponseError(w, http.StatusInternalServerError, "Failed to make the url of the profile image!", err.Error())
+		return 
+	}
+
+	//the browser can reuse the redirect while the signed url is still valid
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(h.urlTTL.Seconds()/2)))
+	http.Redirect(w, r, signed_url, http.StatusFound)
+
+}
+
+//helper to make the signed url of the profile image for the response
+func (h *HandleRequest) profileImageURL(ctx context.Context, key string) string {
+	if key == "" {
+		return ""
+	}
+	signed_url, err := h.files.SignedURL(ctx, profileVariantKey(key, imaging.SizeMedium, imaging.FormatJPEG), h.urlTTL)
+	if err != nil {
+		logger.Log.Warn("Failed to sign the url of the profile image", 
+			zap.String("key", key),
+			zap.Error(err),
+		)
+		return ""
+	}
+	return signed_url
+}
+
+//helper to delete the old profile image when no other users still use the same content
+func (h *HandleRequest) removeProfileImage(ctx context.Context, requestID string, key string) {
+	used, err := h.db.CountUsersByProfileImage(ctx, key)
+	if err != nil || used > 0 {
+		return
+	}
+	keys := []string{key}
+	if path.Ext(key) == "" {
+		keys = keys[:0]
+		for _, size := range imaging.Sizes {
+			for _, format := range imaging.Formats {
+				keys = append(keys, profileVariantKey(key, size, format))
+			}
+		}
+	}
+	for _, variant_key := range keys {
+		if err := h.files.Delete(ctx, variant_key); err != nil {
+			logger.Log.Warn("Failed to remove the old profile image", 
+				zap.String("request_id", requestID),
+				zap.String("key", variant_key),
+				zap.Error(err),
+			)
+		}
+	}
+}
 
-}
\ No newline at end of file
+//helper to get the key of a size variant, the legacy uploads (with an extension) only have one file
+func profileVariantKey(key string, size imaging.Size, format imaging.Format) string {
+	if path.Ext(key) != "" {
+		return key
+	}
+	return key + "/" + size.Name + format.Extension
+}
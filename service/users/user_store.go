@@ -233,3 +233,36 @@ func (s *Store) GetUserById(id uuid.UUID) (*types.User, error) {
 	return &users, nil
 
 }
+
+
+//func to count the users that still use the same profile image key
+func (s *Store) CountUsersByProfileImage(ctx context.Context, key string) (int, error) {
+
+	//base query
+	query := `SELECT COUNT(*) FROM users WHERE profile_image = $1;`
+
+	//execute the query
+	var total int
+	if err := s.store.GetContext(ctx, &total, query, key); err != nil {
+		return 0, fmt.Errorf("failed to count the profile image: %w", err)
+	}
+
+	return total, nil
+
+}
+
+//func to count the users of the role, used to know if the first admin is already made
+func (s *Store) CountUsersByRole(ctx context.Context, role string) (int, error) {
+
+	//base query
+	query := `SELECT COUNT(*) FROM users WHERE role = $1;`
+
+	//execute the query
+	var total int
+	if err := s.store.GetContext(ctx, &total, query, role); err != nil {
+		return 0, fmt.Errorf("failed to count the users of the role: %w", err)
+	}
+
+	return total, nil
+
+}
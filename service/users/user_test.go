@@ -2,9 +2,15 @@ package service
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 
+	"github.com/ArkaniLoveCoding/Shcool-manajement/middleware/logger"
 	"github.com/ArkaniLoveCoding/Shcool-manajement/types"
 )
 
@@ -50,3 +56,134 @@ func (m *mockStore) UpdateDataUser(
 		return m.UpdateUserFn(id, ctx, firstname, lastname, password, email, country, address)
 
 }
+
+//registerStore is the store of the registration tests, it keeps the created users
+type registerStore struct {
+	created []*types.User
+	admins int
+}
+
+func (m *registerStore) GetUserByEmailAndUsername(email string, username string) (*types.User, error) {
+	return nil, nil
+}
+
+func (m *registerStore) CreateUser(ctx context.Context, user *types.User) error {
+	m.created = append(m.created, user)
+	return nil
+}
+
+func (m *registerStore) UpdateDataUser(id uuid.UUID, ctx context.Context, payload types.Update) error {
+	return nil
+}
+
+func (m *registerStore) GetUserById(id uuid.UUID) (*types.User, error) {
+	return nil, nil
+}
+
+func (m *registerStore) CountUsersByProfileImage(ctx context.Context, key string) (int, error) {
+	return 0, nil
+}
+
+func (m *registerStore) CountUsersByRole(ctx context.Context, role string) (int, error) {
+	total := m.admins
+	for _, user := range m.created {
+		if user.Role == role {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func registerRequest(body string, values map[string]any) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(r.Context(), "request_id", "test")
+	for key, value := range values {
+		ctx = context.WithValue(ctx, key, value)
+	}
+	return r.WithContext(ctx)
+}
+
+func TestRegisterRejectsPrivilegedRoles(t *testing.T) {
+	logger.Log = zap.NewNop()
+	for _, role := range []string{"admin", "guru", "orangtua"} {
+		store := &registerStore{}
+		handler := NewHandlerUser(store, nil, 0)
+		w := httptest.NewRecorder()
+		handler.Register_Bp(w, registerRequest(`{"username":"budi","email":"budi@example.com","password":"secret","role":"`+role+`"}`, nil))
+
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Validation error") {
+			t.Fatalf("role %s: expected the validation error, got %d: %s", role, w.Code, w.Body.String())
+		}
+		if len(store.created) != 0 {
+			t.Fatalf("role %s: the user should not be created", role)
+		}
+	}
+}
+
+func TestRegisterIsSiswa(t *testing.T) {
+	logger.Log = zap.NewNop()
+	store := &registerStore{}
+	handler := NewHandlerUser(store, nil, 0)
+	w := httptest.NewRecorder()
+	handler.Register_Bp(w, registerRequest(`{"username":"budi","email":"budi@example.com","password":"secret"}`, nil))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(store.created) != 1 || store.created[0].Role != "siswa" {
+		t.Fatalf("expected one siswa to be created, got %+v", store.created)
+	}
+}
+
+func TestCreateAccountIsAdminOnly(t *testing.T) {
+	logger.Log = zap.NewNop()
+	body := `{"username":"guru","email":"guru@example.com","password":"secret","role":"admin"}`
+
+	store := &registerStore{}
+	w := httptest.NewRecorder()
+	NewHandlerUser(store, nil, 0).CreateAccount_Bp(w, registerRequest(body, map[string]any{"role_user": "guru"}))
+	if w.Code != http.StatusForbidden || len(store.created) != 0 {
+		t.Fatalf("the guru should not create the account, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	NewHandlerUser(store, nil, 0).CreateAccount_Bp(w, registerRequest(body, map[string]any{"role_user": "admin"}))
+	if w.Code != http.StatusCreated || len(store.created) != 1 || store.created[0].Role != "admin" {
+		t.Fatalf("the admin should create the account, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSeedAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	//the fresh install without the config has no way to get an admin
+	store := &registerStore{}
+	if _, err := SeedAdmin(ctx, store, "", "", ""); err == nil {
+		t.Fatalf("expected the missing admin config to fail")
+	}
+	if _, err := SeedAdmin(ctx, store, "", "admin@example.com", "short"); err == nil {
+		t.Fatalf("expected the short password to fail")
+	}
+
+	seeded, err := SeedAdmin(ctx, store, "", "admin@example.com", "secret-admin")
+	if err != nil || !seeded {
+		t.Fatalf("expected the first admin, got %v %v", seeded, err)
+	}
+	if len(store.created) != 1 || store.created[0].Role != "admin" || store.created[0].Username != "admin" || store.created[0].Password == "secret-admin" {
+		t.Fatalf("unexpected admin %+v", store.created)
+	}
+
+	//the next start keeps the admin
+	if seeded, err := SeedAdmin(ctx, store, "", "", ""); err != nil || seeded || len(store.created) != 1 {
+		t.Fatalf("expected no new admin, got %v %v", seeded, err)
+	}
+
+	//the seeded admin can create the guru
+	w := httptest.NewRecorder()
+	body := `{"username":"guru","email":"guru@example.com","password":"secret","role":"guru"}`
+	NewHandlerUser(store, nil, 0).CreateAccount_Bp(w, registerRequest(body, map[string]any{"role_user": store.created[0].Role}))
+	if w.Code != http.StatusCreated || len(store.created) != 2 || store.created[1].Role != "guru" {
+		t.Fatalf("expected the guru account, got %d: %s", w.Code, w.Body.String())
+	}
+}
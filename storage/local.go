@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalStorage keeps the files on the local disk under a root folder
+type LocalStorage struct {
+	root   string
+	signer *Signer
+}
+
+// NewLocalStorage creates the root folder if needed
+func NewLocalStorage(root string, signer *Signer) (*LocalStorage, error) {
+	if root == "" {
+		root = "uploads_user"
+	}
+	if err := os.MkdirAll(root, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create the storage root: %w", err)
+	}
+	return &LocalStorage{root: root, signer: signer}, nil
+}
+
+func (l *LocalStorage) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the file atomically (temp file + rename)
+func (l *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	full, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create the folder: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(full), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create the file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write the file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close the file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), full)
+}
+
+// Get opens the file for reading
+func (l *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	info, err := l.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	full, _ := l.path(key)
+	file, err := os.Open(full)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open the file: %w", err)
+	}
+	return file, info, nil
+}
+
+// Stat returns the metadata of the file
+func (l *LocalStorage) Stat(ctx context.Context, key string) (*Object, error) {
+	full, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(full)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to stat the file: %w", err)
+	}
+	return &Object{
+		Key:         key,
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(full)),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// Delete removes the file, a missing file is not an error
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	full, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove the file: %w", err)
+	}
+	return nil
+}
+
+// SignedURL points to the files route of this api, the route verifies the signature
+func (l *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return l.signer.URL(key, ttl), nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer creates and verifies expiring download urls served by the api itself
+type Signer struct {
+	secret  []byte
+	baseURL string
+}
+
+// NewSigner returns a signer, baseURL is the public prefix of the files route
+func NewSigner(secret string, baseURL string) *Signer {
+	return &Signer{
+		secret:  []byte(secret),
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// URL returns the signed download url for a key
+func (s *Signer) URL(key string, ttl time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+
+	return s.baseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode()
+}
+
+// Verify checks the signature and the expiry of a download url
+func (s *Signer) Verify(key string, expires string, signature string) error {
+	if expires == "" || signature == "" {
+		return errors.New("Missing the signature of the url!")
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errors.New("Invalid expires value!")
+	}
+
+	if !hmac.Equal([]byte(s.sign(key, expires)), []byte(signature)) {
+		return errors.New("Invalid signature!")
+	}
+
+	if time.Now().Unix() > unix {
+		return errors.New("The url has been expired!")
+	}
+
+	return nil
+}
+
+func (s *Signer) sign(key string, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ArkaniLoveCoding/Shcool-manajement/config"
+)
+
+// ErrNotFound is returned when the object key does not exist in the backend
+var ErrNotFound = errors.New("storage: object not found")
+
+// Object describes a stored file
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage is the backend used for every uploaded file (profile images, attachments, ...)
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	Stat(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// New builds the storage backend selected by STORAGE_DRIVER
+func New(cfg config.ConfigParams, signer *Signer) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalStorage(cfg.StorageLocalRoot, signer)
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
+
+// ContentKey returns a content-addressed key (prefix/<sha256><ext>) for the data,
+// so the same file uploaded twice is only stored once
+func ContentKey(prefix string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	return path.Join(prefix, hex.EncodeToString(sum[:])+strings.ToLower(ext))
+}
+
+// PutBytes is a small helper to upload an in-memory file
+func PutBytes(ctx context.Context, s Storage, key string, data []byte, contentType string) error {
+	return s.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+// ValidKey rejects keys that could escape the storage root
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
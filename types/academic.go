@@ -0,0 +1,85 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AcademicStore interface {
+	CreateAcademicYear(ctx context.Context, year *AcademicYear) error
+	GetAcademicYearById(ctx context.Context, id uuid.UUID) (*AcademicYear, error)
+	GetAcademicYearByName(ctx context.Context, name string) (*AcademicYear, error)
+	GetActiveAcademicYear(ctx context.Context) (*AcademicYear, error)
+	GetAllAcademicYears(ctx context.Context) ([]AcademicYear, error)
+	ActivateAcademicYear(ctx context.Context, id uuid.UUID) error
+	CreateTerm(ctx context.Context, term *Term) error
+	GetTermById(ctx context.Context, id uuid.UUID) (*Term, error)
+	GetTermsByAcademicYear(ctx context.Context, academicYearId uuid.UUID) ([]Term, error)
+	GetActiveTerm(ctx context.Context) (*Term, error)
+	OpenTerm(ctx context.Context, id uuid.UUID) (int64, error)
+	CloseTerm(ctx context.Context, id uuid.UUID) error
+}
+
+type AcademicYear struct {
+	Id 				uuid.UUID 		`db:"id"`
+	Name 			string 			`db:"name"`
+	StartDate 		time.Time 		`db:"start_date"`
+	EndDate 		time.Time 		`db:"end_date"`
+	IsActive 		bool 			`db:"is_active"`
+	Created_at 		time.Time 		`db:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at"`
+}
+
+type CreateAcademicYear struct {
+	Name 			string 			`json:"name" validate:"required,len=9"`
+	StartDate 		string 			`json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate 		string 			`json:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+type AcademicYearResponse struct {
+	Id 				uuid.UUID 		`json:"id"`
+	Name 			string 			`json:"name"`
+	StartDate 		string 			`json:"start_date"`
+	EndDate 		string 			`json:"end_date"`
+	IsActive 		bool 			`json:"is_active"`
+	Created_at 		string 			`json:"created_at"`
+	Updated_at 		string 			`json:"updated_at"`
+}
+
+// Term is a semester of an academic year, the status is planned, open or closed
+type Term struct {
+	Id 					uuid.UUID 		`db:"id"`
+	AcademicYearId 		uuid.UUID 		`db:"academic_year_id"`
+	AcademicYear 		string 			`db:"academic_year"`
+	Name 				string 			`db:"name"`
+	Semester 			int 			`db:"semester"`
+	StartDate 			time.Time 		`db:"start_date"`
+	EndDate 			time.Time 		`db:"end_date"`
+	Status 				string 			`db:"status"`
+	OpenedAt 			*time.Time 		`db:"opened_at"`
+	ClosedAt 			*time.Time 		`db:"closed_at"`
+	Created_at 			time.Time 		`db:"created_at"`
+	Updated_at 			time.Time 		`db:"updated_at"`
+}
+
+type CreateTerm struct {
+	Name 				string 			`json:"name" validate:"required,max=50"`
+	Semester 			int 			`json:"semester" validate:"required,min=1,max=2"`
+	StartDate 			string 			`json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate 			string 			`json:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+type TermResponse struct {
+	Id 					uuid.UUID 		`json:"id"`
+	AcademicYearId 		uuid.UUID 		`json:"academic_year_id"`
+	AcademicYear 		string 			`json:"academic_year"`
+	Name 				string 			`json:"name"`
+	Semester 			int 			`json:"semester"`
+	StartDate 			string 			`json:"start_date"`
+	EndDate 			string 			`json:"end_date"`
+	Status 				string 			`json:"status"`
+	OpenedAt 			*time.Time 		`json:"opened_at"`
+	ClosedAt 			*time.Time 		`json:"closed_at"`
+}
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AnnouncementStore interface {
+	CreateAnnouncement(ctx context.Context, announcement *Announcement) error
+	GetAnnouncementById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*Announcement, error)
+	GetAnnouncements(ctx context.Context, filter AnnouncementFilter) ([]Announcement, error)
+	UpdateAnnouncement(ctx context.Context, id uuid.UUID, changes AnnouncementChanges) error
+	SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error
+	DeleteAnnouncement(ctx context.Context, id uuid.UUID) error
+	AddFiles(ctx context.Context, announcementId uuid.UUID, files []AnnouncementFile) ([]AnnouncementFile, error)
+	GetFileById(ctx context.Context, id uuid.UUID) (*AnnouncementFile, error)
+	DeleteFile(ctx context.Context, announcementId uuid.UUID, fileId uuid.UUID) error
+	MarkRead(ctx context.Context, announcementId uuid.UUID, userId uuid.UUID, at time.Time) error
+	GetReads(ctx context.Context, announcementId uuid.UUID) ([]AnnouncementRead, error)
+	GetViewer(ctx context.Context, userId uuid.UUID, role string) (*AnnouncementViewer, error)
+}
+
+// the target of the announcement
+const (
+	TargetSchool 		= "school"
+	TargetGrade 		= "grade"
+	TargetClass 		= "class"
+	TargetRole 			= "role"
+	TargetUser 			= "user"
+)
+
+// the state of the announcement from the publish time and the expiry
+const (
+	AnnouncementScheduled 	= "scheduled"
+	AnnouncementActive 		= "active"
+	AnnouncementExpired 	= "expired"
+)
+
+// Announcement is the notice of the school, the target decides which of the grade level, the class,
+// the role or the user is set
+type Announcement struct {
+	Id 				uuid.UUID 			`db:"id" json:"id"`
+	Title 			string 				`db:"title" json:"title"`
+	Body 			string 				`db:"body" json:"body"`
+	Target 			string 				`db:"target" json:"target"`
+	GradeLevel 		*int 				`db:"grade_level" json:"grade_level"`
+	ClassId 		*uuid.UUID 			`db:"class_id" json:"class_id"`
+	ClassName 		*string 			`db:"class_name" json:"class_name"`
+	TargetRole 		*string 			`db:"target_role" json:"target_role"`
+	UserId 			*uuid.UUID 			`db:"user_id" json:"user_id"`
+	PublishAt 		time.Time 			`db:"publish_at" json:"publish_at"`
+	ExpiresAt 		*time.Time 			`db:"expires_at" json:"expires_at"`
+	IsPinned 		bool 				`db:"is_pinned" json:"is_pinned"`
+	CreatedBy 		*uuid.UUID 			`db:"created_by" json:"created_by"`
+	IsRead 			bool 				`db:"is_read" json:"is_read"`
+	Reads 			int 				`db:"reads" json:"reads"`
+	State 			string 				`db:"-" json:"state"`
+	Files 			[]AnnouncementFile 	`db:"-" json:"files,omitempty"`
+	Created_at 		time.Time 			`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 			`db:"updated_at" json:"updated_at"`
+}
+
+// the empty publish time publishes the announcement now
+type CreateAnnouncement struct {
+	Title 			string 			`json:"title" validate:"required,max=200"`
+	Body 			string 			`json:"body" validate:"required,max=20000"`
+	Target 			string 			`json:"target" validate:"required,oneof=school grade class role user"`
+	GradeLevel 		*int 			`json:"grade_level" validate:"omitempty,min=1,max=12"`
+	ClassId 		*uuid.UUID 		`json:"class_id"`
+	TargetRole 		*string 		`json:"target_role" validate:"omitempty,oneof=guru siswa orangtua admin"`
+	UserId 			*uuid.UUID 		`json:"user_id"`
+	PublishAt 		*time.Time 		`json:"publish_at"`
+	ExpiresAt 		*time.Time 		`json:"expires_at"`
+	IsPinned 		bool 			`json:"is_pinned"`
+}
+
+// the target is not changed, a new announcement is made for the other audience
+type UpdateAnnouncement struct {
+	Title 			*string 		`json:"title" validate:"omitempty,min=1,max=200"`
+	Body 			*string 		`json:"body" validate:"omitempty,min=1,max=20000"`
+	PublishAt 		*time.Time 		`json:"publish_at"`
+	ExpiresAt 		*time.Time 		`json:"expires_at"`
+	ClearExpiry 	bool 			`json:"clear_expiry"`
+}
+
+// AnnouncementChanges is the checked update of the announcement
+type AnnouncementChanges struct {
+	Title 			string
+	Body 			string
+	PublishAt 		time.Time
+	ExpiresAt 		*time.Time
+}
+
+type SetAnnouncementPinned struct {
+	Pinned 			*bool 			`json:"pinned" validate:"required"`
+}
+
+// AnnouncementFilter is the list of the announcements, the nil viewer is the admin that sees every
+// announcement and the empty state lists the announcements of every state
+type AnnouncementFilter struct {
+	Viewer 			*AnnouncementViewer
+	UserId 			uuid.UUID
+	State 			string
+	Target 			string
+	At 				time.Time
+}
+
+// AnnouncementViewer is the audience of the user, the classes and the grade levels of the siswa, of the
+// children of the orangtua or of the classes that the guru teaches
+type AnnouncementViewer struct {
+	UserId 			uuid.UUID
+	Role 			string
+	ClassIds 		[]uuid.UUID
+	GradeLevels 	[]int
+}
+
+type AnnouncementFile struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	AnnouncementId 	uuid.UUID 		`db:"announcement_id" json:"announcement_id"`
+	FileKey 		string 			`db:"file_key" json:"-"`
+	FileName 		string 			`db:"file_name" json:"file_name"`
+	ContentType 	string 			`db:"content_type" json:"content_type"`
+	Size 			int64 			`db:"size" json:"size"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+}
+
+// AnnouncementRead is the read receipt of the user
+type AnnouncementRead struct {
+	UserId 			uuid.UUID 		`db:"user_id" json:"user_id"`
+	Username 		string 			`db:"username" json:"username"`
+	Role 			string 			`db:"role" json:"role"`
+	ReadAt 			time.Time 		`db:"read_at" json:"read_at"`
+}
@@ -0,0 +1,225 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttendanceStore interface {
+	SubmitAttendance(ctx context.Context, submit *AttendanceSubmit) ([]AttendanceRecord, error)
+	GetClassAttendance(ctx context.Context, classId uuid.UUID, date time.Time) ([]AttendanceRecord, error)
+	GetStudentAttendance(ctx context.Context, studentId uuid.UUID, from time.Time, to time.Time) ([]AttendanceRecord, error)
+	GetAttendanceRecord(ctx context.Context, id uuid.UUID) (*AttendanceRecord, error)
+	CorrectAttendance(ctx context.Context, id uuid.UUID, payload CorrectAttendance, changedBy *uuid.UUID) (*AttendanceRecord, error)
+	GetAttendanceHistory(ctx context.Context, recordId uuid.UUID) ([]AttendanceHistory, error)
+	SubmitLessonAttendance(ctx context.Context, submit *LessonAttendanceSubmit) ([]LessonAttendance, error)
+	GetLessonAttendance(ctx context.Context, slotId uuid.UUID, date time.Time) ([]LessonAttendance, error)
+	RedeemCheckIn(ctx context.Context, redeem *CheckInRedeem) (*LessonAttendance, error)
+	GetStatusCounts(ctx context.Context, studentId *uuid.UUID, classId *uuid.UUID, from time.Time, to time.Time) ([]AttendanceStatusCount, error)
+	CreateThreshold(ctx context.Context, threshold *AttendanceThreshold) error
+	GetThresholds(ctx context.Context) ([]AttendanceThreshold, error)
+	UpdateThreshold(ctx context.Context, id uuid.UUID, payload UpdateThreshold) (*AttendanceThreshold, error)
+	GetAlerts(ctx context.Context, filter AlertFilter) ([]AttendanceAlert, error)
+	GetAlertById(ctx context.Context, id uuid.UUID) (*AttendanceAlert, error)
+	AcknowledgeAlert(ctx context.Context, id uuid.UUID, by uuid.UUID) error
+	IsHomeroomTeacher(ctx context.Context, classId uuid.UUID, userId uuid.UUID) (bool, error)
+}
+
+// the status codes of the attendance
+const (
+	AttendanceHadir = "hadir"
+	AttendanceSakit = "sakit"
+	AttendanceIzin = "izin"
+	AttendanceAlpa = "alpa"
+	AttendanceLate = "late"
+)
+
+// the source of the daily status
+const (
+	SourceManual = "manual"
+	SourceLesson = "lesson"
+)
+
+type AttendanceRecord struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	Date 			time.Time 		`db:"date" json:"date"`
+	Status 			string 			`db:"status" json:"status"`
+	Note 			string 			`db:"note" json:"note"`
+	Source 			string 			`db:"source" json:"source"`
+	RecordedBy 		*uuid.UUID 		`db:"recorded_by" json:"recorded_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type AttendanceHistory struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	RecordId 		uuid.UUID 		`db:"record_id" json:"record_id"`
+	OldStatus 		*string 		`db:"old_status" json:"old_status"`
+	NewStatus 		string 			`db:"new_status" json:"new_status"`
+	OldNote 		*string 		`db:"old_note" json:"old_note"`
+	NewNote 		string 			`db:"new_note" json:"new_note"`
+	Reason 			string 			`db:"reason" json:"reason"`
+	ChangedBy 		*uuid.UUID 		`db:"changed_by" json:"changed_by"`
+	ChangedAt 		time.Time 		`db:"changed_at" json:"changed_at"`
+}
+
+type AttendanceEntry struct {
+	StudentId 		uuid.UUID 		`json:"student_id" validate:"required"`
+	Status 			string 			`json:"status" validate:"required,oneof=hadir sakit izin alpa late"`
+	Note 			string 			`json:"note" validate:"max=255"`
+}
+
+// SubmitAttendance is the bulk submit of the roster of a class in one date
+type SubmitAttendance struct {
+	Date 			string 				`json:"date" validate:"required,datetime=2006-01-02"`
+	Entries 		[]AttendanceEntry 	`json:"entries" validate:"required,min=1,dive"`
+	Reason 			string 				`json:"reason" validate:"max=255"`
+}
+
+// AttendanceSubmit is the submit after the handler has read the class, the date and the user
+type AttendanceSubmit struct {
+	ClassId 		uuid.UUID
+	Date 			time.Time
+	Entries 		[]AttendanceEntry
+	Reason 			string
+	RecordedBy 		*uuid.UUID
+}
+
+type CorrectAttendance struct {
+	Status 			string 			`json:"status" validate:"required,oneof=hadir sakit izin alpa late"`
+	Note 			*string 		`json:"note" validate:"omitempty,max=255"`
+	Reason 			string 			`json:"reason" validate:"required,max=255"`
+}
+
+// LessonAttendance is the attendance of a student in one lesson of the timetable
+type LessonAttendance struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	SlotId 			uuid.UUID 		`db:"slot_id" json:"slot_id"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	Subject 		string 			`db:"subject" json:"subject"`
+	Date 			time.Time 		`db:"date" json:"date"`
+	Status 			string 			`db:"status" json:"status"`
+	Note 			string 			`db:"note" json:"note"`
+	RecordedBy 		*uuid.UUID 		`db:"recorded_by" json:"recorded_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+// LessonAttendanceSubmit is the bulk submit of one lesson, LockWindow is the time after the end of
+// the lesson where the attendance can still be changed at the clock of the Location, Override skip the lock (admin)
+type LessonAttendanceSubmit struct {
+	SlotId 			uuid.UUID
+	Date 			time.Time
+	Entries 		[]AttendanceEntry
+	RecordedBy 		*uuid.UUID
+	LockWindow 		time.Duration
+	Location 		*time.Location
+	Override 		bool
+}
+
+type CheckInCode struct {
+	Code 			string 			`json:"code"`
+	SlotId 			uuid.UUID 		`json:"slot_id"`
+	Date 			string 			`json:"date"`
+	ExpiresAt 		time.Time 		`json:"expires_at"`
+}
+
+type RedeemCheckIn struct {
+	Code 			string 			`json:"code" validate:"required,max=512"`
+}
+
+// CheckInRedeem is a verified code redeemed by the user, LateAfter is the grace time after the start of the lesson
+// and Now is the time of the redeem in the timezone of the school
+type CheckInRedeem struct {
+	SlotId 			uuid.UUID
+	Date 			time.Time
+	Nonce 			string
+	UserId 			uuid.UUID
+	LateAfter 		time.Duration
+	Now 			time.Time
+}
+
+// AttendanceSummary is the count of every status of a student (or of a class) between two dates
+type AttendanceSummary struct {
+	StudentId 		*uuid.UUID 		`json:"student_id,omitempty"`
+	StudentName 	string 			`json:"student_name,omitempty"`
+	Hadir 			int 			`json:"hadir"`
+	Sakit 			int 			`json:"sakit"`
+	Izin 			int 			`json:"izin"`
+	Alpa 			int 			`json:"alpa"`
+	Late 			int 			`json:"late"`
+	Total 			int 			`json:"total"`
+	Rate 			float64 		`json:"attendance_rate"`
+}
+
+type ClassAttendanceSummary struct {
+	ClassId 		uuid.UUID 				`json:"class_id"`
+	From 			string 					`json:"from"`
+	To 				string 					`json:"to"`
+	Totals 			AttendanceSummary 		`json:"totals"`
+	Students 		[]AttendanceSummary 	`json:"students"`
+}
+
+// AttendanceStatusCount is one row of the count of a status, used to build the summary
+type AttendanceStatusCount struct {
+	StudentId 		uuid.UUID 		`db:"student_id"`
+	StudentName 	string 			`db:"student_name"`
+	Status 			string 			`db:"status"`
+	Count 			int 			`db:"count"`
+}
+
+type AttendanceThreshold struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	Name 			string 			`db:"name" json:"name"`
+	Status 			string 			`db:"status" json:"status"`
+	Count 			int 			`db:"count" json:"count"`
+	Period 			string 			`db:"period" json:"period"`
+	IsActive 		bool 			`db:"is_active" json:"is_active"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateThreshold struct {
+	Name 			string 			`json:"name" validate:"required,max=100"`
+	Status 			string 			`json:"status" validate:"required,oneof=sakit izin alpa late absent"`
+	Count 			int 			`json:"count" validate:"required,min=1"`
+	Period 			string 			`json:"period" validate:"required,oneof=week month term"`
+}
+
+type UpdateThreshold struct {
+	Name 			*string 		`json:"name" validate:"omitempty,max=100"`
+	Count 			*int 			`json:"count" validate:"omitempty,min=1"`
+	IsActive 		*bool 			`json:"is_active"`
+}
+
+type AttendanceAlert struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	ThresholdId 	uuid.UUID 		`db:"threshold_id" json:"threshold_id"`
+	ThresholdName 	string 			`db:"threshold_name" json:"threshold_name"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	ClassName 		string 			`db:"class_name" json:"class_name"`
+	PeriodStart 	time.Time 		`db:"period_start" json:"period_start"`
+	PeriodEnd 		time.Time 		`db:"period_end" json:"period_end"`
+	Count 			int 			`db:"count" json:"count"`
+	Status 			string 			`db:"status" json:"status"`
+	AcknowledgedBy 	*uuid.UUID 		`db:"acknowledged_by" json:"acknowledged_by"`
+	AcknowledgedAt 	*time.Time 		`db:"acknowledged_at" json:"acknowledged_at"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+// AlertFilter selects the alerts, HomeroomTeacherId limits the alerts to the classes of the teacher
+type AlertFilter struct {
+	ClassId 			*uuid.UUID
+	Status 				string
+	HomeroomTeacherId 	*uuid.UUID
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CalendarStore interface {
+	CreateEvent(ctx context.Context, event *CalendarEvent) error
+	GetEventById(ctx context.Context, id uuid.UUID) (*CalendarEvent, error)
+	GetEvents(ctx context.Context, from time.Time, to time.Time, audience string) ([]CalendarEvent, error)
+	DeleteEvent(ctx context.Context, id uuid.UUID) error
+	SaveFeedToken(ctx context.Context, userId uuid.UUID, tokenHash string) error
+	DeleteFeedToken(ctx context.Context, userId uuid.UUID) error
+	GetFeedOwner(ctx context.Context, tokenHash string) (*CalendarOwner, error)
+	GetLessons(ctx context.Context, owner *CalendarOwner) ([]CalendarLesson, error)
+	GetExams(ctx context.Context, owner *CalendarOwner, from time.Time, to time.Time) ([]CalendarExam, error)
+}
+
+// the audience of the calendar event
+const (
+	AudienceAll 		= "all"
+	AudienceGuru 		= "guru"
+	AudienceSiswa 		= "siswa"
+	AudienceOrangtua 	= "orangtua"
+)
+
+// CalendarEvent is an event of the school calendar, the nil times make an all day event
+type CalendarEvent struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	Title 			string 			`db:"title" json:"title"`
+	Description 	string 			`db:"description" json:"description"`
+	StartDate 		time.Time 		`db:"start_date" json:"start_date"`
+	EndDate 		time.Time 		`db:"end_date" json:"end_date"`
+	StartTime 		*string 		`db:"start_time" json:"start_time"`
+	EndTime 		*string 		`db:"end_time" json:"end_time"`
+	IsHoliday 		bool 			`db:"is_holiday" json:"is_holiday"`
+	Audience 		string 			`db:"audience" json:"audience"`
+	CreatedBy 		*uuid.UUID 		`db:"created_by" json:"created_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateCalendarEvent struct {
+	Title 			string 			`json:"title" validate:"required,max=200"`
+	Description 	string 			`json:"description"`
+	StartDate 		string 			`json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate 		string 			`json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	StartTime 		*string 		`json:"start_time" validate:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime 		*string 		`json:"end_time" validate:"required_with=StartTime,omitempty,datetime=15:04"`
+	IsHoliday 		bool 			`json:"is_holiday"`
+	Audience 		string 			`json:"audience" validate:"omitempty,oneof=all guru siswa orangtua"`
+}
+
+// CalendarOwner is the user of the calendar feed
+type CalendarOwner struct {
+	UserId 			uuid.UUID 		`db:"user_id"`
+	Username 		string 			`db:"username"`
+	Role 			string 			`db:"role"`
+}
+
+// CalendarLesson is a weekly lesson of the feed with the dates of the term
+type CalendarLesson struct {
+	SlotId 			uuid.UUID 		`db:"slot_id"`
+	ClassName 		string 			`db:"class_name"`
+	Subject 		string 			`db:"subject"`
+	DayOfWeek 		int 			`db:"day_of_week"`
+	StartTime 		string 			`db:"start_time"`
+	EndTime 		string 			`db:"end_time"`
+	RoomCode 		string 			`db:"room_code"`
+	TermStart 		time.Time 		`db:"term_start"`
+	TermEnd 		time.Time 		`db:"term_end"`
+}
+
+// CalendarExam is an exam session of the feed, the siswa gets the room and the seat, the orangtua gets the
+// seat of every child (with the name of the child) and the guru gets the room that they proctor (the seat is nil)
+type CalendarExam struct {
+	SessionId 		uuid.UUID 		`db:"session_id"`
+	Title 			string 			`db:"title"`
+	Subject 		string 			`db:"subject"`
+	ExamDate 		time.Time 		`db:"exam_date"`
+	StartTime 		string 			`db:"start_time"`
+	EndTime 		string 			`db:"end_time"`
+	RoomCode 		string 			`db:"room_code"`
+	SeatNo 			*int 			`db:"seat_no"`
+	StudentName 	string 			`db:"student_name"`
+}
+
+type CalendarFeedToken struct {
+	Token 			string 			`json:"token"`
+	URL 			string 			`json:"url"`
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ClassStore interface {
+	CreateClass(ctx context.Context, class *Class) error
+	GetClassById(ctx context.Context, id uuid.UUID) (*Class, error)
+	GetClassByName(ctx context.Context, gradeLevel int, major string, section string, academicYear string) (*Class, error)
+	GetAllClasses(ctx context.Context, academicYear string, gradeLevel int) ([]Class, error)
+	UpdateClass(ctx context.Context, id uuid.UUID, payload UpdateClass) error
+	DeleteClass(ctx context.Context, id uuid.UUID) error
+	GetClassRoster(ctx context.Context, id uuid.UUID, termId *uuid.UUID) ([]Student, error)
+	GetClassMappings(ctx context.Context, status string) ([]ClassMapping, error)
+	ResolveClassMapping(ctx context.Context, rawValue string, class *Class) (int64, error)
+	IsTeacher(ctx context.Context, userId uuid.UUID) (bool, error)
+}
+
+type Class struct {
+	Id 					uuid.UUID 		`db:"id"`
+	Name 				string 			`db:"name"`
+	GradeLevel 			int 			`db:"grade_level"`
+	Major 				string 			`db:"major"`
+	Section 			string 			`db:"section"`
+	AcademicYear 		string 			`db:"academic_year"`
+	HomeroomTeacherId 	*uuid.UUID 		`db:"homeroom_teacher_id"`
+	Created_at 			time.Time 		`db:"created_at"`
+	Updated_at 			time.Time 		`db:"updated_at"`
+}
+
+type CreateClass struct {
+	GradeLevel 			int 			`json:"grade_level" validate:"required,min=1,max=12"`
+	Major 				string 			`json:"major" validate:"max=50"`
+	Section 			string 			`json:"section" validate:"required,max=10"`
+	AcademicYear 		string 			`json:"academic_year" validate:"required,len=9"`
+	HomeroomTeacherId 	*uuid.UUID 		`json:"homeroom_teacher_id"`
+}
+
+type UpdateClass struct {
+	Name 				*string 		`json:"-"`
+	GradeLevel 			*int 			`json:"grade_level" validate:"omitempty,min=1,max=12"`
+	Major 				*string 		`json:"major" validate:"omitempty,max=50"`
+	Section 			*string 		`json:"section" validate:"omitempty,max=10"`
+	AcademicYear 		*string 		`json:"academic_year" validate:"omitempty,len=9"`
+	HomeroomTeacherId 	*uuid.UUID 		`json:"homeroom_teacher_id"`
+}
+
+type ClassResponse struct {
+	Id 					uuid.UUID 		`json:"id"`
+	Name 				string 			`json:"name"`
+	GradeLevel 			int 			`json:"grade_level"`
+	Major 				string 			`json:"major"`
+	Section 			string 			`json:"section"`
+	AcademicYear 		string 			`json:"academic_year"`
+	HomeroomTeacherId 	*uuid.UUID 		`json:"homeroom_teacher_id"`
+	Created_at 			string 			`json:"created_at"`
+	Updated_at 			string 			`json:"updated_at"`
+}
+
+// ClassMapping is one row of the report made when the free-text classes were migrated
+type ClassMapping struct {
+	RawValue 			string 			`db:"raw_value" json:"raw_value"`
+	Normalized 			*string 		`db:"normalized" json:"normalized"`
+	Status 				string 			`db:"status" json:"status"`
+	Reason 				string 			`db:"reason" json:"reason"`
+	StudentCount 		int 			`db:"student_count" json:"student_count"`
+	Created_at 			time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 			time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type ResolveClassMapping struct {
+	RawValue 			string 			`json:"raw_value" validate:"required"`
+	ClassId 			uuid.UUID 		`json:"class_id" validate:"required"`
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExamStore interface {
+	CreateSession(ctx context.Context, session *ExamSession, classIds []uuid.UUID) error
+	GetSessionById(ctx context.Context, id uuid.UUID) (*ExamSession, error)
+	GetSessions(ctx context.Context, termId uuid.UUID, kind string) ([]ExamSession, error)
+	DeleteSession(ctx context.Context, id uuid.UUID) error
+	AddRoom(ctx context.Context, room *ExamRoom) error
+	GetRooms(ctx context.Context, sessionId uuid.UUID) ([]ExamRoom, error)
+	RemoveRoom(ctx context.Context, sessionId uuid.UUID, roomId uuid.UUID) error
+	AddProctor(ctx context.Context, proctor *ExamProctor) error
+	RemoveProctor(ctx context.Context, sessionId uuid.UUID, teacherId uuid.UUID) error
+	GetTeacherBusy(ctx context.Context, teacherId uuid.UUID, termId uuid.UUID, date time.Time) ([]ExamBusy, error)
+	GetRoomBusy(ctx context.Context, roomId uuid.UUID, date time.Time) ([]ExamBusy, error)
+	GetClassBusy(ctx context.Context, classIds []uuid.UUID, date time.Time) ([]ExamBusy, error)
+	GetCandidates(ctx context.Context, sessionId uuid.UUID) ([]ExamCandidate, error)
+	SaveSeats(ctx context.Context, sessionId uuid.UUID, seats []ExamSeat) error
+	GetSeats(ctx context.Context, sessionId uuid.UUID, roomId *uuid.UUID) ([]ExamSeat, error)
+	GetStudentExams(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) ([]StudentExam, error)
+}
+
+// the kind of the exam week
+const (
+	ExamMidterm 		= "midterm"
+	ExamFinal 			= "final"
+)
+
+// the kind of the time that is already taken by the teacher, the room or the class
+const (
+	BusyLesson 			= "lesson"
+	BusyUnavailable 	= "unavailable"
+	BusyExam 			= "exam"
+)
+
+type ExamSession struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	Kind 			string 			`db:"kind" json:"kind"`
+	Title 			string 			`db:"title" json:"title"`
+	SubjectId 		*uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	SubjectName 	*string 		`db:"subject_name" json:"subject_name"`
+	ExamDate 		time.Time 		`db:"exam_date" json:"exam_date"`
+	StartTime 		string 			`db:"start_time" json:"start_time"`
+	EndTime 		string 			`db:"end_time" json:"end_time"`
+	CreatedBy 		*uuid.UUID 		`db:"created_by" json:"created_by"`
+	Classes 		[]ExamClass 	`db:"-" json:"classes"`
+	Rooms 			[]ExamRoom 		`db:"-" json:"rooms,omitempty"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type ExamClass struct {
+	SessionId 		uuid.UUID 		`db:"session_id" json:"-"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	ClassName 		string 			`db:"class_name" json:"class_name"`
+}
+
+// CreateExamSession takes the open term when the term id is not set
+type CreateExamSession struct {
+	TermId 			*uuid.UUID 		`json:"term_id"`
+	Kind 			string 			`json:"kind" validate:"required,oneof=midterm final"`
+	Title 			string 			`json:"title" validate:"required,max=200"`
+	SubjectId 		*uuid.UUID 		`json:"subject_id"`
+	ExamDate 		string 			`json:"exam_date" validate:"required,datetime=2006-01-02"`
+	StartTime 		string 			`json:"start_time" validate:"required,datetime=15:04"`
+	EndTime 		string 			`json:"end_time" validate:"required,datetime=15:04"`
+	ClassIds 		[]uuid.UUID 	`json:"class_ids" validate:"required,min=1,max=50,unique,dive,required"`
+}
+
+// ExamRoom is the room of the session with the proctors and the count of the seated students
+type ExamRoom struct {
+	SessionId 		uuid.UUID 		`db:"session_id" json:"session_id"`
+	RoomId 			uuid.UUID 		`db:"room_id" json:"room_id"`
+	RoomCode 		string 			`db:"room_code" json:"room_code"`
+	RoomName 		string 			`db:"room_name" json:"room_name"`
+	Seats 			int 			`db:"seats" json:"seats"`
+	Position 		int 			`db:"position" json:"position"`
+	Seated 			int 			`db:"seated" json:"seated"`
+	Proctors 		[]ExamProctor 	`db:"-" json:"proctors"`
+}
+
+// AddExamRoom takes the capacity of the room when the seats are not set
+type AddExamRoom struct {
+	RoomId 			uuid.UUID 		`json:"room_id" validate:"required"`
+	Seats 			*int 			`json:"seats" validate:"omitempty,min=1,max=500"`
+}
+
+type ExamProctor struct {
+	SessionId 		uuid.UUID 		`db:"session_id" json:"session_id"`
+	RoomId 			uuid.UUID 		`db:"room_id" json:"room_id"`
+	TeacherId 		uuid.UUID 		`db:"teacher_id" json:"teacher_id"`
+	TeacherName 	string 			`db:"teacher_name" json:"teacher_name"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+}
+
+type AddExamProctor struct {
+	RoomId 			uuid.UUID 		`json:"room_id" validate:"required"`
+	TeacherId 		uuid.UUID 		`json:"teacher_id" validate:"required"`
+}
+
+// ExamBusy is the time of the day that is already taken by the lesson, the unavailability or another exam
+type ExamBusy struct {
+	Kind 			string 			`db:"kind" json:"kind"`
+	SourceId 		uuid.UUID 		`db:"source_id" json:"source_id"`
+	Label 			string 			`db:"label" json:"label"`
+	StartTime 		string 			`db:"start_time" json:"start_time"`
+	EndTime 		string 			`db:"end_time" json:"end_time"`
+}
+
+// ExamConflict is one reason why the room, the proctor or the class cannot be used in the session
+type ExamConflict struct {
+	Kind 			string 			`json:"kind"`
+	Message 		string 			`json:"message"`
+	SourceId 		uuid.UUID 		`json:"source_id"`
+	StartTime 		string 			`json:"start_time"`
+	EndTime 		string 			`json:"end_time"`
+}
+
+// ExamCandidate is the student of a class of the session that needs a seat
+type ExamCandidate struct {
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	ClassName 		string 			`db:"class_name" json:"class_name"`
+}
+
+type ExamSeat struct {
+	SessionId 		uuid.UUID 		`db:"session_id" json:"session_id"`
+	RoomId 			uuid.UUID 		`db:"room_id" json:"room_id"`
+	RoomCode 		string 			`db:"room_code" json:"room_code"`
+	SeatNo 			int 			`db:"seat_no" json:"seat_no"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	ClassName 		string 			`db:"class_name" json:"class_name"`
+}
+
+// StudentExam is the exam session of the student with the room and the seat, nil before the seating
+type StudentExam struct {
+	SessionId 		uuid.UUID 		`db:"session_id" json:"session_id"`
+	Kind 			string 			`db:"kind" json:"kind"`
+	Title 			string 			`db:"title" json:"title"`
+	SubjectName 	*string 		`db:"subject_name" json:"subject_name"`
+	ExamDate 		time.Time 		`db:"exam_date" json:"exam_date"`
+	StartTime 		string 			`db:"start_time" json:"start_time"`
+	EndTime 		string 			`db:"end_time" json:"end_time"`
+	RoomCode 		*string 		`db:"room_code" json:"room_code"`
+	RoomName 		*string 		`db:"room_name" json:"room_name"`
+	SeatNo 			*int 			`db:"seat_no" json:"seat_no"`
+}
@@ -0,0 +1,320 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GradeStore interface {
+	GetWeights(ctx context.Context, key GradebookKey) ([]GradeWeight, error)
+	SetWeights(ctx context.Context, key GradebookKey, weights []GradeWeightEntry) error
+	CreateAssessment(ctx context.Context, assessment *Assessment) error
+	GetAssessmentById(ctx context.Context, id uuid.UUID) (*Assessment, error)
+	GetAssessments(ctx context.Context, key GradebookKey) ([]Assessment, error)
+	DeleteAssessment(ctx context.Context, id uuid.UUID) error
+	SaveScores(ctx context.Context, assessment *Assessment, entries []ScoreEntry, gradedBy *uuid.UUID) error
+	GetScores(ctx context.Context, key GradebookKey, studentId *uuid.UUID) ([]AssessmentScore, error)
+	GetGradebookStudents(ctx context.Context, key GradebookKey) ([]GradebookStudent, error)
+	GetPublication(ctx context.Context, key GradebookKey) (*GradebookPublication, error)
+	Publish(ctx context.Context, key GradebookKey, publishedBy *uuid.UUID, scores []TermScore) error
+	Unpublish(ctx context.Context, key GradebookKey) error
+	GetStudentIdByUser(ctx context.Context, userId uuid.UUID) (*uuid.UUID, error)
+	GetStudentGradebooks(ctx context.Context, studentId uuid.UUID, termId uuid.UUID, onlyPublished bool) ([]StudentGradebook, error)
+	GetTranscriptStudent(ctx context.Context, studentId uuid.UUID) (*TranscriptStudent, error)
+	GetTranscriptScores(ctx context.Context, studentId uuid.UUID) ([]TranscriptScore, error)
+	GetRankingScores(ctx context.Context, termId uuid.UUID, classId *uuid.UUID, gradeLevel *int) ([]RankingScore, error)
+	GetRankingSettings(ctx context.Context, termId uuid.UUID) (*RankingSettings, error)
+	SetRankingSettings(ctx context.Context, termId uuid.UUID, visible bool, updatedBy *uuid.UUID) (*RankingSettings, error)
+	GetStudentClass(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) (*RankingClass, error)
+	GetAssessmentScore(ctx context.Context, assessmentId uuid.UUID, studentId uuid.UUID) (*float64, error)
+	CreateRemedial(ctx context.Context, remedial *Remedial) error
+	GetRemedialById(ctx context.Context, id uuid.UUID) (*Remedial, error)
+	GetRemedials(ctx context.Context, assessmentId *uuid.UUID, studentId *uuid.UUID) ([]Remedial, error)
+	DeleteRemedial(ctx context.Context, id uuid.UUID) error
+	GradeRemedial(ctx context.Context, remedial *Remedial, change *GradeChange) error
+	CreateAppeal(ctx context.Context, appeal *GradeAppeal) error
+	GetAppealById(ctx context.Context, id uuid.UUID) (*GradeAppeal, error)
+	GetAppeals(ctx context.Context, assessmentId *uuid.UUID, studentId *uuid.UUID) ([]GradeAppeal, error)
+	RespondAppeal(ctx context.Context, appeal *GradeAppeal, change *GradeChange) error
+	GetGradeChanges(ctx context.Context, studentId uuid.UUID) ([]GradeChange, error)
+}
+
+// the categories of the assessment
+const (
+	CategoryQuiz 		= "quiz"
+	CategoryAssignment 	= "assignment"
+	CategoryMidterm 	= "midterm"
+	CategoryFinal 		= "final"
+)
+
+// GradebookKey is the gradebook of the subject in the class for one term
+type GradebookKey struct {
+	SubjectId 		uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+}
+
+type GradeWeight struct {
+	Category 		string 			`db:"category" json:"category"`
+	Weight 			int 			`db:"weight" json:"weight"`
+}
+
+type GradeWeightEntry struct {
+	Category 		string 			`json:"category" validate:"required,oneof=quiz assignment midterm final"`
+	Weight 			int 			`json:"weight" validate:"min=0,max=100"`
+}
+
+type SetGradeWeights struct {
+	Weights 		[]GradeWeightEntry 	`json:"weights" validate:"required,min=1,dive"`
+}
+
+type Assessment struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	SubjectId 		uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	Category 		string 			`db:"category" json:"category"`
+	Title 			string 			`db:"title" json:"title"`
+	MaxScore 		float64 		`db:"max_score" json:"max_score"`
+	AssessedOn 		*time.Time 		`db:"assessed_on" json:"assessed_on"`
+	CreatedBy 		*uuid.UUID 		`db:"created_by" json:"created_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateAssessment struct {
+	Category 		string 			`json:"category" validate:"required,oneof=quiz assignment midterm final"`
+	Title 			string 			`json:"title" validate:"required,max=200"`
+	MaxScore 		float64 		`json:"max_score" validate:"omitempty,gt=0,lte=1000"`
+	AssessedOn 		string 			`json:"assessed_on" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type ScoreEntry struct {
+	StudentId 		uuid.UUID 		`json:"student_id" validate:"required"`
+	Score 			float64 		`json:"score" validate:"min=0"`
+}
+
+type SaveScores struct {
+	Scores 			[]ScoreEntry 	`json:"scores" validate:"required,min=1,dive"`
+}
+
+type AssessmentScore struct {
+	AssessmentId 	uuid.UUID 		`db:"assessment_id" json:"assessment_id"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	Score 			float64 		`db:"score" json:"score"`
+	GradedBy 		*uuid.UUID 		`db:"graded_by" json:"graded_by"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type GradebookStudent struct {
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	Name 			string 			`db:"name" json:"name"`
+}
+
+type GradebookPublication struct {
+	PublishedBy 	*uuid.UUID 		`db:"published_by" json:"published_by"`
+	PublishedAt 	time.Time 		`db:"published_at" json:"published_at"`
+}
+
+// CategoryScore is the average percent of the scores in one category of the assessments
+type CategoryScore struct {
+	Category 		string 			`json:"category"`
+	Weight 			int 			`json:"weight"`
+	Average 		float64 		`json:"average"`
+	Assessments 	int 			`json:"assessments"`
+}
+
+// TermScore is the weighted score of the student in the gradebook, Missing counts the assessments without the score
+type TermScore struct {
+	StudentId 		uuid.UUID 		`json:"student_id"`
+	Name 			string 			`json:"name,omitempty"`
+	Score 			*float64 		`json:"score"`
+	Categories 		[]CategoryScore `json:"categories"`
+	Missing 		int 			`json:"missing"`
+	Scores 			map[uuid.UUID]float64 	`json:"scores,omitempty"`
+}
+
+// Gradebook is the full gradebook with the computed term scores of the students
+type Gradebook struct {
+	GradebookKey
+	Weights 		[]GradeWeight 	`json:"weights"`
+	Assessments 	[]Assessment 	`json:"assessments"`
+	Students 		[]TermScore 	`json:"students"`
+	Published 		*GradebookPublication 	`json:"published"`
+}
+
+// StudentGradebook is one gradebook of the student in the term with the name of the subject
+type StudentGradebook struct {
+	GradebookKey
+	SubjectCode 	string 			`db:"subject_code" json:"subject_code"`
+	SubjectName 	string 			`db:"subject_name" json:"subject_name"`
+	PublishedAt 	*time.Time 		`db:"published_at" json:"published_at"`
+}
+
+// StudentGrade is the scores and the term score of the student in one subject
+type StudentGrade struct {
+	StudentGradebook
+	Assessments 	[]Assessment 	`json:"assessments"`
+	Result 			TermScore 		`json:"result"`
+}
+
+// the rounding modes of the grades
+const (
+	RoundHalfUp 		= "half_up"
+	RoundHalfEven 		= "half_even"
+	RoundFloor 			= "floor"
+	RoundCeil 			= "ceil"
+)
+
+// TranscriptRules is the rounding and the default minimum passing score (kkm) of the transcript
+type TranscriptRules struct {
+	Rounding 		string 			`json:"rounding"`
+	Decimals 		int 			`json:"decimals"`
+	DefaultKkm 		float64 		`json:"default_kkm"`
+}
+
+type TranscriptStudent struct {
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	Name 			string 			`db:"name" json:"name"`
+	Status 			string 			`db:"status" json:"status"`
+	GraduatedAt 	*time.Time 		`db:"graduated_at" json:"graduated_at"`
+}
+
+// TranscriptScore is one published term score of the student with the term and the subject
+type TranscriptScore struct {
+	TermId 			uuid.UUID 		`db:"term_id"`
+	TermName 		string 			`db:"term_name"`
+	Semester 		int 			`db:"semester"`
+	AcademicYear 	string 			`db:"academic_year"`
+	ClassName 		string 			`db:"class_name"`
+	SubjectId 		uuid.UUID 		`db:"subject_id"`
+	SubjectCode 	string 			`db:"subject_code"`
+	SubjectName 	string 			`db:"subject_name"`
+	Score 			float64 		`db:"score"`
+	Kkm 			*float64 		`db:"kkm"`
+}
+
+// TranscriptEntry is the final grade of one subject in one term
+type TranscriptEntry struct {
+	SubjectId 		uuid.UUID 		`json:"subject_id"`
+	SubjectCode 	string 			`json:"subject_code"`
+	SubjectName 	string 			`json:"subject_name"`
+	Score 			float64 		`json:"score"`
+	Kkm 			float64 		`json:"kkm"`
+	Passed 			bool 			`json:"passed"`
+	Remedial 		bool 			`json:"remedial"`
+}
+
+type TranscriptTerm struct {
+	TermId 			uuid.UUID 			`json:"term_id"`
+	TermName 		string 				`json:"term_name"`
+	Semester 		int 				`json:"semester"`
+	AcademicYear 	string 				`json:"academic_year"`
+	ClassName 		string 				`json:"class_name"`
+	Entries 		[]TranscriptEntry 	`json:"entries"`
+	Average 		float64 			`json:"average"`
+}
+
+// TranscriptSubject is the average of one subject over every term
+type TranscriptSubject struct {
+	SubjectId 		uuid.UUID 		`json:"subject_id"`
+	SubjectCode 	string 			`json:"subject_code"`
+	SubjectName 	string 			`json:"subject_name"`
+	Terms 			int 			`json:"terms"`
+	Average 		float64 		`json:"average"`
+	Kkm 			float64 		`json:"kkm"`
+	NeedsRemedial 	bool 			`json:"needs_remedial"`
+	RemedialTerms 	[]uuid.UUID 	`json:"remedial_terms"`
+}
+
+type Transcript struct {
+	Student 			*TranscriptStudent 	`json:"student"`
+	Rules 				TranscriptRules 	`json:"rules"`
+	Terms 				[]TranscriptTerm 	`json:"terms"`
+	Subjects 			[]TranscriptSubject `json:"subjects"`
+	CumulativeAverage 	*float64 			`json:"cumulative_average"`
+	RemedialCount 		int 				`json:"remedial_count"`
+}
+
+// the scopes of the ranking
+const (
+	RankScopeClass 			= "class"
+	RankScopeGradeLevel 	= "grade_level"
+)
+
+// RankTieBreaks is the order of the rules that break the ties of the ranking, the students that are still
+// tied after every rule share the same rank and the next rank is skipped (1, 2, 2, 4)
+var RankTieBreaks = []string{"average_desc", "below_kkm_asc", "lowest_score_desc", "shared_rank"}
+
+// RankingScore is one published term score of the student in the ranking
+type RankingScore struct {
+	StudentId 		uuid.UUID 		`db:"student_id"`
+	Name 			string 			`db:"name"`
+	ClassId 		uuid.UUID 		`db:"class_id"`
+	ClassName 		string 			`db:"class_name"`
+	SubjectId 		uuid.UUID 		`db:"subject_id"`
+	Score 			float64 		`db:"score"`
+	Kkm 			*float64 		`db:"kkm"`
+}
+
+type RankEntry struct {
+	Rank 			int 			`json:"rank,omitempty"`
+	Tied 			bool 			`json:"tied"`
+	StudentId 		uuid.UUID 		`json:"student_id"`
+	Name 			string 			`json:"name"`
+	ClassId 		uuid.UUID 		`json:"class_id"`
+	ClassName 		string 			`json:"class_name"`
+	Average 		float64 		`json:"average"`
+	Subjects 		int 			`json:"subjects"`
+	BelowKkm 		int 			`json:"below_kkm"`
+	Lowest 			float64 		`json:"lowest"`
+}
+
+type Ranking struct {
+	Scope 			string 			`json:"scope"`
+	TermId 			uuid.UUID 		`json:"term_id"`
+	ClassId 		*uuid.UUID 		`json:"class_id,omitempty"`
+	GradeLevel 		*int 			`json:"grade_level,omitempty"`
+	TieBreaks 		[]string 		`json:"tie_breaks"`
+	Visible 		bool 			`json:"visible_to_students"`
+	Students 		[]RankEntry 	`json:"students"`
+}
+
+// HonorRoll is the students of the ranking with the average on the minimum and without a subject under the kkm
+type HonorRoll struct {
+	Scope 			string 			`json:"scope"`
+	TermId 			uuid.UUID 		`json:"term_id"`
+	ClassId 		*uuid.UUID 		`json:"class_id,omitempty"`
+	GradeLevel 		*int 			`json:"grade_level,omitempty"`
+	MinAverage 		float64 		`json:"min_average"`
+	Students 		[]RankEntry 	`json:"students"`
+}
+
+type RankingSettings struct {
+	TermId 				uuid.UUID 		`db:"term_id" json:"term_id"`
+	VisibleToStudents 	bool 			`db:"visible_to_students" json:"visible_to_students"`
+	UpdatedBy 			*uuid.UUID 		`db:"updated_by" json:"updated_by"`
+	CreatedAt 			time.Time 		`db:"created_at" json:"created_at"`
+	UpdatedAt 			time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type SetRankingVisibility struct {
+	VisibleToStudents 	*bool 			`json:"visible_to_students" validate:"required"`
+}
+
+// RankingClass is the class of the student in the term
+type RankingClass struct {
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	GradeLevel 		int 			`db:"grade_level" json:"grade_level"`
+}
+
+// MyRank is the ranks of the siswa in the class and in the grade level
+type MyRank struct {
+	TermId 			uuid.UUID 		`json:"term_id"`
+	Class 			*RankEntry 		`json:"class"`
+	ClassSize 		int 			`json:"class_size"`
+	GradeLevel 		*RankEntry 		`json:"grade_level"`
+	GradeLevelSize 	int 			`json:"grade_level_size"`
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GuardianStore interface {
+	CreateGuardian(ctx context.Context, guardian *Guardian) error
+	GetGuardianById(ctx context.Context, id uuid.UUID) (*Guardian, error)
+	GetGuardianByUser(ctx context.Context, userId uuid.UUID) (*Guardian, error)
+	GetGuardians(ctx context.Context, search string) ([]Guardian, error)
+	UpdateGuardian(ctx context.Context, id uuid.UUID, payload UpdateGuardian) (*Guardian, error)
+	DeleteGuardian(ctx context.Context, id uuid.UUID) error
+	LinkStudent(ctx context.Context, link *GuardianLink) error
+	UnlinkStudent(ctx context.Context, guardianId uuid.UUID, studentId uuid.UUID) error
+	GetChildren(ctx context.Context, guardianId uuid.UUID) ([]GuardianChild, error)
+	GetStudentGuardians(ctx context.Context, studentId uuid.UUID) ([]StudentGuardian, error)
+	IsGuardianOf(ctx context.Context, userId uuid.UUID, studentId uuid.UUID) (bool, error)
+}
+
+// the role of the user of the parent or the guardian
+const RoleOrangtua = "orangtua"
+
+// the relationship of the guardian with the student
+const (
+	RelationAyah 		= "ayah"
+	RelationIbu 		= "ibu"
+	RelationWali 		= "wali"
+)
+
+type Guardian struct {
+	Id 				uuid.UUID 			`db:"id" json:"id"`
+	UserId 			*uuid.UUID 			`db:"user_id" json:"user_id"`
+	FullName 		string 				`db:"full_name" json:"full_name"`
+	Phone 			string 				`db:"phone" json:"phone"`
+	Email 			string 				`db:"email" json:"email"`
+	Address 		string 				`db:"address" json:"address"`
+	Occupation 		string 				`db:"occupation" json:"occupation"`
+	Children 		[]GuardianChild 	`db:"-" json:"children,omitempty"`
+	Created_at 		time.Time 			`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 			`db:"updated_at" json:"updated_at"`
+}
+
+// the user id links the login of the role orangtua to the guardian
+type CreateGuardian struct {
+	UserId 			*uuid.UUID 		`json:"user_id"`
+	FullName 		string 			`json:"full_name" validate:"required,max=100"`
+	Phone 			string 			`json:"phone" validate:"omitempty,max=30"`
+	Email 			string 			`json:"email" validate:"omitempty,email,max=255"`
+	Address 		string 			`json:"address"`
+	Occupation 		string 			`json:"occupation" validate:"omitempty,max=100"`
+}
+
+type UpdateGuardian struct {
+	UserId 			*uuid.UUID 		`json:"user_id"`
+	FullName 		*string 		`json:"full_name" validate:"omitempty,min=1,max=100"`
+	Phone 			*string 		`json:"phone" validate:"omitempty,max=30"`
+	Email 			*string 		`json:"email" validate:"omitempty,email,max=255"`
+	Address 		*string 		`json:"address"`
+	Occupation 		*string 		`json:"occupation" validate:"omitempty,max=100"`
+}
+
+// GuardianLink is the student of the guardian with the relationship
+type GuardianLink struct {
+	GuardianId 		uuid.UUID 		`db:"guardian_id" json:"guardian_id"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	Relationship 	string 			`db:"relationship" json:"relationship"`
+	IsPrimary 		bool 			`db:"is_primary" json:"is_primary"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+}
+
+type LinkGuardianStudent struct {
+	StudentId 		uuid.UUID 		`json:"student_id" validate:"required"`
+	Relationship 	string 			`json:"relationship" validate:"required,oneof=ayah ibu wali"`
+	IsPrimary 		bool 			`json:"is_primary"`
+}
+
+// GuardianChild is the student of the guardian with the class
+type GuardianChild struct {
+	GuardianId 		uuid.UUID 		`db:"guardian_id" json:"-"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	ClassId 		*uuid.UUID 		`db:"class_id" json:"class_id"`
+	ClassName 		*string 		`db:"class_name" json:"class_name"`
+	Relationship 	string 			`db:"relationship" json:"relationship"`
+	IsPrimary 		bool 			`db:"is_primary" json:"is_primary"`
+}
+
+// StudentGuardian is the guardian of the student for the staff
+type StudentGuardian struct {
+	GuardianId 		uuid.UUID 		`db:"guardian_id" json:"guardian_id"`
+	FullName 		string 			`db:"full_name" json:"full_name"`
+	Phone 			string 			`db:"phone" json:"phone"`
+	Email 			string 			`db:"email" json:"email"`
+	Relationship 	string 			`db:"relationship" json:"relationship"`
+	IsPrimary 		bool 			`db:"is_primary" json:"is_primary"`
+	HasLogin 		bool 			`db:"has_login" json:"has_login"`
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HomeworkStore interface {
+	CreateHomework(ctx context.Context, homework *Homework) error
+	GetHomeworkById(ctx context.Context, id uuid.UUID) (*Homework, error)
+	GetHomework(ctx context.Context, classId uuid.UUID, termId uuid.UUID, subjectId *uuid.UUID, studentId *uuid.UUID) ([]Homework, error)
+	IsStudentOfClass(ctx context.Context, studentId uuid.UUID, classId uuid.UUID, termId uuid.UUID) (bool, error)
+	SaveSubmission(ctx context.Context, submission *HomeworkSubmission, files []HomeworkFile) error
+	GetSubmissionById(ctx context.Context, id uuid.UUID) (*HomeworkSubmission, error)
+	GetSubmission(ctx context.Context, homeworkId uuid.UUID, studentId uuid.UUID) (*HomeworkSubmission, error)
+	GetSubmissions(ctx context.Context, homeworkId uuid.UUID) ([]HomeworkSubmission, error)
+	GradeSubmission(ctx context.Context, id uuid.UUID, score float64, feedback string, gradedBy *uuid.UUID) error
+	GetFileById(ctx context.Context, id uuid.UUID) (*HomeworkFile, error)
+}
+
+type Homework struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	AssessmentId 	uuid.UUID 		`db:"assessment_id" json:"assessment_id"`
+	SubjectId 		uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	SubjectName 	string 			`db:"subject_name" json:"subject_name"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	Title 			string 			`db:"title" json:"title"`
+	Description 	string 			`db:"description" json:"description"`
+	DueAt 			time.Time 		`db:"due_at" json:"due_at"`
+	AllowLate 		bool 			`db:"allow_late" json:"allow_late"`
+	MaxScore 		float64 		`db:"max_score" json:"max_score"`
+	CreatedBy 		*uuid.UUID 		`db:"created_by" json:"created_by"`
+	Submissions 	int 			`db:"submissions" json:"submissions"`
+	Submission 		*HomeworkSubmission `db:"-" json:"submission,omitempty"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateHomework struct {
+	Title 			string 			`json:"title" validate:"required,max=200"`
+	Description 	string 			`json:"description" validate:"max=10000"`
+	DueAt 			time.Time 		`json:"due_at" validate:"required"`
+	AllowLate 		*bool 			`json:"allow_late"`
+	MaxScore 		float64 		`json:"max_score" validate:"omitempty,gt=0,max=1000"`
+}
+
+type HomeworkSubmission struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	HomeworkId 		uuid.UUID 		`db:"homework_id" json:"homework_id"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	Body 			string 			`db:"body" json:"body"`
+	SubmittedAt 	time.Time 		`db:"submitted_at" json:"submitted_at"`
+	IsLate 			bool 			`db:"is_late" json:"is_late"`
+	Score 			*float64 		`db:"score" json:"score"`
+	Feedback 		string 			`db:"feedback" json:"feedback"`
+	GradedBy 		*uuid.UUID 		`db:"graded_by" json:"graded_by"`
+	GradedAt 		*time.Time 		`db:"graded_at" json:"graded_at"`
+	Files 			[]HomeworkFile 	`db:"-" json:"files"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type HomeworkFile struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	SubmissionId 	uuid.UUID 		`db:"submission_id" json:"submission_id"`
+	FileKey 		string 			`db:"file_key" json:"-"`
+	FileName 		string 			`db:"file_name" json:"file_name"`
+	ContentType 	string 			`db:"content_type" json:"content_type"`
+	Size 			int64 			`db:"size" json:"size"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+}
+
+type GradeSubmission struct {
+	Score 			*float64 		`json:"score" validate:"required,min=0"`
+	Feedback 		string 			`json:"feedback" validate:"max=5000"`
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MajorStore interface {
+	CreateMajor(ctx context.Context, major *Major) error
+	GetMajorById(ctx context.Context, id uuid.UUID) (*Major, error)
+	GetMajorByCode(ctx context.Context, code string) (*Major, error)
+	GetAllMajors(ctx context.Context, onlyActive bool) ([]Major, error)
+	UpdateMajor(ctx context.Context, id uuid.UUID, payload UpdateMajor) error
+	SetCapacity(ctx context.Context, majorId uuid.UUID, academicYear string, seats int) ([]WaitlistEntry, error)
+	GetCapacity(ctx context.Context, majorId uuid.UUID, academicYear string) (*MajorCapacity, error)
+	EnrollStudent(ctx context.Context, student *Student, requestedBy uuid.UUID) (*WaitlistEntry, error)
+	GetWaitlist(ctx context.Context, majorId uuid.UUID, academicYear string) ([]WaitlistEntry, error)
+	CancelWaitlist(ctx context.Context, id uuid.UUID, requestedBy *uuid.UUID) error
+	PromoteWaitlist(ctx context.Context, majorId uuid.UUID, academicYear string) ([]WaitlistEntry, error)
+}
+
+type Major struct {
+	Id 				uuid.UUID 		`db:"id"`
+	Code 			string 			`db:"code"`
+	Name 			string 			`db:"name"`
+	Description 	string 			`db:"description"`
+	IsActive 		bool 			`db:"is_active"`
+	Created_at 		time.Time 		`db:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at"`
+}
+
+type CreateMajor struct {
+	Code 			string 			`json:"code" validate:"required,alphanum,max=20"`
+	Name 			string 			`json:"name" validate:"required,max=100"`
+	Description 	string 			`json:"description"`
+}
+
+type UpdateMajor struct {
+	Name 			*string 		`json:"name" validate:"omitempty,max=100"`
+	Description 	*string 		`json:"description"`
+	IsActive 		*bool 			`json:"is_active"`
+}
+
+type MajorResponse struct {
+	Id 				uuid.UUID 		`json:"id"`
+	Code 			string 			`json:"code"`
+	Name 			string 			`json:"name"`
+	Description 	string 			`json:"description"`
+	IsActive 		bool 			`json:"is_active"`
+	Created_at 		string 			`json:"created_at"`
+	Updated_at 		string 			`json:"updated_at"`
+}
+
+// MajorCapacity is the seat usage of a major in one academic year, Seats is nil when there is no limit
+type MajorCapacity struct {
+	MajorId 		uuid.UUID 		`db:"major_id" json:"major_id"`
+	AcademicYear 	string 			`db:"academic_year" json:"academic_year"`
+	Seats 			*int 			`db:"seats" json:"seats"`
+	Enrolled 		int 			`db:"enrolled" json:"enrolled"`
+	Waiting 		int 			`db:"waiting" json:"waiting"`
+}
+
+type SetCapacity struct {
+	AcademicYear 	string 			`json:"academic_year" validate:"required,len=9"`
+	Seats 			*int 			`json:"seats" validate:"required,min=0"`
+}
+
+// WaitlistEntry is a registration that was rejected because the major was full
+type WaitlistEntry struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	MajorId 		uuid.UUID 		`db:"major_id" json:"major_id"`
+	AcademicYear 	string 			`db:"academic_year" json:"academic_year"`
+	RequestedBy 	uuid.UUID 		`db:"requested_by" json:"requested_by"`
+	Name 			string 			`db:"name" json:"name"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	Address 		string 			`db:"address" json:"address"`
+	StudentProfile 	string 			`db:"student_profile" json:"student_profile"`
+	Status 			string 			`db:"status" json:"status"`
+	StudentId 		*uuid.UUID 		`db:"student_id" json:"student_id"`
+	Position 		int 			`db:"position" json:"position"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationStore interface {
+	CreateNotifications(ctx context.Context, notifications []Notification) (int, error)
+	GetNotifications(ctx context.Context, filter NotificationFilter) ([]Notification, error)
+	CountUnread(ctx context.Context, userId uuid.UUID) ([]NotificationCount, error)
+	MarkRead(ctx context.Context, userId uuid.UUID, id uuid.UUID, at time.Time) error
+	MarkAllRead(ctx context.Context, userId uuid.UUID, notificationType string, at time.Time) (int64, error)
+	GetRecipients(ctx context.Context, audience NotificationAudience) ([]uuid.UUID, error)
+	GetDueHomework(ctx context.Context, userId uuid.UUID, from time.Time, to time.Time) ([]DueHomework, error)
+}
+
+// Notifier is the hook of the domain events, the services tell what happened and the notifications
+// subsystem finds the users and writes the message
+type Notifier interface {
+	Notify(ctx context.Context, event NotificationEvent) error
+}
+
+// the type of the notification
+const (
+	NotifyGradesPublished 		= "grades_published"
+	NotifyHomeworkAssigned 		= "homework_assigned"
+	NotifyHomeworkDue 			= "homework_due"
+	NotifyAbsenceRecorded 		= "absence_recorded"
+)
+
+// NotificationEvent is the domain event, the payload is the data of the event for the message and the
+// client. The event with the dedupe key is only saved once for every user.
+type NotificationEvent struct {
+	Type 			string
+	Audience 		NotificationAudience
+	Payload 		map[string]any
+	DedupeKey 		string
+	At 				time.Time
+}
+
+// NotificationAudience is the users of the event, the students are notified with their user and the
+// guardians when it is asked. The class in the term is every student of the class.
+type NotificationAudience struct {
+	UserIds 		[]uuid.UUID
+	StudentIds 		[]uuid.UUID
+	ClassId 		*uuid.UUID
+	TermId 			*uuid.UUID
+	Guardians 		bool
+}
+
+type Notification struct {
+	Id 				uuid.UUID 			`db:"id" json:"id"`
+	UserId 			uuid.UUID 			`db:"user_id" json:"user_id"`
+	Type 			string 				`db:"type" json:"type"`
+	Title 			string 				`db:"title" json:"title"`
+	Body 			string 				`db:"body" json:"body"`
+	Payload 		json.RawMessage 	`db:"payload" json:"payload"`
+	DedupeKey 		*string 			`db:"dedupe_key" json:"-"`
+	ReadAt 			*time.Time 			`db:"read_at" json:"read_at"`
+	Created_at 		time.Time 			`db:"created_at" json:"created_at"`
+}
+
+// NotificationFilter is the page of the notifications of the user, the before is the created time of
+// the last notification of the previous page
+type NotificationFilter struct {
+	UserId 			uuid.UUID
+	UnreadOnly 		bool
+	Type 			string
+	Before 			*time.Time
+	Limit 			int
+}
+
+// NotificationCount is the count of the unread notifications of one type
+type NotificationCount struct {
+	Type 			string 			`db:"type" json:"type"`
+	Unread 			int 			`db:"unread" json:"unread"`
+}
+
+// UnreadCounter is the unread notifications of the user
+type UnreadCounter struct {
+	Total 			int 					`json:"total"`
+	ByType 			[]NotificationCount 	`json:"by_type"`
+}
+
+// DueHomework is the homework that is not submitted yet by the student of the user
+type DueHomework struct {
+	HomeworkId 		uuid.UUID 		`db:"homework_id"`
+	StudentId 		uuid.UUID 		`db:"student_id"`
+	StudentName 	string 			`db:"student_name"`
+	Title 			string 			`db:"title"`
+	SubjectName 	string 			`db:"subject_name"`
+	DueAt 			time.Time 		`db:"due_at"`
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PromotionStore interface {
+	PreviewPromotion(ctx context.Context, run *PromotionRun) ([]Class, error)
+	CommitPromotion(ctx context.Context, run *PromotionRun) ([]Class, error)
+	GetPromotionRuns(ctx context.Context, fromYear string) ([]PromotionRun, error)
+	GetPromotionRun(ctx context.Context, id uuid.UUID) (*PromotionRun, error)
+	RollbackPromotion(ctx context.Context, id uuid.UUID) error
+	CreateRetention(ctx context.Context, retention *Retention) error
+	GetRetentions(ctx context.Context, academicYear string) ([]Retention, error)
+	DeleteRetention(ctx context.Context, studentId uuid.UUID, academicYear string) error
+}
+
+// PromotionCandidate is an active student of the year with the grade of the current class
+type PromotionCandidate struct {
+	StudentId 		uuid.UUID 		`db:"student_id"`
+	Name 			string 			`db:"name"`
+	ClassId 		uuid.UUID 		`db:"class_id"`
+	Class 			string 			`db:"class"`
+	GradeLevel 		int 			`db:"grade_level"`
+	Major 			string 			`db:"major"`
+	Section 		string 			`db:"section"`
+	Retained 		bool 			`db:"retained"`
+}
+
+// PromotionItem is what happens to one student: promote, retain or graduate
+type PromotionItem struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	RunId 			uuid.UUID 		`db:"run_id" json:"-"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	Name 			string 			`db:"name" json:"name"`
+	Action 			string 			`db:"action" json:"action"`
+	FromClassId 	uuid.UUID 		`db:"from_class_id" json:"from_class_id"`
+	FromClass 		string 			`db:"from_class" json:"from_class"`
+	ToClassId 		*uuid.UUID 		`db:"to_class_id" json:"to_class_id"`
+	ToClass 		string 			`db:"to_class" json:"to_class"`
+	CreateClass 	bool 			`db:"create_class" json:"create_class"`
+}
+
+type PromotionRun struct {
+	Id 				uuid.UUID 			`db:"id" json:"id"`
+	FromYear 		string 				`db:"from_year" json:"from_year"`
+	ToYear 			string 				`db:"to_year" json:"to_year"`
+	TermId 			uuid.UUID 			`db:"term_id" json:"term_id"`
+	FinalGrade 		int 				`db:"final_grade" json:"final_grade"`
+	Status 			string 				`db:"status" json:"status"`
+	Promoted 		int 				`db:"promoted" json:"promoted"`
+	Retained 		int 				`db:"retained" json:"retained"`
+	Graduated 		int 				`db:"graduated" json:"graduated"`
+	CreatedBy 		*uuid.UUID 			`db:"created_by" json:"created_by"`
+	Created_at 		time.Time 			`db:"created_at" json:"created_at"`
+	RolledBackAt 	*time.Time 			`db:"rolled_back_at" json:"rolled_back_at"`
+	Items 			[]PromotionItem 	`db:"-" json:"items,omitempty"`
+}
+
+type PromotionRequest struct {
+	FromYear 		string 		`json:"from_year" validate:"required,len=9"`
+	ToYear 			string 		`json:"to_year" validate:"omitempty,len=9"`
+	FinalGrade 		int 		`json:"final_grade" validate:"omitempty,min=1,max=12"`
+}
+
+// Retention marks a student that is not promoted at the end of the academic year
+type Retention struct {
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	Name 			string 			`db:"name" json:"name"`
+	AcademicYear 	string 			`db:"academic_year" json:"academic_year"`
+	Reason 			string 			`db:"reason" json:"reason"`
+	CreatedBy 		*uuid.UUID 		`db:"created_by" json:"created_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+}
+
+type CreateRetention struct {
+	StudentId 		uuid.UUID 		`json:"student_id" validate:"required"`
+	AcademicYear 	string 			`json:"academic_year" validate:"required,len=9"`
+	Reason 			string 			`json:"reason" validate:"required,max=255"`
+}
@@ -0,0 +1,212 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QuizStore interface {
+	CreateBank(ctx context.Context, bank *QuestionBank) error
+	GetBankById(ctx context.Context, id uuid.UUID) (*QuestionBank, error)
+	GetBanks(ctx context.Context, subjectId *uuid.UUID) ([]QuestionBank, error)
+	CreateQuestion(ctx context.Context, question *Question) error
+	GetQuestions(ctx context.Context, bankId uuid.UUID) ([]Question, error)
+	GetQuestionById(ctx context.Context, id uuid.UUID) (*Question, error)
+	DeleteQuestion(ctx context.Context, id uuid.UUID) error
+	CreateQuiz(ctx context.Context, quiz *Quiz, questionIds []uuid.UUID) error
+	GetQuizById(ctx context.Context, id uuid.UUID) (*Quiz, error)
+	GetQuizzes(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]Quiz, error)
+	GetQuizQuestions(ctx context.Context, quizId uuid.UUID) ([]QuizQuestion, error)
+	IsStudentOfClass(ctx context.Context, studentId uuid.UUID, classId uuid.UUID, termId uuid.UUID) (bool, error)
+	StartAttempt(ctx context.Context, attempt *QuizAttempt, maxAttempts int) error
+	GetAttemptById(ctx context.Context, id uuid.UUID) (*QuizAttempt, error)
+	GetOpenAttempt(ctx context.Context, quizId uuid.UUID, studentId uuid.UUID) (*QuizAttempt, error)
+	GetAttempts(ctx context.Context, quizId uuid.UUID, studentId *uuid.UUID) ([]QuizAttempt, error)
+	GetAnswers(ctx context.Context, attemptId uuid.UUID) ([]AttemptAnswer, error)
+	SaveAnswers(ctx context.Context, attemptId uuid.UUID, answers []AnswerEntry, now time.Time) error
+	FinishAttempt(ctx context.Context, attemptId uuid.UUID, scores map[uuid.UUID]*float64, score float64, status string, now time.Time) error
+	GetGradingQueue(ctx context.Context, quizId uuid.UUID) ([]GradingQueueItem, error)
+	GradeAnswer(ctx context.Context, attemptId uuid.UUID, questionId uuid.UUID, score float64, feedback string, gradedBy *uuid.UUID) error
+	UpdateAttemptScore(ctx context.Context, attemptId uuid.UUID, score float64, status string) error
+}
+
+// the types of the questions
+const (
+	QuestionMultipleChoice 	= "multiple_choice"
+	QuestionMultiSelect 	= "multi_select"
+	QuestionShortAnswer 	= "short_answer"
+	QuestionEssay 			= "essay"
+)
+
+// the status of the attempt, the submitted attempt waits for the manual grading of the essays
+const (
+	AttemptInProgress 	= "in_progress"
+	AttemptSubmitted 	= "submitted"
+	AttemptGraded 		= "graded"
+)
+
+type QuestionBank struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	SubjectId 		uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	Name 			string 			`db:"name" json:"name"`
+	Description 	string 			`db:"description" json:"description"`
+	CreatedBy 		*uuid.UUID 		`db:"created_by" json:"created_by"`
+	Questions 		int 			`db:"questions" json:"questions"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateQuestionBank struct {
+	SubjectId 		uuid.UUID 		`json:"subject_id" validate:"required"`
+	Name 			string 			`json:"name" validate:"required,max=100"`
+	Description 	string 			`json:"description" validate:"max=1000"`
+}
+
+// Question is one question of the bank, the options of the short answer question are the accepted answers
+type Question struct {
+	Id 				uuid.UUID 			`db:"id" json:"id"`
+	BankId 			uuid.UUID 			`db:"bank_id" json:"bank_id"`
+	Type 			string 				`db:"type" json:"type"`
+	Prompt 			string 				`db:"prompt" json:"prompt"`
+	Points 			float64 			`db:"points" json:"points"`
+	Options 		[]QuestionOption 	`db:"-" json:"options"`
+	Created_at 		time.Time 			`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 			`db:"updated_at" json:"updated_at"`
+}
+
+type QuestionOption struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	QuestionId 		uuid.UUID 		`db:"question_id" json:"question_id"`
+	Text 			string 			`db:"text" json:"text"`
+	IsCorrect 		bool 			`db:"is_correct" json:"is_correct"`
+	Position 		int 			`db:"position" json:"position"`
+}
+
+type CreateQuestion struct {
+	Type 			string 					`json:"type" validate:"required,oneof=multiple_choice multi_select short_answer essay"`
+	Prompt 			string 					`json:"prompt" validate:"required,max=5000"`
+	Points 			float64 				`json:"points" validate:"omitempty,gt=0,max=100"`
+	Options 		[]CreateQuestionOption 	`json:"options" validate:"max=10,dive"`
+}
+
+type CreateQuestionOption struct {
+	Text 			string 			`json:"text" validate:"required,max=1000"`
+	Correct 		bool 			`json:"correct"`
+}
+
+type Quiz struct {
+	Id 					uuid.UUID 		`db:"id" json:"id"`
+	SubjectId 			uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	ClassId 			uuid.UUID 		`db:"class_id" json:"class_id"`
+	TermId 				uuid.UUID 		`db:"term_id" json:"term_id"`
+	Title 				string 			`db:"title" json:"title"`
+	TimeLimitMinutes 	int 			`db:"time_limit_minutes" json:"time_limit_minutes"`
+	OpensAt 			time.Time 		`db:"opens_at" json:"opens_at"`
+	ClosesAt 			time.Time 		`db:"closes_at" json:"closes_at"`
+	ShuffleQuestions 	bool 			`db:"shuffle_questions" json:"shuffle_questions"`
+	ShuffleOptions 		bool 			`db:"shuffle_options" json:"shuffle_options"`
+	MaxAttempts 		int 			`db:"max_attempts" json:"max_attempts"`
+	Questions 			int 			`db:"questions" json:"questions"`
+	MaxScore 			float64 		`db:"max_score" json:"max_score"`
+	CreatedBy 			*uuid.UUID 		`db:"created_by" json:"created_by"`
+	Created_at 			time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 			time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateQuiz struct {
+	Title 				string 			`json:"title" validate:"required,max=200"`
+	QuestionIds 		[]uuid.UUID 	`json:"question_ids" validate:"required,min=1,max=200,unique,dive,required"`
+	TimeLimitMinutes 	int 			`json:"time_limit_minutes" validate:"required,min=1,max=600"`
+	OpensAt 			time.Time 		`json:"opens_at" validate:"required"`
+	ClosesAt 			time.Time 		`json:"closes_at" validate:"required,gtfield=OpensAt"`
+	ShuffleQuestions 	*bool 			`json:"shuffle_questions"`
+	ShuffleOptions 		*bool 			`json:"shuffle_options"`
+	MaxAttempts 		int 			`json:"max_attempts" validate:"omitempty,min=1,max=10"`
+}
+
+// QuizDetail is the quiz with the questions and the answers for the staff or with the attempts for the siswa
+type QuizDetail struct {
+	Quiz
+	Items 			[]QuizQuestion 	`json:"items,omitempty"`
+	Attempts 		[]QuizAttempt 	`json:"attempts,omitempty"`
+}
+
+// QuizQuestion is the question of the quiz with the position that the guru chose
+type QuizQuestion struct {
+	Question
+	Position 		int 			`db:"position" json:"position"`
+}
+
+type QuizAttempt struct {
+	Id 				uuid.UUID 			`db:"id" json:"id"`
+	QuizId 			uuid.UUID 			`db:"quiz_id" json:"quiz_id"`
+	StudentId 		uuid.UUID 			`db:"student_id" json:"student_id"`
+	StudentName 	string 				`db:"student_name" json:"student_name"`
+	AttemptNo 		int 				`db:"attempt_no" json:"attempt_no"`
+	Seed 			int64 				`db:"seed" json:"-"`
+	StartedAt 		time.Time 			`db:"started_at" json:"started_at"`
+	DeadlineAt 		time.Time 			`db:"deadline_at" json:"deadline_at"`
+	SubmittedAt 	*time.Time 			`db:"submitted_at" json:"submitted_at"`
+	Status 			string 				`db:"status" json:"status"`
+	Score 			*float64 			`db:"score" json:"score"`
+	MaxScore 		float64 			`db:"max_score" json:"max_score"`
+	Questions 		[]AttemptQuestion 	`db:"-" json:"questions,omitempty"`
+	Created_at 		time.Time 			`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 			`db:"updated_at" json:"updated_at"`
+}
+
+// AttemptQuestion is the question in the order of the attempt without the correct answers
+type AttemptQuestion struct {
+	QuestionId 		uuid.UUID 		`json:"question_id"`
+	Type 			string 			`json:"type"`
+	Prompt 			string 			`json:"prompt"`
+	Points 			float64 		`json:"points"`
+	Options 		[]AttemptOption `json:"options"`
+	Answer 			*AttemptAnswer 	`json:"answer"`
+}
+
+type AttemptOption struct {
+	Id 				uuid.UUID 		`json:"id"`
+	Text 			string 			`json:"text"`
+}
+
+type AttemptAnswer struct {
+	AttemptId 		uuid.UUID 		`json:"attempt_id"`
+	QuestionId 		uuid.UUID 		`json:"question_id"`
+	SelectedOptions []uuid.UUID 	`json:"selected_options"`
+	TextAnswer 		string 			`json:"text_answer"`
+	AutoScore 		*float64 		`json:"auto_score"`
+	ManualScore 	*float64 		`json:"manual_score"`
+	Feedback 		string 			`json:"feedback"`
+	GradedBy 		*uuid.UUID 		`json:"graded_by"`
+	GradedAt 		*time.Time 		`json:"graded_at"`
+}
+
+type AnswerEntry struct {
+	QuestionId 		uuid.UUID 		`json:"question_id" validate:"required"`
+	SelectedOptions []uuid.UUID 	`json:"selected_options" validate:"max=10"`
+	Text 			string 			`json:"text" validate:"max=20000"`
+}
+
+type SaveAnswers struct {
+	Answers 		[]AnswerEntry 	`json:"answers" validate:"required,min=1,max=200,dive"`
+}
+
+type GradeAnswer struct {
+	Score 			*float64 		`json:"score" validate:"required,min=0"`
+	Feedback 		string 			`json:"feedback" validate:"max=5000"`
+}
+
+// GradingQueueItem is one essay answer of the submitted attempt that waits for the manual grading
+type GradingQueueItem struct {
+	AttemptId 		uuid.UUID 		`db:"attempt_id" json:"attempt_id"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	QuestionId 		uuid.UUID 		`db:"question_id" json:"question_id"`
+	Prompt 			string 			`db:"prompt" json:"prompt"`
+	Points 			float64 		`db:"points" json:"points"`
+	TextAnswer 		string 			`db:"text_answer" json:"text_answer"`
+	SubmittedAt 	time.Time 		`db:"submitted_at" json:"submitted_at"`
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// the status of the remedial and of the appeal
+const (
+	RemedialAssigned 	= "assigned"
+	RemedialGraded 		= "graded"
+
+	AppealPending 		= "pending"
+	AppealAccepted 		= "accepted"
+	AppealRejected 		= "rejected"
+
+	ChangeByRemedial 	= "remedial"
+	ChangeByAppeal 		= "appeal"
+)
+
+// Remedial is the remedial work of the student that failed the assessment
+type Remedial struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	AssessmentId 	uuid.UUID 		`db:"assessment_id" json:"assessment_id"`
+	AssessmentTitle string 			`db:"assessment_title" json:"assessment_title"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	Title 			string 			`db:"title" json:"title"`
+	Instructions 	string 			`db:"instructions" json:"instructions"`
+	DueOn 			*time.Time 		`db:"due_on" json:"due_on"`
+	CapScore 		float64 		`db:"cap_score" json:"cap_score"`
+	OriginalScore 	float64 		`db:"original_score" json:"original_score"`
+	RemedialScore 	*float64 		`db:"remedial_score" json:"remedial_score"`
+	FinalScore 		*float64 		`db:"final_score" json:"final_score"`
+	Status 			string 			`db:"status" json:"status"`
+	AssignedBy 		*uuid.UUID 		`db:"assigned_by" json:"assigned_by"`
+	GradedBy 		*uuid.UUID 		`db:"graded_by" json:"graded_by"`
+	GradedAt 		*time.Time 		`db:"graded_at" json:"graded_at"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+// CreateRemedial assigns the remedial work, the default cap score is the kkm in the scale of the assessment
+type CreateRemedial struct {
+	StudentId 		uuid.UUID 		`json:"student_id" validate:"required"`
+	Title 			string 			`json:"title" validate:"required,max=200"`
+	Instructions 	string 			`json:"instructions" validate:"max=5000"`
+	DueOn 			*time.Time 		`json:"due_on"`
+	CapScore 		*float64 		`json:"cap_score" validate:"omitempty,min=0"`
+}
+
+type GradeRemedial struct {
+	Score 			*float64 		`json:"score" validate:"required,min=0"`
+	Reason 			string 			`json:"reason" validate:"max=500"`
+}
+
+type GradeAppeal struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	AssessmentId 	uuid.UUID 		`db:"assessment_id" json:"assessment_id"`
+	AssessmentTitle string 			`db:"assessment_title" json:"assessment_title"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	SubmittedBy 	*uuid.UUID 		`db:"submitted_by" json:"submitted_by"`
+	Reason 			string 			`db:"reason" json:"reason"`
+	Status 			string 			`db:"status" json:"status"`
+	CurrentScore 	float64 		`db:"current_score" json:"current_score"`
+	NewScore 		*float64 		`db:"new_score" json:"new_score"`
+	Response 		string 			`db:"response" json:"response"`
+	RespondedBy 	*uuid.UUID 		`db:"responded_by" json:"responded_by"`
+	RespondedAt 	*time.Time 		`db:"responded_at" json:"responded_at"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+// the student is required when the orangtua submits the appeal for their child
+type CreateGradeAppeal struct {
+	StudentId 		*uuid.UUID 		`json:"student_id"`
+	Reason 			string 			`json:"reason" validate:"required,min=10,max=2000"`
+}
+
+// RespondGradeAppeal is the answer of the guru, the new score is required when the appeal is accepted
+type RespondGradeAppeal struct {
+	Status 			string 			`json:"status" validate:"required,oneof=accepted rejected"`
+	Response 		string 			`json:"response" validate:"required,max=2000"`
+	NewScore 		*float64 		`json:"new_score" validate:"required_if=Status accepted,omitempty,min=0"`
+}
+
+// GradeChange is one change of the recorded score of the student after the remedial or the appeal
+type GradeChange struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	AssessmentId 	uuid.UUID 		`db:"assessment_id" json:"assessment_id"`
+	AssessmentTitle string 			`db:"assessment_title" json:"assessment_title"`
+	SubjectName 	string 			`db:"subject_name" json:"subject_name"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	OldScore 		*float64 		`db:"old_score" json:"old_score"`
+	NewScore 		float64 		`db:"new_score" json:"new_score"`
+	Source 			string 			`db:"source" json:"source"`
+	SourceId 		uuid.UUID 		`db:"source_id" json:"source_id"`
+	Reason 			string 			`db:"reason" json:"reason"`
+	ChangedBy 		*uuid.UUID 		`db:"changed_by" json:"changed_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+}
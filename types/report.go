@@ -0,0 +1,122 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportStore interface {
+	CreateTemplate(ctx context.Context, template *ReportTemplate) error
+	GetTemplateById(ctx context.Context, id uuid.UUID) (*ReportTemplate, error)
+	GetDefaultTemplate(ctx context.Context) (*ReportTemplate, error)
+	GetTemplates(ctx context.Context) ([]ReportTemplate, error)
+	UpdateTemplate(ctx context.Context, id uuid.UUID, payload UpdateReportTemplate) error
+	SetTemplateLogo(ctx context.Context, id uuid.UUID, logoKey string) error
+	SaveNote(ctx context.Context, note *ReportNote) error
+	IsHomeroomOf(ctx context.Context, teacherId uuid.UUID, studentId uuid.UUID, termId uuid.UUID) (bool, error)
+	GetReportData(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) (*ReportCardData, error)
+	GetClassStudentIds(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]uuid.UUID, error)
+	NextReportVersion(ctx context.Context, studentId uuid.UUID, termId uuid.UUID) (int, error)
+	SaveReportCard(ctx context.Context, card *ReportCard) error
+	GetReportCards(ctx context.Context, studentId uuid.UUID, termId *uuid.UUID) ([]ReportCard, error)
+	GetReportCardById(ctx context.Context, id uuid.UUID) (*ReportCard, error)
+}
+
+type ReportTemplate struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	Name 			string 			`db:"name" json:"name"`
+	Title 			string 			`db:"title" json:"title"`
+	SchoolName 		string 			`db:"school_name" json:"school_name"`
+	Address 		string 			`db:"address" json:"address"`
+	Contact 		string 			`db:"contact" json:"contact"`
+	City 			string 			`db:"city" json:"city"`
+	PrincipalName 	string 			`db:"principal_name" json:"principal_name"`
+	PrincipalNip 	string 			`db:"principal_nip" json:"principal_nip"`
+	FooterText 		string 			`db:"footer_text" json:"footer_text"`
+	LogoKey 		*string 		`db:"logo_key" json:"logo_key"`
+	IsDefault 		bool 			`db:"is_default" json:"is_default"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateReportTemplate struct {
+	Name 			string 			`json:"name" validate:"required,max=100"`
+	Title 			string 			`json:"title" validate:"max=200"`
+	SchoolName 		string 			`json:"school_name" validate:"required,max=200"`
+	Address 		string 			`json:"address"`
+	Contact 		string 			`json:"contact"`
+	City 			string 			`json:"city" validate:"max=100"`
+	PrincipalName 	string 			`json:"principal_name" validate:"max=100"`
+	PrincipalNip 	string 			`json:"principal_nip" validate:"max=50"`
+	FooterText 		string 			`json:"footer_text"`
+	IsDefault 		bool 			`json:"is_default"`
+}
+
+type UpdateReportTemplate struct {
+	Title 			*string 		`json:"title" validate:"omitempty,max=200"`
+	SchoolName 		*string 		`json:"school_name" validate:"omitempty,max=200"`
+	Address 		*string 		`json:"address"`
+	Contact 		*string 		`json:"contact"`
+	City 			*string 		`json:"city" validate:"omitempty,max=100"`
+	PrincipalName 	*string 		`json:"principal_name" validate:"omitempty,max=100"`
+	PrincipalNip 	*string 		`json:"principal_nip" validate:"omitempty,max=50"`
+	FooterText 		*string 		`json:"footer_text"`
+	IsDefault 		*bool 			`json:"is_default"`
+}
+
+type ReportNote struct {
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	Note 			string 			`db:"note" json:"note"`
+	WrittenBy 		*uuid.UUID 		`db:"written_by" json:"written_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type SaveReportNote struct {
+	Note 			string 			`json:"note" validate:"required,max=2000"`
+}
+
+// ReportGrade is the published term score of one subject in the report card
+type ReportGrade struct {
+	SubjectCode 	string 			`db:"subject_code" json:"subject_code"`
+	SubjectName 	string 			`db:"subject_name" json:"subject_name"`
+	Score 			float64 		`db:"score" json:"score"`
+}
+
+// ReportCardData is everything that is printed in the report card of the student in one term
+type ReportCardData struct {
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	StudentName 	string 			`db:"student_name" json:"student_name"`
+	ClassName 		string 			`db:"class_name" json:"class_name"`
+	HomeroomTeacher string 			`db:"homeroom_teacher" json:"homeroom_teacher"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	TermName 		string 			`db:"term_name" json:"term_name"`
+	Semester 		int 			`db:"semester" json:"semester"`
+	AcademicYear 	string 			`db:"academic_year" json:"academic_year"`
+	Note 			string 			`db:"note" json:"note"`
+	Grades 			[]ReportGrade 	`db:"-" json:"grades"`
+	Attendance 		AttendanceSummary 	`db:"-" json:"attendance"`
+}
+
+// ReportCard is one generated version of the report card
+type ReportCard struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	StudentId 		uuid.UUID 		`db:"student_id" json:"student_id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	Version 		int 			`db:"version" json:"version"`
+	TemplateId 		*uuid.UUID 		`db:"template_id" json:"template_id"`
+	FileKey 		string 			`db:"file_key" json:"-"`
+	Checksum 		string 			`db:"checksum" json:"checksum"`
+	Size 			int64 			`db:"size" json:"size"`
+	Reason 			string 			`db:"reason" json:"reason"`
+	GeneratedBy 	*uuid.UUID 		`db:"generated_by" json:"generated_by"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+}
+
+type GenerateReportCard struct {
+	TemplateId 		*uuid.UUID 		`json:"template_id"`
+	Reason 			string 			`json:"reason" validate:"max=500"`
+}
@@ -10,6 +10,7 @@ import (
 type StudentStore interface {
 	CreateNewStudent(ctx context.Context, student *Student) error
 	GetStudentByName(name string) (*Student, error)
+	GetStudentByUserId(ctx context.Context, userId uuid.UUID) (*Student, error)
 	GetAllStudents(
 		ctx context.Context,
 		limit int,
@@ -24,8 +25,12 @@ type Student struct {
 	Id 				uuid.UUID 		`db:"id"`
 	Name 			string 			`db:"name"`
 	Class 			string 			`db:"class"`
+	ClassId 		*uuid.UUID 		`db:"class_id"`
 	Address 		string			`db:"address"`
 	Major 			string 			`db:"major"`
+	MajorId 		*uuid.UUID 		`db:"major_id"`
+	AcademicYear 	*string 		`db:"academic_year"`
+	UserId 			*uuid.UUID 		`db:"user_id"`
 	StudentProfile	string 			`db:"student_profile"`
 	Created_at 		time.Time 		`db:"created_at"`
 	Updated_at      time.Time 		`db:"updated_at"`
@@ -34,7 +39,8 @@ type Student struct {
 type RegisterAsStudent struct {
 	Id 				uuid.UUID 		`json:"id"`
 	Name 			string 			`json:"name" validate:"required"`
-	Class 			string 			`json:"class" validate:"required"`
+	Class 			string 			`json:"class" validate:"required_without=ClassId"`
+	ClassId 		*uuid.UUID 		`json:"class_id"`
 	Address 		string 			`json:"address" validate:"required"`
 	Major 			string 			`json:"major" validate:"required"`
 	StudentProfile 	string 			`json:"student_profile"`
@@ -57,6 +63,7 @@ type StudentResponse struct {
 	Id 				uuid.UUID 		`json:"id"`
 	Name 			string 			`json:"name"`
 	Class 			string 			`json:"class"`
+	ClassId 		*uuid.UUID 		`json:"class_id"`
 	Address 		string 			`json:"address"`
 	Major 			string 			`json:"major"`
 	StudentProfile 	string 			`json:"student_profile"`
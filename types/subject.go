@@ -0,0 +1,98 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubjectStore interface {
+	CreateSubject(ctx context.Context, subject *Subject) error
+	GetSubjectById(ctx context.Context, id uuid.UUID) (*Subject, error)
+	GetSubjectByCode(ctx context.Context, code string) (*Subject, error)
+	GetAllSubjects(ctx context.Context, onlyActive bool) ([]Subject, error)
+	UpdateSubject(ctx context.Context, id uuid.UUID, payload UpdateSubject) error
+	SetSubjectHours(ctx context.Context, subjectId uuid.UUID, hours []SubjectHoursEntry) ([]SubjectHours, error)
+	GetSubjectHours(ctx context.Context, subjectId uuid.UUID) ([]SubjectHours, error)
+	GetCurriculum(ctx context.Context, gradeLevel int, major string) ([]SubjectHours, error)
+	CreateAssignment(ctx context.Context, assignment *TeachingAssignment) error
+	GetAssignmentById(ctx context.Context, id uuid.UUID) (*TeachingAssignment, error)
+	GetAssignments(ctx context.Context, filter AssignmentFilter) ([]TeachingAssignment, error)
+	DeleteAssignment(ctx context.Context, id uuid.UUID) error
+	CanTeach(ctx context.Context, teacherId uuid.UUID, classId uuid.UUID, termId *uuid.UUID, subjectId *uuid.UUID) (bool, error)
+}
+
+type Subject struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	Code 			string 			`db:"code" json:"code"`
+	Name 			string 			`db:"name" json:"name"`
+	Description 	string 			`db:"description" json:"description"`
+	Kkm 			*float64 		`db:"kkm" json:"kkm"`
+	IsActive 		bool 			`db:"is_active" json:"is_active"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateSubject struct {
+	Code 			string 			`json:"code" validate:"required,alphanum,max=20"`
+	Name 			string 			`json:"name" validate:"required,max=100"`
+	Description 	string 			`json:"description"`
+	Kkm 			*float64 		`json:"kkm" validate:"omitempty,min=0,max=100"`
+}
+
+type UpdateSubject struct {
+	Name 			*string 		`json:"name" validate:"omitempty,max=100"`
+	Description 	*string 		`json:"description"`
+	Kkm 			*float64 		`json:"kkm" validate:"omitempty,min=0,max=100"`
+	IsActive 		*bool 			`json:"is_active"`
+}
+
+// SubjectHours is the weekly hours of the subject in one grade, an empty major means every major
+type SubjectHours struct {
+	SubjectId 		uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	SubjectCode 	string 			`db:"subject_code" json:"subject_code"`
+	SubjectName 	string 			`db:"subject_name" json:"subject_name"`
+	GradeLevel 		int 			`db:"grade_level" json:"grade_level"`
+	Major 			string 			`db:"major" json:"major"`
+	HoursPerWeek 	int 			`db:"hours_per_week" json:"hours_per_week"`
+}
+
+type SubjectHoursEntry struct {
+	GradeLevel 		int 			`json:"grade_level" validate:"required,min=1,max=12"`
+	Major 			string 			`json:"major" validate:"max=50"`
+	HoursPerWeek 	int 			`json:"hours_per_week" validate:"required,min=1,max=40"`
+}
+
+type SetSubjectHours struct {
+	Hours 			[]SubjectHoursEntry 	`json:"hours" validate:"dive"`
+}
+
+// TeachingAssignment links the guru to the subject that they teach in the class for one term
+type TeachingAssignment struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	TeacherId 		uuid.UUID 		`db:"teacher_id" json:"teacher_id"`
+	TeacherName 	string 			`db:"teacher_name" json:"teacher_name"`
+	SubjectId 		uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	SubjectCode 	string 			`db:"subject_code" json:"subject_code"`
+	SubjectName 	string 			`db:"subject_name" json:"subject_name"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	ClassName 		string 			`db:"class_name" json:"class_name"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateAssignment struct {
+	TeacherId 		uuid.UUID 		`json:"teacher_id" validate:"required"`
+	SubjectId 		uuid.UUID 		`json:"subject_id" validate:"required"`
+	ClassId 		uuid.UUID 		`json:"class_id" validate:"required"`
+	TermId 			*uuid.UUID 		`json:"term_id"`
+}
+
+type AssignmentFilter struct {
+	TeacherId 		*uuid.UUID
+	SubjectId 		*uuid.UUID
+	ClassId 		*uuid.UUID
+	TermId 			*uuid.UUID
+}
@@ -0,0 +1,183 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimetableStore interface {
+	CreateSlot(ctx context.Context, slot *TimetableSlot) ([]SlotConflict, error)
+	GetSlotById(ctx context.Context, id uuid.UUID) (*TimetableSlot, error)
+	GetClassTimetable(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]TimetableSlot, error)
+	DeleteSlot(ctx context.Context, id uuid.UUID) error
+	GetClassQuota(ctx context.Context, classId uuid.UUID, termId uuid.UUID) ([]SubjectQuota, error)
+	CreatePeriod(ctx context.Context, period *Period) error
+	GetPeriodById(ctx context.Context, id uuid.UUID) (*Period, error)
+	GetPeriods(ctx context.Context) ([]Period, error)
+	DeletePeriod(ctx context.Context, id uuid.UUID) error
+	CreateRoom(ctx context.Context, room *Room) error
+	GetRoomById(ctx context.Context, id uuid.UUID) (*Room, error)
+	GetRooms(ctx context.Context, onlyActive bool) ([]Room, error)
+	UpdateRoom(ctx context.Context, id uuid.UUID, payload UpdateRoom) error
+	SetSlotPinned(ctx context.Context, id uuid.UUID, pinned bool) error
+	CreateUnavailability(ctx context.Context, entry *Unavailability) error
+	GetUnavailability(ctx context.Context, teacherId *uuid.UUID, roomId *uuid.UUID) ([]Unavailability, error)
+	DeleteUnavailability(ctx context.Context, id uuid.UUID) error
+	GenerateTimetable(ctx context.Context, generation *TimetableGeneration) (*GenerationResult, error)
+}
+
+// TimetableSlot is a weekly lesson of the class, day_of_week is 1 (monday) to 7 (sunday)
+type TimetableSlot struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	ClassId 		uuid.UUID 		`db:"class_id" json:"class_id"`
+	TermId 			uuid.UUID 		`db:"term_id" json:"term_id"`
+	DayOfWeek 		int 			`db:"day_of_week" json:"day_of_week"`
+	StartTime 		string 			`db:"start_time" json:"start_time"`
+	EndTime 		string 			`db:"end_time" json:"end_time"`
+	Subject 		string 			`db:"subject" json:"subject"`
+	SubjectId 		*uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	TeacherId 		*uuid.UUID 		`db:"teacher_id" json:"teacher_id"`
+	PeriodId 		*uuid.UUID 		`db:"period_id" json:"period_id"`
+	RoomId 			*uuid.UUID 		`db:"room_id" json:"room_id"`
+	IsPinned 		bool 			`db:"is_pinned" json:"is_pinned"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+// CreateSlot takes the time of the period when the period id is set, otherwise the start and the end time
+type CreateSlot struct {
+	TermId 			uuid.UUID 		`json:"term_id" validate:"required"`
+	DayOfWeek 		int 			`json:"day_of_week" validate:"required,min=1,max=7"`
+	PeriodId 		*uuid.UUID 		`json:"period_id"`
+	StartTime 		string 			`json:"start_time" validate:"required_without=PeriodId,omitempty,datetime=15:04"`
+	EndTime 		string 			`json:"end_time" validate:"required_without=PeriodId,omitempty,datetime=15:04"`
+	Subject 		string 			`json:"subject" validate:"required_without=SubjectId,max=100"`
+	SubjectId 		*uuid.UUID 		`json:"subject_id"`
+	TeacherId 		*uuid.UUID 		`json:"teacher_id"`
+	RoomId 			*uuid.UUID 		`json:"room_id"`
+	Pinned 			bool 			`json:"pinned"`
+}
+
+// SlotConflict is one reason why the proposed slot is rejected, the slot id is the existing slot that clashes
+type SlotConflict struct {
+	Kind 			string 			`json:"kind"`
+	Message 		string 			`json:"message"`
+	SlotId 			*uuid.UUID 		`json:"slot_id,omitempty"`
+	ClassId 		*uuid.UUID 		`json:"class_id,omitempty"`
+	TeacherId 		*uuid.UUID 		`json:"teacher_id,omitempty"`
+	RoomId 			*uuid.UUID 		`json:"room_id,omitempty"`
+	DayOfWeek 		int 			`json:"day_of_week,omitempty"`
+	StartTime 		string 			`json:"start_time,omitempty"`
+	EndTime 		string 			`json:"end_time,omitempty"`
+	Subject 		string 			`json:"subject,omitempty"`
+}
+
+// SubjectQuota is the planned lessons of the subject in the week against the weekly hours of the curriculum
+type SubjectQuota struct {
+	SubjectId 		uuid.UUID 		`db:"subject_id" json:"subject_id"`
+	SubjectCode 	string 			`db:"subject_code" json:"subject_code"`
+	SubjectName 	string 			`db:"subject_name" json:"subject_name"`
+	Required 		int 			`db:"required" json:"required"`
+	Planned 		int 			`db:"planned" json:"planned"`
+	Status 			string 			`db:"-" json:"status"`
+}
+
+// Period is one lesson hour of the bell schedule
+type Period struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	Number 			int 			`db:"number" json:"number"`
+	Name 			string 			`db:"name" json:"name"`
+	StartTime 		string 			`db:"start_time" json:"start_time"`
+	EndTime 		string 			`db:"end_time" json:"end_time"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreatePeriod struct {
+	Number 			int 			`json:"number" validate:"required,min=1"`
+	Name 			string 			`json:"name" validate:"required,max=50"`
+	StartTime 		string 			`json:"start_time" validate:"required,datetime=15:04"`
+	EndTime 		string 			`json:"end_time" validate:"required,datetime=15:04"`
+}
+
+type Room struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	Code 			string 			`db:"code" json:"code"`
+	Name 			string 			`db:"name" json:"name"`
+	Capacity 		*int 			`db:"capacity" json:"capacity"`
+	IsActive 		bool 			`db:"is_active" json:"is_active"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateRoom struct {
+	Code 			string 			`json:"code" validate:"required,alphanum,max=20"`
+	Name 			string 			`json:"name" validate:"required,max=100"`
+	Capacity 		*int 			`json:"capacity" validate:"omitempty,min=1"`
+}
+
+type UpdateRoom struct {
+	Name 			*string 		`json:"name" validate:"omitempty,max=100"`
+	Capacity 		*int 			`json:"capacity" validate:"omitempty,min=1"`
+	IsActive 		*bool 			`json:"is_active"`
+}
+
+// Unavailability blocks the teacher or the room in one day, a nil period blocks the whole day
+type Unavailability struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	TeacherId 		*uuid.UUID 		`db:"teacher_id" json:"teacher_id"`
+	RoomId 			*uuid.UUID 		`db:"room_id" json:"room_id"`
+	DayOfWeek 		int 			`db:"day_of_week" json:"day_of_week"`
+	PeriodId 		*uuid.UUID 		`db:"period_id" json:"period_id"`
+	Reason 			string 			`db:"reason" json:"reason"`
+	Created_at 		time.Time 		`db:"created_at" json:"created_at"`
+	Updated_at 		time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+type CreateUnavailability struct {
+	TeacherId 		*uuid.UUID 		`json:"teacher_id" validate:"required_without=RoomId,excluded_with=RoomId"`
+	RoomId 			*uuid.UUID 		`json:"room_id" validate:"required_without=TeacherId"`
+	DayOfWeek 		int 			`json:"day_of_week" validate:"required,min=1,max=7"`
+	PeriodId 		*uuid.UUID 		`json:"period_id"`
+	Reason 			string 			`json:"reason" validate:"max=255"`
+}
+
+type SetSlotPinned struct {
+	Pinned 			*bool 			`json:"pinned" validate:"required"`
+}
+
+// GenerateTimetable is the request of the solver, the default classes are the classes of the year
+// of the term and the default days are monday to friday
+type GenerateTimetable struct {
+	ClassIds 		[]uuid.UUID 	`json:"class_ids"`
+	Days 			[]int 			`json:"days" validate:"omitempty,dive,min=1,max=7"`
+	DryRun 			bool 			`json:"dry_run"`
+}
+
+type TimetableGeneration struct {
+	TermId 			uuid.UUID
+	ClassIds 		[]uuid.UUID
+	Days 			[]int
+	DryRun 			bool
+	MaxSteps 		int
+}
+
+// UnsatConstraint is one reason why the solver cannot make a timetable without conflicts
+type UnsatConstraint struct {
+	Kind 			string 			`json:"kind"`
+	Message 		string 			`json:"message"`
+	ClassId 		*uuid.UUID 		`json:"class_id,omitempty"`
+	TeacherId 		*uuid.UUID 		`json:"teacher_id,omitempty"`
+	SubjectId 		*uuid.UUID 		`json:"subject_id,omitempty"`
+	RoomId 			*uuid.UUID 		`json:"room_id,omitempty"`
+}
+
+type GenerationResult struct {
+	TermId 			uuid.UUID 			`json:"term_id"`
+	Saved 			bool 				`json:"saved"`
+	Slots 			[]TimetableSlot 	`json:"slots"`
+	Replaced 		int 				`json:"replaced"`
+	Unsatisfied 	[]UnsatConstraint 	`json:"unsatisfied"`
+}
@@ -17,6 +17,8 @@ type UserStore interface {
 		payload Update,
 		) error
 	GetUserById(id uuid.UUID) (*User, error)
+	CountUsersByProfileImage(ctx context.Context, key string) (int, error)
+	CountUsersByRole(ctx context.Context, role string) (int, error)
 }
 
 type User struct {
@@ -36,11 +38,20 @@ type Register struct {
 	Email 			string 		`json:"email" validate:"required,email,min=2,max=100"`
 	Password 		string 		`json:"password" validate:"required,min=2,max=100"`
 	Profile_Image 	string 		`json:"profile_image"`
-	Role 			string 		`json:"role"`
+	Role 			string 		`json:"role" validate:"omitempty,oneof=siswa"`
 	Created_at 		time.Time 	`json:"created_at"`
 	Updated_at 		time.Time 	`json:"updated_at"`
 }
 
+// CreateAccount is the account that the admin makes, the guru, the admin and the orangtua cannot
+// register themselves
+type CreateAccount struct {
+	Username 		string 		`json:"username" validate:"required,min=2,max=100"`
+	Email 			string 		`json:"email" validate:"required,email,min=2,max=100"`
+	Password 		string 		`json:"password" validate:"required,min=2,max=100"`
+	Role 			string 		`json:"role" validate:"required,oneof=guru siswa admin orangtua"`
+}
+
 type Login struct {
 	Username 		string 	`json:"username" validate:"required"`
 	Email 			string	`json:"email" validate:"required,email"`
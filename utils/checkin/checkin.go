@@ -0,0 +1,103 @@
+// Package checkin makes the short-lived codes shown as a QR code in the class, a code is signed
+// with HMAC so the server does not have to save the issued codes.
+package checkin
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCode = errors.New("checkin: invalid code")
+	ErrExpiredCode = errors.New("checkin: the code has expired")
+)
+
+// Claims is what the code says: the lesson, the date of the lesson and the nonce used against replay
+type Claims struct {
+	SlotId 		uuid.UUID
+	Date 		time.Time
+	Nonce 		string
+	ExpiresAt 	time.Time
+}
+
+// Issue makes a new code of the lesson valid for ttl
+func Issue(secret []byte, slotId uuid.UUID, date time.Time, ttl time.Duration, now time.Time) (string, Claims, error) {
+
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", Claims{}, err
+	}
+
+	claims := Claims{
+		SlotId: slotId,
+		Date: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Nonce: hex.EncodeToString(nonce),
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+	}
+	payload := fmt.Sprintf("%s|%s|%s|%d", claims.SlotId, claims.Date.Format("2006-01-02"), claims.Nonce, claims.ExpiresAt.Unix())
+
+	return encode(payload) + "." + encode(string(sign(secret, payload))), claims, nil
+
+}
+
+// Parse checks the signature and the expiry of the code
+func Parse(secret []byte, code string, now time.Time) (Claims, error) {
+
+	payloadPart, signaturePart, ok := strings.Cut(code, ".")
+	if !ok {
+		return Claims{}, ErrInvalidCode
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return Claims{}, ErrInvalidCode
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signaturePart)
+	if err != nil || !hmac.Equal(signature, sign(secret, string(payload))) {
+		return Claims{}, ErrInvalidCode
+	}
+
+	fields := strings.Split(string(payload), "|")
+	if len(fields) != 4 {
+		return Claims{}, ErrInvalidCode
+	}
+	slotId, err := uuid.Parse(fields[0])
+	if err != nil {
+		return Claims{}, ErrInvalidCode
+	}
+	date, err := time.Parse("2006-01-02", fields[1])
+	if err != nil {
+		return Claims{}, ErrInvalidCode
+	}
+	expires, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return Claims{}, ErrInvalidCode
+	}
+
+	claims := Claims{SlotId: slotId, Date: date, Nonce: fields[2], ExpiresAt: time.Unix(expires, 0).UTC()}
+	if now.After(claims.ExpiresAt) {
+		return Claims{}, ErrExpiredCode
+	}
+
+	return claims, nil
+
+}
+
+func sign(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func encode(value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
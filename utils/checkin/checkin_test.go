@@ -0,0 +1,38 @@
+package checkin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestIssueAndParse(t *testing.T) {
+	secret := []byte("secret")
+	slot := uuid.New()
+	now := time.Date(2025, time.August, 4, 8, 0, 0, 0, time.UTC)
+
+	code, issued, err := Issue(secret, slot, now, 30*time.Second, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := Parse(secret, code, now.Add(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.SlotId != slot || claims.Nonce != issued.Nonce || !claims.Date.Equal(issued.Date) {
+		t.Fatalf("got %+v, want %+v", claims, issued)
+	}
+
+	if _, err := Parse(secret, code, now.Add(time.Minute)); !errors.Is(err, ErrExpiredCode) {
+		t.Fatalf("expected expired, got %v", err)
+	}
+	if _, err := Parse([]byte("other"), code, now); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+	if _, err := Parse(secret, code[:len(code)-2]+"xx", now); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("expected invalid code, got %v", err)
+	}
+}
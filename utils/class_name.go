@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClassName is the parsed form of a class like "XI IPA 1"
+type ClassName struct {
+	GradeLevel int
+	Major      string
+	Section    string
+}
+
+var (
+	classSeparator    = regexp.MustCompile(`[^A-Z0-9]+`)
+	classDigitLetter  = regexp.MustCompile(`([0-9])([A-Z])`)
+	classLetterDigit  = regexp.MustCompile(`([A-Z])([0-9])`)
+	classWithMajor    = regexp.MustCompile(`^([IVX]+|[0-9]{1,2}) ([A-Z]+) ([0-9]+)$`)
+	classWithoutMajor = regexp.MustCompile(`^([IVX]+|[0-9]{1,2}) ([A-Z])$`)
+
+	romanGrades = map[string]int{
+		"I": 1, "II": 2, "III": 3, "IV": 4, "V": 5, "VI": 6,
+		"VII": 7, "VIII": 8, "IX": 9, "X": 10, "XI": 11, "XII": 12,
+	}
+	gradeRomans = []string{"", "I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+)
+
+// NormalizeClassName upper-cases the value and puts one space between every token,
+// "xi-ipa-1" and "11 IPA1" become "XI IPA 1" and "11 IPA 1"
+func NormalizeClassName(raw string) string {
+	value := classSeparator.ReplaceAllString(strings.ToUpper(raw), " ")
+	value = classDigitLetter.ReplaceAllString(value, "$1 $2")
+	value = classLetterDigit.ReplaceAllString(value, "$1 $2")
+	return strings.TrimSpace(value)
+}
+
+// ParseClassName reads the grade (roman or arabic), the major and the section of a class,
+// the migration 000004 uses the same rules in sql
+func ParseClassName(raw string) (ClassName, error) {
+
+	value := NormalizeClassName(raw)
+
+	var grade, major, section string
+	if match := classWithMajor.FindStringSubmatch(value); match != nil {
+		grade, major, section = match[1], match[2], match[3]
+	} else if match := classWithoutMajor.FindStringSubmatch(value); match != nil {
+		grade, section = match[1], match[2]
+	} else {
+		return ClassName{}, fmt.Errorf("Cannot read the class %q, use the format like XI IPA 1", raw)
+	}
+
+	level, ok := romanGrades[grade]
+	if !ok {
+		number, err := strconv.Atoi(grade)
+		if err != nil {
+			return ClassName{}, fmt.Errorf("Invalid grade level of the class %q", raw)
+		}
+		level = number
+	}
+	if level < 1 || level > 12 {
+		return ClassName{}, errors.New("The grade level must be between 1 and 12")
+	}
+
+	return ClassName{GradeLevel: level, Major: major, Section: section}, nil
+
+}
+
+// String returns the canonical name, for example "XI IPA 1"
+func (c ClassName) String() string {
+	grade := strconv.Itoa(c.GradeLevel)
+	if c.GradeLevel > 0 && c.GradeLevel < len(gradeRomans) {
+		grade = gradeRomans[c.GradeLevel]
+	}
+	if c.Major == "" {
+		return grade + " " + c.Section
+	}
+	return grade + " " + c.Major + " " + c.Section
+}
+
+// AcademicYearOf returns the school year ("2025/2026") of a date, the year starts in july
+func AcademicYearOf(t time.Time) string {
+	year := t.Year()
+	if t.Month() < time.July {
+		year--
+	}
+	return fmt.Sprintf("%d/%d", year, year+1)
+}
+
+// ValidAcademicYear reports if the name is two following years like "2025/2026"
+func ValidAcademicYear(name string) bool {
+	if len(name) != 9 || name[4] != '/' {
+		return false
+	}
+	first, err := strconv.Atoi(name[:4])
+	if err != nil {
+		return false
+	}
+	second, err := strconv.Atoi(name[5:])
+	if err != nil {
+		return false
+	}
+	return second == first+1
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClassName(t *testing.T) {
+	same := []string{"XI IPA 1", "11 IPA1", "xi-ipa-1", " 11-ipa 1 "}
+	for _, raw := range same {
+		name, err := ParseClassName(raw)
+		if err != nil {
+			t.Fatalf("%q: %v", raw, err)
+		}
+		if name.String() != "XI IPA 1" {
+			t.Fatalf("%q parsed as %q", raw, name.String())
+		}
+	}
+
+	name, err := ParseClassName("7a")
+	if err != nil || name.String() != "VII A" {
+		t.Fatalf("7a parsed as %q (%v)", name.String(), err)
+	}
+
+	for _, raw := range []string{"XIIPA1", "kelas sebelas", "13 IPA 1", ""} {
+		if _, err := ParseClassName(raw); err == nil {
+			t.Fatalf("%q should be ambiguous", raw)
+		}
+	}
+}
+
+func TestAcademicYearOf(t *testing.T) {
+	if got := AcademicYearOf(time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)); got != "2025/2026" {
+		t.Fatalf("got %s", got)
+	}
+	if got := AcademicYearOf(time.Date(2026, time.June, 30, 0, 0, 0, 0, time.UTC)); got != "2025/2026" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestValidAcademicYear(t *testing.T) {
+	for _, name := range []string{"2025/2026", "1999/2000"} {
+		if !ValidAcademicYear(name) {
+			t.Fatalf("%q should be valid", name)
+		}
+	}
+	for _, name := range []string{"2024-2025", "2025/2025", "2025/2027", "25/26", "2025/26", "abcd/efgh", "+202/2026"} {
+		if ValidAcademicYear(name) {
+			t.Fatalf("%q should not be valid", name)
+		}
+	}
+}
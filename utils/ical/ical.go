@@ -0,0 +1,134 @@
+// Package ical writes the iCalendar (RFC 5545) feeds of the schedules. Every time is written in UTC,
+// the all day events are written as dates.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event is one VEVENT, RRule is the recurrence rule without the "RRULE:" prefix and ExDates are
+// the starts of the occurrences that are skipped
+type Event struct {
+	UID 			string
+	Summary 		string
+	Description 	string
+	Location 		string
+	Categories 		string
+	Start 			time.Time
+	End 			time.Time
+	AllDay 			bool
+	RRule 			string
+	ExDates 		[]time.Time
+}
+
+// Calendar is one VCALENDAR, Stamp is the DTSTAMP of every event
+type Calendar struct {
+	Name 			string
+	ProdId 			string
+	Stamp 			time.Time
+	Events 			[]Event
+}
+
+const (
+	dateTimeFormat 	= "20060102T150405Z"
+	dateFormat 		= "20060102"
+	maxLineOctets 	= 75
+)
+
+// Write writes the calendar with the CRLF line endings and the long lines folded at 75 octets
+func Write(w io.Writer, calendar Calendar) error {
+
+	out := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(out, name+":"+value)
+	}
+
+	prodId := calendar.ProdId
+	if prodId == "" {
+		prodId = "-//Shcool-manajement//Schedule//EN"
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodId)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if calendar.Name != "" {
+		line("X-WR-CALNAME", Escape(calendar.Name))
+	}
+
+	for _, event := range calendar.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", Escape(event.UID))
+		line("DTSTAMP", calendar.Stamp.UTC().Format(dateTimeFormat))
+		if event.AllDay {
+			line("DTSTART;VALUE=DATE", event.Start.Format(dateFormat))
+			line("DTEND;VALUE=DATE", event.End.Format(dateFormat))
+		} else {
+			line("DTSTART", event.Start.UTC().Format(dateTimeFormat))
+			line("DTEND", event.End.UTC().Format(dateTimeFormat))
+		}
+		if event.RRule != "" {
+			line("RRULE", event.RRule)
+		}
+		for _, exDate := range event.ExDates {
+			if event.AllDay {
+				line("EXDATE;VALUE=DATE", exDate.Format(dateFormat))
+			} else {
+				line("EXDATE", exDate.UTC().Format(dateTimeFormat))
+			}
+		}
+		line("SUMMARY", Escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", Escape(event.Description))
+		}
+		if event.Location != "" {
+			line("LOCATION", Escape(event.Location))
+		}
+		if event.Categories != "" {
+			line("CATEGORIES", Escape(event.Categories))
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return out.Flush()
+
+}
+
+// WeeklyUntil makes the weekly recurrence rule that ends at the given time
+func WeeklyUntil(until time.Time) string {
+	return "FREQ=WEEKLY;UNTIL=" + until.UTC().Format(dateTimeFormat)
+}
+
+// Escape escapes the text value of a property
+func Escape(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+//helper to fold the content line, the continuation line starts with one space and a rune is never split
+func writeFolded(out *bufio.Writer, content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		out.WriteString(content[:cut])
+		out.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1
+	}
+	out.WriteString(content)
+	out.WriteString("\r\n")
+}
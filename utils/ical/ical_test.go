@@ -0,0 +1,82 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	start := time.Date(2025, time.July, 14, 7, 0, 0, 0, jakarta)
+	calendar := Calendar{
+		Name: "Jadwal 10 IPA 1",
+		Stamp: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+		Events: []Event{
+			{
+				UID: "slot-1@school",
+				Summary: "Matematika, 10 IPA 1",
+				Location: "R101",
+				Start: start,
+				End: start.Add(45 * time.Minute),
+				RRule: WeeklyUntil(time.Date(2025, time.December, 31, 23, 59, 59, 0, jakarta)),
+				ExDates: []time.Time{start.AddDate(0, 0, 7*5)},
+			},
+			{
+				UID: "event-1@school",
+				Summary: "Libur",
+				Start: time.Date(2025, time.August, 18, 0, 0, 0, 0, time.UTC),
+				End: time.Date(2025, time.August, 19, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := Write(&b, calendar); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20250714T000000Z\r\n",
+		"DTEND:20250714T004500Z\r\n",
+		"RRULE:FREQ=WEEKLY;UNTIL=20251231T165959Z\r\n",
+		"EXDATE:20250818T000000Z\r\n",
+		"SUMMARY:Matematika\\, 10 IPA 1\r\n",
+		"DTSTART;VALUE=DATE:20250818\r\n",
+		"DTEND;VALUE=DATE:20250819\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestFolding(t *testing.T) {
+	var b bytes.Buffer
+	long := strings.Repeat("é", 100)
+	if err := Write(&b, Calendar{Events: []Event{{UID: "x", Summary: long}}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("the line is not folded: %d octets", len(line))
+		}
+		if !strings.HasPrefix(line, " ") && strings.Contains(line, "�") {
+			t.Fatalf("a rune was split")
+		}
+	}
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+long+"\r\n") {
+		t.Fatalf("the unfolded summary is not the same")
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := Escape("a;b,c\\d\ne"); got != `a\;b\,c\\d\ne` {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,157 @@
+// Package imaging normalizes uploaded pictures: it decodes them, applies the
+// EXIF orientation, crops them square and renders the size variants that are
+// saved in the storage. Re-encoding the pixels also drops every EXIF field.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Size is a named variant of the picture, Pixels is the width and height of the square
+type Size struct {
+	Name   string
+	Pixels int
+}
+
+// Format is an output encoding of the variants
+type Format struct {
+	Name        string
+	Extension   string
+	ContentType string
+}
+
+var (
+	SizeSmall    = Size{Name: "small", Pixels: 96}
+	SizeMedium   = Size{Name: "medium", Pixels: 320}
+	SizeOriginal = Size{Name: "original", Pixels: 1024}
+
+	FormatJPEG = Format{Name: "jpeg", Extension: ".jpg", ContentType: "image/jpeg"}
+	FormatPNG  = Format{Name: "png", Extension: ".png", ContentType: "image/png"}
+
+	// Sizes and Formats are every variant generated for a profile picture
+	Sizes   = []Size{SizeSmall, SizeMedium, SizeOriginal}
+	Formats = []Format{FormatJPEG, FormatPNG}
+)
+
+// MaxPixels protects the decoder against decompression bombs
+const MaxPixels = 40_000_000
+
+var ErrUnsupported = errors.New("unsupported image format")
+
+// Variant is one encoded size/format of the processed picture
+type Variant struct {
+	Size   Size
+	Format Format
+	Data   []byte
+}
+
+// LookupSize returns the size by name, an empty name is the medium size
+func LookupSize(name string) (Size, bool) {
+	if name == "" {
+		return SizeMedium, true
+	}
+	for _, size := range Sizes {
+		if size.Name == name {
+			return size, true
+		}
+	}
+	return Size{}, false
+}
+
+// LookupFormat returns the format by name, an empty name is jpeg
+func LookupFormat(name string) (Format, bool) {
+	switch name {
+	case "", "jpeg", "jpg":
+		return FormatJPEG, true
+	case "png":
+		return FormatPNG, true
+	}
+	return Format{}, false
+}
+
+// Process decodes the upload and renders every size in every format
+func Process(data []byte) ([]Variant, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("invalid image dimension %dx%d", config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	square := CropSquare(Orient(src, Orientation(data)))
+
+	var variants []Variant
+	for _, size := range Sizes {
+		resized := Resize(square, size.Pixels)
+		for _, format := range Formats {
+			encoded, err := Encode(resized, format)
+			if err != nil {
+				return nil, err
+			}
+			variants = append(variants, Variant{Size: size, Format: format, Data: encoded})
+		}
+	}
+
+	return variants, nil
+}
+
+// CropSquare keeps the centered square of the picture
+func CropSquare(src image.Image) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, image.Pt(x, y), draw.Src)
+	return dst
+}
+
+// Resize scales a square picture down to pixels, smaller pictures are never upscaled
+func Resize(src image.Image, pixels int) image.Image {
+	b := src.Bounds()
+	if b.Dx() <= pixels {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, pixels, pixels))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+// Encode writes the picture in the format, jpeg has no alpha so it is flattened on white
+func Encode(img image.Image, format Format) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case FormatJPEG:
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+	case FormatPNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode png: %w", err)
+		}
+	default:
+		return nil, ErrUnsupported
+	}
+	return buf.Bytes(), nil
+}
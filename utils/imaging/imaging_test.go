@@ -0,0 +1,91 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// withOrientation injects an APP1 Exif segment with the orientation tag after the SOI marker
+func withOrientation(t *testing.T, jpg []byte, orientation uint16) []byte {
+	t.Helper()
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(ifd[0:], 1)
+	binary.BigEndian.PutUint16(ifd[2:], 0x0112)
+	binary.BigEndian.PutUint16(ifd[4:], 3)
+	binary.BigEndian.PutUint32(ifd[6:], 1)
+	binary.BigEndian.PutUint16(ifd[10:], orientation)
+	payload := append([]byte("Exif\x00\x00"), append(tiff, ifd...)...)
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, segment...)
+	return append(out, jpg[2:]...)
+}
+
+func TestProcessOrientsCropsAndResizes(t *testing.T) {
+	// 400x200 picture, left half red and right half blue
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 200 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	data := withOrientation(t, buf.Bytes(), 6)
+
+	if got := Orientation(data); got != 6 {
+		t.Fatalf("orientation = %d, want 6", got)
+	}
+
+	// rotated 90° clockwise the red half ends up on top
+	oriented := Orient(src, 6)
+	if b := oriented.Bounds(); b.Dx() != 200 || b.Dy() != 400 {
+		t.Fatalf("oriented bounds = %v", b)
+	}
+	if r, _, _, _ := oriented.At(100, 10).RGBA(); r>>8 != 255 {
+		t.Fatal("expected the top of the rotated picture to be red")
+	}
+
+	variants, err := Process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != len(Sizes)*len(Formats) {
+		t.Fatalf("got %d variants", len(variants))
+	}
+	for _, variant := range variants {
+		img, _, err := image.Decode(bytes.NewReader(variant.Data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := min(variant.Size.Pixels, 200)
+		if b := img.Bounds(); b.Dx() != want || b.Dy() != want {
+			t.Fatalf("%s/%s bounds = %v, want %d", variant.Size.Name, variant.Format.Name, b, want)
+		}
+		if variant.Format == FormatJPEG && Orientation(variant.Data) != 1 {
+			t.Fatal("expected the exif data to be stripped")
+		}
+	}
+}
+
+func TestProcessRejectsGarbage(t *testing.T) {
+	if _, err := Process([]byte("not an image")); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,30 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestPNGAndSVG(t *testing.T) {
+	data, err := PNG("check-in code", 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 256 {
+		t.Fatalf("got width %d", img.Bounds().Dx())
+	}
+
+	svg, err := SVG("check-in code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(svg), "<svg") || !strings.Contains(string(svg), "h1v1h-1z") {
+		t.Fatalf("unexpected svg %s", svg[:40])
+	}
+}